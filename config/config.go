@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 // Config holds all application configuration
@@ -10,6 +11,9 @@ type Config struct {
 	Database DatabaseConfig
 	Server   ServerConfig
 	Security SecurityConfig
+	Library  LibraryConfig
+	Catalog  CatalogConfig
+	Notices  NoticesConfig
 }
 
 // DatabaseConfig holds database configuration
@@ -39,12 +43,43 @@ type SecurityConfig struct {
 	CORSCredentials bool
 }
 
+// LibraryConfig holds lending configuration
+type LibraryConfig struct {
+	LoanPoliciesFile    string
+	HoldPickupDays      int
+	MaxUnpaidFinesCents int64
+	MembershipDays      int
+	BorrowingLimits     map[string]int
+}
+
+// CatalogConfig holds cataloguing configuration
+type CatalogConfig struct {
+	ISBNRangesFile string // RangeMessage.xml of the International ISBN Agency
+}
+
+// NoticesConfig holds due-date reminder and overdue notice configuration
+type NoticesConfig struct {
+	Notifier        string // "smtp", "webhook" or empty to disable notices
+	IntervalMinutes int
+	ReminderDays    []int
+	OverdueDays     []int
+	SMTPHost        string
+	SMTPPort        int
+	SMTPUsername    string
+	SMTPPassword    string
+	SMTPFrom        string
+	WebhookURL      string
+}
+
 // Load loads configuration from environment variables
 func Load() *Config {
 	return &Config{
 		Database: loadDatabaseConfig(),
 		Server:   loadServerConfig(),
 		Security: loadSecurityConfig(),
+		Library:  loadLibraryConfig(),
+		Catalog:  loadCatalogConfig(),
+		Notices:  loadNoticesConfig(),
 	}
 }
 
@@ -104,6 +139,112 @@ func loadSecurityConfig() SecurityConfig {
 	}
 }
 
+func loadLibraryConfig() LibraryConfig {
+	holdPickupDays := 3
+	if days := os.Getenv("HOLD_PICKUP_DAYS"); days != "" {
+		if d, err := strconv.Atoi(days); err == nil && d > 0 {
+			holdPickupDays = d
+		}
+	}
+
+	var maxUnpaidFines int64 = 500
+	if fines := os.Getenv("MAX_UNPAID_FINES_CENTS"); fines != "" {
+		if f, err := strconv.ParseInt(fines, 10, 64); err == nil && f >= 0 {
+			maxUnpaidFines = f
+		}
+	}
+
+	membershipDays := 365
+	if days := os.Getenv("MEMBERSHIP_DAYS"); days != "" {
+		if d, err := strconv.Atoi(days); err == nil && d > 0 {
+			membershipDays = d
+		}
+	}
+
+	return LibraryConfig{
+		LoanPoliciesFile:    os.Getenv("LOAN_POLICIES_FILE"),
+		HoldPickupDays:      holdPickupDays,
+		MaxUnpaidFinesCents: maxUnpaidFines,
+		MembershipDays:      membershipDays,
+		BorrowingLimits:     getEnvLimits("BORROWING_LIMITS", map[string]int{"adult": 5, "child": 2}),
+	}
+}
+
+func loadCatalogConfig() CatalogConfig {
+	return CatalogConfig{
+		ISBNRangesFile: os.Getenv("ISBN_RANGES_FILE"),
+	}
+}
+
+func loadNoticesConfig() NoticesConfig {
+	interval := 60
+	if minutes := os.Getenv("NOTICE_INTERVAL_MINUTES"); minutes != "" {
+		if m, err := strconv.Atoi(minutes); err == nil && m > 0 {
+			interval = m
+		}
+	}
+
+	smtpPort := 25
+	if portStr := os.Getenv("SMTP_PORT"); portStr != "" {
+		if p, err := strconv.Atoi(portStr); err == nil && p > 0 {
+			smtpPort = p
+		}
+	}
+
+	return NoticesConfig{
+		Notifier:        os.Getenv("NOTIFIER"),
+		IntervalMinutes: interval,
+		ReminderDays:    getEnvDays("NOTICE_REMINDER_DAYS", []int{3, 1}),
+		OverdueDays:     getEnvDays("NOTICE_OVERDUE_DAYS", []int{1, 7, 14, 30}),
+		SMTPHost:        getEnvOrDefault("SMTP_HOST", "localhost"),
+		SMTPPort:        smtpPort,
+		SMTPUsername:    os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:    os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:        getEnvOrDefault("SMTP_FROM", "library@localhost"),
+		WebhookURL:      os.Getenv("NOTICE_WEBHOOK_URL"),
+	}
+}
+
+// getEnvDays parses a comma-separated list of positive day counts, falling
+// back to the default when the variable is unset or malformed.
+func getEnvDays(key string, defaultValue []int) []int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	days := make([]int, 0)
+	for _, part := range strings.Split(value, ",") {
+		d, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || d <= 0 {
+			return defaultValue
+		}
+		days = append(days, d)
+	}
+	return days
+}
+
+// getEnvLimits parses a comma-separated list of type=count pairs such as
+// "adult=5,child=2", falling back to the default when the variable is unset
+// or malformed.
+func getEnvLimits(key string, defaultValue map[string]int) map[string]int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	limits := make(map[string]int)
+	for _, part := range strings.Split(value, ",") {
+		name, count, found := strings.Cut(part, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(count))
+		if !found || strings.TrimSpace(name) == "" || err != nil || n <= 0 {
+			return defaultValue
+		}
+		limits[strings.TrimSpace(name)] = n
+	}
+	return limits
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
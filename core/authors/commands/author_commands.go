@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/authors/errors"
+	"books/core/authors/models"
+	"books/core/authors/repositories"
+)
+
+type AddAuthorCommand struct {
+	ID             string
+	Name           string
+	AlternateNames []string
+	BirthYear      int
+	DeathYear      int
+}
+
+type AddAuthorCommandHandler struct {
+	repo repositories.AuthorRepository
+}
+
+func NewAddAuthorCommandHandler(repo repositories.AuthorRepository) *AddAuthorCommandHandler {
+	return &AddAuthorCommandHandler{repo: repo}
+}
+
+func (h *AddAuthorCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(AddAuthorCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	author, err := models.NewAuthor(command.Name, command.AlternateNames, command.BirthYear, command.DeathYear)
+	if err != nil {
+		return err
+	}
+	author.ID = command.ID
+
+	if err := checkDuplicate(ctx, h.repo, author); err != nil {
+		return err
+	}
+
+	return h.repo.SaveAuthor(ctx, author)
+}
+
+// UpdateAuthorCommand changes an author's record. Empty fields are left as
+// they are; an empty, non-nil AlternateNames clears the alternate names.
+type UpdateAuthorCommand struct {
+	ID             string
+	Name           string
+	AlternateNames []string
+	BirthYear      int
+	DeathYear      int
+}
+
+type UpdateAuthorCommandHandler struct {
+	repo repositories.AuthorRepository
+}
+
+func NewUpdateAuthorCommandHandler(repo repositories.AuthorRepository) *UpdateAuthorCommandHandler {
+	return &UpdateAuthorCommandHandler{repo: repo}
+}
+
+func (h *UpdateAuthorCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(UpdateAuthorCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	if command.Name == "" && command.AlternateNames == nil && command.BirthYear == 0 && command.DeathYear == 0 {
+		return stderrors.New("at least one field must be provided for update")
+	}
+
+	author, err := h.repo.GetAuthorByID(ctx, command.ID)
+	if err != nil {
+		return err
+	}
+
+	if command.Name != "" {
+		author.Name = command.Name
+	}
+	if command.AlternateNames != nil {
+		author.AlternateNames = command.AlternateNames
+	}
+	if command.BirthYear != 0 {
+		author.BirthYear = command.BirthYear
+	}
+	if command.DeathYear != 0 {
+		author.DeathYear = command.DeathYear
+	}
+
+	author.Normalize()
+	if err := author.Validate(); err != nil {
+		return err
+	}
+	if err := checkDuplicate(ctx, h.repo, author); err != nil {
+		return err
+	}
+
+	return h.repo.UpdateAuthor(ctx, author)
+}
+
+// checkDuplicate refuses an author who is already on record: another author
+// has a form of their name exactly and the same birth year, both of which
+// may be unknown. Authors sharing a name are told apart by their birth year.
+func checkDuplicate(ctx context.Context, repo repositories.AuthorRepository, author *models.Author) error {
+	for _, surname := range author.Surnames() {
+		candidates, err := repo.FindAuthorsBySurname(ctx, surname)
+		if err != nil {
+			return err
+		}
+
+		for _, candidate := range candidates {
+			if candidate.ID == author.ID || candidate.BirthYear != author.BirthYear {
+				continue
+			}
+			for _, name := range author.Names() {
+				if match, ok := candidate.Match(name); ok && match.Exact {
+					return errors.ErrDuplicateAuthor
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"books/core/authors/errors"
+	"books/core/authors/repositories"
+	storage_models "books/core/storage/models"
+	storage_repositories "books/core/storage/repositories"
+)
+
+type authorCommandTestCase struct {
+	name      string
+	command   interface{}
+	expectErr error
+}
+
+// newTestRepository holds J. R. R. Tolkien, born 1892, as "tolkien".
+func newTestRepository(t *testing.T) *repositories.AuthorInMemoryRepository {
+	t.Helper()
+	repo := repositories.NewAuthorInMemoryRepository()
+
+	err := NewAddAuthorCommandHandler(repo).Handle(context.Background(), AddAuthorCommand{
+		ID:             "tolkien",
+		Name:           "J. R. R. Tolkien",
+		AlternateNames: []string{"John Ronald Reuel Tolkien"},
+		BirthYear:      1892,
+		DeathYear:      1973,
+	})
+	if err != nil {
+		t.Fatalf("failed to add author: %v", err)
+	}
+	return repo
+}
+
+func checkError(t *testing.T, err, expected error) {
+	t.Helper()
+	if expected == nil {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	if err == nil || (!stderrors.Is(err, expected) && err.Error() != expected.Error()) {
+		t.Fatalf("expected error %v, got %v", expected, err)
+	}
+}
+
+func TestAddAuthorCommandHandler(t *testing.T) {
+	tests := []authorCommandTestCase{
+		{
+			name:    "Add an author",
+			command: AddAuthorCommand{Name: "Christopher Tolkien", BirthYear: 1924},
+		},
+		{
+			name:    "Same name with another birth year",
+			command: AddAuthorCommand{Name: "J.R.R. Tolkien", BirthYear: 1950},
+		},
+		{
+			name:      "Name form already on record",
+			command:   AddAuthorCommand{Name: "Tolkien, J.R.R.", BirthYear: 1892},
+			expectErr: errors.ErrDuplicateAuthor,
+		},
+		{
+			name:      "Alternate name already on record",
+			command:   AddAuthorCommand{Name: "Someone Else", AlternateNames: []string{"JRR Tolkien"}, BirthYear: 1892},
+			expectErr: errors.ErrDuplicateAuthor,
+		},
+		{
+			name:      "Missing name",
+			command:   AddAuthorCommand{Name: " "},
+			expectErr: stderrors.New("author name cannot be empty"),
+		},
+		{
+			name:      "Death before birth",
+			command:   AddAuthorCommand{Name: "Ann Writer", BirthYear: 1950, DeathYear: 1900},
+			expectErr: stderrors.New("invalid death year: it is before the birth year"),
+		},
+		{
+			name:      "Invalid command type",
+			command:   "not a command",
+			expectErr: stderrors.New("invalid command type"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			err := NewAddAuthorCommandHandler(repo).Handle(context.Background(), tc.command)
+			checkError(t, err, tc.expectErr)
+			if tc.expectErr != nil {
+				return
+			}
+
+			authors, _ := repo.GetAllAuthors(context.Background())
+			if len(authors) != 2 {
+				t.Errorf("expected 2 authors, got %d", len(authors))
+			}
+		})
+	}
+}
+
+func TestAddAuthorDropsVariantNames(t *testing.T) {
+	repo := repositories.NewAuthorInMemoryRepository()
+	err := NewAddAuthorCommandHandler(repo).Handle(context.Background(), AddAuthorCommand{
+		ID:             "austen",
+		Name:           " Jane Austen ",
+		AlternateNames: []string{"Austen, Jane", "A Lady", "a lady"},
+	})
+	if err != nil {
+		t.Fatalf("failed to add author: %v", err)
+	}
+
+	author, _ := repo.GetAuthorByID(context.Background(), "austen")
+	if author.Name != "Jane Austen" || len(author.AlternateNames) != 1 || author.AlternateNames[0] != "A Lady" {
+		t.Errorf("unexpected author %+v", author)
+	}
+}
+
+func TestUpdateAuthorCommandHandler(t *testing.T) {
+	tests := []authorCommandTestCase{
+		{
+			name:    "Alternate names that are forms of the name",
+			command: UpdateAuthorCommand{ID: "tolkien", AlternateNames: []string{"JRR Tolkien", "Tolkien, J. R. R."}},
+		},
+		{
+			name:      "Unknown author",
+			command:   UpdateAuthorCommand{ID: "missing", Name: "Nobody"},
+			expectErr: errors.ErrAuthorNotFound,
+		},
+		{
+			name:      "Empty update",
+			command:   UpdateAuthorCommand{ID: "tolkien"},
+			expectErr: stderrors.New("at least one field must be provided for update"),
+		},
+		{
+			name:      "Death before birth",
+			command:   UpdateAuthorCommand{ID: "tolkien", DeathYear: 1800},
+			expectErr: stderrors.New("invalid death year: it is before the birth year"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			err := NewUpdateAuthorCommandHandler(repo).Handle(context.Background(), tc.command)
+			checkError(t, err, tc.expectErr)
+			if tc.expectErr != nil {
+				return
+			}
+
+			author, _ := repo.GetAuthorByID(context.Background(), "tolkien")
+			if author.Name != "J. R. R. Tolkien" || len(author.AlternateNames) != 0 || author.BirthYear != 1892 {
+				t.Errorf("expected only variants of the name to remain, got %+v", author)
+			}
+		})
+	}
+}
+
+func TestUpdateAuthorToDuplicate(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	_ = NewAddAuthorCommandHandler(repo).Handle(ctx, AddAuthorCommand{ID: "other", Name: "Ronald Tolkien", BirthYear: 1892})
+
+	err := NewUpdateAuthorCommandHandler(repo).Handle(ctx, UpdateAuthorCommand{ID: "other", Name: "Tolkien, J. R. R."})
+	checkError(t, err, errors.ErrDuplicateAuthor)
+}
+
+func TestMergeAuthorsCommandHandler(t *testing.T) {
+	repo := newTestRepository(t)
+	books := storage_repositories.NewBookStorageInMemoryRepository()
+	ctx := context.Background()
+
+	err := NewAddAuthorCommandHandler(repo).Handle(ctx, AddAuthorCommand{ID: "duplicate", Name: "Tolkien, JRR", DeathYear: 1973})
+	if err != nil {
+		t.Fatalf("failed to add duplicate: %v", err)
+	}
+
+	for isbn, contributors := range map[string][]storage_models.Contributor{
+		"9783161484100": {{Name: "JRR Tolkien", Role: storage_models.RoleAuthor, AuthorID: "duplicate"}},
+		"9780306406157": {
+			{Name: "J. R. R. Tolkien", Role: storage_models.RoleAuthor, AuthorID: "tolkien"},
+			{Name: "Tolkien, JRR", Role: storage_models.RoleIllustrator, AuthorID: "duplicate"},
+		},
+		"9780140449136": {{Name: "Ann Writer", Role: storage_models.RoleAuthor}},
+	} {
+		book, _ := storage_models.NewBook(isbn, "Book "+isbn, contributors[0].Name)
+		if err := book.SetDetails(storage_models.BookDetails{Contributors: contributors}); err != nil {
+			t.Fatalf("failed to set contributors: %v", err)
+		}
+		_ = books.Save(ctx, book)
+	}
+
+	handler := NewMergeAuthorsCommandHandler(repo, books)
+
+	checkError(t, handler.Handle(ctx, MergeAuthorsCommand{CanonicalID: "tolkien", DuplicateID: "tolkien"}), errors.ErrSelfMerge)
+	checkError(t, handler.Handle(ctx, MergeAuthorsCommand{CanonicalID: "tolkien", DuplicateID: "missing"}), errors.ErrAuthorNotFound)
+
+	if err := handler.Handle(ctx, MergeAuthorsCommand{CanonicalID: "tolkien", DuplicateID: "duplicate"}); err != nil {
+		t.Fatalf("failed to merge: %v", err)
+	}
+
+	if _, err := repo.GetAuthorByID(ctx, "duplicate"); !stderrors.Is(err, errors.ErrAuthorNotFound) {
+		t.Errorf("expected the duplicate to be removed, got %v", err)
+	}
+
+	linked, _ := books.FindByAuthorID(ctx, "tolkien")
+	if len(linked) != 2 {
+		t.Fatalf("expected both books to credit the canonical author, got %d", len(linked))
+	}
+	for _, book := range linked {
+		for _, contributor := range book.Contributors {
+			if contributor.AuthorID == "duplicate" {
+				t.Errorf("expected %s to no longer credit the duplicate, got %+v", book.ISBN, book.Contributors)
+			}
+		}
+	}
+	if book, _ := books.FindByISBN(ctx, "9783161484100"); book.Contributors[0].Name != "JRR Tolkien" {
+		t.Errorf("expected the credited name to be kept, got %+v", book.Contributors)
+	}
+
+	// "Tolkien, JRR" is a variant of the canonical name, so it is not kept
+	// as an alternate name.
+	author, _ := repo.GetAuthorByID(ctx, "tolkien")
+	if len(author.AlternateNames) != 1 || author.BirthYear != 1892 || author.DeathYear != 1973 {
+		t.Errorf("unexpected canonical author %+v", author)
+	}
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/authors/errors"
+	"books/core/authors/repositories"
+	"books/core/storage/repositories/interfaces"
+)
+
+// MergeAuthorsCommand folds a duplicate author record into the canonical
+// one: every book crediting the duplicate is re-pointed to the canonical
+// author, which takes on the duplicate's names, and the duplicate is
+// removed.
+type MergeAuthorsCommand struct {
+	CanonicalID string
+	DuplicateID string
+}
+
+type MergeAuthorsCommandHandler struct {
+	repo  repositories.AuthorRepository
+	books interfaces.BookRepository
+}
+
+func NewMergeAuthorsCommandHandler(repo repositories.AuthorRepository, books interfaces.BookRepository) *MergeAuthorsCommandHandler {
+	return &MergeAuthorsCommandHandler{
+		repo:  repo,
+		books: books,
+	}
+}
+
+func (h *MergeAuthorsCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(MergeAuthorsCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	if command.CanonicalID == command.DuplicateID {
+		return errors.ErrSelfMerge
+	}
+
+	canonical, err := h.repo.GetAuthorByID(ctx, command.CanonicalID)
+	if err != nil {
+		return err
+	}
+	duplicate, err := h.repo.GetAuthorByID(ctx, command.DuplicateID)
+	if err != nil {
+		return err
+	}
+
+	books, err := h.books.FindByAuthorID(ctx, duplicate.ID)
+	if err != nil {
+		return err
+	}
+	// The books are re-pointed first, so that a failure part way leaves both
+	// records in place to merge again.
+	for _, book := range books {
+		for i := range book.Contributors {
+			if book.Contributors[i].AuthorID == duplicate.ID {
+				book.Contributors[i].AuthorID = canonical.ID
+			}
+		}
+		if err := h.books.Save(ctx, book); err != nil {
+			return err
+		}
+	}
+
+	canonical.Absorb(duplicate)
+	if err := canonical.Validate(); err != nil {
+		return err
+	}
+	if err := h.repo.UpdateAuthor(ctx, canonical); err != nil {
+		return err
+	}
+
+	return h.repo.DeleteAuthor(ctx, duplicate.ID)
+}
@@ -0,0 +1,11 @@
+package errors
+
+import (
+	"errors"
+)
+
+var (
+	ErrAuthorNotFound  = errors.New("author not found")
+	ErrDuplicateAuthor = errors.New("an author with this name and birth year already exists")
+	ErrSelfMerge       = errors.New("an author cannot be merged into itself")
+)
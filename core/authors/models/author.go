@@ -0,0 +1,172 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxNameLength     = 255
+	maxAlternateNames = 50
+)
+
+// Author is the authority record of a person books are credited to. Name is
+// the form the catalog displays; AlternateNames are the other forms the
+// person is credited under. Years are zero when not known.
+type Author struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	AlternateNames []string  `json:"alternate_names"`
+	BirthYear      int       `json:"birth_year,omitempty"`
+	DeathYear      int       `json:"death_year,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Match is an author a name may refer to.
+type Match struct {
+	Author *Author `json:"author"`
+	// Name is the form of the author's name that matched.
+	Name string `json:"name"`
+	// Exact is set when the names only differ in punctuation, case and
+	// order; otherwise the surnames agree and the given names are
+	// compatible, as "J. Tolkien" is with "John Ronald Reuel Tolkien".
+	Exact bool `json:"exact"`
+}
+
+func NewAuthor(name string, alternateNames []string, birthYear, deathYear int) (*Author, error) {
+	author := &Author{
+		Name:           name,
+		AlternateNames: alternateNames,
+		BirthYear:      birthYear,
+		DeathYear:      deathYear,
+		CreatedAt:      time.Now(),
+	}
+	author.Normalize()
+
+	if err := author.Validate(); err != nil {
+		return nil, err
+	}
+	return author, nil
+}
+
+// Normalize trims the names and drops alternate names that are only
+// variants of the name or of each other.
+func (a *Author) Normalize() {
+	a.Name = strings.TrimSpace(a.Name)
+
+	alternates := make([]string, 0, len(a.AlternateNames))
+	seen := map[string]bool{NameKey(a.Name): true}
+	for _, name := range a.AlternateNames {
+		name = strings.TrimSpace(name)
+		key := NameKey(name)
+		if name != "" && seen[key] {
+			continue
+		}
+		seen[key] = true
+		alternates = append(alternates, name)
+	}
+	a.AlternateNames = alternates
+}
+
+// Validate checks an author. Authors are expected to be normalized.
+func (a *Author) Validate() error {
+	if a.Name == "" {
+		return errors.New("author name cannot be empty")
+	}
+	if utf8.RuneCountInString(a.Name) > maxNameLength {
+		return fmt.Errorf("invalid author name: longer than %d characters", maxNameLength)
+	}
+
+	if len(a.AlternateNames) > maxAlternateNames {
+		return fmt.Errorf("invalid alternate names: at most %d are allowed", maxAlternateNames)
+	}
+	for _, name := range a.AlternateNames {
+		if name == "" {
+			return errors.New("invalid alternate name: names cannot be empty")
+		}
+		if utf8.RuneCountInString(name) > maxNameLength {
+			return fmt.Errorf("invalid alternate name %q: longer than %d characters", name, maxNameLength)
+		}
+	}
+
+	latest := time.Now().Year()
+	if a.BirthYear < 0 || a.BirthYear > latest {
+		return fmt.Errorf("invalid birth year %d", a.BirthYear)
+	}
+	if a.DeathYear < 0 || a.DeathYear > latest {
+		return fmt.Errorf("invalid death year %d", a.DeathYear)
+	}
+	if a.BirthYear != 0 && a.DeathYear != 0 && a.DeathYear < a.BirthYear {
+		return errors.New("invalid death year: it is before the birth year")
+	}
+	return nil
+}
+
+// Names lists every form of the author's name, starting with Name.
+func (a *Author) Names() []string {
+	return append([]string{a.Name}, a.AlternateNames...)
+}
+
+// Surnames lists the surname keys of every form of the author's name once.
+func (a *Author) Surnames() []string {
+	surnames := make([]string, 0, len(a.AlternateNames)+1)
+	seen := make(map[string]bool)
+	for _, name := range a.Names() {
+		surname := Surname(name)
+		if surname == "" || seen[surname] {
+			continue
+		}
+		seen[surname] = true
+		surnames = append(surnames, surname)
+	}
+	return surnames
+}
+
+// Match compares a name with every form of the author's name, preferring an
+// exact match.
+func (a *Author) Match(name string) (Match, bool) {
+	tokens := nameTokens(name)
+	key := strings.Join(tokens, " ")
+	if key == "" {
+		return Match{}, false
+	}
+
+	var compatible *Match
+	for _, form := range a.Names() {
+		formTokens := nameTokens(form)
+		if strings.Join(formTokens, " ") == key {
+			return Match{Author: a, Name: form, Exact: true}, true
+		}
+		if compatible == nil && compatibleNames(tokens, formTokens) {
+			compatible = &Match{Author: a, Name: form}
+		}
+	}
+
+	if compatible != nil {
+		return *compatible, true
+	}
+	return Match{}, false
+}
+
+// Absorb takes on the names of a duplicate record as alternate names, and
+// its years where they are not known.
+func (a *Author) Absorb(duplicate *Author) {
+	a.AlternateNames = append(a.AlternateNames, duplicate.Names()...)
+	if a.BirthYear == 0 {
+		a.BirthYear = duplicate.BirthYear
+	}
+	if a.DeathYear == 0 {
+		a.DeathYear = duplicate.DeathYear
+	}
+	a.Normalize()
+}
+
+// Clone returns a copy that shares no memory with a.
+func (a *Author) Clone() *Author {
+	c := *a
+	c.AlternateNames = append([]string{}, a.AlternateNames...)
+	return &c
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// nameSuffixes are generational suffixes, which are neither given names nor
+// surnames.
+var nameSuffixes = map[string]bool{"jr": true, "sr": true, "ii": true, "iii": true, "iv": true}
+
+// NameKey reduces a personal name to the form its variants share, so that
+// "Tolkien, J.R.R.", "J. R. R. Tolkien" and "JRR Tolkien" all become
+// "j r r tolkien".
+func NameKey(name string) string {
+	return strings.Join(nameTokens(name), " ")
+}
+
+// Surname returns the key of the surname in a name, or "" when the name has
+// none.
+func Surname(name string) string {
+	_, surname := splitName(nameTokens(name))
+	return surname
+}
+
+// nameTokens lowercases the words of a name in display order, splitting
+// initials such as "J.R.R." or "JRR" into one token each.
+func nameTokens(name string) []string {
+	fields := strings.FieldsFunc(invertName(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for i, field := range fields {
+		if i < len(fields)-1 && isInitials(field) {
+			for _, r := range field {
+				tokens = append(tokens, string(unicode.ToLower(r)))
+			}
+			continue
+		}
+		tokens = append(tokens, strings.ToLower(field))
+	}
+	return tokens
+}
+
+// invertName turns "Tolkien, J.R.R." into "J.R.R. Tolkien", keeping a
+// trailing suffix as in "King, Martin Luther, Jr." at the end.
+func invertName(name string) string {
+	parts := strings.Split(name, ",")
+
+	suffix := ""
+	if last := strings.TrimSpace(parts[len(parts)-1]); len(parts) > 1 && isSuffix(last) {
+		suffix = last
+		parts = parts[:len(parts)-1]
+	}
+
+	if len(parts) == 2 && strings.TrimSpace(parts[0]) != "" && strings.TrimSpace(parts[1]) != "" {
+		name = strings.TrimSpace(parts[1]) + " " + strings.TrimSpace(parts[0])
+	} else {
+		name = strings.Join(parts, ",")
+	}
+
+	if suffix != "" {
+		name += " " + suffix
+	}
+	return name
+}
+
+// isInitials reports whether a word is a run of two or three capitals, as
+// in "JRR".
+func isInitials(word string) bool {
+	count := utf8.RuneCountInString(word)
+	if count < 2 || count > 3 {
+		return false
+	}
+	for _, r := range word {
+		if !unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isSuffix(word string) bool {
+	return nameSuffixes[strings.ToLower(strings.Trim(word, ". "))]
+}
+
+// splitName separates the given names from the surname, leaving out
+// suffixes.
+func splitName(tokens []string) ([]string, string) {
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if !nameSuffixes[token] {
+			words = append(words, token)
+		}
+	}
+	if len(words) == 0 {
+		return nil, ""
+	}
+	return words[:len(words)-1], words[len(words)-1]
+}
+
+// compatibleNames reports whether two names may belong to the same person:
+// they share a surname and their given names agree for as far as both go,
+// where an initial agrees with any name it begins.
+func compatibleNames(a, b []string) bool {
+	givenA, surnameA := splitName(a)
+	givenB, surnameB := splitName(b)
+	if surnameA == "" || surnameA != surnameB || len(givenA) == 0 || len(givenB) == 0 {
+		return false
+	}
+
+	for i := 0; i < len(givenA) && i < len(givenB); i++ {
+		short, long := givenA[i], givenB[i]
+		if utf8.RuneCountInString(short) > utf8.RuneCountInString(long) {
+			short, long = long, short
+		}
+		if short == long {
+			continue
+		}
+		if utf8.RuneCountInString(short) != 1 || !strings.HasPrefix(long, short) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package queries
+
+import (
+	"context"
+	stderrors "errors"
+	"sort"
+	"strings"
+
+	"books/core/authors/models"
+	"books/core/authors/repositories"
+)
+
+type GetAuthorQuery struct {
+	ID string
+}
+
+type ListAuthorsQuery struct{}
+
+// MatchAuthorsQuery finds the authors a name may refer to, exact matches
+// first.
+type MatchAuthorsQuery struct {
+	Name string
+}
+
+type AuthorQueryHandler struct {
+	repo repositories.AuthorRepository
+}
+
+func NewAuthorQueryHandler(repo repositories.AuthorRepository) *AuthorQueryHandler {
+	return &AuthorQueryHandler{
+		repo: repo,
+	}
+}
+
+// Handle answers every author query, so it is registered once per query type.
+func (h *AuthorQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	switch query := q.(type) {
+	case GetAuthorQuery:
+		return h.repo.GetAuthorByID(ctx, query.ID)
+	case ListAuthorsQuery:
+		return h.repo.GetAllAuthors(ctx)
+	case MatchAuthorsQuery:
+		return h.match(ctx, query.Name)
+	default:
+		return nil, stderrors.New("invalid query type")
+	}
+}
+
+func (h *AuthorQueryHandler) match(ctx context.Context, name string) ([]models.Match, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, stderrors.New("author name cannot be empty")
+	}
+
+	matches := make([]models.Match, 0)
+	surname := models.Surname(name)
+	if surname == "" {
+		return matches, nil
+	}
+
+	candidates, err := h.repo.FindAuthorsBySurname(ctx, surname)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		if match, ok := candidate.Match(name); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	// Candidates come ordered by name, which the sort keeps within each kind.
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Exact && !matches[j].Exact
+	})
+	return matches, nil
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"books/core/authors/errors"
+	"books/core/authors/models"
+)
+
+type AuthorInMemoryRepository struct {
+	authors map[string]*models.Author
+	mutex   sync.RWMutex
+}
+
+func NewAuthorInMemoryRepository() *AuthorInMemoryRepository {
+	return &AuthorInMemoryRepository{
+		authors: make(map[string]*models.Author),
+	}
+}
+
+func (r *AuthorInMemoryRepository) GetAuthorByID(ctx context.Context, id string) (*models.Author, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	author, exists := r.authors[id]
+	if !exists {
+		return nil, errors.ErrAuthorNotFound
+	}
+
+	return author.Clone(), nil
+}
+
+func (r *AuthorInMemoryRepository) GetAllAuthors(ctx context.Context) ([]*models.Author, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.Author, 0, len(r.authors))
+	for _, author := range r.authors {
+		result = append(result, author.Clone())
+	}
+
+	sortAuthors(result)
+	return result, nil
+}
+
+func (r *AuthorInMemoryRepository) FindAuthorsBySurname(ctx context.Context, surname string) ([]*models.Author, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.Author, 0)
+	for _, author := range r.authors {
+		for _, s := range author.Surnames() {
+			if s == surname {
+				result = append(result, author.Clone())
+				break
+			}
+		}
+	}
+
+	sortAuthors(result)
+	return result, nil
+}
+
+func (r *AuthorInMemoryRepository) SaveAuthor(ctx context.Context, author *models.Author) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if author.ID == "" {
+		author.ID = newID()
+	}
+
+	r.authors[author.ID] = author.Clone()
+	return nil
+}
+
+func (r *AuthorInMemoryRepository) UpdateAuthor(ctx context.Context, author *models.Author) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.authors[author.ID]; !exists {
+		return errors.ErrAuthorNotFound
+	}
+
+	r.authors[author.ID] = author.Clone()
+	return nil
+}
+
+func (r *AuthorInMemoryRepository) DeleteAuthor(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.authors[id]; !exists {
+		return errors.ErrAuthorNotFound
+	}
+
+	delete(r.authors, id)
+	return nil
+}
+
+// sortAuthors orders authors by name, matching the Postgres ordering.
+func sortAuthors(authors []*models.Author) {
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Name != authors[j].Name {
+			return authors[i].Name < authors[j].Name
+		}
+		return authors[i].ID < authors[j].ID
+	})
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+var _ AuthorRepository = (*AuthorInMemoryRepository)(nil)
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+
+	"books/core/authors/errors"
+	"books/core/authors/models"
+
+	"github.com/lib/pq"
+)
+
+const pqInvalidText = "22P02"
+
+// authorColumns lists the columns read by scanAuthor, in order.
+const authorColumns = `id, name, alternate_names, birth_year, death_year, created_at`
+
+type AuthorPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewAuthorPostgresRepository(db *sql.DB) *AuthorPostgresRepository {
+	return &AuthorPostgresRepository{
+		db: db,
+	}
+}
+
+func (r *AuthorPostgresRepository) GetAuthorByID(ctx context.Context, id string) (*models.Author, error) {
+	query := `SELECT ` + authorColumns + ` FROM authors WHERE id = $1`
+
+	author, err := scanAuthor(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows || isInvalidUUID(err) {
+		return nil, errors.ErrAuthorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find author: %w", err)
+	}
+
+	return author, nil
+}
+
+func (r *AuthorPostgresRepository) GetAllAuthors(ctx context.Context) ([]*models.Author, error) {
+	query := `SELECT ` + authorColumns + ` FROM authors ORDER BY name, id`
+	return r.queryAuthors(ctx, query)
+}
+
+func (r *AuthorPostgresRepository) FindAuthorsBySurname(ctx context.Context, surname string) ([]*models.Author, error) {
+	query := `SELECT ` + authorColumns + ` FROM authors WHERE surnames @> ARRAY[$1]::text[] ORDER BY name, id`
+	return r.queryAuthors(ctx, query, surname)
+}
+
+func (r *AuthorPostgresRepository) SaveAuthor(ctx context.Context, author *models.Author) error {
+	query := `
+		INSERT INTO authors (id, name, alternate_names, surnames, birth_year, death_year, created_at)
+		VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		author.ID,
+		author.Name,
+		pq.Array(author.AlternateNames),
+		pq.Array(author.Surnames()),
+		author.BirthYear,
+		author.DeathYear,
+		author.CreatedAt,
+	).Scan(&author.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save author: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthorPostgresRepository) UpdateAuthor(ctx context.Context, author *models.Author) error {
+	query := `
+		UPDATE authors
+		SET name = $2, alternate_names = $3, surnames = $4, birth_year = $5, death_year = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		author.ID,
+		author.Name,
+		pq.Array(author.AlternateNames),
+		pq.Array(author.Surnames()),
+		author.BirthYear,
+		author.DeathYear,
+	)
+	if isInvalidUUID(err) {
+		return errors.ErrAuthorNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update author: %w", err)
+	}
+
+	return expectOneRow(result)
+}
+
+// DeleteAuthor removes an author. Contributors still linked to it are
+// unlinked by the database.
+func (r *AuthorPostgresRepository) DeleteAuthor(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM authors WHERE id = $1`, id)
+	if isInvalidUUID(err) {
+		return errors.ErrAuthorNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete author: %w", err)
+	}
+
+	return expectOneRow(result)
+}
+
+func (r *AuthorPostgresRepository) queryAuthors(ctx context.Context, query string, args ...interface{}) ([]*models.Author, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query authors: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	authors := make([]*models.Author, 0)
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan author: %w", err)
+		}
+		authors = append(authors, author)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate authors: %w", err)
+	}
+
+	return authors, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAuthor(row rowScanner) (*models.Author, error) {
+	author := &models.Author{}
+	alternateNames := []string{}
+
+	err := row.Scan(
+		&author.ID,
+		&author.Name,
+		pq.Array(&alternateNames),
+		&author.BirthYear,
+		&author.DeathYear,
+		&author.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	author.AlternateNames = alternateNames
+	return author, nil
+}
+
+func expectOneRow(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.ErrAuthorNotFound
+	}
+	return nil
+}
+
+func isInvalidUUID(err error) bool {
+	var pqErr *pq.Error
+	return stderrors.As(err, &pqErr) && pqErr.Code == pqInvalidText
+}
+
+var _ AuthorRepository = (*AuthorPostgresRepository)(nil)
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"books/core/authors/errors"
+	"books/core/authors/models"
+	"books/internal/testdb"
+)
+
+var db *sql.DB
+var repo *AuthorPostgresRepository
+
+func TestMain(m *testing.M) {
+	testdb.Run(m, func(conn *sql.DB) {
+		db = conn
+		repo = NewAuthorPostgresRepository(db)
+	})
+}
+
+func cleanupDB(t *testing.T) {
+	_, err := db.Exec("DELETE FROM authors")
+	if err != nil {
+		t.Fatalf("Failed to cleanup authors: %v", err)
+	}
+}
+
+func TestSaveAuthor(t *testing.T) {
+	cleanupDB(t)
+
+	author, _ := models.NewAuthor("J. R. R. Tolkien", []string{"John Ronald Reuel Tolkien"}, 1892, 1973)
+	if err := repo.SaveAuthor(context.Background(), author); err != nil {
+		t.Fatalf("Failed to save author: %v", err)
+	}
+	if author.ID == "" {
+		t.Fatalf("expected author ID to be assigned")
+	}
+
+	found, err := repo.GetAuthorByID(context.Background(), author.ID)
+	if err != nil {
+		t.Fatalf("Failed to find author: %v", err)
+	}
+	if found.Name != "J. R. R. Tolkien" || len(found.AlternateNames) != 1 || found.BirthYear != 1892 || found.DeathYear != 1973 {
+		t.Errorf("unexpected author %+v", found)
+	}
+}
+
+func TestFindAuthorsBySurname(t *testing.T) {
+	cleanupDB(t)
+
+	for _, name := range []string{"J. R. R. Tolkien", "Christopher Tolkien", "Jane Austen"} {
+		author, _ := models.NewAuthor(name, nil, 0, 0)
+		_ = repo.SaveAuthor(context.Background(), author)
+	}
+	pseudonym, _ := models.NewAuthor("Mary Westmacott", []string{"Agatha Christie"}, 0, 0)
+	_ = repo.SaveAuthor(context.Background(), pseudonym)
+
+	authors, err := repo.FindAuthorsBySurname(context.Background(), "tolkien")
+	if err != nil {
+		t.Fatalf("Failed to find authors: %v", err)
+	}
+	if len(authors) != 2 || authors[0].Name != "Christopher Tolkien" || authors[1].Name != "J. R. R. Tolkien" {
+		t.Errorf("expected both Tolkiens ordered by name, got %+v", authors)
+	}
+
+	authors, _ = repo.FindAuthorsBySurname(context.Background(), "christie")
+	if len(authors) != 1 || authors[0].Name != "Mary Westmacott" {
+		t.Errorf("expected alternate names to be searched, got %+v", authors)
+	}
+}
+
+func TestUpdateAndDeleteAuthor(t *testing.T) {
+	cleanupDB(t)
+
+	author, _ := models.NewAuthor("Mary Westmacott", nil, 1890, 0)
+	_ = repo.SaveAuthor(context.Background(), author)
+
+	author.AlternateNames = []string{"Agatha Christie"}
+	if err := repo.UpdateAuthor(context.Background(), author); err != nil {
+		t.Fatalf("Failed to update author: %v", err)
+	}
+	if authors, _ := repo.FindAuthorsBySurname(context.Background(), "christie"); len(authors) != 1 {
+		t.Errorf("expected the surnames to follow the update, got %+v", authors)
+	}
+
+	if err := repo.DeleteAuthor(context.Background(), author.ID); err != nil {
+		t.Fatalf("Failed to delete author: %v", err)
+	}
+	if err := repo.DeleteAuthor(context.Background(), author.ID); err != errors.ErrAuthorNotFound {
+		t.Errorf("expected ErrAuthorNotFound, got %v", err)
+	}
+	if err := repo.UpdateAuthor(context.Background(), author); err != errors.ErrAuthorNotFound {
+		t.Errorf("expected ErrAuthorNotFound, got %v", err)
+	}
+}
+
+func TestGetAuthorByIDNotFound(t *testing.T) {
+	cleanupDB(t)
+
+	for _, id := range []string{"00000000-0000-0000-0000-000000000000", "not-a-uuid"} {
+		if _, err := repo.GetAuthorByID(context.Background(), id); err != errors.ErrAuthorNotFound {
+			t.Errorf("expected ErrAuthorNotFound for %q, got %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"books/core/authors/models"
+)
+
+type AuthorRepository interface {
+	GetAuthorByID(ctx context.Context, id string) (*models.Author, error)
+	// GetAllAuthors returns the authors ordered by name.
+	GetAllAuthors(ctx context.Context) ([]*models.Author, error)
+	// FindAuthorsBySurname returns the authors with a form of their name
+	// ending in the surname, given as a key from models.Surname.
+	FindAuthorsBySurname(ctx context.Context, surname string) ([]*models.Author, error)
+	SaveAuthor(ctx context.Context, author *models.Author) error
+	UpdateAuthor(ctx context.Context, author *models.Author) error
+	DeleteAuthor(ctx context.Context, id string) error
+}
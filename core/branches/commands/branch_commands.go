@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+
+	"books/core/branches/models"
+	"books/core/branches/repositories"
+)
+
+type AddBranchCommand struct {
+	ID      string
+	Code    string
+	Name    string
+	Address string
+}
+
+type AddBranchCommandHandler struct {
+	repo repositories.BranchRepository
+}
+
+func NewAddBranchCommandHandler(repo repositories.BranchRepository) *AddBranchCommandHandler {
+	return &AddBranchCommandHandler{repo: repo}
+}
+
+func (h *AddBranchCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(AddBranchCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	branch, err := models.NewBranch(command.Code, command.Name, command.Address)
+	if err != nil {
+		return err
+	}
+	branch.ID = command.ID
+
+	return h.repo.SaveBranch(ctx, branch)
+}
+
+// UpdateBranchCommand changes a branch's details. Empty fields are left as
+// they are.
+type UpdateBranchCommand struct {
+	ID      string
+	Code    string
+	Name    string
+	Address string
+}
+
+type UpdateBranchCommandHandler struct {
+	repo repositories.BranchRepository
+}
+
+func NewUpdateBranchCommandHandler(repo repositories.BranchRepository) *UpdateBranchCommandHandler {
+	return &UpdateBranchCommandHandler{repo: repo}
+}
+
+func (h *UpdateBranchCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(UpdateBranchCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	if command.Code == "" && command.Name == "" && command.Address == "" {
+		return stderrors.New("at least one field must be provided for update")
+	}
+
+	branch, err := h.repo.GetBranchByID(ctx, command.ID)
+	if err != nil {
+		return err
+	}
+
+	if command.Code != "" {
+		branch.Code = strings.ToLower(strings.TrimSpace(command.Code))
+	}
+	if command.Name != "" {
+		branch.Name = strings.TrimSpace(command.Name)
+	}
+	if command.Address != "" {
+		branch.Address = strings.TrimSpace(command.Address)
+	}
+
+	if err := branch.Validate(); err != nil {
+		return err
+	}
+
+	return h.repo.UpdateBranch(ctx, branch)
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"books/core/branches/errors"
+	"books/core/branches/repositories"
+	"context"
+	stderrors "errors"
+	"testing"
+)
+
+var errInvalidCode = stderrors.New("invalid branch code: use up to 20 lowercase letters, digits and dashes")
+
+type branchCommandTestCase struct {
+	name      string
+	command   interface{}
+	expectErr error
+}
+
+func newTestRepository(t *testing.T) *repositories.BranchInMemoryRepository {
+	t.Helper()
+	repo := repositories.NewBranchInMemoryRepository()
+
+	err := NewAddBranchCommandHandler(repo).Handle(context.Background(), AddBranchCommand{ID: "main", Code: "main", Name: "Main Library"})
+	if err != nil {
+		t.Fatalf("failed to add branch: %v", err)
+	}
+	return repo
+}
+
+func checkError(t *testing.T, err, expected error) {
+	t.Helper()
+	if expected == nil {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	if err == nil || (!stderrors.Is(err, expected) && err.Error() != expected.Error()) {
+		t.Fatalf("expected error %v, got %v", expected, err)
+	}
+}
+
+func TestAddBranchCommandHandler(t *testing.T) {
+	tests := []branchCommandTestCase{
+		{
+			name:    "Add a branch",
+			command: AddBranchCommand{Code: "North", Name: "North Branch", Address: "1 North St"},
+		},
+		{
+			name:      "Duplicate code",
+			command:   AddBranchCommand{Code: "MAIN", Name: "Another Main"},
+			expectErr: errors.ErrDuplicateBranchCode,
+		},
+		{
+			name:      "Invalid code",
+			command:   AddBranchCommand{Code: "north branch", Name: "North Branch"},
+			expectErr: errInvalidCode,
+		},
+		{
+			name:      "Missing name",
+			command:   AddBranchCommand{Code: "north"},
+			expectErr: stderrors.New("branch name cannot be empty"),
+		},
+		{
+			name:      "Invalid command type",
+			command:   "not a command",
+			expectErr: stderrors.New("invalid command type"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			err := NewAddBranchCommandHandler(repo).Handle(context.Background(), tc.command)
+			checkError(t, err, tc.expectErr)
+			if tc.expectErr != nil {
+				return
+			}
+
+			branches, _ := repo.GetAllBranches(context.Background())
+			if len(branches) != 2 {
+				t.Fatalf("expected 2 branches, got %d", len(branches))
+			}
+			if branches[1].Code != "north" {
+				t.Errorf("expected code to be lowercased, got %q", branches[1].Code)
+			}
+		})
+	}
+}
+
+func TestUpdateBranchCommandHandler(t *testing.T) {
+	tests := []branchCommandTestCase{
+		{
+			name:    "Rename a branch",
+			command: UpdateBranchCommand{ID: "main", Name: "Central Library"},
+		},
+		{
+			name:      "Unknown branch",
+			command:   UpdateBranchCommand{ID: "missing", Name: "Central Library"},
+			expectErr: errors.ErrBranchNotFound,
+		},
+		{
+			name:      "Empty update",
+			command:   UpdateBranchCommand{ID: "main"},
+			expectErr: stderrors.New("at least one field must be provided for update"),
+		},
+		{
+			name:      "Invalid code",
+			command:   UpdateBranchCommand{ID: "main", Code: "-main"},
+			expectErr: errInvalidCode,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			err := NewUpdateBranchCommandHandler(repo).Handle(context.Background(), tc.command)
+			checkError(t, err, tc.expectErr)
+			if tc.expectErr != nil {
+				return
+			}
+
+			branch, _ := repo.GetBranchByID(context.Background(), "main")
+			if branch.Name != "Central Library" || branch.Code != "main" {
+				t.Errorf("unexpected branch after update: %+v", branch)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package errors
+
+import (
+	"errors"
+)
+
+var (
+	ErrBranchNotFound      = errors.New("branch not found")
+	ErrDuplicateBranchCode = errors.New("a branch with this code already exists")
+)
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// codePattern is the form of a branch code: a short lowercase slug such as
+// "central" or "north-side".
+var codePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,19}$`)
+
+// Branch is a library location. Copies have a home branch they belong to and
+// a current branch they are at; holds name the branch they are picked up at.
+type Branch struct {
+	ID        string    `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewBranch(code, name, address string) (*Branch, error) {
+	branch := &Branch{
+		Code:      strings.ToLower(strings.TrimSpace(code)),
+		Name:      strings.TrimSpace(name),
+		Address:   strings.TrimSpace(address),
+		CreatedAt: time.Now(),
+	}
+
+	if err := branch.Validate(); err != nil {
+		return nil, err
+	}
+	return branch, nil
+}
+
+func (b *Branch) Validate() error {
+	if !codePattern.MatchString(b.Code) {
+		return errors.New("invalid branch code: use up to 20 lowercase letters, digits and dashes")
+	}
+	if b.Name == "" {
+		return errors.New("branch name cannot be empty")
+	}
+	return nil
+}
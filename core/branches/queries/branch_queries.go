@@ -0,0 +1,36 @@
+package queries
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/branches/repositories"
+)
+
+type GetBranchQuery struct {
+	ID string
+}
+
+type ListBranchesQuery struct{}
+
+type BranchQueryHandler struct {
+	repo repositories.BranchRepository
+}
+
+func NewBranchQueryHandler(repo repositories.BranchRepository) *BranchQueryHandler {
+	return &BranchQueryHandler{
+		repo: repo,
+	}
+}
+
+// Handle answers every branch query, so it is registered once per query type.
+func (h *BranchQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	switch query := q.(type) {
+	case GetBranchQuery:
+		return h.repo.GetBranchByID(ctx, query.ID)
+	case ListBranchesQuery:
+		return h.repo.GetAllBranches(ctx)
+	default:
+		return nil, stderrors.New("invalid query type")
+	}
+}
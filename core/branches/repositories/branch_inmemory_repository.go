@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"books/core/branches/errors"
+	"books/core/branches/models"
+)
+
+type BranchInMemoryRepository struct {
+	branches map[string]*models.Branch
+	mutex    sync.RWMutex
+}
+
+func NewBranchInMemoryRepository() *BranchInMemoryRepository {
+	return &BranchInMemoryRepository{
+		branches: make(map[string]*models.Branch),
+	}
+}
+
+func (r *BranchInMemoryRepository) GetBranchByID(ctx context.Context, id string) (*models.Branch, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	branch, exists := r.branches[id]
+	if !exists {
+		return nil, errors.ErrBranchNotFound
+	}
+
+	c := *branch
+	return &c, nil
+}
+
+func (r *BranchInMemoryRepository) GetAllBranches(ctx context.Context) ([]*models.Branch, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.Branch, 0, len(r.branches))
+	for _, branch := range r.branches {
+		c := *branch
+		result = append(result, &c)
+	}
+
+	// Alphabetical by name, matching the Postgres ordering.
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Code < result[j].Code
+	})
+	return result, nil
+}
+
+func (r *BranchInMemoryRepository) SaveBranch(ctx context.Context, branch *models.Branch) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.codeTaken(branch.Code, "") {
+		return errors.ErrDuplicateBranchCode
+	}
+
+	if branch.ID == "" {
+		branch.ID = newID()
+	}
+
+	c := *branch
+	r.branches[branch.ID] = &c
+	return nil
+}
+
+func (r *BranchInMemoryRepository) UpdateBranch(ctx context.Context, branch *models.Branch) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.branches[branch.ID]; !exists {
+		return errors.ErrBranchNotFound
+	}
+	if r.codeTaken(branch.Code, branch.ID) {
+		return errors.ErrDuplicateBranchCode
+	}
+
+	c := *branch
+	r.branches[branch.ID] = &c
+	return nil
+}
+
+func (r *BranchInMemoryRepository) codeTaken(code, exceptID string) bool {
+	for id, existing := range r.branches {
+		if id != exceptID && existing.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+var _ BranchRepository = (*BranchInMemoryRepository)(nil)
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+
+	"books/core/branches/errors"
+	"books/core/branches/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	pqUniqueViolation = "23505"
+	pqInvalidText     = "22P02"
+)
+
+type BranchPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewBranchPostgresRepository(db *sql.DB) *BranchPostgresRepository {
+	return &BranchPostgresRepository{
+		db: db,
+	}
+}
+
+func (r *BranchPostgresRepository) GetBranchByID(ctx context.Context, id string) (*models.Branch, error) {
+	query := `SELECT id, code, name, address, created_at FROM branches WHERE id = $1`
+
+	branch, err := scanBranch(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows || isInvalidUUID(err) {
+		return nil, errors.ErrBranchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find branch: %w", err)
+	}
+
+	return branch, nil
+}
+
+func (r *BranchPostgresRepository) GetAllBranches(ctx context.Context) ([]*models.Branch, error) {
+	query := `SELECT id, code, name, address, created_at FROM branches ORDER BY name, code`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query branches: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	branches := make([]*models.Branch, 0)
+	for rows.Next() {
+		branch, err := scanBranch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan branch: %w", err)
+		}
+		branches = append(branches, branch)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate branches: %w", err)
+	}
+
+	return branches, nil
+}
+
+func (r *BranchPostgresRepository) SaveBranch(ctx context.Context, branch *models.Branch) error {
+	query := `
+		INSERT INTO branches (id, code, name, address, created_at)
+		VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		branch.ID,
+		branch.Code,
+		branch.Name,
+		branch.Address,
+		branch.CreatedAt,
+	).Scan(&branch.ID)
+	if err != nil {
+		return mapBranchWriteError(err)
+	}
+
+	return nil
+}
+
+func (r *BranchPostgresRepository) UpdateBranch(ctx context.Context, branch *models.Branch) error {
+	query := `UPDATE branches SET code = $2, name = $3, address = $4 WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, branch.ID, branch.Code, branch.Name, branch.Address)
+	if isInvalidUUID(err) {
+		return errors.ErrBranchNotFound
+	}
+	if err != nil {
+		return mapBranchWriteError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.ErrBranchNotFound
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBranch(row rowScanner) (*models.Branch, error) {
+	branch := &models.Branch{}
+
+	err := row.Scan(&branch.ID, &branch.Code, &branch.Name, &branch.Address, &branch.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+func isInvalidUUID(err error) bool {
+	var pqErr *pq.Error
+	return stderrors.As(err, &pqErr) && pqErr.Code == pqInvalidText
+}
+
+func mapBranchWriteError(err error) error {
+	var pqErr *pq.Error
+	if stderrors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+		return errors.ErrDuplicateBranchCode
+	}
+	return fmt.Errorf("failed to save branch: %w", err)
+}
+
+var _ BranchRepository = (*BranchPostgresRepository)(nil)
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"books/core/branches/errors"
+	"books/core/branches/models"
+	"books/internal/testdb"
+)
+
+var db *sql.DB
+var repo *BranchPostgresRepository
+
+func TestMain(m *testing.M) {
+	testdb.Run(m, func(conn *sql.DB) {
+		db = conn
+		repo = NewBranchPostgresRepository(db)
+	})
+}
+
+func cleanupDB(t *testing.T) {
+	_, err := db.Exec("DELETE FROM branches")
+	if err != nil {
+		t.Fatalf("Failed to cleanup branches: %v", err)
+	}
+}
+
+func TestSaveBranch(t *testing.T) {
+	cleanupDB(t)
+
+	branch, _ := models.NewBranch("main", "Main Library", "1 High St")
+	if err := repo.SaveBranch(context.Background(), branch); err != nil {
+		t.Fatalf("Failed to save branch: %v", err)
+	}
+	if branch.ID == "" {
+		t.Fatalf("expected branch ID to be assigned")
+	}
+
+	found, err := repo.GetBranchByID(context.Background(), branch.ID)
+	if err != nil {
+		t.Fatalf("Failed to find branch: %v", err)
+	}
+	if found.Code != "main" || found.Name != "Main Library" || found.Address != "1 High St" {
+		t.Errorf("unexpected branch %+v", found)
+	}
+
+	duplicate, _ := models.NewBranch("main", "Another Main", "")
+	if err := repo.SaveBranch(context.Background(), duplicate); err != errors.ErrDuplicateBranchCode {
+		t.Errorf("expected ErrDuplicateBranchCode, got %v", err)
+	}
+}
+
+func TestGetAllBranchesOrderedByName(t *testing.T) {
+	cleanupDB(t)
+
+	for _, code := range []string{"west", "east", "north"} {
+		branch, _ := models.NewBranch(code, code+" branch", "")
+		_ = repo.SaveBranch(context.Background(), branch)
+	}
+
+	branches, err := repo.GetAllBranches(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list branches: %v", err)
+	}
+	if len(branches) != 3 {
+		t.Fatalf("expected 3 branches, got %d", len(branches))
+	}
+	for i, code := range []string{"east", "north", "west"} {
+		if branches[i].Code != code {
+			t.Errorf("expected %s at position %d, got %s", code, i, branches[i].Code)
+		}
+	}
+}
+
+func TestUpdateBranch(t *testing.T) {
+	cleanupDB(t)
+
+	main, _ := models.NewBranch("main", "Main Library", "")
+	_ = repo.SaveBranch(context.Background(), main)
+	north, _ := models.NewBranch("north", "North Branch", "")
+	_ = repo.SaveBranch(context.Background(), north)
+
+	main.Name = "Central Library"
+	if err := repo.UpdateBranch(context.Background(), main); err != nil {
+		t.Fatalf("Failed to update branch: %v", err)
+	}
+
+	found, _ := repo.GetBranchByID(context.Background(), main.ID)
+	if found.Name != "Central Library" {
+		t.Errorf("expected branch to be renamed, got %+v", found)
+	}
+
+	north.Code = "main"
+	if err := repo.UpdateBranch(context.Background(), north); err != errors.ErrDuplicateBranchCode {
+		t.Errorf("expected ErrDuplicateBranchCode, got %v", err)
+	}
+}
+
+func TestGetBranchByIDNotFound(t *testing.T) {
+	cleanupDB(t)
+
+	for _, id := range []string{"00000000-0000-0000-0000-000000000000", "not-a-uuid"} {
+		if _, err := repo.GetBranchByID(context.Background(), id); err != errors.ErrBranchNotFound {
+			t.Errorf("expected ErrBranchNotFound for %q, got %v", id, err)
+		}
+	}
+}
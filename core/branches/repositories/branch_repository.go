@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+
+	"books/core/branches/models"
+)
+
+type BranchRepository interface {
+	GetBranchByID(ctx context.Context, id string) (*models.Branch, error)
+	// GetAllBranches returns the branches ordered by name.
+	GetAllBranches(ctx context.Context) ([]*models.Branch, error)
+	SaveBranch(ctx context.Context, branch *models.Branch) error
+	UpdateBranch(ctx context.Context, branch *models.Branch) error
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+
+	"books/core/calendar/ics"
+	"books/core/calendar/models"
+	"books/core/calendar/repositories"
+)
+
+// SetOpeningHoursCommand replaces the weekly opening hours. An empty week
+// opens the library every day again.
+type SetOpeningHoursCommand struct {
+	Hours []models.OpeningHours
+}
+
+type AddClosedDatesCommand struct {
+	Dates []models.ClosedDate
+}
+
+// ImportClosedDatesCommand adds the days covered by the events of an
+// iCalendar file as closed dates.
+type ImportClosedDatesCommand struct {
+	ICalendar []byte
+}
+
+type RemoveClosedDateCommand struct {
+	Date string
+}
+
+type SetOpeningHoursCommandHandler struct {
+	repo repositories.CalendarRepository
+}
+
+func NewSetOpeningHoursCommandHandler(repo repositories.CalendarRepository) *SetOpeningHoursCommandHandler {
+	return &SetOpeningHoursCommandHandler{
+		repo: repo,
+	}
+}
+
+func (h *SetOpeningHoursCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(SetOpeningHoursCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	if err := models.ValidateHours(command.Hours); err != nil {
+		return err
+	}
+
+	return h.repo.SetOpeningHours(ctx, command.Hours)
+}
+
+type AddClosedDatesCommandHandler struct {
+	repo repositories.CalendarRepository
+}
+
+func NewAddClosedDatesCommandHandler(repo repositories.CalendarRepository) *AddClosedDatesCommandHandler {
+	return &AddClosedDatesCommandHandler{
+		repo: repo,
+	}
+}
+
+func (h *AddClosedDatesCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(AddClosedDatesCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	return saveClosedDates(ctx, h.repo, command.Dates)
+}
+
+type ImportClosedDatesCommandHandler struct {
+	repo repositories.CalendarRepository
+}
+
+func NewImportClosedDatesCommandHandler(repo repositories.CalendarRepository) *ImportClosedDatesCommandHandler {
+	return &ImportClosedDatesCommandHandler{
+		repo: repo,
+	}
+}
+
+func (h *ImportClosedDatesCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(ImportClosedDatesCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	dates, err := ics.ParseClosedDates(bytes.NewReader(command.ICalendar))
+	if err != nil {
+		return err
+	}
+
+	return saveClosedDates(ctx, h.repo, dates)
+}
+
+type RemoveClosedDateCommandHandler struct {
+	repo repositories.CalendarRepository
+}
+
+func NewRemoveClosedDateCommandHandler(repo repositories.CalendarRepository) *RemoveClosedDateCommandHandler {
+	return &RemoveClosedDateCommandHandler{
+		repo: repo,
+	}
+}
+
+func (h *RemoveClosedDateCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(RemoveClosedDateCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	return h.repo.DeleteClosedDate(ctx, command.Date)
+}
+
+// saveClosedDates validates every date before saving any of them.
+func saveClosedDates(ctx context.Context, repo repositories.CalendarRepository, dates []models.ClosedDate) error {
+	for _, closed := range dates {
+		if err := closed.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(dates) == 0 {
+		return nil
+	}
+	return repo.SaveClosedDates(ctx, dates)
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"books/core/calendar/errors"
+	"books/core/calendar/models"
+	"books/core/calendar/repositories"
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+)
+
+// weekdays opens the library Monday to Saturday.
+func weekdays() []models.OpeningHours {
+	hours := make([]models.OpeningHours, 0, 6)
+	for day := time.Monday; day <= time.Saturday; day++ {
+		hours = append(hours, models.OpeningHours{Weekday: day, Opens: "09:00", Closes: "18:00"})
+	}
+	return hours
+}
+
+func TestSetOpeningHoursCommandHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name    string
+		hours   []models.OpeningHours
+		wantErr bool
+	}{
+		{name: "Monday to Saturday", hours: weekdays()},
+		{name: "Always open", hours: nil},
+		{name: "Closes before it opens", hours: []models.OpeningHours{{Weekday: time.Monday, Opens: "18:00", Closes: "09:00"}}, wantErr: true},
+		{name: "Bad time", hours: []models.OpeningHours{{Weekday: time.Monday, Opens: "9am", Closes: "18:00"}}, wantErr: true},
+		{name: "Weekday twice", hours: append(weekdays(), models.OpeningHours{Weekday: time.Monday, Opens: "10:00", Closes: "12:00"}), wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := repositories.NewCalendarInMemoryRepository()
+			handler := NewSetOpeningHoursCommandHandler(repo)
+
+			err := handler.Handle(context.Background(), SetOpeningHoursCommand{Hours: tc.hours})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+
+			calendar, _ := repo.GetCalendar(context.Background())
+			if !tc.wantErr && len(calendar.Hours) != len(tc.hours) {
+				t.Errorf("expected %d days of hours, got %d", len(tc.hours), len(calendar.Hours))
+			}
+		})
+	}
+
+	if err := NewSetOpeningHoursCommandHandler(repositories.NewCalendarInMemoryRepository()).Handle(context.Background(), "not a command"); err == nil {
+		t.Errorf("expected invalid command type to be refused")
+	}
+}
+
+func TestClosedDateCommands(t *testing.T) {
+	repo := repositories.NewCalendarInMemoryRepository()
+	ctx := context.Background()
+
+	add := NewAddClosedDatesCommandHandler(repo)
+	if err := add.Handle(ctx, AddClosedDatesCommand{Dates: []models.ClosedDate{{Date: "2026-12-25", Reason: "Christmas"}, {Date: "25/12/2026"}}}); err == nil {
+		t.Fatalf("expected a malformed date to be refused")
+	}
+	if calendar, _ := repo.GetCalendar(ctx); len(calendar.Closed) != 0 {
+		t.Fatalf("expected nothing to be saved when one date is invalid, got %+v", calendar.Closed)
+	}
+
+	if err := add.Handle(ctx, AddClosedDatesCommand{Dates: []models.ClosedDate{{Date: "2026-12-25", Reason: "Christmas"}}}); err != nil {
+		t.Fatalf("failed to add closed date: %v", err)
+	}
+
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20261231\r\nDTEND;VALUE=DATE:20270102\r\nSUMMARY:New Year\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	if err := NewImportClosedDatesCommandHandler(repo).Handle(ctx, ImportClosedDatesCommand{ICalendar: []byte(ics)}); err != nil {
+		t.Fatalf("failed to import closed dates: %v", err)
+	}
+
+	calendar, _ := repo.GetCalendar(ctx)
+	if len(calendar.Closed) != 3 || calendar.Closed[2] != (models.ClosedDate{Date: "2027-01-01", Reason: "New Year"}) {
+		t.Fatalf("unexpected closed dates %+v", calendar.Closed)
+	}
+
+	remove := NewRemoveClosedDateCommandHandler(repo)
+	if err := remove.Handle(ctx, RemoveClosedDateCommand{Date: "2026-12-25"}); err != nil {
+		t.Fatalf("failed to remove closed date: %v", err)
+	}
+	if err := remove.Handle(ctx, RemoveClosedDateCommand{Date: "2026-12-25"}); !stderrors.Is(err, errors.ErrClosedDateNotFound) {
+		t.Errorf("expected ErrClosedDateNotFound, got %v", err)
+	}
+}
+
+func TestCalendarDueDate(t *testing.T) {
+	calendar := models.Calendar{
+		Hours:  weekdays(),
+		Closed: []models.ClosedDate{{Date: "2026-12-28"}},
+	}
+
+	// Thursday 10 December 2026, 11:00.
+	borrowed := time.Date(2026, time.December, 10, 11, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name   string
+		period time.Duration
+		want   time.Time
+	}{
+		{name: "Due on an open day", period: 14 * 24 * time.Hour, want: time.Date(2026, time.December, 24, 11, 0, 0, 0, time.Local)},
+		{name: "Due on a Sunday", period: 3 * 24 * time.Hour, want: time.Date(2026, time.December, 14, 18, 0, 0, 0, time.Local)},
+		{name: "Due on a Sunday before a closed Monday", period: 17 * 24 * time.Hour, want: time.Date(2026, time.December, 29, 18, 0, 0, 0, time.Local)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := calendar.DueDate(borrowed, tc.period); !got.Equal(tc.want) {
+				t.Errorf("expected due date %v, got %v", tc.want, got)
+			}
+		})
+	}
+
+	if got := (models.Calendar{}).DueDate(borrowed, 3*24*time.Hour); !got.Equal(borrowed.Add(3 * 24 * time.Hour)) {
+		t.Errorf("expected an unconfigured calendar to leave the deadline alone, got %v", got)
+	}
+
+	// Friday 11th to Tuesday 29th is 19 days, less three Sundays and the 28th.
+	if days := calendar.OpenDaysBetween(borrowed, time.Date(2026, time.December, 29, 18, 0, 0, 0, time.Local)); days != 15 {
+		t.Errorf("expected 15 open days, got %d", days)
+	}
+	if days := (models.Calendar{}).OpenDaysBetween(borrowed, borrowed.AddDate(0, 0, 5)); days != 5 {
+		t.Errorf("expected every day to be open, got %d", days)
+	}
+}
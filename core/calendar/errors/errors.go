@@ -0,0 +1,10 @@
+package errors
+
+import (
+	"errors"
+)
+
+var (
+	ErrClosedDateNotFound = errors.New("closed date not found")
+	ErrInvalidICalendar   = errors.New("invalid iCalendar data")
+)
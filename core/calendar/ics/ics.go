@@ -0,0 +1,175 @@
+// Package ics reads closed dates from iCalendar (RFC 5545) files, such as
+// the public holiday calendars published by governments and the exports of
+// common calendar applications.
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"books/core/calendar/errors"
+	"books/core/calendar/models"
+)
+
+// maxEventDays bounds how many closed dates a single event can produce.
+const maxEventDays = 366
+
+type event struct {
+	start   string
+	end     string
+	summary string
+}
+
+// ParseClosedDates returns a closed date for every day covered by the
+// VEVENTs in r. All-day events end on the day before DTEND, as RFC 5545
+// specifies; timed events close every day they touch. Recurrence rules are
+// not expanded, so only the first occurrence of a recurring event is read.
+func ParseClosedDates(r io.Reader) ([]models.ClosedDate, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := make([]models.ClosedDate, 0)
+	seen := make(map[string]bool)
+	var current *event
+
+	for _, line := range lines {
+		name, value := splitProperty(line)
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current = &event{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if current == nil {
+				return nil, fmt.Errorf("%w: END:VEVENT without BEGIN", errors.ErrInvalidICalendar)
+			}
+			days, err := current.days()
+			if err != nil {
+				return nil, err
+			}
+			for _, day := range days {
+				closed := models.NewClosedDate(day, current.summary)
+				if !seen[closed.Date] {
+					seen[closed.Date] = true
+					dates = append(dates, closed)
+				}
+			}
+			current = nil
+		case current == nil:
+			continue
+		case name == "DTSTART":
+			current.start = strings.TrimSpace(value)
+		case name == "DTEND":
+			current.end = strings.TrimSpace(value)
+		case name == "SUMMARY":
+			current.summary = unescape(value)
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("%w: unterminated VEVENT", errors.ErrInvalidICalendar)
+	}
+	return dates, nil
+}
+
+// days lists the dates the event covers.
+func (e *event) days() ([]time.Time, error) {
+	if e.start == "" {
+		return nil, fmt.Errorf("%w: VEVENT without DTSTART", errors.ErrInvalidICalendar)
+	}
+
+	start, allDay, err := parseDate(e.start)
+	if err != nil {
+		return nil, err
+	}
+	if e.end == "" {
+		return []time.Time{start}, nil
+	}
+
+	end, _, err := parseDate(e.end)
+	if err != nil {
+		return nil, err
+	}
+
+	// DTEND is exclusive for all-day events, and a timed event ending at
+	// midnight does not touch the following day.
+	last := end
+	if allDay || isMidnight(e.end) {
+		last = end.AddDate(0, 0, -1)
+	}
+	if last.Before(start) {
+		return []time.Time{start}, nil
+	}
+
+	days := make([]time.Time, 0)
+	for day := start; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if len(days) == maxEventDays {
+			return nil, fmt.Errorf("%w: event %q spans more than %d days", errors.ErrInvalidICalendar, e.summary, maxEventDays)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// parseDate reads a DATE or DATE-TIME value and returns its calendar day,
+// and whether it was a plain date.
+func parseDate(value string) (time.Time, bool, error) {
+	if len(value) < 8 {
+		return time.Time{}, false, fmt.Errorf("%w: bad date %q", errors.ErrInvalidICalendar, value)
+	}
+
+	day, err := time.ParseInLocation("20060102", value[:8], time.Local)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: bad date %q", errors.ErrInvalidICalendar, value)
+	}
+	return day, len(value) == 8, nil
+}
+
+func isMidnight(value string) bool {
+	return len(value) >= 15 && value[8:15] == "T000000"
+}
+
+// unfold joins continuation lines, which start with a space or a tab.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	lines := make([]string, 0)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidICalendar, err)
+	}
+	return lines, nil
+}
+
+// splitProperty splits "NAME;PARAM=x:value" into its name and value. The
+// VALUE and TZID parameters are dropped; dates are read as local calendar
+// days either way.
+func splitProperty(line string) (string, string) {
+	head, value, found := strings.Cut(line, ":")
+	if !found {
+		return strings.ToUpper(line), ""
+	}
+
+	name, _, _ := strings.Cut(head, ";")
+	return strings.ToUpper(name), value
+}
+
+func unescape(value string) string {
+	replacer := strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return strings.TrimSpace(replacer.Replace(value))
+}
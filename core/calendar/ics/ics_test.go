@@ -0,0 +1,84 @@
+package ics
+
+import (
+	"books/core/calendar/errors"
+	"books/core/calendar/models"
+	stderrors "errors"
+	"strings"
+	"testing"
+)
+
+const holidays = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Example//Holidays//EN
+BEGIN:VEVENT
+UID:1@example.com
+DTSTART;VALUE=DATE:20261225
+DTEND;VALUE=DATE:20261227
+SUMMARY:Christmas\, Boxing Day
+END:VEVENT
+BEGIN:VEVENT
+UID:2@example.com
+DTSTART;VALUE=DATE:20260101
+SUMMARY:New Year's
+  Day
+END:VEVENT
+BEGIN:VEVENT
+UID:3@example.com
+DTSTART;TZID=Europe/London:20260410T090000
+DTEND;TZID=Europe/London:20260411T000000
+SUMMARY:Stocktaking
+BEGIN:VALARM
+TRIGGER:-PT15M
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:4@example.com
+DTSTART;VALUE=DATE:20261226
+DTEND;VALUE=DATE:20261227
+SUMMARY:Boxing Day
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestParseClosedDates(t *testing.T) {
+	dates, err := ParseClosedDates(strings.NewReader(strings.ReplaceAll(holidays, "\n", "\r\n")))
+	if err != nil {
+		t.Fatalf("failed to parse calendar: %v", err)
+	}
+
+	want := []models.ClosedDate{
+		{Date: "2026-12-25", Reason: "Christmas, Boxing Day"},
+		{Date: "2026-12-26", Reason: "Christmas, Boxing Day"},
+		{Date: "2026-01-01", Reason: "New Year's Day"},
+		{Date: "2026-04-10", Reason: "Stocktaking"},
+	}
+	if len(dates) != len(want) {
+		t.Fatalf("expected %d closed dates, got %+v", len(want), dates)
+	}
+	for i := range want {
+		if dates[i] != want[i] {
+			t.Errorf("expected %+v, got %+v", want[i], dates[i])
+		}
+	}
+}
+
+func TestParseClosedDatesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "Missing DTSTART", data: "BEGIN:VEVENT\nSUMMARY:Closed\nEND:VEVENT\n"},
+		{name: "Bad date", data: "BEGIN:VEVENT\nDTSTART:2026-12-25\nEND:VEVENT\n"},
+		{name: "Unterminated event", data: "BEGIN:VEVENT\nDTSTART:20261225\n"},
+		{name: "Event too long", data: "BEGIN:VEVENT\nDTSTART:20260101\nDTEND:20280101\nEND:VEVENT\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseClosedDates(strings.NewReader(tc.data)); !stderrors.Is(err, errors.ErrInvalidICalendar) {
+				t.Errorf("expected ErrInvalidICalendar, got %v", err)
+			}
+		})
+	}
+}
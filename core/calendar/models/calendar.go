@@ -0,0 +1,184 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayout is the format of closed dates.
+const DateLayout = "2006-01-02"
+
+// ClockLayout is the format of opening and closing times.
+const ClockLayout = "15:04"
+
+// MaxReasonLength is the longest reason a closed date keeps.
+const MaxReasonLength = 255
+
+// maxSearchDays bounds the search for the next open day, so a calendar that
+// is closed for good cannot loop forever.
+const maxSearchDays = 366
+
+// OpeningHours says when the library opens and closes on a day of the week.
+type OpeningHours struct {
+	Weekday time.Weekday `json:"weekday"`
+	Opens   string       `json:"opens"`
+	Closes  string       `json:"closes"`
+}
+
+func (h OpeningHours) Validate() error {
+	if h.Weekday < time.Sunday || h.Weekday > time.Saturday {
+		return errors.New("invalid weekday")
+	}
+
+	opens, err := time.Parse(ClockLayout, h.Opens)
+	if err != nil {
+		return fmt.Errorf("invalid opening time %q, expected HH:MM", h.Opens)
+	}
+	closes, err := time.Parse(ClockLayout, h.Closes)
+	if err != nil {
+		return fmt.Errorf("invalid closing time %q, expected HH:MM", h.Closes)
+	}
+	if !opens.Before(closes) {
+		return fmt.Errorf("invalid opening hours for %s: the library must open before it closes", h.Weekday)
+	}
+	return nil
+}
+
+// ClosedDate is a day the library is shut although its weekday has opening
+// hours, such as a public holiday or a branch closure.
+type ClosedDate struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason"`
+}
+
+// NewClosedDate closes the given day, cutting the reason to MaxReasonLength.
+func NewClosedDate(day time.Time, reason string) ClosedDate {
+	reason = strings.TrimSpace(reason)
+	if runes := []rune(reason); len(runes) > MaxReasonLength {
+		reason = string(runes[:MaxReasonLength])
+	}
+
+	return ClosedDate{
+		Date:   day.Format(DateLayout),
+		Reason: reason,
+	}
+}
+
+func (d ClosedDate) Validate() error {
+	if _, err := time.Parse(DateLayout, d.Date); err != nil {
+		return fmt.Errorf("invalid closed date %q, expected YYYY-MM-DD", d.Date)
+	}
+	if len([]rune(d.Reason)) > MaxReasonLength {
+		return fmt.Errorf("invalid reason for %s: at most %d characters", d.Date, MaxReasonLength)
+	}
+	return nil
+}
+
+// Calendar is the library's opening calendar. A calendar without opening
+// hours is open every day, so an unconfigured library lends as before;
+// once hours are set, weekdays without hours are closed.
+type Calendar struct {
+	Hours  []OpeningHours `json:"opening_hours"`
+	Closed []ClosedDate   `json:"closed_dates"`
+}
+
+// ValidateHours checks a week of opening hours, allowing each weekday once.
+func ValidateHours(hours []OpeningHours) error {
+	seen := make(map[time.Weekday]bool)
+	for _, h := range hours {
+		if err := h.Validate(); err != nil {
+			return err
+		}
+		if seen[h.Weekday] {
+			return fmt.Errorf("invalid opening hours: %s is listed twice", h.Weekday)
+		}
+		seen[h.Weekday] = true
+	}
+	return nil
+}
+
+// IsOpen reports whether the library opens on the given day.
+func (c Calendar) IsOpen(day time.Time) bool {
+	date := day.Format(DateLayout)
+	for _, closed := range c.Closed {
+		if closed.Date == date {
+			return false
+		}
+	}
+
+	if len(c.Hours) == 0 {
+		return true
+	}
+	_, open := c.hoursOn(day.Weekday())
+	return open
+}
+
+// DueDate returns the deadline of a loan of the given period starting at
+// from. A deadline that falls on a closed day moves to closing time on the
+// next open day.
+func (c Calendar) DueDate(from time.Time, loanPeriod time.Duration) time.Time {
+	deadline := from.Add(loanPeriod)
+	if c.IsOpen(deadline) {
+		return deadline
+	}
+
+	day := deadline
+	for i := 0; i < maxSearchDays; i++ {
+		day = day.AddDate(0, 0, 1)
+		if c.IsOpen(day) {
+			return c.closingTime(day)
+		}
+	}
+	return deadline
+}
+
+// OpenDaysBetween counts the open days after from up to and including to.
+// It is negative when to is before from.
+func (c Calendar) OpenDaysBetween(from, to time.Time) int {
+	if to.Before(from) {
+		return -c.OpenDaysBetween(to, from)
+	}
+
+	days := 0
+	end := startOfDay(to)
+	for day := startOfDay(from).AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if c.IsOpen(day) {
+			days++
+		}
+	}
+	return days
+}
+
+func (c Calendar) hoursOn(weekday time.Weekday) (OpeningHours, bool) {
+	for _, h := range c.Hours {
+		if h.Weekday == weekday {
+			return h, true
+		}
+	}
+	return OpeningHours{}, false
+}
+
+// closingTime is when the library closes on the given day, or the end of
+// the day when no hours are set.
+func (c Calendar) closingTime(day time.Time) time.Time {
+	year, month, date := day.Date()
+	endOfDay := time.Date(year, month, date, 23, 59, 59, 0, day.Location())
+
+	h, ok := c.hoursOn(day.Weekday())
+	if !ok {
+		return endOfDay
+	}
+
+	closes, err := time.Parse(ClockLayout, h.Closes)
+	if err != nil {
+		return endOfDay
+	}
+	return time.Date(year, month, date, closes.Hour(), closes.Minute(), 0, 0, day.Location())
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
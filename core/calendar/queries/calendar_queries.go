@@ -0,0 +1,28 @@
+package queries
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/calendar/repositories"
+)
+
+type GetCalendarQuery struct{}
+
+type GetCalendarQueryHandler struct {
+	repo repositories.CalendarRepository
+}
+
+func NewGetCalendarQueryHandler(repo repositories.CalendarRepository) *GetCalendarQueryHandler {
+	return &GetCalendarQueryHandler{
+		repo: repo,
+	}
+}
+
+func (h *GetCalendarQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	if _, ok := q.(GetCalendarQuery); !ok {
+		return nil, stderrors.New("invalid query type")
+	}
+
+	return h.repo.GetCalendar(ctx)
+}
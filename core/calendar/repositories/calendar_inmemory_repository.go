@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"books/core/calendar/errors"
+	"books/core/calendar/models"
+)
+
+type CalendarInMemoryRepository struct {
+	hours  []models.OpeningHours
+	closed map[string]models.ClosedDate
+	mutex  sync.RWMutex
+}
+
+func NewCalendarInMemoryRepository() *CalendarInMemoryRepository {
+	return &CalendarInMemoryRepository{
+		hours:  make([]models.OpeningHours, 0),
+		closed: make(map[string]models.ClosedDate),
+	}
+}
+
+func (r *CalendarInMemoryRepository) GetCalendar(ctx context.Context) (*models.Calendar, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	calendar := &models.Calendar{
+		Hours:  append([]models.OpeningHours{}, r.hours...),
+		Closed: make([]models.ClosedDate, 0, len(r.closed)),
+	}
+	for _, closed := range r.closed {
+		calendar.Closed = append(calendar.Closed, closed)
+	}
+
+	// Same ordering as the Postgres repository.
+	sort.Slice(calendar.Hours, func(i, j int) bool {
+		return calendar.Hours[i].Weekday < calendar.Hours[j].Weekday
+	})
+	sort.Slice(calendar.Closed, func(i, j int) bool {
+		return calendar.Closed[i].Date < calendar.Closed[j].Date
+	})
+	return calendar, nil
+}
+
+func (r *CalendarInMemoryRepository) SetOpeningHours(ctx context.Context, hours []models.OpeningHours) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.hours = append([]models.OpeningHours{}, hours...)
+	return nil
+}
+
+func (r *CalendarInMemoryRepository) SaveClosedDates(ctx context.Context, dates []models.ClosedDate) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, closed := range dates {
+		r.closed[closed.Date] = closed
+	}
+	return nil
+}
+
+func (r *CalendarInMemoryRepository) DeleteClosedDate(ctx context.Context, date string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.closed[date]; !exists {
+		return errors.ErrClosedDateNotFound
+	}
+	delete(r.closed, date)
+	return nil
+}
+
+var _ CalendarRepository = (*CalendarInMemoryRepository)(nil)
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+
+	"books/core/calendar/errors"
+	"books/core/calendar/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	pqInvalidDatetime  = "22007"
+	pqDatetimeOverflow = "22008"
+)
+
+type CalendarPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewCalendarPostgresRepository(db *sql.DB) *CalendarPostgresRepository {
+	return &CalendarPostgresRepository{
+		db: db,
+	}
+}
+
+func (r *CalendarPostgresRepository) GetCalendar(ctx context.Context) (*models.Calendar, error) {
+	hours, err := r.getOpeningHours(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	closed, err := r.getClosedDates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Calendar{
+		Hours:  hours,
+		Closed: closed,
+	}, nil
+}
+
+func (r *CalendarPostgresRepository) SetOpeningHours(ctx context.Context, hours []models.OpeningHours) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM opening_hours`); err != nil {
+		return fmt.Errorf("failed to clear opening hours: %w", err)
+	}
+
+	query := `INSERT INTO opening_hours (weekday, opens_at, closes_at) VALUES ($1, $2, $3)`
+	for _, h := range hours {
+		if _, err := tx.ExecContext(ctx, query, int(h.Weekday), h.Opens, h.Closes); err != nil {
+			return fmt.Errorf("failed to save opening hours: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit opening hours: %w", err)
+	}
+	return nil
+}
+
+func (r *CalendarPostgresRepository) SaveClosedDates(ctx context.Context, dates []models.ClosedDate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO closed_dates (closed_on, reason)
+		VALUES ($1, $2)
+		ON CONFLICT (closed_on) DO UPDATE SET reason = EXCLUDED.reason
+	`
+	for _, closed := range dates {
+		if _, err := tx.ExecContext(ctx, query, closed.Date, closed.Reason); err != nil {
+			return fmt.Errorf("failed to save closed date: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit closed dates: %w", err)
+	}
+	return nil
+}
+
+func (r *CalendarPostgresRepository) DeleteClosedDate(ctx context.Context, date string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM closed_dates WHERE closed_on = $1`, date)
+	if isInvalidDate(err) {
+		return errors.ErrClosedDateNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete closed date: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.ErrClosedDateNotFound
+	}
+	return nil
+}
+
+func (r *CalendarPostgresRepository) getOpeningHours(ctx context.Context) ([]models.OpeningHours, error) {
+	query := `
+		SELECT weekday, to_char(opens_at, 'HH24:MI'), to_char(closes_at, 'HH24:MI')
+		FROM opening_hours
+		ORDER BY weekday
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query opening hours: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	hours := make([]models.OpeningHours, 0)
+	for rows.Next() {
+		var h models.OpeningHours
+		if err := rows.Scan(&h.Weekday, &h.Opens, &h.Closes); err != nil {
+			return nil, fmt.Errorf("failed to scan opening hours: %w", err)
+		}
+		hours = append(hours, h)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate opening hours: %w", err)
+	}
+
+	return hours, nil
+}
+
+func (r *CalendarPostgresRepository) getClosedDates(ctx context.Context) ([]models.ClosedDate, error) {
+	query := `
+		SELECT to_char(closed_on, 'YYYY-MM-DD'), reason
+		FROM closed_dates
+		ORDER BY closed_on
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query closed dates: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	dates := make([]models.ClosedDate, 0)
+	for rows.Next() {
+		var closed models.ClosedDate
+		if err := rows.Scan(&closed.Date, &closed.Reason); err != nil {
+			return nil, fmt.Errorf("failed to scan closed date: %w", err)
+		}
+		dates = append(dates, closed)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate closed dates: %w", err)
+	}
+
+	return dates, nil
+}
+
+func isInvalidDate(err error) bool {
+	var pqErr *pq.Error
+	return stderrors.As(err, &pqErr) && (pqErr.Code == pqInvalidDatetime || pqErr.Code == pqDatetimeOverflow)
+}
+
+var _ CalendarRepository = (*CalendarPostgresRepository)(nil)
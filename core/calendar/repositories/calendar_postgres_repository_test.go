@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"books/core/calendar/errors"
+	"books/core/calendar/models"
+	"books/internal/testdb"
+)
+
+var db *sql.DB
+var repo *CalendarPostgresRepository
+
+func TestMain(m *testing.M) {
+	testdb.Run(m, func(conn *sql.DB) {
+		db = conn
+		repo = NewCalendarPostgresRepository(db)
+	})
+}
+
+func cleanupDB(t *testing.T) {
+	for _, table := range []string{"opening_hours", "closed_dates"} {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("Failed to cleanup %s: %v", table, err)
+		}
+	}
+}
+
+func TestOpeningHours(t *testing.T) {
+	cleanupDB(t)
+
+	calendar, err := repo.GetCalendar(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to load calendar: %v", err)
+	}
+	if len(calendar.Hours) != 0 || !calendar.IsOpen(time.Now()) {
+		t.Fatalf("expected an unconfigured calendar to be always open, got %+v", calendar)
+	}
+
+	hours := []models.OpeningHours{
+		{Weekday: time.Saturday, Opens: "10:00", Closes: "14:00"},
+		{Weekday: time.Monday, Opens: "09:00", Closes: "18:30"},
+	}
+	if err := repo.SetOpeningHours(context.Background(), hours); err != nil {
+		t.Fatalf("Failed to save opening hours: %v", err)
+	}
+	if err := repo.SetOpeningHours(context.Background(), hours[1:]); err != nil {
+		t.Fatalf("Failed to replace opening hours: %v", err)
+	}
+
+	calendar, _ = repo.GetCalendar(context.Background())
+	if len(calendar.Hours) != 1 || calendar.Hours[0] != hours[1] {
+		t.Errorf("expected only Monday's hours, got %+v", calendar.Hours)
+	}
+}
+
+func TestClosedDates(t *testing.T) {
+	cleanupDB(t)
+
+	dates := []models.ClosedDate{
+		{Date: "2026-12-26", Reason: "Boxing Day"},
+		{Date: "2026-12-25", Reason: "Christmas"},
+	}
+	if err := repo.SaveClosedDates(context.Background(), dates); err != nil {
+		t.Fatalf("Failed to save closed dates: %v", err)
+	}
+	if err := repo.SaveClosedDates(context.Background(), []models.ClosedDate{{Date: "2026-12-25", Reason: "Christmas Day"}}); err != nil {
+		t.Fatalf("Failed to update closed date: %v", err)
+	}
+
+	calendar, err := repo.GetCalendar(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to load calendar: %v", err)
+	}
+	if len(calendar.Closed) != 2 || calendar.Closed[0] != (models.ClosedDate{Date: "2026-12-25", Reason: "Christmas Day"}) {
+		t.Fatalf("unexpected closed dates %+v", calendar.Closed)
+	}
+
+	if err := repo.DeleteClosedDate(context.Background(), "2026-12-26"); err != nil {
+		t.Fatalf("Failed to delete closed date: %v", err)
+	}
+	if err := repo.DeleteClosedDate(context.Background(), "2026-12-26"); err != errors.ErrClosedDateNotFound {
+		t.Errorf("expected ErrClosedDateNotFound, got %v", err)
+	}
+	if err := repo.DeleteClosedDate(context.Background(), "not-a-date"); err != errors.ErrClosedDateNotFound {
+		t.Errorf("expected ErrClosedDateNotFound for malformed date, got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"books/core/calendar/models"
+)
+
+type CalendarRepository interface {
+	GetCalendar(ctx context.Context) (*models.Calendar, error)
+	// SetOpeningHours replaces the whole week; weekdays left out are closed.
+	SetOpeningHours(ctx context.Context, hours []models.OpeningHours) error
+	// SaveClosedDates adds closed dates, replacing the reason of dates that
+	// are already closed.
+	SaveClosedDates(ctx context.Context, dates []models.ClosedDate) error
+	DeleteClosedDate(ctx context.Context, date string) error
+}
@@ -1,40 +1,199 @@
 package core
 
 import (
+	author_commands "books/core/authors/commands"
+	author_errors "books/core/authors/errors"
+	author_models "books/core/authors/models"
+	author_queries "books/core/authors/queries"
+	author_repositories "books/core/authors/repositories"
+	branch_commands "books/core/branches/commands"
+	branch_errors "books/core/branches/errors"
+	branch_models "books/core/branches/models"
+	branch_queries "books/core/branches/queries"
+	branch_repositories "books/core/branches/repositories"
+	calendar_commands "books/core/calendar/commands"
+	calendar_models "books/core/calendar/models"
+	calendar_queries "books/core/calendar/queries"
+	calendar_repositories "books/core/calendar/repositories"
+	isbns "books/core/isbn"
+	library_commands "books/core/library/commands"
+	library_errors "books/core/library/errors"
+	library_models "books/core/library/models"
+	"books/core/library/policies"
+	library_queries "books/core/library/quieries"
+	library_repositories "books/core/library/repositories"
+	patron_commands "books/core/patrons/commands"
+	patron_models "books/core/patrons/models"
+	patron_queries "books/core/patrons/queries"
+	patron_repositories "books/core/patrons/repositories"
 	"books/core/storage/commands"
 	"books/core/storage/models"
+	"books/core/storage/queries"
 	"books/core/storage/repositories/interfaces"
 	"context"
+	"strings"
 	"time"
 )
 
 type Core struct {
-	commandBus  commands.CommandBus
-	repository  interfaces.BookRepository
+	commandBus commands.CommandBus
+	queryBus   queries.QueryBus
+	isbnRanges *isbns.Ranges
 }
 
-func NewCore(bookRepository interfaces.BookRepository) *Core {
+// Repositories groups the storage ports Core is built on.
+type Repositories struct {
+	Books     interfaces.BookRepository
+	Copies    interfaces.CopyRepository
+	Rentals   library_repositories.BookRepository
+	Holds     library_repositories.HoldRepository
+	Ledger    library_repositories.LedgerRepository
+	Patrons   patron_repositories.PatronRepository
+	Calendar  calendar_repositories.CalendarRepository
+	Branches  branch_repositories.BranchRepository
+	Transfers library_repositories.TransferRepository
+	Authors   author_repositories.AuthorRepository
+}
+
+// NewCore wires the command and query handlers. isbnRanges, when loaded,
+// hyphenates ISBNs and refuses books in unassigned ISBN ranges.
+func NewCore(repositories Repositories, rules policies.LendingRules, isbnRanges *isbns.Ranges) *Core {
 	commandBus := commands.NewCommandBus()
 
-	addBookHandler := commands.NewAddBookCommandHandler(bookRepository)
-	updateBookHandler := commands.NewUpdateBookCommandHandler(bookRepository)
-	deleteBookHandler := commands.NewDeleteBookCommandHandler(bookRepository)
+	bookRepository := repositories.Books
+	copyRepository := repositories.Copies
+	rentalRepository := repositories.Rentals
+
+	addBookHandler := commands.NewAddBookCommandHandler(bookRepository, copyRepository, repositories.Authors, isbnRanges)
+	updateBookHandler := commands.NewUpdateBookCommandHandler(bookRepository, repositories.Authors)
+	deleteBookHandler := commands.NewDeleteBookCommandHandler(bookRepository, rentalRepository, repositories.Holds)
+	restoreBookHandler := commands.NewRestoreBookCommandHandler(bookRepository)
+	purgeDeletedBooksHandler := commands.NewPurgeDeletedBooksCommandHandler(bookRepository, copyRepository, rentalRepository, repositories.Holds)
+	addCopyHandler := commands.NewAddCopyCommandHandler(bookRepository, copyRepository, repositories.Branches)
+	updateCopyHandler := commands.NewUpdateCopyCommandHandler(copyRepository, repositories.Branches)
+	deleteCopyHandler := commands.NewDeleteCopyCommandHandler(copyRepository)
 
 	commandBus.RegisterHandler("*commands.AddBookCommand", addBookHandler)
 	commandBus.RegisterHandler("*commands.UpdateBookCommand", updateBookHandler)
 	commandBus.RegisterHandler("*commands.DeleteBookCommand", deleteBookHandler)
+	commandBus.RegisterHandler("*commands.RestoreBookCommand", restoreBookHandler)
+	commandBus.RegisterHandler("*commands.PurgeDeletedBooksCommand", purgeDeletedBooksHandler)
+	commandBus.RegisterHandler("*commands.AddCopyCommand", addCopyHandler)
+	commandBus.RegisterHandler("*commands.UpdateCopyCommand", updateCopyHandler)
+	commandBus.RegisterHandler("*commands.DeleteCopyCommand", deleteCopyHandler)
+
+	copyMover := library_commands.NewCopyMover(copyRepository, repositories.Transfers, repositories.Branches)
+	holdQueue := library_commands.NewHoldQueue(repositories.Holds, rentalRepository, copyMover, rules.HoldPickupWindow)
+
+	bookRentalHandler := library_commands.NewBookRentalCommandHandler(rentalRepository, repositories.Patrons, repositories.Ledger, rules, holdQueue, repositories.Calendar)
+	returnBookHandler := library_commands.NewReturnBookCommandHandler(rentalRepository, repositories.Ledger, rules.Loans, holdQueue, copyMover)
+	renewRentalHandler := library_commands.NewRenewRentalCommandHandler(rentalRepository, rules.Loans, holdQueue, repositories.Calendar)
+	placeHoldHandler := library_commands.NewPlaceHoldCommandHandler(rentalRepository, holdQueue, copyMover)
+	cancelHoldHandler := library_commands.NewCancelHoldCommandHandler(holdQueue)
+	payFineHandler := library_commands.NewPayFineCommandHandler(repositories.Ledger)
+	waiveFineHandler := library_commands.NewWaiveFineCommandHandler(repositories.Ledger)
+	markRentalLostHandler := library_commands.NewMarkRentalLostCommandHandler(rentalRepository, copyRepository, repositories.Ledger, rules.Loans)
+	markRentalDamagedHandler := library_commands.NewMarkRentalDamagedCommandHandler(rentalRepository, copyRepository, repositories.Ledger, rules.Loans)
+	markRentalFoundHandler := library_commands.NewMarkRentalFoundCommandHandler(rentalRepository, copyRepository, repositories.Ledger, holdQueue)
+	startTransferHandler := library_commands.NewStartTransferCommandHandler(rentalRepository, copyRepository, holdQueue, copyMover)
+	receiveTransferHandler := library_commands.NewReceiveTransferCommandHandler(repositories.Transfers, holdQueue, copyMover)
+
+	commandBus.RegisterHandler("commands.BookRentalCommand", bookRentalHandler)
+	commandBus.RegisterHandler("commands.ReturnBookCommand", returnBookHandler)
+	commandBus.RegisterHandler("commands.RenewRentalCommand", renewRentalHandler)
+	commandBus.RegisterHandler("commands.PlaceHoldCommand", placeHoldHandler)
+	commandBus.RegisterHandler("commands.CancelHoldCommand", cancelHoldHandler)
+	commandBus.RegisterHandler("commands.PayFineCommand", payFineHandler)
+	commandBus.RegisterHandler("commands.WaiveFineCommand", waiveFineHandler)
+	commandBus.RegisterHandler("commands.MarkRentalLostCommand", markRentalLostHandler)
+	commandBus.RegisterHandler("commands.MarkRentalDamagedCommand", markRentalDamagedHandler)
+	commandBus.RegisterHandler("commands.MarkRentalFoundCommand", markRentalFoundHandler)
+	commandBus.RegisterHandler("commands.StartTransferCommand", startTransferHandler)
+	commandBus.RegisterHandler("commands.ReceiveTransferCommand", receiveTransferHandler)
+
+	registerPatronHandler := patron_commands.NewRegisterPatronCommandHandler(repositories.Patrons, rules.MembershipPeriod)
+	updatePatronHandler := patron_commands.NewUpdatePatronCommandHandler(repositories.Patrons)
+	suspendPatronHandler := patron_commands.NewSuspendPatronCommandHandler(repositories.Patrons)
+	reinstatePatronHandler := patron_commands.NewReinstatePatronCommandHandler(repositories.Patrons)
+	renewPatronHandler := patron_commands.NewRenewPatronCommandHandler(repositories.Patrons, rules.MembershipPeriod)
+	deletePatronHandler := patron_commands.NewDeletePatronCommandHandler(repositories.Patrons)
+
+	commandBus.RegisterHandler("commands.RegisterPatronCommand", registerPatronHandler)
+	commandBus.RegisterHandler("commands.UpdatePatronCommand", updatePatronHandler)
+	commandBus.RegisterHandler("commands.SuspendPatronCommand", suspendPatronHandler)
+	commandBus.RegisterHandler("commands.ReinstatePatronCommand", reinstatePatronHandler)
+	commandBus.RegisterHandler("commands.RenewPatronCommand", renewPatronHandler)
+	commandBus.RegisterHandler("commands.DeletePatronCommand", deletePatronHandler)
+
+	commandBus.RegisterHandler("commands.SetOpeningHoursCommand", calendar_commands.NewSetOpeningHoursCommandHandler(repositories.Calendar))
+	commandBus.RegisterHandler("commands.AddClosedDatesCommand", calendar_commands.NewAddClosedDatesCommandHandler(repositories.Calendar))
+	commandBus.RegisterHandler("commands.ImportClosedDatesCommand", calendar_commands.NewImportClosedDatesCommandHandler(repositories.Calendar))
+	commandBus.RegisterHandler("commands.RemoveClosedDateCommand", calendar_commands.NewRemoveClosedDateCommandHandler(repositories.Calendar))
+
+	commandBus.RegisterHandler("commands.AddBranchCommand", branch_commands.NewAddBranchCommandHandler(repositories.Branches))
+	commandBus.RegisterHandler("commands.UpdateBranchCommand", branch_commands.NewUpdateBranchCommandHandler(repositories.Branches))
+
+	commandBus.RegisterHandler("commands.AddAuthorCommand", author_commands.NewAddAuthorCommandHandler(repositories.Authors))
+	commandBus.RegisterHandler("commands.UpdateAuthorCommand", author_commands.NewUpdateAuthorCommandHandler(repositories.Authors))
+	commandBus.RegisterHandler("commands.MergeAuthorsCommand", author_commands.NewMergeAuthorsCommandHandler(repositories.Authors, bookRepository))
+
+	queryBus := queries.NewQueryBus()
+
+	queries.NewQueries(bookRepository, copyRepository).Register(queryBus)
+
+	holdQueryHandler := library_queries.NewHoldQueryHandler(repositories.Holds, holdQueue)
+
+	queryBus.RegisterHandler("queries.GetRentalBookQuery", library_queries.NewGetRentalBookQueryHandler(rentalRepository))
+	queryBus.RegisterHandler("queries.GetLibraryBookQuery", library_queries.NewGetLibraryBookQueryHandler(bookRepository, copyRepository, rentalRepository))
+	queryBus.RegisterHandler("queries.ListLibraryBooksQuery", library_queries.NewListLibraryBooksQueryHandler(bookRepository, copyRepository, rentalRepository))
+	queryBus.RegisterHandler("queries.ListUserRentalsQuery", library_queries.NewListUserRentalsQueryHandler(rentalRepository))
+	queryBus.RegisterHandler("queries.ListOverdueRentalsQuery", library_queries.NewListOverdueRentalsQueryHandler(rentalRepository))
+	queryBus.RegisterHandler("queries.GetHoldQuery", holdQueryHandler)
+	queryBus.RegisterHandler("queries.ListBookHoldsQuery", holdQueryHandler)
+	queryBus.RegisterHandler("queries.ListUserHoldsQuery", holdQueryHandler)
+	queryBus.RegisterHandler("queries.GetUserBalanceQuery", library_queries.NewGetUserBalanceQueryHandler(repositories.Ledger))
+
+	transferQueryHandler := library_queries.NewTransferQueryHandler(repositories.Transfers)
+
+	queryBus.RegisterHandler("queries.GetTransferQuery", transferQueryHandler)
+	queryBus.RegisterHandler("queries.GetCopyTransferQuery", transferQueryHandler)
+	queryBus.RegisterHandler("queries.ListInboundTransfersQuery", transferQueryHandler)
+
+	patronQueryHandler := patron_queries.NewPatronQueryHandler(repositories.Patrons)
+
+	queryBus.RegisterHandler("queries.GetPatronQuery", patronQueryHandler)
+	queryBus.RegisterHandler("queries.GetPatronByCardNumberQuery", patronQueryHandler)
+	queryBus.RegisterHandler("queries.ListPatronsQuery", patronQueryHandler)
+
+	queryBus.RegisterHandler("queries.GetCalendarQuery", calendar_queries.NewGetCalendarQueryHandler(repositories.Calendar))
+
+	branchQueryHandler := branch_queries.NewBranchQueryHandler(repositories.Branches)
+
+	queryBus.RegisterHandler("queries.GetBranchQuery", branchQueryHandler)
+	queryBus.RegisterHandler("queries.ListBranchesQuery", branchQueryHandler)
+
+	authorQueryHandler := author_queries.NewAuthorQueryHandler(repositories.Authors)
+
+	queryBus.RegisterHandler("queries.GetAuthorQuery", authorQueryHandler)
+	queryBus.RegisterHandler("queries.ListAuthorsQuery", authorQueryHandler)
+	queryBus.RegisterHandler("queries.MatchAuthorsQuery", authorQueryHandler)
 
 	return &Core{
 		commandBus: commandBus,
-		repository: bookRepository,
+		queryBus:   queryBus,
+		isbnRanges: isbnRanges,
 	}
 }
 
-func (c *Core) AddBook(ctx context.Context, title, author, isbn string) (*models.Book, error) {
+// AddBook catalogues a book together with its first copy.
+func (c *Core) AddBook(ctx context.Context, title, author, isbn, category, barcode string, details models.BookDetails) (*models.Book, error) {
 	cmd := &commands.AddBookCommand{
-		Title:  title,
-		Author: author,
-		ISBN:   isbn,
+		Title:    title,
+		Author:   author,
+		ISBN:     isbn,
+		Category: category,
+		Barcode:  barcode,
+		Details:  details,
 	}
 
 	err := c.commandBus.Dispatch(ctx, cmd)
@@ -42,18 +201,18 @@ func (c *Core) AddBook(ctx context.Context, title, author, isbn string) (*models
 		return nil, err
 	}
 
-	book, err := models.NewBook(isbn, title, author, time.Now())
-	if err != nil {
-		return nil, err
-	}
-	return book, nil
+	return c.GetBookByISBN(ctx, isbn)
 }
 
-func (c *Core) UpdateBook(ctx context.Context, isbn, title, author string) (*models.Book, error) {
+// UpdateBook changes the given fields and details of a book, leaving empty
+// ones as they are.
+func (c *Core) UpdateBook(ctx context.Context, isbn, title, author, category string, details models.BookDetails) (*models.Book, error) {
 	cmd := &commands.UpdateBookCommand{
-		ISBN:  isbn,
-		Title: title,
-		Author: author,
+		ISBN:     isbn,
+		Title:    title,
+		Author:   author,
+		Category: category,
+		Details:  details,
 	}
 
 	err := c.commandBus.Dispatch(ctx, cmd)
@@ -64,6 +223,8 @@ func (c *Core) UpdateBook(ctx context.Context, isbn, title, author string) (*mod
 	return c.GetBookByISBN(ctx, isbn)
 }
 
+// DeleteBook takes a book out of the catalog until it is restored, refusing
+// a book that is on loan and cancelling its open holds.
 func (c *Core) DeleteBook(ctx context.Context, isbn string) error {
 	cmd := &commands.DeleteBookCommand{
 		ISBN: isbn,
@@ -72,10 +233,627 @@ func (c *Core) DeleteBook(ctx context.Context, isbn string) error {
 	return c.commandBus.Dispatch(ctx, cmd)
 }
 
-func (c *Core) GetAllBooks(ctx context.Context) ([]*models.Book, error) {
-	return c.repository.FindAll(ctx)
+// RestoreBook brings a deleted book back into the catalog.
+func (c *Core) RestoreBook(ctx context.Context, isbn string) (*models.Book, error) {
+	cmd := &commands.RestoreBookCommand{
+		ISBN: isbn,
+	}
+
+	if err := c.commandBus.Dispatch(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	return c.GetBookByISBN(ctx, isbn)
+}
+
+// PurgeDeletedBooks permanently removes the books deleted longer ago than
+// the retention period, the default one when it is zero. It returns the
+// ISBNs of the books purged and of those kept because a copy is on loan or
+// a hold is still waiting or ready.
+func (c *Core) PurgeDeletedBooks(ctx context.Context, retention time.Duration) (purged, kept []string, err error) {
+	cmd := &commands.PurgeDeletedBooksCommand{
+		Retention: retention,
+	}
+
+	if err := c.commandBus.Dispatch(ctx, cmd); err != nil {
+		return nil, nil, err
+	}
+
+	return cmd.Purged, cmd.Kept, nil
+}
+
+// ListBooks returns a page of the catalog. Pass the NextCursor of a page in
+// the filter to get the page after it.
+func (c *Core) ListBooks(ctx context.Context, filter models.BookFilter) (*models.BookPage, error) {
+	return queries.Ask[*models.BookPage](ctx, c.queryBus, queries.ListBooksQuery{Filter: filter})
+}
+
+// SearchBooks returns a page of the books matching the words of a search,
+// from the most relevant.
+func (c *Core) SearchBooks(ctx context.Context, search models.BookSearch) (*models.BookSearchResult, error) {
+	return queries.Ask[*models.BookSearchResult](ctx, c.queryBus, queries.SearchBooksQuery{Search: search})
+}
+
+// FindBooksByContributor returns the books crediting a contributor whose name
+// contains name. An empty role matches every role.
+func (c *Core) FindBooksByContributor(ctx context.Context, name string, role models.ContributorRole) ([]*models.Book, error) {
+	return queries.Ask[[]*models.Book](ctx, c.queryBus, queries.FindBooksByContributorQuery{Name: name, Role: role})
 }
 
 func (c *Core) GetBookByISBN(ctx context.Context, isbn string) (*models.Book, error) {
-	return c.repository.FindByISBN(ctx, isbn)
+	return queries.Ask[*models.Book](ctx, c.queryBus, queries.GetBookByISBNQuery{ISBN: isbn})
+}
+
+// SplitISBN divides an ISBN into its hyphenated parts. It fails with
+// isbn.ErrNoRanges when no ISBN ranges were loaded.
+func (c *Core) SplitISBN(isbn string) (isbns.Parts, error) {
+	return c.isbnRanges.Split(isbn)
+}
+
+// AddCopy adds a physical copy of a book to the collection, shelved at its
+// home branch.
+func (c *Core) AddCopy(ctx context.Context, isbn, barcode, shelfLocation, condition, homeBranchID string) (*models.Copy, error) {
+	cmd := &commands.AddCopyCommand{
+		ISBN:          isbn,
+		Barcode:       barcode,
+		ShelfLocation: shelfLocation,
+		Condition:     condition,
+		HomeBranchID:  homeBranchID,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	copies, err := c.GetBookCopies(ctx, isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bookCopy := range copies {
+		if bookCopy.Barcode == cmd.Barcode {
+			return bookCopy, nil
+		}
+	}
+
+	return nil, interfaces.ErrCopyNotFound
+}
+
+func (c *Core) UpdateCopy(ctx context.Context, id, barcode, shelfLocation, condition, homeBranchID string) (*models.Copy, error) {
+	cmd := &commands.UpdateCopyCommand{
+		ID:            id,
+		Barcode:       barcode,
+		ShelfLocation: shelfLocation,
+		Condition:     condition,
+		HomeBranchID:  homeBranchID,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return queries.Ask[*models.Copy](ctx, c.queryBus, queries.GetCopyQuery{ID: id})
+}
+
+func (c *Core) DeleteCopy(ctx context.Context, id string) error {
+	cmd := &commands.DeleteCopyCommand{
+		ID: id,
+	}
+
+	return c.commandBus.Dispatch(ctx, cmd)
+}
+
+func (c *Core) GetBookCopies(ctx context.Context, isbn string) ([]*models.Copy, error) {
+	return queries.Ask[[]*models.Copy](ctx, c.queryBus, queries.ListBookCopiesQuery{ISBN: isbn})
+}
+
+// RentBook lends the user a copy of the book at the given branch, or at any
+// branch when branchID is empty, and returns the book's availability there.
+func (c *Core) RentBook(ctx context.Context, isbn, userID, branchID string) (*library_models.LibraryBook, error) {
+	cmd := library_commands.BookRentalCommand{
+		BookID:   isbn,
+		UserID:   userID,
+		BranchID: branchID,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetLibraryBook(ctx, isbn, branchID)
+}
+
+// ReturnBook closes the user's open rental of the book and returns the closed
+// rental record, including whether it came back late. A copy returned at a
+// branch other than its home branch is sent back home.
+func (c *Core) ReturnBook(ctx context.Context, isbn, userID, branchID string) (*library_models.BookRental, error) {
+	cmd := library_commands.ReturnBookCommand{
+		BookID:   isbn,
+		UserID:   userID,
+		BranchID: branchID,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	rentals, err := c.GetUserRentals(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rental := range rentals {
+		if rental.BookID == isbns.Key(isbn) && rental.IsReturned() {
+			return rental, nil
+		}
+	}
+
+	return nil, library_errors.ErrNotFound
+}
+
+func (c *Core) RenewRental(ctx context.Context, rentalID, userID string) (*library_models.BookRental, error) {
+	cmd := library_commands.RenewRentalCommand{
+		RentalID: rentalID,
+		UserID:   userID,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetRental(ctx, rentalID)
+}
+
+// MarkRentalLost closes a loan whose copy was lost, charges the replacement
+// fee and takes the copy out of circulation. A zero fee uses the loan policy's.
+func (c *Core) MarkRentalLost(ctx context.Context, rentalID string, feeCents int64) (*library_models.BookRental, error) {
+	cmd := library_commands.MarkRentalLostCommand{
+		RentalID: rentalID,
+		FeeCents: feeCents,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetRental(ctx, rentalID)
+}
+
+// MarkRentalDamaged closes a loan whose copy came back unusable, charges the
+// replacement fee and withdraws the copy.
+func (c *Core) MarkRentalDamaged(ctx context.Context, rentalID string, feeCents int64) (*library_models.BookRental, error) {
+	cmd := library_commands.MarkRentalDamagedCommand{
+		RentalID: rentalID,
+		FeeCents: feeCents,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetRental(ctx, rentalID)
+}
+
+// MarkRentalFound reverses the replacement fee of a lost rental and puts its
+// copy back on the shelf.
+func (c *Core) MarkRentalFound(ctx context.Context, rentalID string) (*library_models.BookRental, error) {
+	err := c.commandBus.Dispatch(ctx, library_commands.MarkRentalFoundCommand{RentalID: rentalID})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetRental(ctx, rentalID)
+}
+
+// PlaceHold puts the user at the back of the book's hold queue, to pick the
+// book up at the given branch.
+func (c *Core) PlaceHold(ctx context.Context, isbn, userID, pickupBranchID string) (*library_models.Hold, error) {
+	cmd := library_commands.PlaceHoldCommand{
+		BookID:         isbn,
+		UserID:         userID,
+		PickupBranchID: pickupBranchID,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	holds, err := c.GetUserHolds(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hold := range holds {
+		if hold.BookID == isbns.Key(isbn) && hold.IsActive() {
+			return hold, nil
+		}
+	}
+
+	return nil, library_errors.ErrNotFound
+}
+
+func (c *Core) CancelHold(ctx context.Context, holdID, userID string) (*library_models.Hold, error) {
+	cmd := library_commands.CancelHoldCommand{
+		HoldID: holdID,
+		UserID: userID,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return queries.Ask[*library_models.Hold](ctx, c.queryBus, library_queries.GetHoldQuery{HoldID: holdID})
+}
+
+// GetBookHolds returns the active holds of a book in queue order.
+func (c *Core) GetBookHolds(ctx context.Context, isbn string) ([]*library_models.Hold, error) {
+	if _, err := c.GetBookByISBN(ctx, isbn); err != nil {
+		return nil, err
+	}
+
+	return queries.Ask[[]*library_models.Hold](ctx, c.queryBus, library_queries.ListBookHoldsQuery{BookID: isbn})
+}
+
+func (c *Core) GetUserHolds(ctx context.Context, userID string) ([]*library_models.Hold, error) {
+	return queries.Ask[[]*library_models.Hold](ctx, c.queryBus, library_queries.ListUserHoldsQuery{UserID: userID})
+}
+
+// GetUserBalance returns the user's fines ledger and unpaid balance.
+func (c *Core) GetUserBalance(ctx context.Context, userID string) (*library_models.FineAccount, error) {
+	return queries.Ask[*library_models.FineAccount](ctx, c.queryBus, library_queries.GetUserBalanceQuery{UserID: userID})
+}
+
+func (c *Core) PayFine(ctx context.Context, userID string, amountCents int64, note string) (*library_models.FineAccount, error) {
+	cmd := library_commands.PayFineCommand{
+		UserID:      userID,
+		AmountCents: amountCents,
+		Note:        note,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetUserBalance(ctx, userID)
+}
+
+func (c *Core) WaiveFine(ctx context.Context, userID string, amountCents int64, note string) (*library_models.FineAccount, error) {
+	cmd := library_commands.WaiveFineCommand{
+		UserID:      userID,
+		AmountCents: amountCents,
+		Note:        note,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetUserBalance(ctx, userID)
+}
+
+func (c *Core) GetUserRentals(ctx context.Context, userID string) ([]*library_models.BookRental, error) {
+	return queries.Ask[[]*library_models.BookRental](ctx, c.queryBus, library_queries.ListUserRentalsQuery{UserID: userID})
+}
+
+func (c *Core) GetRental(ctx context.Context, rentalID string) (*library_models.BookRental, error) {
+	return queries.Ask[*library_models.BookRental](ctx, c.queryBus, library_queries.GetRentalBookQuery{RentalID: rentalID})
+}
+
+// GetOverdueRentals returns the open rentals past their deadline, longest
+// overdue first.
+func (c *Core) GetOverdueRentals(ctx context.Context) ([]*library_models.BookRental, error) {
+	return queries.Ask[[]*library_models.BookRental](ctx, c.queryBus, library_queries.ListOverdueRentalsQuery{})
+}
+
+// GetLibraryBook returns the availability of a book at the given branch, or
+// across all branches when branchID is empty.
+func (c *Core) GetLibraryBook(ctx context.Context, isbn, branchID string) (*library_models.LibraryBook, error) {
+	return queries.Ask[*library_models.LibraryBook](ctx, c.queryBus, library_queries.GetLibraryBookQuery{ISBN: isbn, BranchID: branchID})
+}
+
+func (c *Core) GetLibraryBooks(ctx context.Context, branchID string) ([]*library_models.LibraryBook, error) {
+	if branchID != "" {
+		if _, err := c.GetBranch(ctx, branchID); err != nil {
+			return nil, err
+		}
+	}
+
+	return queries.Ask[[]*library_models.LibraryBook](ctx, c.queryBus, library_queries.ListLibraryBooksQuery{BranchID: branchID})
+}
+
+func (c *Core) RegisterPatron(ctx context.Context, cardNumber, name, email, patronType string) (*patron_models.Patron, error) {
+	cmd := patron_commands.RegisterPatronCommand{
+		CardNumber: cardNumber,
+		Name:       name,
+		Email:      email,
+		PatronType: patronType,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return queries.Ask[*patron_models.Patron](ctx, c.queryBus, patron_queries.GetPatronByCardNumberQuery{CardNumber: strings.TrimSpace(cardNumber)})
+}
+
+func (c *Core) UpdatePatron(ctx context.Context, id, cardNumber, name, email, patronType string) (*patron_models.Patron, error) {
+	cmd := patron_commands.UpdatePatronCommand{
+		ID:         id,
+		CardNumber: cardNumber,
+		Name:       name,
+		Email:      email,
+		PatronType: patronType,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetPatron(ctx, id)
+}
+
+// SuspendPatron blocks the patron from borrowing until reinstated.
+func (c *Core) SuspendPatron(ctx context.Context, id string) (*patron_models.Patron, error) {
+	err := c.commandBus.Dispatch(ctx, patron_commands.SuspendPatronCommand{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetPatron(ctx, id)
+}
+
+func (c *Core) ReinstatePatron(ctx context.Context, id string) (*patron_models.Patron, error) {
+	err := c.commandBus.Dispatch(ctx, patron_commands.ReinstatePatronCommand{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetPatron(ctx, id)
+}
+
+// RenewPatron extends the patron's membership by one membership period.
+func (c *Core) RenewPatron(ctx context.Context, id string) (*patron_models.Patron, error) {
+	err := c.commandBus.Dispatch(ctx, patron_commands.RenewPatronCommand{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetPatron(ctx, id)
+}
+
+func (c *Core) DeletePatron(ctx context.Context, id string) error {
+	return c.commandBus.Dispatch(ctx, patron_commands.DeletePatronCommand{ID: id})
+}
+
+func (c *Core) GetPatron(ctx context.Context, id string) (*patron_models.Patron, error) {
+	return queries.Ask[*patron_models.Patron](ctx, c.queryBus, patron_queries.GetPatronQuery{ID: id})
+}
+
+func (c *Core) GetPatrons(ctx context.Context) ([]*patron_models.Patron, error) {
+	return queries.Ask[[]*patron_models.Patron](ctx, c.queryBus, patron_queries.ListPatronsQuery{})
+}
+
+func (c *Core) GetCalendar(ctx context.Context) (*calendar_models.Calendar, error) {
+	return queries.Ask[*calendar_models.Calendar](ctx, c.queryBus, calendar_queries.GetCalendarQuery{})
+}
+
+// SetOpeningHours replaces the weekly opening hours. Weekdays left out are
+// closed; an empty week opens the library every day.
+func (c *Core) SetOpeningHours(ctx context.Context, hours []calendar_models.OpeningHours) (*calendar_models.Calendar, error) {
+	err := c.commandBus.Dispatch(ctx, calendar_commands.SetOpeningHoursCommand{Hours: hours})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetCalendar(ctx)
+}
+
+func (c *Core) AddClosedDates(ctx context.Context, dates []calendar_models.ClosedDate) (*calendar_models.Calendar, error) {
+	err := c.commandBus.Dispatch(ctx, calendar_commands.AddClosedDatesCommand{Dates: dates})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetCalendar(ctx)
+}
+
+// ImportClosedDates closes every day covered by the events of an iCalendar file.
+func (c *Core) ImportClosedDates(ctx context.Context, icalendar []byte) (*calendar_models.Calendar, error) {
+	err := c.commandBus.Dispatch(ctx, calendar_commands.ImportClosedDatesCommand{ICalendar: icalendar})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetCalendar(ctx)
+}
+
+func (c *Core) RemoveClosedDate(ctx context.Context, date string) error {
+	return c.commandBus.Dispatch(ctx, calendar_commands.RemoveClosedDateCommand{Date: date})
+}
+
+func (c *Core) AddBranch(ctx context.Context, code, name, address string) (*branch_models.Branch, error) {
+	cmd := branch_commands.AddBranchCommand{
+		Code:    code,
+		Name:    name,
+		Address: address,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	branches, err := c.GetBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, branch := range branches {
+		if branch.Code == strings.ToLower(strings.TrimSpace(code)) {
+			return branch, nil
+		}
+	}
+
+	return nil, branch_errors.ErrBranchNotFound
+}
+
+func (c *Core) UpdateBranch(ctx context.Context, id, code, name, address string) (*branch_models.Branch, error) {
+	cmd := branch_commands.UpdateBranchCommand{
+		ID:      id,
+		Code:    code,
+		Name:    name,
+		Address: address,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetBranch(ctx, id)
+}
+
+func (c *Core) GetBranch(ctx context.Context, id string) (*branch_models.Branch, error) {
+	return queries.Ask[*branch_models.Branch](ctx, c.queryBus, branch_queries.GetBranchQuery{ID: id})
+}
+
+func (c *Core) GetBranches(ctx context.Context) ([]*branch_models.Branch, error) {
+	return queries.Ask[[]*branch_models.Branch](ctx, c.queryBus, branch_queries.ListBranchesQuery{})
+}
+
+// StartTransfer sends a copy on the shelf to another branch. The copy cannot
+// be lent until the transfer is received.
+func (c *Core) StartTransfer(ctx context.Context, copyID, toBranchID string) (*library_models.Transfer, error) {
+	cmd := library_commands.StartTransferCommand{
+		CopyID:     copyID,
+		ToBranchID: toBranchID,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return queries.Ask[*library_models.Transfer](ctx, c.queryBus, library_queries.GetCopyTransferQuery{CopyID: copyID})
+}
+
+// ReceiveTransfer puts a copy in transit back in circulation at its
+// destination, where it may be set aside for a hold.
+func (c *Core) ReceiveTransfer(ctx context.Context, transferID string) (*library_models.Transfer, error) {
+	err := c.commandBus.Dispatch(ctx, library_commands.ReceiveTransferCommand{TransferID: transferID})
+	if err != nil {
+		return nil, err
+	}
+
+	return queries.Ask[*library_models.Transfer](ctx, c.queryBus, library_queries.GetTransferQuery{TransferID: transferID})
+}
+
+// GetInboundTransfers returns the copies on their way to a branch, oldest first.
+func (c *Core) GetInboundTransfers(ctx context.Context, branchID string) ([]*library_models.Transfer, error) {
+	if _, err := c.GetBranch(ctx, branchID); err != nil {
+		return nil, err
+	}
+
+	return queries.Ask[[]*library_models.Transfer](ctx, c.queryBus, library_queries.ListInboundTransfersQuery{BranchID: branchID})
+}
+
+// AddAuthor creates an authority record. It is refused when an author with
+// one of the names and the same birth year is already on record.
+func (c *Core) AddAuthor(ctx context.Context, name string, alternateNames []string, birthYear, deathYear int) (*author_models.Author, error) {
+	cmd := author_commands.AddAuthorCommand{
+		Name:           name,
+		AlternateNames: alternateNames,
+		BirthYear:      birthYear,
+		DeathYear:      deathYear,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := c.MatchAuthors(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The name and birth year are unique among authors, so they find the
+	// new record.
+	for _, match := range matches {
+		if match.Exact && match.Author.BirthYear == birthYear {
+			return match.Author, nil
+		}
+	}
+
+	return nil, author_errors.ErrAuthorNotFound
+}
+
+// UpdateAuthor changes the given fields of an author, leaving empty ones as
+// they are.
+func (c *Core) UpdateAuthor(ctx context.Context, id, name string, alternateNames []string, birthYear, deathYear int) (*author_models.Author, error) {
+	cmd := author_commands.UpdateAuthorCommand{
+		ID:             id,
+		Name:           name,
+		AlternateNames: alternateNames,
+		BirthYear:      birthYear,
+		DeathYear:      deathYear,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetAuthor(ctx, id)
+}
+
+// MergeAuthors folds the duplicate author into the canonical one and returns
+// the canonical author.
+func (c *Core) MergeAuthors(ctx context.Context, canonicalID, duplicateID string) (*author_models.Author, error) {
+	cmd := author_commands.MergeAuthorsCommand{
+		CanonicalID: canonicalID,
+		DuplicateID: duplicateID,
+	}
+
+	err := c.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetAuthor(ctx, canonicalID)
+}
+
+func (c *Core) GetAuthor(ctx context.Context, id string) (*author_models.Author, error) {
+	return queries.Ask[*author_models.Author](ctx, c.queryBus, author_queries.GetAuthorQuery{ID: id})
+}
+
+func (c *Core) GetAuthors(ctx context.Context) ([]*author_models.Author, error) {
+	return queries.Ask[[]*author_models.Author](ctx, c.queryBus, author_queries.ListAuthorsQuery{})
+}
+
+// MatchAuthors suggests the authors a name may refer to, exact matches first.
+func (c *Core) MatchAuthors(ctx context.Context, name string) ([]author_models.Match, error) {
+	return queries.Ask[[]author_models.Match](ctx, c.queryBus, author_queries.MatchAuthorsQuery{Name: name})
+}
+
+// GetAuthorBooks returns the books crediting an author, ordered by title.
+func (c *Core) GetAuthorBooks(ctx context.Context, id string) ([]*models.Book, error) {
+	if _, err := c.GetAuthor(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return queries.Ask[[]*models.Book](ctx, c.queryBus, queries.FindBooksByAuthorQuery{AuthorID: id})
 }
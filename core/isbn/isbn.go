@@ -0,0 +1,188 @@
+// Package isbn validates International Standard Book Numbers and converts
+// them between their 10 and 13 digit forms. Books are identified by the
+// canonical form returned by Normalize, the bare ISBN-13, so that every way
+// of writing an ISBN refers to the same book. Ranges hyphenates ISBNs using
+// the ranges published by the International ISBN Agency.
+package isbn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// bookland is the prefix of the ISBN-13s that have an ISBN-10 form.
+const bookland = "978"
+
+var (
+	ErrInvalidLength  = errors.New("ISBN must be 10 or 13 characters (excluding hyphens)")
+	ErrInvalidISBN10  = errors.New("ISBN-10 must contain only digits (and optionally X as last character)")
+	ErrInvalidISBN13  = errors.New("ISBN-13 must contain only digits")
+	ErrISBN10Checksum = errors.New("invalid ISBN-10 checksum")
+	ErrISBN13Checksum = errors.New("invalid ISBN-13 checksum")
+	ErrNoISBN10Form   = errors.New("invalid ISBN-10 conversion: only ISBN-13s starting with 978 have an ISBN-10")
+	ErrNotAnISBN10    = errors.New("invalid ISBN-10: it must be 10 characters (excluding hyphens)")
+	ErrNotAnISBN13    = errors.New("invalid ISBN-13: it must be 13 characters (excluding hyphens)")
+)
+
+// Clean removes the hyphens and spaces an ISBN is often printed with and
+// upper-cases an ISBN-10 check character. It does not validate.
+func Clean(s string) string {
+	s = strings.ReplaceAll(s, "-", "")
+	s = strings.ReplaceAll(s, " ", "")
+	return strings.ToUpper(s)
+}
+
+// Validate checks that s is an ISBN-10 or ISBN-13, written with or without
+// hyphens and spaces.
+func Validate(s string) error {
+	cleaned := Clean(s)
+
+	switch len(cleaned) {
+	case 10:
+		return validateISBN10(cleaned)
+	case 13:
+		return validateISBN13(cleaned)
+	default:
+		return ErrInvalidLength
+	}
+}
+
+// Normalize returns the canonical form of an ISBN: the ISBN-13 without
+// hyphens or spaces. An ISBN-10 is converted to its 978 ISBN-13.
+func Normalize(s string) (string, error) {
+	if err := Validate(s); err != nil {
+		return "", err
+	}
+
+	cleaned := Clean(s)
+	if len(cleaned) == 10 {
+		return to13(cleaned), nil
+	}
+	return cleaned, nil
+}
+
+// Key returns the canonical form of s to look a book up by. A string that
+// is not an ISBN is returned as it is, so it finds nothing rather than
+// failing the lookup.
+func Key(s string) string {
+	canonical, err := Normalize(s)
+	if err != nil {
+		return s
+	}
+	return canonical
+}
+
+// ToISBN13 converts an ISBN-10 to its ISBN-13.
+func ToISBN13(isbn10 string) (string, error) {
+	cleaned := Clean(isbn10)
+	if len(cleaned) != 10 {
+		return "", ErrNotAnISBN10
+	}
+	if err := validateISBN10(cleaned); err != nil {
+		return "", err
+	}
+	return to13(cleaned), nil
+}
+
+// ToISBN10 converts an ISBN-13 to its ISBN-10. Only ISBN-13s starting with
+// 978 have one; those starting with 979 were never issued as ISBN-10s.
+func ToISBN10(isbn13 string) (string, error) {
+	cleaned := Clean(isbn13)
+	if len(cleaned) != 13 {
+		return "", ErrNotAnISBN13
+	}
+	if err := validateISBN13(cleaned); err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(cleaned, bookland) {
+		return "", ErrNoISBN10Form
+	}
+
+	body := cleaned[3:12]
+	return body + isbn10CheckDigit(body), nil
+}
+
+// to13 converts a valid, cleaned ISBN-10.
+func to13(isbn10 string) string {
+	body := bookland + isbn10[:9]
+	return body + isbn13CheckDigit(body)
+}
+
+// isbn10CheckDigit computes the check character of the first nine digits of
+// an ISBN-10.
+func isbn10CheckDigit(body string) string {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return "X"
+	}
+	return fmt.Sprint(check)
+}
+
+// isbn13CheckDigit computes the check digit of the first twelve digits of
+// an ISBN-13.
+func isbn13CheckDigit(body string) string {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := int(body[i] - '0')
+		if i%2 == 0 {
+			sum += digit
+		} else {
+			sum += digit * 3
+		}
+	}
+	return fmt.Sprint((10 - sum%10) % 10)
+}
+
+func validateISBN10(isbn string) error {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		digit := int(isbn[i] - '0')
+		if digit < 0 || digit > 9 {
+			return ErrInvalidISBN10
+		}
+		sum += digit * (10 - i)
+	}
+
+	lastChar := isbn[9]
+	var lastDigit int
+	if lastChar == 'X' {
+		lastDigit = 10
+	} else {
+		lastDigit = int(lastChar - '0')
+		if lastDigit < 0 || lastDigit > 9 {
+			return ErrInvalidISBN10
+		}
+	}
+	sum += lastDigit
+
+	if sum%11 != 0 {
+		return ErrISBN10Checksum
+	}
+	return nil
+}
+
+func validateISBN13(isbn string) error {
+	sum := 0
+	for i := 0; i < 13; i++ {
+		digit := int(isbn[i] - '0')
+		if digit < 0 || digit > 9 {
+			return ErrInvalidISBN13
+		}
+		if i%2 == 0 {
+			sum += digit
+		} else {
+			sum += digit * 3
+		}
+	}
+
+	if sum%10 != 0 {
+		return ErrISBN13Checksum
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package isbn
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  string
+		expectErr error
+	}{
+		{name: "Bare ISBN-13", input: "9780134685991", expected: "9780134685991"},
+		{name: "Hyphenated ISBN-13", input: "978-0-13-468599-1", expected: "9780134685991"},
+		{name: "ISBN-13 with spaces", input: "978 0 13 468599 1", expected: "9780134685991"},
+		{name: "ISBN-10", input: "0134685997", expected: "9780134685991"},
+		{name: "Hyphenated ISBN-10", input: "0-306-40615-2", expected: "9780306406157"},
+		{name: "ISBN-10 with X check digit", input: "0-8044-2957-x", expected: "9780804429573"},
+		{name: "979 ISBN-13", input: "979-10-90636-07-1", expected: "9791090636071"},
+		{name: "Wrong length", input: "978-0-13-46859", expectErr: ErrInvalidLength},
+		{name: "Bad ISBN-10 checksum", input: "0-306-40615-3", expectErr: ErrISBN10Checksum},
+		{name: "Bad ISBN-13 checksum", input: "978-0-13-468599-2", expectErr: ErrISBN13Checksum},
+		{name: "X inside an ISBN-10", input: "0X06406152", expectErr: ErrInvalidISBN10},
+		{name: "Letters in an ISBN-13", input: "978013468599A", expectErr: ErrInvalidISBN13},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			normalized, err := Normalize(tc.input)
+			if err != tc.expectErr {
+				t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+			}
+			if normalized != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, normalized)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	if key := Key("0-306-40615-2"); key != "9780306406157" {
+		t.Errorf("expected the canonical ISBN-13, got %q", key)
+	}
+	if key := Key("not-an-isbn"); key != "not-an-isbn" {
+		t.Errorf("expected an invalid ISBN to be kept as it is, got %q", key)
+	}
+}
+
+func TestConversion(t *testing.T) {
+	pairs := map[string]string{
+		"0134685997": "9780134685991",
+		"0306406152": "9780306406157",
+		"080442957X": "9780804429573",
+		"0000000000": "9780000000002",
+	}
+
+	for isbn10, isbn13 := range pairs {
+		if converted, err := ToISBN13(isbn10); err != nil || converted != isbn13 {
+			t.Errorf("ToISBN13(%s): expected %s, got %q (%v)", isbn10, isbn13, converted, err)
+		}
+		if converted, err := ToISBN10(isbn13); err != nil || converted != isbn10 {
+			t.Errorf("ToISBN10(%s): expected %s, got %q (%v)", isbn13, isbn10, converted, err)
+		}
+	}
+
+	if _, err := ToISBN10("9791090636071"); err != ErrNoISBN10Form {
+		t.Errorf("expected ErrNoISBN10Form for a 979 ISBN, got %v", err)
+	}
+	if _, err := ToISBN10("0306406152"); err != ErrNotAnISBN13 {
+		t.Errorf("expected ErrNotAnISBN13, got %v", err)
+	}
+	if _, err := ToISBN13("9780306406157"); err != ErrNotAnISBN10 {
+		t.Errorf("expected ErrNotAnISBN10, got %v", err)
+	}
+	if _, err := ToISBN13("0306406153"); err != ErrISBN10Checksum {
+		t.Errorf("expected ErrISBN10Checksum, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package isbn
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// rangeDigits is how many digits the rules of a range message compare.
+const rangeDigits = 7
+
+var (
+	ErrUnassignedRange = errors.New("invalid ISBN: it falls in a range that has not been assigned")
+	ErrNoRanges        = errors.New("ISBN ranges are not loaded")
+)
+
+// Ranges tells how ISBNs divide into their parts, as published by the
+// International ISBN Agency in its RangeMessage.xml. Each prefix is divided
+// into registration groups, each serving a country or language area, and
+// each group into the ranges of its registrants.
+type Ranges struct {
+	// Date is the date the range message was issued.
+	Date     string
+	prefixes map[string][]rule
+	groups   map[string]group
+}
+
+type group struct {
+	agency string
+	rules  []rule
+}
+
+// rule gives the length of the part that starts with a number from low to
+// high. A length of zero marks a range that has not been assigned.
+type rule struct {
+	low    int
+	high   int
+	length int
+}
+
+// Parts are the parts of an ISBN-13, which are printed separated by hyphens.
+type Parts struct {
+	Prefix      string
+	Group       string
+	Registrant  string
+	Publication string
+	CheckDigit  string
+	// Agency is the country or language area the registration group serves.
+	Agency string
+}
+
+// String returns the hyphenated ISBN-13.
+func (p Parts) String() string {
+	return strings.Join([]string{p.Prefix, p.Group, p.Registrant, p.Publication, p.CheckDigit}, "-")
+}
+
+// ISBN10 returns the hyphenated ISBN-10, which only ISBNs with the 978
+// prefix have.
+func (p Parts) ISBN10() (string, bool) {
+	if p.Prefix != bookland {
+		return "", false
+	}
+
+	body := p.Group + p.Registrant + p.Publication
+	return strings.Join([]string{p.Group, p.Registrant, p.Publication, isbn10CheckDigit(body)}, "-"), true
+}
+
+type rangeMessage struct {
+	Date     string         `xml:"MessageDate"`
+	Prefixes []rangeElement `xml:"EAN.UCCPrefixes>EAN.UCC"`
+	Groups   []rangeElement `xml:"RegistrationGroups>Group"`
+}
+
+type rangeElement struct {
+	Prefix string `xml:"Prefix"`
+	Agency string `xml:"Agency"`
+	Rules  []struct {
+		Range  string `xml:"Range"`
+		Length int    `xml:"Length"`
+	} `xml:"Rules>Rule"`
+}
+
+// LoadRanges reads a RangeMessage.xml file.
+func LoadRanges(path string) (*Ranges, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ISBN ranges: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return ParseRanges(file)
+}
+
+// ParseRanges reads a range message in the XML format of RangeMessage.xml.
+func ParseRanges(r io.Reader) (*Ranges, error) {
+	var message rangeMessage
+	if err := xml.NewDecoder(r).Decode(&message); err != nil {
+		return nil, fmt.Errorf("failed to parse ISBN ranges: %w", err)
+	}
+	if len(message.Prefixes) == 0 {
+		return nil, errors.New("failed to parse ISBN ranges: no prefixes are listed")
+	}
+
+	ranges := &Ranges{
+		Date:     strings.TrimSpace(message.Date),
+		prefixes: make(map[string][]rule, len(message.Prefixes)),
+		groups:   make(map[string]group, len(message.Groups)),
+	}
+	for _, element := range message.Prefixes {
+		rules, err := element.rules()
+		if err != nil {
+			return nil, err
+		}
+		ranges.prefixes[strings.TrimSpace(element.Prefix)] = rules
+	}
+	for _, element := range message.Groups {
+		rules, err := element.rules()
+		if err != nil {
+			return nil, err
+		}
+		ranges.groups[strings.TrimSpace(element.Prefix)] = group{
+			agency: strings.TrimSpace(element.Agency),
+			rules:  rules,
+		}
+	}
+
+	return ranges, nil
+}
+
+func (e rangeElement) rules() ([]rule, error) {
+	rules := make([]rule, 0, len(e.Rules))
+	for _, r := range e.Rules {
+		low, high, found := strings.Cut(strings.TrimSpace(r.Range), "-")
+		lowValue, lowErr := strconv.Atoi(low)
+		highValue, highErr := strconv.Atoi(high)
+		if !found || len(low) != rangeDigits || len(high) != rangeDigits || lowErr != nil || highErr != nil || lowValue > highValue {
+			return nil, fmt.Errorf("failed to parse ISBN ranges: invalid range %q under %s", r.Range, e.Prefix)
+		}
+		if r.Length < 0 || r.Length > rangeDigits {
+			return nil, fmt.Errorf("failed to parse ISBN ranges: invalid length %d under %s", r.Length, e.Prefix)
+		}
+		rules = append(rules, rule{low: lowValue, high: highValue, length: r.Length})
+	}
+	return rules, nil
+}
+
+// Split divides an ISBN, given in any form, into its parts. An ISBN whose
+// registration group or registrant falls in a range that has not been
+// assigned is refused with ErrUnassignedRange.
+func (r *Ranges) Split(s string) (Parts, error) {
+	if r == nil {
+		return Parts{}, ErrNoRanges
+	}
+
+	canonical, err := Normalize(s)
+	if err != nil {
+		return Parts{}, err
+	}
+
+	parts := Parts{Prefix: canonical[:3], CheckDigit: canonical[12:]}
+	rest := canonical[3:12]
+
+	rules, ok := r.prefixes[parts.Prefix]
+	if !ok {
+		return Parts{}, ErrUnassignedRange
+	}
+	length := partLength(rules, rest)
+	if length == 0 {
+		return Parts{}, ErrUnassignedRange
+	}
+	parts.Group, rest = rest[:length], rest[length:]
+
+	group, ok := r.groups[parts.Prefix+"-"+parts.Group]
+	if !ok {
+		return Parts{}, ErrUnassignedRange
+	}
+	length = partLength(group.rules, rest)
+	if length == 0 || length >= len(rest) {
+		return Parts{}, ErrUnassignedRange
+	}
+	parts.Registrant, parts.Publication = rest[:length], rest[length:]
+	parts.Agency = group.agency
+
+	return parts, nil
+}
+
+// Hyphenate returns an ISBN, given in any form, as a hyphenated ISBN-13.
+func (r *Ranges) Hyphenate(s string) (string, error) {
+	parts, err := r.Split(s)
+	if err != nil {
+		return "", err
+	}
+	return parts.String(), nil
+}
+
+// partLength finds the length of the part digits start with; the rules
+// compare their first seven digits, padded with zeros.
+func partLength(rules []rule, digits string) int {
+	key := digits + strings.Repeat("0", rangeDigits)
+	value, err := strconv.Atoi(key[:rangeDigits])
+	if err != nil {
+		return 0
+	}
+
+	for _, rule := range rules {
+		if value >= rule.low && value <= rule.high {
+			return rule.length
+		}
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+package isbn
+
+import (
+	"strings"
+	"testing"
+)
+
+func loadTestRanges(t *testing.T) *Ranges {
+	t.Helper()
+	ranges, err := LoadRanges("testdata/RangeMessage.xml")
+	if err != nil {
+		t.Fatalf("failed to load ranges: %v", err)
+	}
+	return ranges
+}
+
+// withCheckDigit completes the first twelve digits of an ISBN-13.
+func withCheckDigit(body string) string {
+	return body + isbn13CheckDigit(body)
+}
+
+func TestHyphenate(t *testing.T) {
+	ranges := loadTestRanges(t)
+	if ranges.Date != "Thu, 1 Oct 2026 09:00:00 BST" {
+		t.Errorf("unexpected message date %q", ranges.Date)
+	}
+
+	tests := []struct {
+		input     string
+		expected  string
+		agency    string
+		expectErr error
+	}{
+		{input: "9780134685991", expected: "978-0-13-468599-1", agency: "English language"},
+		{input: "9780306406157", expected: "978-0-306-40615-7", agency: "English language"},
+		{input: "0-8044-2957-X", expected: "978-0-8044-2957-3", agency: "English language"},
+		{input: "9780000000002", expected: "978-0-00-000000-2", agency: "English language"},
+		{input: withCheckDigit("978064800001"), expected: "978-0-6480000-1-3", agency: "English language"},
+		{input: "978-3-16-148410-0", expected: "978-3-16-148410-0", agency: "German language"},
+		{input: "979-10-90636-07-1", expected: "979-10-90636-07-1", agency: "France"},
+		{input: withCheckDigit("978650100000"), expected: "978-65-01-00000-8", agency: "Brazil"},
+		{input: withCheckDigit("978680000000"), expectErr: ErrUnassignedRange},
+		{input: withCheckDigit("978650200000"), expectErr: ErrUnassignedRange},
+		{input: withCheckDigit("979000000000"), expectErr: ErrUnassignedRange},
+		{input: withCheckDigit("978200000000"), expectErr: ErrUnassignedRange},
+		{input: "978-0-13-468599-2", expectErr: ErrISBN13Checksum},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			parts, err := ranges.Split(tc.input)
+			if err != tc.expectErr {
+				t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+			}
+			if tc.expectErr != nil {
+				return
+			}
+			if parts.String() != tc.expected || parts.Agency != tc.agency {
+				t.Errorf("expected %s (%s), got %s (%s)", tc.expected, tc.agency, parts, parts.Agency)
+			}
+		})
+	}
+}
+
+func TestPartsISBN10(t *testing.T) {
+	ranges := loadTestRanges(t)
+
+	parts, _ := ranges.Split("9780134685991")
+	if isbn10, ok := parts.ISBN10(); !ok || isbn10 != "0-13-468599-7" {
+		t.Errorf("expected 0-13-468599-7, got %q", isbn10)
+	}
+
+	parts, _ = ranges.Split("9780804429573")
+	if isbn10, ok := parts.ISBN10(); !ok || isbn10 != "0-8044-2957-X" {
+		t.Errorf("expected 0-8044-2957-X, got %q", isbn10)
+	}
+
+	parts, _ = ranges.Split("9791090636071")
+	if _, ok := parts.ISBN10(); ok {
+		t.Errorf("expected a 979 ISBN to have no ISBN-10")
+	}
+}
+
+func TestRangesNotLoaded(t *testing.T) {
+	var ranges *Ranges
+	if _, err := ranges.Hyphenate("9780134685991"); err != ErrNoRanges {
+		t.Errorf("expected ErrNoRanges, got %v", err)
+	}
+}
+
+func TestParseRangesErrors(t *testing.T) {
+	tests := map[string]string{
+		"not XML":     "not xml",
+		"no prefixes": `<ISBNRangeMessage></ISBNRangeMessage>`,
+		"bad range": `<ISBNRangeMessage><EAN.UCCPrefixes><EAN.UCC><Prefix>978</Prefix>
+			<Rules><Rule><Range>5999999-0000000</Range><Length>1</Length></Rule></Rules>
+			</EAN.UCC></EAN.UCCPrefixes></ISBNRangeMessage>`,
+		"bad length": `<ISBNRangeMessage><EAN.UCCPrefixes><EAN.UCC><Prefix>978</Prefix>
+			<Rules><Rule><Range>0000000-5999999</Range><Length>8</Length></Rule></Rules>
+			</EAN.UCC></EAN.UCCPrefixes></ISBNRangeMessage>`,
+	}
+
+	for name, message := range tests {
+		if _, err := ParseRanges(strings.NewReader(message)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if _, err := LoadRanges("testdata/missing.xml"); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+
+	"books/core/library/models"
+	"books/core/library/repositories"
+	storage_models "books/core/storage/models"
+)
+
+// shelfCopies returns the copies of a book that are in circulation and not
+// on loan, together with the book's open rentals.
+func shelfCopies(ctx context.Context, repo repositories.BookRepository, bookID string) ([]*storage_models.Copy, []*models.BookRental, error) {
+	copies, err := repo.GetCopiesByISBN(ctx, bookID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activeRentals, err := repo.GetActiveBookRentalsByBookID(ctx, bookID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	onLoan := make(map[string]bool, len(activeRentals))
+	for _, rental := range activeRentals {
+		onLoan[rental.CopyID] = true
+	}
+
+	available := make([]*storage_models.Copy, 0, len(copies))
+	for _, bookCopy := range copies {
+		if bookCopy.InCirculation() && !onLoan[bookCopy.ID] {
+			available = append(available, bookCopy)
+		}
+	}
+
+	return available, activeRentals, nil
+}
+
+// setAside matches every ready hold with a copy on the shelf at its pickup
+// branch. It returns the copy kept for each hold by hold ID, and the copies
+// left free.
+func setAside(onShelf []*storage_models.Copy, holds []*models.Hold) (map[string]*storage_models.Copy, []*storage_models.Copy) {
+	free := append([]*storage_models.Copy(nil), onShelf...)
+	held := make(map[string]*storage_models.Copy)
+
+	for _, hold := range holds {
+		if !hold.IsReady() {
+			continue
+		}
+		if i := servingCopy(free, hold); i >= 0 {
+			held[hold.ID] = free[i]
+			free = append(free[:i], free[i+1:]...)
+		}
+	}
+
+	return held, free
+}
+
+// servingCopy returns the index of the first copy at the hold's pickup
+// branch, or -1.
+func servingCopy(copies []*storage_models.Copy, hold *models.Hold) int {
+	for i, bookCopy := range copies {
+		if hold.CanBeServedBy(bookCopy) {
+			return i
+		}
+	}
+	return -1
+}
+
+// copyAt returns the first copy at the branch, or any copy when no branch is
+// given.
+func copyAt(copies []*storage_models.Copy, branchID string) *storage_models.Copy {
+	for _, bookCopy := range copies {
+		if branchID == "" || bookCopy.IsAt(branchID) {
+			return bookCopy
+		}
+	}
+	return nil
+}
+
+// readyHold returns the user's ready hold, if any.
+func readyHold(holds []*models.Hold, userID string) *models.Hold {
+	for _, hold := range holds {
+		if hold.IsReady() && hold.UserID == userID {
+			return hold
+		}
+	}
+	return nil
+}
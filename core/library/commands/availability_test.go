@@ -0,0 +1,129 @@
+package commands
+
+import (
+	calendar_repositories "books/core/calendar/repositories"
+	"books/core/library/errors"
+	"books/core/library/repositories"
+	storage_models "books/core/storage/models"
+	storage_repositories "books/core/storage/repositories"
+	"context"
+	stderrors "errors"
+	"testing"
+)
+
+type multiCopyFixture struct {
+	rentals *repositories.BookRentalInMemoryRepository
+	holds   *repositories.HoldInMemoryRepository
+	copies  *storage_repositories.CopyStorageInMemoryRepository
+	ledger  *repositories.LedgerInMemoryRepository
+	queue   *HoldQueue
+	rent    *BookRentalCommandHandler
+	ret     *ReturnBookCommandHandler
+	place   *PlaceHoldCommandHandler
+}
+
+// newMultiCopyFixture stocks book1 with the given number of copies.
+func newMultiCopyFixture(t *testing.T, copies int) *multiCopyFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	books := storage_repositories.NewBookStorageInMemoryRepository()
+	if err := books.Save(ctx, &storage_models.Book{ISBN: "book1", Title: "Test Book"}); err != nil {
+		t.Fatalf("failed to save book: %v", err)
+	}
+
+	copyRepo := storage_repositories.NewCopyStorageInMemoryRepository()
+	for i := 1; i <= copies; i++ {
+		bookCopy, err := storage_models.NewCopy("book1", storage_models.DefaultBarcode("book1", i), "", "")
+		if err != nil {
+			t.Fatalf("failed to create copy: %v", err)
+		}
+		if err := copyRepo.SaveCopy(ctx, bookCopy); err != nil {
+			t.Fatalf("failed to save copy: %v", err)
+		}
+	}
+
+	rentals := repositories.NewBookRentalInMemoryRepository(books, copyRepo)
+	holds := repositories.NewHoldInMemoryRepository()
+	mover := newTestCopyMover(copyRepo)
+	queue := NewHoldQueue(holds, rentals, mover, testPickupWindow)
+	ledger := repositories.NewLedgerInMemoryRepository()
+
+	return &multiCopyFixture{
+		rentals: rentals,
+		holds:   holds,
+		copies:  copyRepo,
+		ledger:  ledger,
+		queue:   queue,
+		rent:    NewBookRentalCommandHandler(rentals, testPatrons("user1", "user2", "user3", "user4"), ledger, testLendingRules(), queue, calendar_repositories.NewCalendarInMemoryRepository()),
+		ret:     NewReturnBookCommandHandler(rentals, ledger, testLoanPolicies(), queue, mover),
+		place:   NewPlaceHoldCommandHandler(rentals, queue, mover),
+	}
+}
+
+func (f *multiCopyFixture) borrow(t *testing.T, userID string) error {
+	t.Helper()
+	return f.rent.Handle(context.Background(), BookRentalCommand{BookID: "book1", UserID: userID})
+}
+
+func TestRentalsAreSpreadOverCopies(t *testing.T) {
+	f := newMultiCopyFixture(t, 2)
+
+	if err := f.borrow(t, "user1"); err != nil {
+		t.Fatalf("expected first copy to be lent, got %v", err)
+	}
+	if err := f.borrow(t, "user2"); err != nil {
+		t.Fatalf("expected second copy to be lent, got %v", err)
+	}
+	if err := f.borrow(t, "user3"); !stderrors.Is(err, errors.ErrBookAlreadyBorrowed) {
+		t.Errorf("expected all copies to be out, got %v", err)
+	}
+
+	active, err := f.rentals.GetActiveBookRentalsByBookID(context.Background(), "book1")
+	if err != nil {
+		t.Fatalf("failed to load rentals: %v", err)
+	}
+	if len(active) != 2 || active[0].CopyID == "" || active[0].CopyID == active[1].CopyID {
+		t.Errorf("expected two rentals on different copies, got %+v", active)
+	}
+}
+
+func TestHoldsWaitForEveryCopy(t *testing.T) {
+	f := newMultiCopyFixture(t, 2)
+	ctx := context.Background()
+
+	if err := f.borrow(t, "user1"); err != nil {
+		t.Fatalf("failed to borrow: %v", err)
+	}
+
+	err := f.place.Handle(ctx, PlaceHoldCommand{BookID: "book1", UserID: "user3"})
+	if !stderrors.Is(err, errors.ErrBookAvailable) {
+		t.Errorf("expected hold to be refused while a copy is on the shelf, got %v", err)
+	}
+
+	if err := f.borrow(t, "user2"); err != nil {
+		t.Fatalf("failed to borrow: %v", err)
+	}
+	if err := f.place.Handle(ctx, PlaceHoldCommand{BookID: "book1", UserID: "user3"}); err != nil {
+		t.Fatalf("expected hold once every copy is out, got %v", err)
+	}
+
+	if err := f.ret.Handle(ctx, ReturnBookCommand{BookID: "book1", UserID: "user2"}); err != nil {
+		t.Fatalf("failed to return: %v", err)
+	}
+
+	holds, err := f.holds.GetActiveHoldsByBookID(ctx, "book1")
+	if err != nil {
+		t.Fatalf("failed to load holds: %v", err)
+	}
+	if len(holds) != 1 || !holds[0].IsReady() {
+		t.Fatalf("expected returned copy to be set aside, got %+v", holds)
+	}
+
+	if err := f.borrow(t, "user4"); !stderrors.Is(err, errors.ErrBookOnHold) {
+		t.Errorf("expected copy held for user3 to be refused, got %v", err)
+	}
+	if err := f.borrow(t, "user3"); err != nil {
+		t.Errorf("expected user3 to pick up the held copy, got %v", err)
+	}
+}
@@ -4,23 +4,50 @@ import (
 	"context"
 	stderrors "errors"
 
+	calendar_models "books/core/calendar/models"
+	"books/core/isbn"
 	"books/core/library/errors"
 	"books/core/library/models"
+	"books/core/library/policies"
 	"books/core/library/repositories"
+	patron_errors "books/core/patrons/errors"
+	patron_models "books/core/patrons/models"
+	patron_repositories "books/core/patrons/repositories"
+	storage_models "books/core/storage/models"
 )
 
+// CalendarSource loads the opening calendar that due dates are computed from.
+type CalendarSource interface {
+	GetCalendar(ctx context.Context) (*calendar_models.Calendar, error)
+}
+
 type BookRentalCommand struct {
 	ID     string
 	BookID string
 	UserID string
+	// BranchID is the branch the book is borrowed at. Empty lends a copy
+	// from any branch.
+	BranchID string
 }
 
 type BookRentalCommandHandler struct {
-	repo repositories.BookRepository
+	repo     repositories.BookRepository
+	patrons  patron_repositories.PatronRepository
+	ledger   repositories.LedgerRepository
+	rules    policies.LendingRules
+	queue    *HoldQueue
+	calendar CalendarSource
 }
 
-func NewBookRentalCommandHandler(repo repositories.BookRepository) *BookRentalCommandHandler {
-	return &BookRentalCommandHandler{repo: repo}
+func NewBookRentalCommandHandler(repo repositories.BookRepository, patrons patron_repositories.PatronRepository, ledger repositories.LedgerRepository, rules policies.LendingRules, queue *HoldQueue, calendar CalendarSource) *BookRentalCommandHandler {
+	return &BookRentalCommandHandler{
+		repo:     repo,
+		patrons:  patrons,
+		ledger:   ledger,
+		rules:    rules,
+		queue:    queue,
+		calendar: calendar,
+	}
 }
 
 func (h *BookRentalCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
@@ -28,42 +55,133 @@ func (h *BookRentalCommandHandler) Handle(ctx context.Context, cmd interface{})
 	if !ok {
 		return stderrors.New("invalid command type")
 	}
+	command.BookID = isbn.Key(command.BookID)
 
-	exists, err := h.repo.BookExists(ctx, command.BookID)
+	patron, err := h.patrons.GetPatronByID(ctx, command.UserID)
 	if err != nil {
 		return err
 	}
 
-	if !exists {
-		return stderrors.New("book not found in storage")
+	switch patron.CurrentStatus() {
+	case patron_models.PatronStatusSuspended:
+		return patron_errors.ErrPatronSuspended
+	case patron_models.PatronStatusExpired:
+		return patron_errors.ErrPatronExpired
 	}
 
-	activeRental, err := h.repo.GetActiveBookRentalByBookID(ctx, command.BookID)
-	if err != nil && !stderrors.Is(err, errors.ErrNotFound) {
+	book, err := h.repo.GetBookByISBN(ctx, command.BookID)
+	if stderrors.Is(err, errors.ErrNotFound) {
+		return errors.ErrBookNotInStorage
+	}
+	if err != nil {
 		return err
 	}
 
-	if activeRental != nil && !activeRental.IsReturned() {
-		return stderrors.New("book is already borrowed by someone else")
+	entries, err := h.ledger.GetUserLedgerEntries(ctx, command.UserID)
+	if err != nil {
+		return err
+	}
+	if models.NewFineAccount(command.UserID, entries).BalanceCents > h.rules.MaxUnpaidFinesCents {
+		return errors.ErrFinesOutstanding
+	}
+
+	// Copies set aside for holds may only go to the patrons they are held for.
+	holds, err := h.queue.Sync(ctx, command.BookID)
+	if err != nil {
+		return err
 	}
 
-	userRentals, err := h.repo.GetAllUserRentals(ctx, command.UserID)
+	onShelf, _, err := shelfCopies(ctx, h.repo, command.BookID)
 	if err != nil {
 		return err
 	}
 
-	for _, rental := range userRentals {
-		if rental.BookID == command.BookID && !rental.IsReturned() {
-			return stderrors.New("you already have this book, please return it before renting again")
+	if len(onShelf) == 0 {
+		return errors.ErrBookAlreadyBorrowed
+	}
+
+	held, free := setAside(onShelf, holds)
+	userHold := readyHold(holds, command.UserID)
+
+	var bookCopy *storage_models.Copy
+	if userHold != nil {
+		bookCopy = held[userHold.ID]
+	}
+	if bookCopy == nil {
+		if len(free) == 0 {
+			return errors.ErrBookOnHold
 		}
+		bookCopy = copyAt(free, command.BranchID)
+	}
+	if bookCopy == nil || (command.BranchID != "" && !bookCopy.IsAt(command.BranchID)) {
+		return errors.ErrNotAtBranch
+	}
+
+	openRentals, err := h.repo.GetActiveUserRentals(ctx, command.UserID)
+	if err != nil {
+		return err
+	}
+
+	policy := h.rules.Loans.Resolve(book.Category, patron.PatronType)
+	if err := h.checkBorrowingLimits(openRentals, command.BookID, patron.PatronType, policy); err != nil {
+		return err
+	}
+
+	calendar, err := h.calendar.GetCalendar(ctx)
+	if err != nil {
+		return err
 	}
 
-	rental := models.NewBookRental(command.BookID, command.UserID)
+	rental := models.NewBookRentalForPeriod(command.BookID, command.UserID, policy.LoanPeriod(), *calendar)
+	rental.ID = command.ID
+	rental.CopyID = bookCopy.ID
+	rental.PatronType = patron.PatronType
 
 	err = h.repo.SaveBookRental(ctx, rental)
 	if err != nil {
 		return err
 	}
 
+	if userHold != nil {
+		return h.queue.Fulfill(ctx, userHold)
+	}
+
+	return nil
+}
+
+// checkBorrowingLimits refuses a new loan while the patron has an overdue
+// item, already has this book, or has reached either the cap for their patron
+// type or the loan policy's concurrent loan limit.
+func (h *BookRentalCommandHandler) checkBorrowingLimits(openRentals []*models.BookRental, bookID, patronType string, policy policies.LoanPolicy) error {
+	overdue := 0
+	for _, rental := range openRentals {
+		if rental.BookID == bookID {
+			return errors.ErrBookAlreadyRented
+		}
+		if rental.IsOverdue() {
+			overdue++
+		}
+	}
+
+	if overdue > 0 {
+		return &errors.BorrowingBlockedError{
+			Reason:       errors.BlockedByOverdueItems,
+			OpenLoans:    len(openRentals),
+			OverdueLoans: overdue,
+		}
+	}
+
+	limit := policy.MaxConcurrentLoans
+	if typeLimit, ok := h.rules.BorrowingLimits.Limit(patronType); ok && typeLimit < limit {
+		limit = typeLimit
+	}
+	if len(openRentals) >= limit {
+		return &errors.BorrowingBlockedError{
+			Reason:    errors.BlockedByLoanLimit,
+			Limit:     limit,
+			OpenLoans: len(openRentals),
+		}
+	}
+
 	return nil
 }
@@ -1,18 +1,25 @@
 package commands
 
 import (
+	calendar_repositories "books/core/calendar/repositories"
 	"books/core/library/errors"
 	"books/core/library/models"
+	"books/core/library/policies"
 	"books/core/library/repositories"
+	patron_errors "books/core/patrons/errors"
+	patron_models "books/core/patrons/models"
+	patron_repositories "books/core/patrons/repositories"
 	storage_models "books/core/storage/models"
 	"context"
 	stderrors "errors"
 	"testing"
+	"time"
 )
 
 // Mock repository for testing
 type mockBookRepository struct {
 	books       map[string]*storage_models.Book
+	copies      map[string][]*storage_models.Copy
 	rentals     map[string]*models.BookRental
 	userRentals map[string][]*models.BookRental
 	saveErr     error
@@ -21,6 +28,7 @@ type mockBookRepository struct {
 func newMockRepository() *mockBookRepository {
 	return &mockBookRepository{
 		books:       make(map[string]*storage_models.Book),
+		copies:      make(map[string][]*storage_models.Copy),
 		rentals:     make(map[string]*models.BookRental),
 		userRentals: make(map[string][]*models.BookRental),
 	}
@@ -30,7 +38,7 @@ func newMockRepository() *mockBookRepository {
 func (m *mockBookRepository) GetBookByISBN(ctx context.Context, isbn string) (*storage_models.Book, error) {
 	book, exists := m.books[isbn]
 	if !exists {
-		return nil, stderrors.New("book not found")
+		return nil, errors.ErrNotFound
 	}
 	return book, nil
 }
@@ -40,12 +48,66 @@ func (m *mockBookRepository) BookExists(ctx context.Context, isbn string) (bool,
 	return exists, nil
 }
 
-func (m *mockBookRepository) GetActiveBookRentalByBookID(ctx context.Context, bookID string) (*models.BookRental, error) {
-	rental, exists := m.rentals[bookID]
-	if !exists {
-		return nil, errors.ErrNotFound
+func (m *mockBookRepository) GetBookRentalByID(ctx context.Context, id string) (*models.BookRental, error) {
+	for _, rental := range m.rentals {
+		if rental.ID == id {
+			return rental, nil
+		}
+	}
+	return nil, errors.ErrNotFound
+}
+
+// defaultCopyID names the single copy a book has unless the test configures
+// its copies explicitly.
+func defaultCopyID(isbn string) string {
+	return isbn + "-copy"
+}
+
+func (m *mockBookRepository) GetCopiesByISBN(ctx context.Context, isbn string) ([]*storage_models.Copy, error) {
+	if copies, exists := m.copies[isbn]; exists {
+		return copies, nil
+	}
+	if _, exists := m.books[isbn]; !exists {
+		return []*storage_models.Copy{}, nil
+	}
+	return []*storage_models.Copy{{ID: defaultCopyID(isbn), ISBN: isbn, Status: storage_models.CopyStatusCirculating}}, nil
+}
+
+func (m *mockBookRepository) GetActiveBookRentalsByBookID(ctx context.Context, bookID string) ([]*models.BookRental, error) {
+	var active []*models.BookRental
+	for _, rental := range m.rentals {
+		if rental.BookID != bookID || rental.IsReturned() {
+			continue
+		}
+		if rental.CopyID == "" {
+			rental.CopyID = defaultCopyID(bookID)
+		}
+		// A copy, as the repositories return, so a failed update leaves the
+		// rental as it was.
+		c := *rental
+		active = append(active, &c)
+	}
+	return active, nil
+}
+
+func (m *mockBookRepository) GetActiveBookRentals(ctx context.Context) ([]*models.BookRental, error) {
+	var active []*models.BookRental
+	for _, rental := range m.rentals {
+		if !rental.IsReturned() {
+			active = append(active, rental)
+		}
+	}
+	return active, nil
+}
+
+func (m *mockBookRepository) GetAllBookRentals(ctx context.Context, bookID string) ([]*models.BookRental, error) {
+	var rentals []*models.BookRental
+	for _, rental := range m.rentals {
+		if rental.BookID == bookID {
+			rentals = append(rentals, rental)
+		}
 	}
-	return rental, nil
+	return rentals, nil
 }
 
 func (m *mockBookRepository) GetAllUserRentals(ctx context.Context, userID string) ([]*models.BookRental, error) {
@@ -56,6 +118,16 @@ func (m *mockBookRepository) GetAllUserRentals(ctx context.Context, userID strin
 	return rentals, nil
 }
 
+func (m *mockBookRepository) GetActiveUserRentals(ctx context.Context, userID string) ([]*models.BookRental, error) {
+	active := []*models.BookRental{}
+	for _, rental := range m.userRentals[userID] {
+		if !rental.IsReturned() {
+			active = append(active, rental)
+		}
+	}
+	return active, nil
+}
+
 func (m *mockBookRepository) SaveBookRental(ctx context.Context, rental *models.BookRental) error {
 	if m.saveErr != nil {
 		return m.saveErr
@@ -73,15 +145,79 @@ func (m *mockBookRepository) SaveBookRental(ctx context.Context, rental *models.
 	return nil
 }
 
+func (m *mockBookRepository) UpdateBookRental(ctx context.Context, rental *models.BookRental) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.rentals[rental.BookID] = rental
+	return nil
+}
+
+func (m *mockBookRepository) MarkLostRentalFound(ctx context.Context, id string) error {
+	rental, err := m.GetBookRentalByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rental.CloseReason != models.RentalLost {
+		return errors.ErrRentalNotLost
+	}
+	rental.CloseReason = models.RentalFound
+	return nil
+}
+
 // Verify the mock implements the interface
 var _ repositories.BookRepository = (*mockBookRepository)(nil)
 
 type bookRentalTestCase struct {
-	name        string
-	setupRepo   func(repo *mockBookRepository)
-	command     BookRentalCommand
-	expectError bool
-	errorMsg    string
+	name           string
+	setupRepo      func(repo *mockBookRepository)
+	setupPatrons   func(patrons *patron_repositories.PatronInMemoryRepository)
+	command        BookRentalCommand
+	expectError    bool
+	errorMsg       string
+	expectedPeriod time.Duration
+}
+
+// testPatrons registers an active adult patron for each of the given IDs.
+func testPatrons(userIDs ...string) *patron_repositories.PatronInMemoryRepository {
+	patrons := patron_repositories.NewPatronInMemoryRepository()
+	for _, userID := range userIDs {
+		setTestPatron(patrons, userID, "adult", patron_models.PatronStatusActive, time.Now().Add(policies.DefaultMembershipPeriod))
+	}
+	return patrons
+}
+
+func setTestPatron(patrons *patron_repositories.PatronInMemoryRepository, userID, patronType string, status patron_models.PatronStatus, expiresAt time.Time) {
+	patron := &patron_models.Patron{
+		ID:         userID,
+		CardNumber: "card-" + userID,
+		Name:       userID,
+		PatronType: patronType,
+		Status:     status,
+		ExpiresAt:  expiresAt,
+	}
+	if _, err := patrons.GetPatronByID(context.Background(), userID); err == nil {
+		_ = patrons.UpdatePatron(context.Background(), patron)
+		return
+	}
+	_ = patrons.SavePatron(context.Background(), patron)
+}
+
+func testLendingRules() policies.LendingRules {
+	return policies.LendingRules{
+		Loans:               testLoanPolicies(),
+		HoldPickupWindow:    testPickupWindow,
+		MaxUnpaidFinesCents: 500,
+	}
+}
+
+func testLoanPolicies() *policies.LoanPolicies {
+	loanPolicies, _ := policies.NewLoanPolicies(policies.DefaultLoanPolicy, []policies.LoanPolicy{
+		{Name: "reference", Category: "reference", LoanPeriodDays: 3, MaxConcurrentLoans: 5},
+		{Name: "staff", PatronType: "staff", LoanPeriodDays: 28, MaxRenewals: 5, MaxConcurrentLoans: 20},
+		{Name: "child", PatronType: "child", LoanPeriodDays: 14, MaxConcurrentLoans: 1},
+	})
+	return loanPolicies
 }
 
 func getBookRentalTestCases() []bookRentalTestCase {
@@ -95,7 +231,94 @@ func getBookRentalTestCases() []bookRentalTestCase {
 				BookID: "book1",
 				UserID: "user1",
 			},
-			expectError: false,
+			expectError:    false,
+			expectedPeriod: models.DefaultLoanPeriod,
+		},
+		{
+			name: "Loan period comes from the book category",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book", Category: "reference"}
+			},
+			command: BookRentalCommand{
+				BookID: "book1",
+				UserID: "user1",
+			},
+			expectError:    false,
+			expectedPeriod: 3 * 24 * time.Hour,
+		},
+		{
+			name: "Loan period comes from the patron type",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book", Category: "standard"}
+			},
+			setupPatrons: func(patrons *patron_repositories.PatronInMemoryRepository) {
+				setTestPatron(patrons, "user1", "staff", patron_models.PatronStatusActive, time.Now().Add(time.Hour))
+			},
+			command: BookRentalCommand{
+				BookID: "book1",
+				UserID: "user1",
+			},
+			expectError:    false,
+			expectedPeriod: 28 * 24 * time.Hour,
+		},
+		{
+			name: "Concurrent loan limit reached",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				repo.books["book2"] = &storage_models.Book{ISBN: "book2", Title: "Other Book"}
+				repo.userRentals["user1"] = []*models.BookRental{models.NewBookRental("book2", "user1")}
+			},
+			setupPatrons: func(patrons *patron_repositories.PatronInMemoryRepository) {
+				setTestPatron(patrons, "user1", "child", patron_models.PatronStatusActive, time.Now().Add(time.Hour))
+			},
+			command: BookRentalCommand{
+				BookID: "book1",
+				UserID: "user1",
+			},
+			expectError: true,
+			errorMsg:    "loan limit reached, please return a book before renting another",
+		},
+		{
+			name: "Unknown patron",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+			},
+			command: BookRentalCommand{
+				BookID: "book1",
+				UserID: "stranger",
+			},
+			expectError: true,
+			errorMsg:    patron_errors.ErrPatronNotFound.Error(),
+		},
+		{
+			name: "Suspended patron",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+			},
+			setupPatrons: func(patrons *patron_repositories.PatronInMemoryRepository) {
+				setTestPatron(patrons, "user1", "adult", patron_models.PatronStatusSuspended, time.Now().Add(time.Hour))
+			},
+			command: BookRentalCommand{
+				BookID: "book1",
+				UserID: "user1",
+			},
+			expectError: true,
+			errorMsg:    patron_errors.ErrPatronSuspended.Error(),
+		},
+		{
+			name: "Expired patron",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+			},
+			setupPatrons: func(patrons *patron_repositories.PatronInMemoryRepository) {
+				setTestPatron(patrons, "user1", "adult", patron_models.PatronStatusActive, time.Now().Add(-time.Hour))
+			},
+			command: BookRentalCommand{
+				BookID: "book1",
+				UserID: "user1",
+			},
+			expectError: true,
+			errorMsg:    patron_errors.ErrPatronExpired.Error(),
 		},
 		{
 			name:        "Invalid command type",
@@ -180,7 +403,11 @@ func TestBookRentalCommandHandler_Handle(t *testing.T) {
 			// Setup
 			repo := newMockRepository()
 			tc.setupRepo(repo)
-			handler := NewBookRentalCommandHandler(repo)
+			patrons := testPatrons("user1")
+			if tc.setupPatrons != nil {
+				tc.setupPatrons(patrons)
+			}
+			handler := NewBookRentalCommandHandler(repo, patrons, repositories.NewLedgerInMemoryRepository(), testLendingRules(), newTestHoldQueue(repo), calendar_repositories.NewCalendarInMemoryRepository())
 
 			// Execute
 			var err error
@@ -221,7 +448,71 @@ func TestBookRentalCommandHandler_Handle(t *testing.T) {
 				if rental.IsReturned() {
 					t.Errorf("expected rental to not be returned, but it was")
 				}
+				if tc.expectedPeriod != 0 {
+					period := rental.ReturnDeadline.Sub(rental.BorrowedAt)
+					if period != tc.expectedPeriod {
+						t.Errorf("expected loan period %v, got %v", tc.expectedPeriod, period)
+					}
+				}
+			}
+		})
+	}
+}
+func TestBookRentalBorrowingLimits(t *testing.T) {
+	rules := testLendingRules()
+	rules.BorrowingLimits = policies.BorrowingLimits{"adult": 2}
+
+	overdue := models.NewBookRental("book3", "user1")
+	overdue.ReturnDeadline = time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		rentals []*models.BookRental
+		want    *errors.BorrowingBlockedError
+	}{
+		{
+			name:    "under the patron type limit",
+			rentals: []*models.BookRental{models.NewBookRental("book2", "user1")},
+		},
+		{
+			name:    "patron type limit reached",
+			rentals: []*models.BookRental{models.NewBookRental("book2", "user1"), models.NewBookRental("book3", "user1")},
+			want:    &errors.BorrowingBlockedError{Reason: errors.BlockedByLoanLimit, Limit: 2, OpenLoans: 2},
+		},
+		{
+			name:    "overdue item",
+			rentals: []*models.BookRental{overdue},
+			want:    &errors.BorrowingBlockedError{Reason: errors.BlockedByOverdueItems, OpenLoans: 1, OverdueLoans: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newMockRepository()
+			repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+			repo.userRentals["user1"] = tc.rentals
+
+			handler := NewBookRentalCommandHandler(repo, testPatrons("user1"), repositories.NewLedgerInMemoryRepository(), rules, newTestHoldQueue(repo), calendar_repositories.NewCalendarInMemoryRepository())
+			err := handler.Handle(context.Background(), BookRentalCommand{BookID: "book1", UserID: "user1"})
+
+			if tc.want == nil {
+				if err != nil {
+					t.Errorf("expected no error but got: %v", err)
+				}
+				return
+			}
+
+			var blocked *errors.BorrowingBlockedError
+			if !stderrors.As(err, &blocked) {
+				t.Fatalf("expected a borrowing blocked error, got %v", err)
+			}
+			if *blocked != *tc.want {
+				t.Errorf("expected %+v, got %+v", *tc.want, *blocked)
 			}
 		})
 	}
-}
\ No newline at end of file
+
+	if !stderrors.Is(&errors.BorrowingBlockedError{Reason: errors.BlockedByOverdueItems}, errors.ErrOverdueItems) {
+		t.Errorf("expected overdue block to match ErrOverdueItems")
+	}
+}
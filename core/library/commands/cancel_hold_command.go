@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/library/errors"
+)
+
+type CancelHoldCommand struct {
+	HoldID string
+	UserID string
+}
+
+type CancelHoldCommandHandler struct {
+	queue *HoldQueue
+}
+
+func NewCancelHoldCommandHandler(queue *HoldQueue) *CancelHoldCommandHandler {
+	return &CancelHoldCommandHandler{queue: queue}
+}
+
+func (h *CancelHoldCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(CancelHoldCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	hold, err := h.queue.Get(ctx, command.HoldID)
+	if err != nil {
+		return err
+	}
+
+	if hold.UserID != command.UserID {
+		return errors.ErrHoldNotOwned
+	}
+
+	if !hold.IsActive() {
+		return errors.ErrHoldClosed
+	}
+
+	return h.queue.Cancel(ctx, hold)
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+
+	branch_repositories "books/core/branches/repositories"
+	"books/core/library/errors"
+	"books/core/library/models"
+	"books/core/library/repositories"
+	storage_models "books/core/storage/models"
+	"books/core/storage/repositories/interfaces"
+)
+
+// CopyMover keeps track of which branch copies are at. It is shared by the
+// return, hold and transfer handlers so that every move of a copy between
+// branches leaves a transfer record.
+type CopyMover struct {
+	copies    interfaces.CopyRepository
+	transfers repositories.TransferRepository
+	branches  branch_repositories.BranchRepository
+}
+
+func NewCopyMover(copies interfaces.CopyRepository, transfers repositories.TransferRepository, branches branch_repositories.BranchRepository) *CopyMover {
+	return &CopyMover{
+		copies:    copies,
+		transfers: transfers,
+		branches:  branches,
+	}
+}
+
+// CheckBranch returns ErrBranchNotFound unless the branch exists. An empty ID
+// names no branch and is accepted.
+func (m *CopyMover) CheckBranch(ctx context.Context, branchID string) error {
+	if branchID == "" {
+		return nil
+	}
+
+	_, err := m.branches.GetBranchByID(ctx, branchID)
+	return err
+}
+
+// CheckIn records that the copy is at the branch, as when it is returned there.
+func (m *CopyMover) CheckIn(ctx context.Context, copyID, branchID string) error {
+	bookCopy, err := m.copies.FindCopyByID(ctx, copyID)
+	if err != nil {
+		return err
+	}
+
+	bookCopy.CurrentBranchID = branchID
+	return m.copies.UpdateCopy(ctx, bookCopy)
+}
+
+// Send puts a copy on the shelf in transit to another branch.
+func (m *CopyMover) Send(ctx context.Context, bookCopy *storage_models.Copy, toBranchID string, reason models.TransferReason) (*models.Transfer, error) {
+	if !bookCopy.InCirculation() {
+		return nil, errors.ErrCopyNotInHouse
+	}
+	if bookCopy.CurrentBranchID == "" {
+		return nil, errors.ErrCopyWithoutBranch
+	}
+	if bookCopy.CurrentBranchID == toBranchID {
+		return nil, errors.ErrCopyAlreadyAtBranch
+	}
+
+	transfer := models.NewTransfer(bookCopy.ID, bookCopy.ISBN, bookCopy.CurrentBranchID, toBranchID, reason)
+
+	bookCopy.Status = storage_models.CopyStatusInTransit
+	if err := m.copies.UpdateCopy(ctx, bookCopy); err != nil {
+		return nil, err
+	}
+
+	if err := m.transfers.SaveTransfer(ctx, transfer); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// Receive closes the transfer and puts the copy back in circulation at its
+// destination.
+func (m *CopyMover) Receive(ctx context.Context, transfer *models.Transfer) error {
+	if !transfer.IsOpen() {
+		return errors.ErrTransferReceived
+	}
+
+	bookCopy, err := m.copies.FindCopyByID(ctx, transfer.CopyID)
+	if err != nil {
+		return err
+	}
+
+	transfer.Receive()
+	if err := m.transfers.UpdateTransfer(ctx, transfer); err != nil {
+		return err
+	}
+
+	bookCopy.CurrentBranchID = transfer.ToBranchID
+	if bookCopy.InTransit() {
+		bookCopy.Status = storage_models.CopyStatusCirculating
+	}
+	return m.copies.UpdateCopy(ctx, bookCopy)
+}
+
+// Inbound counts the copies of a book in transit to each branch.
+func (m *CopyMover) Inbound(ctx context.Context, bookID string) (map[string]int, error) {
+	transfers, err := m.transfers.GetOpenTransfersByBookID(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	inbound := make(map[string]int, len(transfers))
+	for _, transfer := range transfers {
+		inbound[transfer.ToBranchID]++
+	}
+	return inbound, nil
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	calendar_repositories "books/core/calendar/repositories"
+	"books/core/library/errors"
+	"books/core/library/models"
+	"books/core/library/repositories"
+	storage_models "books/core/storage/models"
+	"context"
+	stderrors "errors"
+	"testing"
+)
+
+type settleFineTestCase struct {
+	name          string
+	charged       int64
+	command       interface{}
+	expectErr     error
+	expectBalance int64
+}
+
+func getSettleFineTestCases() []settleFineTestCase {
+	return []settleFineTestCase{
+		{
+			name:          "Partial payment",
+			charged:       300,
+			command:       PayFineCommand{UserID: "user1", AmountCents: 100},
+			expectBalance: 200,
+		},
+		{
+			name:          "Full waiver",
+			charged:       300,
+			command:       WaiveFineCommand{UserID: "user1", AmountCents: 300, Note: "first offence"},
+			expectBalance: 0,
+		},
+		{
+			name:          "Overpayment",
+			charged:       300,
+			command:       PayFineCommand{UserID: "user1", AmountCents: 301},
+			expectErr:     errors.ErrAmountExceedsBalance,
+			expectBalance: 300,
+		},
+		{
+			name:          "Zero amount",
+			charged:       300,
+			command:       WaiveFineCommand{UserID: "user1"},
+			expectErr:     errors.ErrInvalidAmount,
+			expectBalance: 300,
+		},
+		{
+			name:          "Negative amount",
+			charged:       300,
+			command:       PayFineCommand{UserID: "user1", AmountCents: -50},
+			expectErr:     errors.ErrInvalidAmount,
+			expectBalance: 300,
+		},
+	}
+}
+
+func TestSettleFineCommandHandlers_Handle(t *testing.T) {
+	for _, tc := range getSettleFineTestCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			ledger := repositories.NewLedgerInMemoryRepository()
+			_ = ledger.SaveLedgerEntry(context.Background(), models.NewLedgerEntry("user1", models.LedgerEntryCharge, tc.charged, ""))
+
+			var err error
+			switch tc.command.(type) {
+			case PayFineCommand:
+				err = NewPayFineCommandHandler(ledger).Handle(context.Background(), tc.command)
+			case WaiveFineCommand:
+				err = NewWaiveFineCommandHandler(ledger).Handle(context.Background(), tc.command)
+			}
+
+			if !stderrors.Is(err, tc.expectErr) {
+				t.Errorf("expected error %v, got %v", tc.expectErr, err)
+			}
+
+			entries, _ := ledger.GetUserLedgerEntries(context.Background(), "user1")
+			if balance := models.NewFineAccount("user1", entries).BalanceCents; balance != tc.expectBalance {
+				t.Errorf("expected balance %d, got %d", tc.expectBalance, balance)
+			}
+		})
+	}
+}
+
+func TestBookRentalBlockedByUnpaidFines(t *testing.T) {
+	repo := newMockRepository()
+	repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+
+	ledger := repositories.NewLedgerInMemoryRepository()
+	handler := NewBookRentalCommandHandler(repo, testPatrons("user1", "user2"), ledger, testLendingRules(), newTestHoldQueue(repo), calendar_repositories.NewCalendarInMemoryRepository())
+
+	// A balance equal to the threshold still allows borrowing.
+	_ = ledger.SaveLedgerEntry(context.Background(), models.NewLedgerEntry("user1", models.LedgerEntryCharge, 500, ""))
+	_ = ledger.SaveLedgerEntry(context.Background(), models.NewLedgerEntry("user2", models.LedgerEntryCharge, 501, ""))
+
+	err := handler.Handle(context.Background(), BookRentalCommand{BookID: "book1", UserID: "user2"})
+	if !stderrors.Is(err, errors.ErrFinesOutstanding) {
+		t.Errorf("expected ErrFinesOutstanding, got %v", err)
+	}
+
+	if err := handler.Handle(context.Background(), BookRentalCommand{BookID: "book1", UserID: "user1"}); err != nil {
+		t.Errorf("expected patron at the threshold to borrow, got %v", err)
+	}
+}
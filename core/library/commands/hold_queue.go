@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"books/core/library/models"
+	"books/core/library/repositories"
+)
+
+// HoldQueue applies the first-come, first-served rules of a book's holds.
+// It is shared by the rental, return and hold handlers so they agree on who
+// is at the head of the queue.
+type HoldQueue struct {
+	holds        repositories.HoldRepository
+	books        repositories.BookRepository
+	mover        *CopyMover
+	pickupWindow time.Duration
+}
+
+func NewHoldQueue(holds repositories.HoldRepository, books repositories.BookRepository, mover *CopyMover, pickupWindow time.Duration) *HoldQueue {
+	return &HoldQueue{
+		holds:        holds,
+		books:        books,
+		mover:        mover,
+		pickupWindow: pickupWindow,
+	}
+}
+
+// Sync expires ready holds that were not picked up in time and sets copies on
+// the shelf aside for waiting patrons in queue order. A patron whose pickup
+// branch has no free copy is sent one from another branch, unless one is
+// already on its way there. It returns the remaining active holds in queue
+// order.
+func (q *HoldQueue) Sync(ctx context.Context, bookID string) ([]*models.Hold, error) {
+	holds, err := q.holds.GetActiveHoldsByBookID(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]*models.Hold, 0, len(holds))
+	for _, hold := range holds {
+		if hold.IsPickupExpired() {
+			hold.Expire()
+			if err := q.holds.UpdateHold(ctx, hold); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		active = append(active, hold)
+	}
+
+	onShelf, _, err := shelfCopies(ctx, q.books, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	inbound, err := q.mover.Inbound(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, free := setAside(onShelf, active)
+	for _, hold := range active {
+		if len(free) == 0 {
+			break
+		}
+		if hold.IsReady() {
+			continue
+		}
+
+		if i := servingCopy(free, hold); i >= 0 {
+			free = append(free[:i], free[i+1:]...)
+			hold.MarkReady(q.pickupWindow)
+			if err := q.holds.UpdateHold(ctx, hold); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if inbound[hold.PickupBranchID] > 0 {
+			inbound[hold.PickupBranchID]--
+			continue
+		}
+
+		sent := free[0]
+		free = free[1:]
+		if _, err := q.mover.Send(ctx, sent, hold.PickupBranchID, models.TransferHold); err != nil {
+			return nil, err
+		}
+	}
+
+	return active, nil
+}
+
+// HasHoldsByOthers implements HoldChecker.
+func (q *HoldQueue) HasHoldsByOthers(ctx context.Context, bookID, userID string) (bool, error) {
+	holds, err := q.holds.GetActiveHoldsByBookID(ctx, bookID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, hold := range holds {
+		if hold.UserID != userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (q *HoldQueue) Get(ctx context.Context, id string) (*models.Hold, error) {
+	return q.holds.GetHoldByID(ctx, id)
+}
+
+func (q *HoldQueue) Add(ctx context.Context, hold *models.Hold) error {
+	return q.holds.SaveHold(ctx, hold)
+}
+
+// Fulfill closes the hold once its patron has borrowed the book.
+func (q *HoldQueue) Fulfill(ctx context.Context, hold *models.Hold) error {
+	hold.Fulfill()
+	return q.holds.UpdateHold(ctx, hold)
+}
+
+// Cancel withdraws the hold. A cancelled ready hold hands its copy to the
+// next patron in line.
+func (q *HoldQueue) Cancel(ctx context.Context, hold *models.Hold) error {
+	wasReady := hold.IsReady()
+
+	hold.Cancel()
+	if err := q.holds.UpdateHold(ctx, hold); err != nil {
+		return err
+	}
+
+	if wasReady {
+		_, err := q.Sync(ctx, hold.BookID)
+		return err
+	}
+	return nil
+}
+
+var _ HoldChecker = (*HoldQueue)(nil)
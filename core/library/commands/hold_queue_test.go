@@ -0,0 +1,281 @@
+package commands
+
+import (
+	branch_repositories "books/core/branches/repositories"
+	calendar_repositories "books/core/calendar/repositories"
+	"books/core/library/errors"
+	"books/core/library/models"
+	"books/core/library/repositories"
+	storage_models "books/core/storage/models"
+	storage_repositories "books/core/storage/repositories"
+	"books/core/storage/repositories/interfaces"
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+)
+
+const testPickupWindow = 3 * 24 * time.Hour
+
+func newTestHoldQueue(books repositories.BookRepository) *HoldQueue {
+	return NewHoldQueue(repositories.NewHoldInMemoryRepository(), books, newTestCopyMover(storage_repositories.NewCopyStorageInMemoryRepository()), testPickupWindow)
+}
+
+func newTestCopyMover(copies interfaces.CopyRepository) *CopyMover {
+	return NewCopyMover(copies, repositories.NewTransferInMemoryRepository(), branch_repositories.NewBranchInMemoryRepository())
+}
+
+type holdTestFixture struct {
+	repo   *mockBookRepository
+	holds  *repositories.HoldInMemoryRepository
+	queue  *HoldQueue
+	rent   *BookRentalCommandHandler
+	ret    *ReturnBookCommandHandler
+	place  *PlaceHoldCommandHandler
+	cancel *CancelHoldCommandHandler
+}
+
+func newHoldTestFixture() *holdTestFixture {
+	repo := newMockRepository()
+	repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+
+	holds := repositories.NewHoldInMemoryRepository()
+	mover := newTestCopyMover(storage_repositories.NewCopyStorageInMemoryRepository())
+	queue := NewHoldQueue(holds, repo, mover, testPickupWindow)
+	ledger := repositories.NewLedgerInMemoryRepository()
+
+	return &holdTestFixture{
+		repo:   repo,
+		holds:  holds,
+		queue:  queue,
+		rent:   NewBookRentalCommandHandler(repo, testPatrons("user1", "user2", "user3"), ledger, testLendingRules(), queue, calendar_repositories.NewCalendarInMemoryRepository()),
+		ret:    NewReturnBookCommandHandler(repo, ledger, testLoanPolicies(), queue, mover),
+		place:  NewPlaceHoldCommandHandler(repo, queue, mover),
+		cancel: NewCancelHoldCommandHandler(queue),
+	}
+}
+
+func (f *holdTestFixture) lend(t *testing.T, userID string) {
+	t.Helper()
+	rental := models.NewBookRental("book1", userID)
+	f.repo.rentals["book1"] = rental
+	f.repo.userRentals[userID] = append(f.repo.userRentals[userID], rental)
+}
+
+func (f *holdTestFixture) placeHold(t *testing.T, userID string) {
+	t.Helper()
+	if err := f.place.Handle(context.Background(), PlaceHoldCommand{BookID: "book1", UserID: userID}); err != nil {
+		t.Fatalf("failed to place hold for %s: %v", userID, err)
+	}
+}
+
+func (f *holdTestFixture) queueOf(t *testing.T) []*models.Hold {
+	t.Helper()
+	holds, err := f.holds.GetActiveHoldsByBookID(context.Background(), "book1")
+	if err != nil {
+		t.Fatalf("failed to load holds: %v", err)
+	}
+	return holds
+}
+
+type placeHoldTestCase struct {
+	name      string
+	setup     func(t *testing.T, f *holdTestFixture)
+	command   interface{}
+	expectErr error
+}
+
+func getPlaceHoldTestCases() []placeHoldTestCase {
+	return []placeHoldTestCase{
+		{
+			name: "Hold on a borrowed book",
+			setup: func(t *testing.T, f *holdTestFixture) {
+				f.lend(t, "user1")
+			},
+			command: PlaceHoldCommand{BookID: "book1", UserID: "user2"},
+		},
+		{
+			name:      "Book is on the shelf",
+			setup:     func(t *testing.T, f *holdTestFixture) {},
+			command:   PlaceHoldCommand{BookID: "book1", UserID: "user2"},
+			expectErr: errors.ErrBookAvailable,
+		},
+		{
+			name:      "Book not in storage",
+			setup:     func(t *testing.T, f *holdTestFixture) {},
+			command:   PlaceHoldCommand{BookID: "missing", UserID: "user2"},
+			expectErr: errors.ErrBookNotInStorage,
+		},
+		{
+			name: "Borrower cannot hold their own loan",
+			setup: func(t *testing.T, f *holdTestFixture) {
+				f.lend(t, "user1")
+			},
+			command:   PlaceHoldCommand{BookID: "book1", UserID: "user1"},
+			expectErr: errors.ErrBookAlreadyRented,
+		},
+		{
+			name: "Second hold by the same patron",
+			setup: func(t *testing.T, f *holdTestFixture) {
+				f.lend(t, "user1")
+				f.placeHold(t, "user2")
+			},
+			command:   PlaceHoldCommand{BookID: "book1", UserID: "user2"},
+			expectErr: errors.ErrHoldAlreadyPlaced,
+		},
+		{
+			name:      "Invalid command type",
+			setup:     func(t *testing.T, f *holdTestFixture) {},
+			command:   "not a command",
+			expectErr: stderrors.New("invalid command type"),
+		},
+	}
+}
+
+func TestPlaceHoldCommandHandler_Handle(t *testing.T) {
+	for _, tc := range getPlaceHoldTestCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newHoldTestFixture()
+			tc.setup(t, f)
+
+			err := f.place.Handle(context.Background(), tc.command)
+
+			if tc.expectErr != nil {
+				if err == nil || err.Error() != tc.expectErr.Error() {
+					t.Errorf("expected error '%v', got '%v'", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			holds := f.queueOf(t)
+			if len(holds) != 1 || holds[0].Status != models.HoldStatusWaiting {
+				t.Errorf("expected one waiting hold, got %+v", holds)
+			}
+		})
+	}
+}
+
+func TestReturnedBookGoesToHeadOfQueue(t *testing.T) {
+	f := newHoldTestFixture()
+	f.lend(t, "user1")
+	f.placeHold(t, "user2")
+	f.placeHold(t, "user3")
+
+	if err := f.ret.Handle(context.Background(), ReturnBookCommand{BookID: "book1", UserID: "user1"}); err != nil {
+		t.Fatalf("failed to return book: %v", err)
+	}
+
+	holds := f.queueOf(t)
+	if len(holds) != 2 {
+		t.Fatalf("expected 2 active holds, got %d", len(holds))
+	}
+	if holds[0].UserID != "user2" || !holds[0].IsReady() || holds[0].PickupDeadline == nil {
+		t.Errorf("expected user2's hold to be ready for pickup, got %+v", holds[0])
+	}
+	if holds[1].Status != models.HoldStatusWaiting {
+		t.Errorf("expected user3 to keep waiting, got %s", holds[1].Status)
+	}
+
+	err := f.rent.Handle(context.Background(), BookRentalCommand{BookID: "book1", UserID: "user3"})
+	if !stderrors.Is(err, errors.ErrBookOnHold) {
+		t.Errorf("expected patron behind the head of the queue to be refused, got %v", err)
+	}
+
+	if err := f.rent.Handle(context.Background(), BookRentalCommand{BookID: "book1", UserID: "user2"}); err != nil {
+		t.Fatalf("expected head of the queue to borrow the book, got %v", err)
+	}
+
+	holds = f.queueOf(t)
+	if len(holds) != 1 || holds[0].UserID != "user3" {
+		t.Errorf("expected only user3 left in the queue, got %+v", holds)
+	}
+}
+
+func TestUnclaimedHoldPassesToNextPatron(t *testing.T) {
+	f := newHoldTestFixture()
+	f.lend(t, "user1")
+	f.placeHold(t, "user2")
+	f.placeHold(t, "user3")
+
+	if err := f.ret.Handle(context.Background(), ReturnBookCommand{BookID: "book1", UserID: "user1"}); err != nil {
+		t.Fatalf("failed to return book: %v", err)
+	}
+
+	head := f.queueOf(t)[0]
+	expired := time.Now().Add(-time.Hour)
+	head.PickupDeadline = &expired
+	if err := f.holds.UpdateHold(context.Background(), head); err != nil {
+		t.Fatalf("failed to backdate hold: %v", err)
+	}
+
+	err := f.rent.Handle(context.Background(), BookRentalCommand{BookID: "book1", UserID: "user2"})
+	if !stderrors.Is(err, errors.ErrBookOnHold) {
+		t.Errorf("expected expired hold to lose its place, got %v", err)
+	}
+
+	expiredHold, _ := f.holds.GetHoldByID(context.Background(), head.ID)
+	if expiredHold.Status != models.HoldStatusExpired {
+		t.Errorf("expected hold to be expired, got %s", expiredHold.Status)
+	}
+
+	holds := f.queueOf(t)
+	if len(holds) != 1 || holds[0].UserID != "user3" || !holds[0].IsReady() {
+		t.Errorf("expected user3's hold to be ready, got %+v", holds)
+	}
+}
+
+func TestCancelHoldCommandHandler_Handle(t *testing.T) {
+	f := newHoldTestFixture()
+	f.lend(t, "user1")
+	f.placeHold(t, "user2")
+	f.placeHold(t, "user3")
+
+	if err := f.ret.Handle(context.Background(), ReturnBookCommand{BookID: "book1", UserID: "user1"}); err != nil {
+		t.Fatalf("failed to return book: %v", err)
+	}
+	ready := f.queueOf(t)[0]
+
+	err := f.cancel.Handle(context.Background(), CancelHoldCommand{HoldID: ready.ID, UserID: "user3"})
+	if !stderrors.Is(err, errors.ErrHoldNotOwned) {
+		t.Errorf("expected ErrHoldNotOwned, got %v", err)
+	}
+
+	if err := f.cancel.Handle(context.Background(), CancelHoldCommand{HoldID: ready.ID, UserID: "user2"}); err != nil {
+		t.Fatalf("failed to cancel hold: %v", err)
+	}
+
+	err = f.cancel.Handle(context.Background(), CancelHoldCommand{HoldID: ready.ID, UserID: "user2"})
+	if !stderrors.Is(err, errors.ErrHoldClosed) {
+		t.Errorf("expected ErrHoldClosed, got %v", err)
+	}
+
+	err = f.cancel.Handle(context.Background(), CancelHoldCommand{HoldID: "missing", UserID: "user2"})
+	if !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	holds := f.queueOf(t)
+	if len(holds) != 1 || holds[0].UserID != "user3" || !holds[0].IsReady() {
+		t.Errorf("expected cancelled hold to pass the book to user3, got %+v", holds)
+	}
+}
+
+func TestHoldQueue_HasHoldsByOthers(t *testing.T) {
+	f := newHoldTestFixture()
+	f.lend(t, "user1")
+
+	onHold, err := f.queue.HasHoldsByOthers(context.Background(), "book1", "user1")
+	if err != nil || onHold {
+		t.Errorf("expected no holds, got %v (err %v)", onHold, err)
+	}
+
+	f.placeHold(t, "user2")
+
+	onHold, err = f.queue.HasHoldsByOthers(context.Background(), "book1", "user1")
+	if err != nil || !onHold {
+		t.Errorf("expected holds by others, got %v (err %v)", onHold, err)
+	}
+}
@@ -0,0 +1,211 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/library/errors"
+	"books/core/library/models"
+	"books/core/library/policies"
+	"books/core/library/repositories"
+	storage_models "books/core/storage/models"
+	"books/core/storage/repositories/interfaces"
+)
+
+// MarkRentalLostCommand closes a loan whose copy the patron lost. FeeCents
+// overrides the loan policy's replacement fee when set.
+type MarkRentalLostCommand struct {
+	RentalID string
+	FeeCents int64
+}
+
+// MarkRentalDamagedCommand closes a loan whose copy came back unusable.
+// FeeCents overrides the loan policy's replacement fee when set.
+type MarkRentalDamagedCommand struct {
+	RentalID string
+	FeeCents int64
+}
+
+// MarkRentalFoundCommand puts the copy of a lost rental back on the shelf
+// and reverses its replacement fee.
+type MarkRentalFoundCommand struct {
+	RentalID string
+}
+
+// writeOff charges rentals for copies that will not return to the shelf and
+// takes the copy out of circulation. The copy record and the rental history
+// are kept.
+type writeOff struct {
+	repo     repositories.BookRepository
+	copies   interfaces.CopyRepository
+	ledger   repositories.LedgerRepository
+	policies *policies.LoanPolicies
+}
+
+func (w writeOff) handle(ctx context.Context, rentalID string, feeCents int64, reason models.RentalCloseReason) error {
+	if feeCents < 0 {
+		return errors.ErrInvalidAmount
+	}
+
+	rental, err := w.repo.GetBookRentalByID(ctx, rentalID)
+	if err != nil {
+		return err
+	}
+
+	if rental.IsReturned() {
+		return errors.ErrRentalClosed
+	}
+
+	bookCopy, err := w.copies.FindCopyByID(ctx, rental.CopyID)
+	if err != nil {
+		return err
+	}
+
+	if feeCents == 0 {
+		book, err := w.repo.GetBookByISBN(ctx, rental.BookID)
+		if err != nil {
+			return err
+		}
+		feeCents = w.policies.Resolve(book.Category, rental.PatronType).ReplacementFeeCents
+	}
+
+	rental.MarkAsWrittenOff(reason)
+
+	if err := w.repo.UpdateBookRental(ctx, rental); err != nil {
+		return err
+	}
+
+	if feeCents > 0 {
+		if err := w.ledger.SaveLedgerEntry(ctx, models.NewReplacementCharge(rental, feeCents)); err != nil {
+			return err
+		}
+	}
+
+	if reason == models.RentalDamaged {
+		bookCopy.Status = storage_models.CopyStatusWithdrawn
+		bookCopy.Condition = storage_models.CopyConditionDamaged
+	} else {
+		bookCopy.Status = storage_models.CopyStatusLost
+	}
+
+	return w.copies.UpdateCopy(ctx, bookCopy)
+}
+
+type MarkRentalLostCommandHandler struct {
+	writeOff writeOff
+}
+
+func NewMarkRentalLostCommandHandler(repo repositories.BookRepository, copies interfaces.CopyRepository, ledger repositories.LedgerRepository, loanPolicies *policies.LoanPolicies) *MarkRentalLostCommandHandler {
+	return &MarkRentalLostCommandHandler{
+		writeOff: writeOff{repo: repo, copies: copies, ledger: ledger, policies: loanPolicies},
+	}
+}
+
+func (h *MarkRentalLostCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(MarkRentalLostCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	return h.writeOff.handle(ctx, command.RentalID, command.FeeCents, models.RentalLost)
+}
+
+type MarkRentalDamagedCommandHandler struct {
+	writeOff writeOff
+}
+
+func NewMarkRentalDamagedCommandHandler(repo repositories.BookRepository, copies interfaces.CopyRepository, ledger repositories.LedgerRepository, loanPolicies *policies.LoanPolicies) *MarkRentalDamagedCommandHandler {
+	return &MarkRentalDamagedCommandHandler{
+		writeOff: writeOff{repo: repo, copies: copies, ledger: ledger, policies: loanPolicies},
+	}
+}
+
+func (h *MarkRentalDamagedCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(MarkRentalDamagedCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	return h.writeOff.handle(ctx, command.RentalID, command.FeeCents, models.RentalDamaged)
+}
+
+type MarkRentalFoundCommandHandler struct {
+	repo   repositories.BookRepository
+	copies interfaces.CopyRepository
+	ledger repositories.LedgerRepository
+	queue  *HoldQueue
+}
+
+func NewMarkRentalFoundCommandHandler(repo repositories.BookRepository, copies interfaces.CopyRepository, ledger repositories.LedgerRepository, queue *HoldQueue) *MarkRentalFoundCommandHandler {
+	return &MarkRentalFoundCommandHandler{
+		repo:   repo,
+		copies: copies,
+		ledger: ledger,
+		queue:  queue,
+	}
+}
+
+func (h *MarkRentalFoundCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(MarkRentalFoundCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	rental, err := h.repo.GetBookRentalByID(ctx, command.RentalID)
+	if err != nil {
+		return err
+	}
+
+	if rental.CloseReason != models.RentalLost {
+		return errors.ErrRentalNotLost
+	}
+
+	bookCopy, err := h.copies.FindCopyByID(ctx, rental.CopyID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.repo.MarkLostRentalFound(ctx, rental.ID); err != nil {
+		return err
+	}
+
+	entries, err := h.ledger.GetUserLedgerEntries(ctx, rental.UserID)
+	if err != nil {
+		return err
+	}
+
+	if charged := replacementFees(entries, rental.ID); charged > 0 {
+		reversal := models.NewLedgerEntry(rental.UserID, models.LedgerEntryReversal, charged, "replacement fee reversed, item found")
+		reversal.RentalID = rental.ID
+		if err := h.ledger.SaveLedgerEntry(ctx, reversal); err != nil {
+			return err
+		}
+	}
+
+	bookCopy.Status = storage_models.CopyStatusCirculating
+	if err := h.copies.UpdateCopy(ctx, bookCopy); err != nil {
+		return err
+	}
+
+	// The copy back on the shelf goes to the first patron waiting for the book.
+	_, err = h.queue.Sync(ctx, rental.BookID)
+	return err
+}
+
+// replacementFees returns the replacement fees charged for a rental that
+// have not been reversed yet.
+func replacementFees(entries []*models.LedgerEntry, rentalID string) int64 {
+	var total int64
+	for _, entry := range entries {
+		if entry.RentalID != rentalID {
+			continue
+		}
+		switch {
+		case entry.Type == models.LedgerEntryCharge && entry.Note == models.ReplacementFeeNote:
+			total += entry.AmountCents
+		case entry.Type == models.LedgerEntryReversal:
+			total -= entry.AmountCents
+		}
+	}
+	return total
+}
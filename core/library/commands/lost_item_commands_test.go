@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"books/core/library/errors"
+	"books/core/library/models"
+	storage_models "books/core/storage/models"
+	"context"
+	stderrors "errors"
+	"testing"
+)
+
+// lendOnlyCopy lends the single copy of book1 to user1 and returns the rental.
+func lendOnlyCopy(t *testing.T, f *multiCopyFixture) *models.BookRental {
+	t.Helper()
+
+	if err := f.borrow(t, "user1"); err != nil {
+		t.Fatalf("failed to borrow: %v", err)
+	}
+	rentals, err := f.rentals.GetAllUserRentals(context.Background(), "user1")
+	if err != nil || len(rentals) != 1 {
+		t.Fatalf("expected one rental, got %d (%v)", len(rentals), err)
+	}
+	return rentals[0]
+}
+
+func (f *multiCopyFixture) balance(t *testing.T, userID string) int64 {
+	t.Helper()
+	entries, err := f.ledger.GetUserLedgerEntries(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("failed to load ledger: %v", err)
+	}
+	return models.NewFineAccount(userID, entries).BalanceCents
+}
+
+func TestMarkRentalLostAndFound(t *testing.T) {
+	f := newMultiCopyFixture(t, 1)
+	ctx := context.Background()
+	rental := lendOnlyCopy(t, f)
+
+	lost := NewMarkRentalLostCommandHandler(f.rentals, f.copies, f.ledger, testLoanPolicies())
+	found := NewMarkRentalFoundCommandHandler(f.rentals, f.copies, f.ledger, f.queue)
+
+	if err := found.Handle(ctx, MarkRentalFoundCommand{RentalID: rental.ID}); !stderrors.Is(err, errors.ErrRentalNotLost) {
+		t.Errorf("expected an open rental not to be found, got %v", err)
+	}
+
+	if err := lost.Handle(ctx, MarkRentalLostCommand{RentalID: rental.ID}); err != nil {
+		t.Fatalf("failed to mark rental lost: %v", err)
+	}
+
+	closed, _ := f.rentals.GetBookRentalByID(ctx, rental.ID)
+	if !closed.IsReturned() || closed.CloseReason != models.RentalLost {
+		t.Errorf("expected rental closed as lost, got %+v", closed)
+	}
+	if balance := f.balance(t, "user1"); balance != 2500 {
+		t.Errorf("expected the default replacement fee of 2500, got %d", balance)
+	}
+
+	bookCopy, _ := f.copies.FindCopyByID(ctx, rental.CopyID)
+	if bookCopy.Status != storage_models.CopyStatusLost {
+		t.Errorf("expected copy out of circulation, got %s", bookCopy.Status)
+	}
+	if err := f.borrow(t, "user2"); !stderrors.Is(err, errors.ErrBookAlreadyBorrowed) {
+		t.Errorf("expected lost copy not to be lent, got %v", err)
+	}
+
+	if err := lost.Handle(ctx, MarkRentalLostCommand{RentalID: rental.ID}); !stderrors.Is(err, errors.ErrRentalClosed) {
+		t.Errorf("expected a closed rental to be refused, got %v", err)
+	}
+
+	if err := found.Handle(ctx, MarkRentalFoundCommand{RentalID: rental.ID}); err != nil {
+		t.Fatalf("failed to mark rental found: %v", err)
+	}
+	if balance := f.balance(t, "user1"); balance != 0 {
+		t.Errorf("expected the replacement fee to be reversed, got %d", balance)
+	}
+	if err := found.Handle(ctx, MarkRentalFoundCommand{RentalID: rental.ID}); !stderrors.Is(err, errors.ErrRentalNotLost) {
+		t.Errorf("expected a found rental not to be found twice, got %v", err)
+	}
+	if err := f.borrow(t, "user2"); err != nil {
+		t.Errorf("expected found copy to be back on the shelf, got %v", err)
+	}
+}
+
+func TestMarkRentalDamaged(t *testing.T) {
+	f := newMultiCopyFixture(t, 1)
+	ctx := context.Background()
+	rental := lendOnlyCopy(t, f)
+
+	damaged := NewMarkRentalDamagedCommandHandler(f.rentals, f.copies, f.ledger, testLoanPolicies())
+
+	if err := damaged.Handle(ctx, MarkRentalDamagedCommand{RentalID: rental.ID, FeeCents: -1}); !stderrors.Is(err, errors.ErrInvalidAmount) {
+		t.Errorf("expected negative fee to be refused, got %v", err)
+	}
+	if err := damaged.Handle(ctx, MarkRentalDamagedCommand{RentalID: rental.ID, FeeCents: 1200}); err != nil {
+		t.Fatalf("failed to mark rental damaged: %v", err)
+	}
+
+	if balance := f.balance(t, "user1"); balance != 1200 {
+		t.Errorf("expected the overridden fee of 1200, got %d", balance)
+	}
+
+	bookCopy, _ := f.copies.FindCopyByID(ctx, rental.CopyID)
+	if bookCopy.Status != storage_models.CopyStatusWithdrawn || bookCopy.Condition != storage_models.CopyConditionDamaged {
+		t.Errorf("expected damaged copy to be withdrawn, got %+v", bookCopy)
+	}
+
+	found := NewMarkRentalFoundCommandHandler(f.rentals, f.copies, f.ledger, f.queue)
+	if err := found.Handle(ctx, MarkRentalFoundCommand{RentalID: rental.ID}); !stderrors.Is(err, errors.ErrRentalNotLost) {
+		t.Errorf("expected a damaged rental not to be found, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/library/errors"
+	"books/core/library/models"
+	"books/core/library/repositories"
+)
+
+type PayFineCommand struct {
+	UserID      string
+	AmountCents int64
+	Note        string
+}
+
+type PayFineCommandHandler struct {
+	ledger repositories.LedgerRepository
+}
+
+func NewPayFineCommandHandler(ledger repositories.LedgerRepository) *PayFineCommandHandler {
+	return &PayFineCommandHandler{ledger: ledger}
+}
+
+func (h *PayFineCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(PayFineCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	return settleBalance(ctx, h.ledger, models.NewLedgerEntry(
+		command.UserID, models.LedgerEntryPayment, command.AmountCents, command.Note,
+	))
+}
+
+// settleBalance records a payment or waiver, refusing to take the balance
+// below zero.
+func settleBalance(ctx context.Context, ledger repositories.LedgerRepository, entry *models.LedgerEntry) error {
+	if entry.AmountCents <= 0 {
+		return errors.ErrInvalidAmount
+	}
+
+	entries, err := ledger.GetUserLedgerEntries(ctx, entry.UserID)
+	if err != nil {
+		return err
+	}
+
+	if entry.AmountCents > models.NewFineAccount(entry.UserID, entries).BalanceCents {
+		return errors.ErrAmountExceedsBalance
+	}
+
+	return ledger.SaveLedgerEntry(ctx, entry)
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/isbn"
+	"books/core/library/errors"
+	"books/core/library/models"
+	"books/core/library/repositories"
+)
+
+type PlaceHoldCommand struct {
+	ID     string
+	BookID string
+	UserID string
+	// PickupBranchID is where the patron will collect the book. Empty
+	// accepts a copy at any branch.
+	PickupBranchID string
+}
+
+type PlaceHoldCommandHandler struct {
+	repo  repositories.BookRepository
+	queue *HoldQueue
+	mover *CopyMover
+}
+
+func NewPlaceHoldCommandHandler(repo repositories.BookRepository, queue *HoldQueue, mover *CopyMover) *PlaceHoldCommandHandler {
+	return &PlaceHoldCommandHandler{
+		repo:  repo,
+		queue: queue,
+		mover: mover,
+	}
+}
+
+func (h *PlaceHoldCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(PlaceHoldCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+	command.BookID = isbn.Key(command.BookID)
+
+	exists, err := h.repo.BookExists(ctx, command.BookID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.ErrBookNotInStorage
+	}
+
+	if err := h.mover.CheckBranch(ctx, command.PickupBranchID); err != nil {
+		return err
+	}
+
+	holds, err := h.queue.Sync(ctx, command.BookID)
+	if err != nil {
+		return err
+	}
+
+	onShelf, activeRentals, err := shelfCopies(ctx, h.repo, command.BookID)
+	if err != nil {
+		return err
+	}
+
+	for _, rental := range activeRentals {
+		if rental.UserID == command.UserID {
+			return errors.ErrBookAlreadyRented
+		}
+	}
+
+	hold := models.NewHold(command.BookID, command.UserID)
+	hold.ID = command.ID
+	hold.PickupBranchID = command.PickupBranchID
+
+	// Holds are only taken once no copy is free at the pickup branch. A copy
+	// free elsewhere is sent over once the hold is in the queue.
+	_, free := setAside(onShelf, holds)
+	if servingCopy(free, hold) >= 0 {
+		return errors.ErrBookAvailable
+	}
+
+	for _, existing := range holds {
+		if existing.UserID == command.UserID {
+			return errors.ErrHoldAlreadyPlaced
+		}
+	}
+
+	if err := h.queue.Add(ctx, hold); err != nil {
+		return err
+	}
+
+	_, err = h.queue.Sync(ctx, command.BookID)
+	return err
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/library/errors"
+	"books/core/library/policies"
+	"books/core/library/repositories"
+)
+
+// HoldChecker reports whether patrons other than the borrower are waiting
+// for a book.
+type HoldChecker interface {
+	HasHoldsByOthers(ctx context.Context, bookID, userID string) (bool, error)
+}
+
+type RenewRentalCommand struct {
+	RentalID string
+	UserID   string
+}
+
+type RenewRentalCommandHandler struct {
+	repo     repositories.BookRepository
+	policies *policies.LoanPolicies
+	holds    HoldChecker
+	calendar CalendarSource
+}
+
+func NewRenewRentalCommandHandler(repo repositories.BookRepository, loanPolicies *policies.LoanPolicies, holds HoldChecker, calendar CalendarSource) *RenewRentalCommandHandler {
+	return &RenewRentalCommandHandler{
+		repo:     repo,
+		policies: loanPolicies,
+		holds:    holds,
+		calendar: calendar,
+	}
+}
+
+func (h *RenewRentalCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(RenewRentalCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	rental, err := h.repo.GetBookRentalByID(ctx, command.RentalID)
+	if err != nil {
+		return err
+	}
+
+	if rental.UserID != command.UserID {
+		return errors.ErrRentalNotOwned
+	}
+
+	if rental.IsReturned() {
+		return errors.ErrRentalClosed
+	}
+
+	if rental.IsOverdue() {
+		return errors.ErrRentalOverdue
+	}
+
+	book, err := h.repo.GetBookByISBN(ctx, rental.BookID)
+	if err != nil {
+		return err
+	}
+
+	policy := h.policies.Resolve(book.Category, rental.PatronType)
+	if rental.RenewalCount >= policy.MaxRenewals {
+		return errors.ErrRenewalLimitReached
+	}
+
+	onHold, err := h.holds.HasHoldsByOthers(ctx, rental.BookID, rental.UserID)
+	if err != nil {
+		return err
+	}
+	if onHold {
+		return errors.ErrBookOnHold
+	}
+
+	calendar, err := h.calendar.GetCalendar(ctx)
+	if err != nil {
+		return err
+	}
+
+	rental.Renew(policy.LoanPeriod(), *calendar)
+
+	return h.repo.UpdateBookRental(ctx, rental)
+}
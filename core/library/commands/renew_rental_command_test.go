@@ -0,0 +1,194 @@
+package commands
+
+import (
+	calendar_repositories "books/core/calendar/repositories"
+	"books/core/library/errors"
+	"books/core/library/models"
+	storage_models "books/core/storage/models"
+	"context"
+	"testing"
+	"time"
+)
+
+type stubHoldChecker struct {
+	onHold bool
+}
+
+func (s stubHoldChecker) HasHoldsByOthers(ctx context.Context, bookID, userID string) (bool, error) {
+	return s.onHold, nil
+}
+
+type renewRentalTestCase struct {
+	name                 string
+	setupRepo            func(repo *mockBookRepository)
+	holds                HoldChecker
+	command              interface{}
+	expectError          bool
+	errorMsg             string
+	expectedRenewalCount int
+}
+
+func newTestRental(bookID, userID, patronType string) *models.BookRental {
+	rental := models.NewBookRental(bookID, userID)
+	rental.ID = "rental-" + bookID
+	rental.PatronType = patronType
+	return rental
+}
+
+func getRenewRentalTestCases() []renewRentalTestCase {
+	return []renewRentalTestCase{
+		{
+			name: "Successfully renew a rental",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				repo.rentals["book1"] = newTestRental("book1", "user1", "")
+			},
+			command:              RenewRentalCommand{RentalID: "rental-book1", UserID: "user1"},
+			expectError:          false,
+			expectedRenewalCount: 1,
+		},
+		{
+			name:        "Invalid command type",
+			setupRepo:   func(repo *mockBookRepository) {},
+			command:     "not a command",
+			expectError: true,
+			errorMsg:    "invalid command type",
+		},
+		{
+			name:        "Rental not found",
+			setupRepo:   func(repo *mockBookRepository) {},
+			command:     RenewRentalCommand{RentalID: "missing", UserID: "user1"},
+			expectError: true,
+			errorMsg:    "not found",
+		},
+		{
+			name: "Rental belongs to another user",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				repo.rentals["book1"] = newTestRental("book1", "user2", "")
+			},
+			command:     RenewRentalCommand{RentalID: "rental-book1", UserID: "user1"},
+			expectError: true,
+			errorMsg:    "book is borrowed by another user",
+		},
+		{
+			name: "Returned rental",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				rental := newTestRental("book1", "user1", "")
+				rental.MarkAsReturned()
+				repo.rentals["book1"] = rental
+			},
+			command:     RenewRentalCommand{RentalID: "rental-book1", UserID: "user1"},
+			expectError: true,
+			errorMsg:    "rental is already closed",
+		},
+		{
+			name: "Overdue rental",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				rental := newTestRental("book1", "user1", "")
+				rental.ReturnDeadline = time.Now().Add(-time.Hour)
+				repo.rentals["book1"] = rental
+			},
+			command:     RenewRentalCommand{RentalID: "rental-book1", UserID: "user1"},
+			expectError: true,
+			errorMsg:    "overdue rentals cannot be renewed",
+		},
+		{
+			name: "Renewal limit reached",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book", Category: "reference"}
+				repo.rentals["book1"] = newTestRental("book1", "user1", "")
+			},
+			command:     RenewRentalCommand{RentalID: "rental-book1", UserID: "user1"},
+			expectError: true,
+			errorMsg:    "renewal limit reached for this rental",
+		},
+		{
+			name: "Renewal limit follows the patron type",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				rental := newTestRental("book1", "user1", "staff")
+				rental.RenewalCount = 4
+				repo.rentals["book1"] = rental
+			},
+			command:              RenewRentalCommand{RentalID: "rental-book1", UserID: "user1"},
+			expectError:          false,
+			expectedRenewalCount: 5,
+		},
+		{
+			name: "Another patron has a hold",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				repo.rentals["book1"] = newTestRental("book1", "user1", "")
+			},
+			holds:       stubHoldChecker{onHold: true},
+			command:     RenewRentalCommand{RentalID: "rental-book1", UserID: "user1"},
+			expectError: true,
+			errorMsg:    "book is on hold for another patron",
+		},
+		{
+			name: "Update error",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				repo.rentals["book1"] = newTestRental("book1", "user1", "")
+				repo.saveErr = errors.ErrDatabase
+			},
+			command:     RenewRentalCommand{RentalID: "rental-book1", UserID: "user1"},
+			expectError: true,
+			errorMsg:    "database error",
+		},
+	}
+}
+
+func TestRenewRentalCommandHandler_Handle(t *testing.T) {
+	tests := getRenewRentalTestCases()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newMockRepository()
+			tc.setupRepo(repo)
+
+			holds := tc.holds
+			if holds == nil {
+				holds = stubHoldChecker{}
+			}
+			handler := NewRenewRentalCommandHandler(repo, testLoanPolicies(), holds, calendar_repositories.NewCalendarInMemoryRepository())
+
+			var previousDeadline time.Time
+			if command, ok := tc.command.(RenewRentalCommand); ok {
+				if rental, err := repo.GetBookRentalByID(context.Background(), command.RentalID); err == nil {
+					previousDeadline = rental.ReturnDeadline
+				}
+			}
+
+			err := handler.Handle(context.Background(), tc.command)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("expected error but got nil")
+					return
+				}
+				if err.Error() != tc.errorMsg {
+					t.Errorf("expected error message '%s', got '%s'", tc.errorMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("expected no error but got: %v", err)
+				return
+			}
+
+			command := tc.command.(RenewRentalCommand)
+			rental, _ := repo.GetBookRentalByID(context.Background(), command.RentalID)
+			if rental.RenewalCount != tc.expectedRenewalCount {
+				t.Errorf("expected renewal count %d, got %d", tc.expectedRenewalCount, rental.RenewalCount)
+			}
+			if !rental.ReturnDeadline.After(previousDeadline) {
+				t.Errorf("expected deadline to move past %v, got %v", previousDeadline, rental.ReturnDeadline)
+			}
+		})
+	}
+}
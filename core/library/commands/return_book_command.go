@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/isbn"
+	"books/core/library/errors"
+	"books/core/library/models"
+	"books/core/library/policies"
+	"books/core/library/repositories"
+)
+
+type ReturnBookCommand struct {
+	BookID string
+	UserID string
+	// BranchID is the branch the book is returned at. A copy returned away
+	// from its home branch is sent back there.
+	BranchID string
+}
+
+type ReturnBookCommandHandler struct {
+	repo     repositories.BookRepository
+	ledger   repositories.LedgerRepository
+	policies *policies.LoanPolicies
+	queue    *HoldQueue
+	mover    *CopyMover
+}
+
+func NewReturnBookCommandHandler(repo repositories.BookRepository, ledger repositories.LedgerRepository, loanPolicies *policies.LoanPolicies, queue *HoldQueue, mover *CopyMover) *ReturnBookCommandHandler {
+	return &ReturnBookCommandHandler{
+		repo:     repo,
+		ledger:   ledger,
+		policies: loanPolicies,
+		queue:    queue,
+		mover:    mover,
+	}
+}
+
+func (h *ReturnBookCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(ReturnBookCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+	command.BookID = isbn.Key(command.BookID)
+
+	if err := h.mover.CheckBranch(ctx, command.BranchID); err != nil {
+		return err
+	}
+
+	activeRentals, err := h.repo.GetActiveBookRentalsByBookID(ctx, command.BookID)
+	if err != nil {
+		return err
+	}
+
+	if len(activeRentals) == 0 {
+		return errors.ErrBookNotBorrowed
+	}
+
+	var rental *models.BookRental
+	for _, active := range activeRentals {
+		if active.UserID == command.UserID {
+			rental = active
+			break
+		}
+	}
+	if rental == nil {
+		return errors.ErrRentalNotOwned
+	}
+
+	book, err := h.repo.GetBookByISBN(ctx, rental.BookID)
+	if err != nil {
+		return err
+	}
+
+	rental.MarkAsReturned()
+
+	// The fine is charged before the rental is closed: a closed rental
+	// cannot be returned again, while a charged one can, and is not fined
+	// twice.
+	if rental.ReturnedLate {
+		policy := h.policies.Resolve(book.Category, rental.PatronType)
+		if fine := policy.Fine(rental.DaysOverdue()); fine > 0 {
+			if err := h.ledger.SaveFineCharge(ctx, models.NewFineCharge(rental, fine)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := h.repo.UpdateBookRental(ctx, rental); err != nil {
+		return err
+	}
+
+	if command.BranchID == "" {
+		// The returned copy goes to the first patron waiting for the book.
+		_, err = h.queue.Sync(ctx, rental.BookID)
+		return err
+	}
+
+	if err := h.mover.CheckIn(ctx, rental.CopyID, command.BranchID); err != nil {
+		return err
+	}
+
+	holds, err := h.queue.Sync(ctx, rental.BookID)
+	if err != nil {
+		return err
+	}
+
+	return h.sendHome(ctx, rental, holds)
+}
+
+// sendHome starts the transfer of a copy returned away from its home branch,
+// unless the copy was set aside or sent on for a hold.
+func (h *ReturnBookCommandHandler) sendHome(ctx context.Context, rental *models.BookRental, holds []*models.Hold) error {
+	onShelf, _, err := shelfCopies(ctx, h.repo, rental.BookID)
+	if err != nil {
+		return err
+	}
+
+	_, free := setAside(onShelf, holds)
+	for _, bookCopy := range free {
+		if bookCopy.ID == rental.CopyID && bookCopy.AwayFromHome() {
+			_, err := h.mover.Send(ctx, bookCopy, bookCopy.HomeBranchID, models.TransferReturn)
+			return err
+		}
+	}
+	return nil
+}
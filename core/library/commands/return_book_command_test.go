@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"books/core/library/errors"
+	"books/core/library/models"
+	"books/core/library/repositories"
+	storage_models "books/core/storage/models"
+	"context"
+	"testing"
+	"time"
+)
+
+type returnBookTestCase struct {
+	name        string
+	setupRepo   func(repo *mockBookRepository)
+	command     interface{}
+	expectError bool
+	errorMsg    string
+	expectLate  bool
+	expectFine  int64
+}
+
+func getReturnBookTestCases() []returnBookTestCase {
+	return []returnBookTestCase{
+		{
+			name: "Successfully return a book",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				repo.rentals["book1"] = models.NewBookRental("book1", "user1")
+			},
+			command: ReturnBookCommand{
+				BookID: "book1",
+				UserID: "user1",
+			},
+			expectError: false,
+		},
+		{
+			name: "Late return is recorded",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				rental := models.NewBookRental("book1", "user1")
+				rental.ReturnDeadline = time.Now().Add(-36 * time.Hour)
+				repo.rentals["book1"] = rental
+			},
+			command: ReturnBookCommand{
+				BookID: "book1",
+				UserID: "user1",
+			},
+			expectError: false,
+			expectLate:  true,
+			expectFine:  50,
+		},
+		{
+			name: "Late return fine is capped",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				rental := models.NewBookRental("book1", "user1")
+				rental.ReturnDeadline = time.Now().Add(-90 * 24 * time.Hour)
+				repo.rentals["book1"] = rental
+			},
+			command: ReturnBookCommand{
+				BookID: "book1",
+				UserID: "user1",
+			},
+			expectError: false,
+			expectLate:  true,
+			expectFine:  1000,
+		},
+		{
+			name:        "Invalid command type",
+			setupRepo:   func(repo *mockBookRepository) {},
+			command:     "not a command",
+			expectError: true,
+			errorMsg:    "invalid command type",
+		},
+		{
+			name: "Book is not borrowed",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+			},
+			command: ReturnBookCommand{
+				BookID: "book1",
+				UserID: "user1",
+			},
+			expectError: true,
+			errorMsg:    "book is not currently borrowed",
+		},
+		{
+			name: "Book is borrowed by another user",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				repo.rentals["book1"] = models.NewBookRental("book1", "user2")
+			},
+			command: ReturnBookCommand{
+				BookID: "book1",
+				UserID: "user1",
+			},
+			expectError: true,
+			errorMsg:    "book is borrowed by another user",
+		},
+		{
+			name: "Update error",
+			setupRepo: func(repo *mockBookRepository) {
+				repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+				repo.rentals["book1"] = models.NewBookRental("book1", "user1")
+				repo.saveErr = errors.ErrDatabase
+			},
+			command: ReturnBookCommand{
+				BookID: "book1",
+				UserID: "user1",
+			},
+			expectError: true,
+			errorMsg:    "database error",
+		},
+	}
+}
+
+func TestReturnBookCommandHandler_Handle(t *testing.T) {
+	tests := getReturnBookTestCases()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newMockRepository()
+			tc.setupRepo(repo)
+			ledger := repositories.NewLedgerInMemoryRepository()
+			queue := newTestHoldQueue(repo)
+			handler := NewReturnBookCommandHandler(repo, ledger, testLoanPolicies(), queue, queue.mover)
+
+			err := handler.Handle(context.Background(), tc.command)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("expected error but got nil")
+					return
+				}
+				if err.Error() != tc.errorMsg {
+					t.Errorf("expected error message '%s', got '%s'", tc.errorMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("expected no error but got: %v", err)
+				return
+			}
+
+			command := tc.command.(ReturnBookCommand)
+			rental := repo.rentals[command.BookID]
+			if !rental.IsReturned() {
+				t.Errorf("expected rental to be returned")
+			}
+			if rental.ReturnedLate != tc.expectLate {
+				t.Errorf("expected ReturnedLate %v, got %v", tc.expectLate, rental.ReturnedLate)
+			}
+
+			entries, _ := ledger.GetUserLedgerEntries(context.Background(), command.UserID)
+			if balance := models.NewFineAccount(command.UserID, entries).BalanceCents; balance != tc.expectFine {
+				t.Errorf("expected fine of %d, got %d", tc.expectFine, balance)
+			}
+		})
+	}
+}
+
+func TestLateReturnRetriedAfterFailedUpdate(t *testing.T) {
+	repo := newMockRepository()
+	repo.books["book1"] = &storage_models.Book{ISBN: "book1", Title: "Test Book"}
+	rental := models.NewBookRental("book1", "user1")
+	rental.ReturnDeadline = time.Now().Add(-36 * time.Hour)
+	repo.rentals["book1"] = rental
+
+	ledger := repositories.NewLedgerInMemoryRepository()
+	queue := newTestHoldQueue(repo)
+	handler := NewReturnBookCommandHandler(repo, ledger, testLoanPolicies(), queue, queue.mover)
+	command := ReturnBookCommand{BookID: "book1", UserID: "user1"}
+
+	repo.saveErr = errors.ErrDatabase
+	if err := handler.Handle(context.Background(), command); err != errors.ErrDatabase {
+		t.Fatalf("expected the failed update to be reported, got %v", err)
+	}
+	if repo.rentals["book1"].IsReturned() {
+		t.Fatalf("expected the rental to stay open")
+	}
+
+	repo.saveErr = nil
+	if err := handler.Handle(context.Background(), command); err != nil {
+		t.Fatalf("expected the retried return to succeed, got %v", err)
+	}
+
+	entries, _ := ledger.GetUserLedgerEntries(context.Background(), "user1")
+	if len(entries) != 1 || entries[0].AmountCents != 50 {
+		t.Errorf("expected one fine of 50, got %+v", entries)
+	}
+}
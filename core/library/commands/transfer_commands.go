@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/library/errors"
+	"books/core/library/models"
+	"books/core/library/repositories"
+	"books/core/storage/repositories/interfaces"
+)
+
+// StartTransferCommand sends a copy on the shelf to another branch.
+type StartTransferCommand struct {
+	CopyID     string
+	ToBranchID string
+}
+
+type StartTransferCommandHandler struct {
+	repo   repositories.BookRepository
+	copies interfaces.CopyRepository
+	queue  *HoldQueue
+	mover  *CopyMover
+}
+
+func NewStartTransferCommandHandler(repo repositories.BookRepository, copies interfaces.CopyRepository, queue *HoldQueue, mover *CopyMover) *StartTransferCommandHandler {
+	return &StartTransferCommandHandler{
+		repo:   repo,
+		copies: copies,
+		queue:  queue,
+		mover:  mover,
+	}
+}
+
+func (h *StartTransferCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(StartTransferCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	if command.ToBranchID == "" {
+		return stderrors.New("invalid transfer: destination branch is required")
+	}
+	if err := h.mover.CheckBranch(ctx, command.ToBranchID); err != nil {
+		return err
+	}
+
+	bookCopy, err := h.copies.FindCopyByID(ctx, command.CopyID)
+	if err != nil {
+		return err
+	}
+
+	holds, err := h.queue.Sync(ctx, bookCopy.ISBN)
+	if err != nil {
+		return err
+	}
+
+	onShelf, activeRentals, err := shelfCopies(ctx, h.repo, bookCopy.ISBN)
+	if err != nil {
+		return err
+	}
+
+	for _, rental := range activeRentals {
+		if rental.CopyID == bookCopy.ID {
+			return errors.ErrCopyOnLoan
+		}
+	}
+
+	// A copy set aside for a hold stays at the pickup branch.
+	held, _ := setAside(onShelf, holds)
+	for _, heldCopy := range held {
+		if heldCopy.ID == bookCopy.ID {
+			return errors.ErrBookOnHold
+		}
+	}
+
+	_, err = h.mover.Send(ctx, bookCopy, command.ToBranchID, models.TransferManual)
+	return err
+}
+
+// ReceiveTransferCommand records that a copy in transit has arrived at its
+// destination.
+type ReceiveTransferCommand struct {
+	TransferID string
+}
+
+type ReceiveTransferCommandHandler struct {
+	transfers repositories.TransferRepository
+	queue     *HoldQueue
+	mover     *CopyMover
+}
+
+func NewReceiveTransferCommandHandler(transfers repositories.TransferRepository, queue *HoldQueue, mover *CopyMover) *ReceiveTransferCommandHandler {
+	return &ReceiveTransferCommandHandler{
+		transfers: transfers,
+		queue:     queue,
+		mover:     mover,
+	}
+}
+
+func (h *ReceiveTransferCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(ReceiveTransferCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	transfer, err := h.transfers.GetTransferByID(ctx, command.TransferID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.mover.Receive(ctx, transfer); err != nil {
+		return err
+	}
+
+	// The copy may be what a waiting patron at this branch is queued for.
+	_, err = h.queue.Sync(ctx, transfer.BookID)
+	return err
+}
@@ -0,0 +1,270 @@
+package commands
+
+import (
+	branch_models "books/core/branches/models"
+	branch_repositories "books/core/branches/repositories"
+	calendar_repositories "books/core/calendar/repositories"
+	"books/core/library/errors"
+	"books/core/library/models"
+	"books/core/library/repositories"
+	storage_models "books/core/storage/models"
+	storage_repositories "books/core/storage/repositories"
+	"context"
+	stderrors "errors"
+	"testing"
+)
+
+type branchFixture struct {
+	copies    *storage_repositories.CopyStorageInMemoryRepository
+	holds     *repositories.HoldInMemoryRepository
+	transfers *repositories.TransferInMemoryRepository
+	rent      *BookRentalCommandHandler
+	ret       *ReturnBookCommandHandler
+	place     *PlaceHoldCommandHandler
+	start     *StartTransferCommandHandler
+	receive   *ReceiveTransferCommandHandler
+}
+
+// newBranchFixture stocks book1 with one copy at each of the given home
+// branches. The branches "main" and "north" exist.
+func newBranchFixture(t *testing.T, homes ...string) *branchFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	books := storage_repositories.NewBookStorageInMemoryRepository()
+	if err := books.Save(ctx, &storage_models.Book{ISBN: "book1", Title: "Test Book"}); err != nil {
+		t.Fatalf("failed to save book: %v", err)
+	}
+
+	branches := branch_repositories.NewBranchInMemoryRepository()
+	for _, code := range []string{"main", "north"} {
+		branch, _ := branch_models.NewBranch(code, code, "")
+		branch.ID = code
+		if err := branches.SaveBranch(ctx, branch); err != nil {
+			t.Fatalf("failed to save branch: %v", err)
+		}
+	}
+
+	copyRepo := storage_repositories.NewCopyStorageInMemoryRepository()
+	for i, home := range homes {
+		bookCopy, err := storage_models.NewCopy("book1", storage_models.DefaultBarcode("book1", i+1), "", "")
+		if err != nil {
+			t.Fatalf("failed to create copy: %v", err)
+		}
+		bookCopy.HomeBranchID = home
+		bookCopy.CurrentBranchID = home
+		if err := copyRepo.SaveCopy(ctx, bookCopy); err != nil {
+			t.Fatalf("failed to save copy: %v", err)
+		}
+	}
+
+	rentals := repositories.NewBookRentalInMemoryRepository(books, copyRepo)
+	holds := repositories.NewHoldInMemoryRepository()
+	transfers := repositories.NewTransferInMemoryRepository()
+	mover := NewCopyMover(copyRepo, transfers, branches)
+	queue := NewHoldQueue(holds, rentals, mover, testPickupWindow)
+	ledger := repositories.NewLedgerInMemoryRepository()
+
+	return &branchFixture{
+		copies:    copyRepo,
+		holds:     holds,
+		transfers: transfers,
+		rent:      NewBookRentalCommandHandler(rentals, testPatrons("user1", "user2"), ledger, testLendingRules(), queue, calendar_repositories.NewCalendarInMemoryRepository()),
+		ret:       NewReturnBookCommandHandler(rentals, ledger, testLoanPolicies(), queue, mover),
+		place:     NewPlaceHoldCommandHandler(rentals, queue, mover),
+		start:     NewStartTransferCommandHandler(rentals, copyRepo, queue, mover),
+		receive:   NewReceiveTransferCommandHandler(transfers, queue, mover),
+	}
+}
+
+func (f *branchFixture) onlyCopy(t *testing.T) *storage_models.Copy {
+	t.Helper()
+	copies, err := f.copies.FindCopiesByISBN(context.Background(), "book1")
+	if err != nil || len(copies) != 1 {
+		t.Fatalf("expected a single copy, got %d (%v)", len(copies), err)
+	}
+	return copies[0]
+}
+
+func (f *branchFixture) openTransfer(t *testing.T, copyID string) *models.Transfer {
+	t.Helper()
+	transfer, err := f.transfers.GetOpenTransferByCopyID(context.Background(), copyID)
+	if err != nil {
+		t.Fatalf("expected copy %s to be in transit: %v", copyID, err)
+	}
+	return transfer
+}
+
+func TestRentalAtBranchWithoutCopy(t *testing.T) {
+	f := newBranchFixture(t, "main")
+
+	err := f.rent.Handle(context.Background(), BookRentalCommand{BookID: "book1", UserID: "user1", BranchID: "north"})
+	if !stderrors.Is(err, errors.ErrNotAtBranch) {
+		t.Fatalf("expected ErrNotAtBranch, got %v", err)
+	}
+
+	if err := f.rent.Handle(context.Background(), BookRentalCommand{BookID: "book1", UserID: "user1", BranchID: "main"}); err != nil {
+		t.Fatalf("expected copy to be lent at its branch, got %v", err)
+	}
+}
+
+func TestReturnAtOtherBranchSendsCopyHome(t *testing.T) {
+	f := newBranchFixture(t, "main")
+	ctx := context.Background()
+
+	if err := f.rent.Handle(ctx, BookRentalCommand{BookID: "book1", UserID: "user1", BranchID: "main"}); err != nil {
+		t.Fatalf("failed to rent: %v", err)
+	}
+	if err := f.ret.Handle(ctx, ReturnBookCommand{BookID: "book1", UserID: "user1", BranchID: "north"}); err != nil {
+		t.Fatalf("failed to return: %v", err)
+	}
+
+	bookCopy := f.onlyCopy(t)
+	if !bookCopy.InTransit() || bookCopy.CurrentBranchID != "north" {
+		t.Fatalf("expected copy in transit from north, got %+v", bookCopy)
+	}
+
+	transfer := f.openTransfer(t, bookCopy.ID)
+	if transfer.FromBranchID != "north" || transfer.ToBranchID != "main" || transfer.Reason != models.TransferReturn {
+		t.Errorf("unexpected transfer %+v", transfer)
+	}
+
+	err := f.rent.Handle(ctx, BookRentalCommand{BookID: "book1", UserID: "user2"})
+	if !stderrors.Is(err, errors.ErrBookAlreadyBorrowed) {
+		t.Errorf("expected copy in transit not to be lent, got %v", err)
+	}
+
+	if err := f.receive.Handle(ctx, ReceiveTransferCommand{TransferID: transfer.ID}); err != nil {
+		t.Fatalf("failed to receive transfer: %v", err)
+	}
+	bookCopy = f.onlyCopy(t)
+	if !bookCopy.InCirculation() || bookCopy.CurrentBranchID != "main" {
+		t.Errorf("expected copy back on the shelf at main, got %+v", bookCopy)
+	}
+
+	err = f.receive.Handle(ctx, ReceiveTransferCommand{TransferID: transfer.ID})
+	if !stderrors.Is(err, errors.ErrTransferReceived) {
+		t.Errorf("expected ErrTransferReceived, got %v", err)
+	}
+}
+
+func TestReturnAtHomeBranchStaysOnShelf(t *testing.T) {
+	f := newBranchFixture(t, "main")
+	ctx := context.Background()
+
+	_ = f.rent.Handle(ctx, BookRentalCommand{BookID: "book1", UserID: "user1"})
+	if err := f.ret.Handle(ctx, ReturnBookCommand{BookID: "book1", UserID: "user1", BranchID: "main"}); err != nil {
+		t.Fatalf("failed to return: %v", err)
+	}
+
+	if bookCopy := f.onlyCopy(t); !bookCopy.InCirculation() || bookCopy.CurrentBranchID != "main" {
+		t.Errorf("expected copy on the shelf at main, got %+v", bookCopy)
+	}
+}
+
+func TestHoldAtOtherBranchTransfersCopy(t *testing.T) {
+	f := newBranchFixture(t, "main")
+	ctx := context.Background()
+
+	err := f.place.Handle(ctx, PlaceHoldCommand{BookID: "book1", UserID: "user1", PickupBranchID: "main"})
+	if !stderrors.Is(err, errors.ErrBookAvailable) {
+		t.Fatalf("expected ErrBookAvailable at the copy's branch, got %v", err)
+	}
+
+	if err := f.place.Handle(ctx, PlaceHoldCommand{ID: "hold1", BookID: "book1", UserID: "user1", PickupBranchID: "north"}); err != nil {
+		t.Fatalf("failed to place hold: %v", err)
+	}
+
+	bookCopy := f.onlyCopy(t)
+	transfer := f.openTransfer(t, bookCopy.ID)
+	if transfer.ToBranchID != "north" || transfer.Reason != models.TransferHold {
+		t.Errorf("unexpected transfer %+v", transfer)
+	}
+
+	hold, _ := f.holds.GetHoldByID(ctx, "hold1")
+	if hold.IsReady() {
+		t.Fatalf("expected hold to wait for the copy")
+	}
+
+	if err := f.receive.Handle(ctx, ReceiveTransferCommand{TransferID: transfer.ID}); err != nil {
+		t.Fatalf("failed to receive transfer: %v", err)
+	}
+
+	hold, _ = f.holds.GetHoldByID(ctx, "hold1")
+	if !hold.IsReady() {
+		t.Errorf("expected hold to be ready once the copy arrived, got %s", hold.Status)
+	}
+
+	err = f.rent.Handle(ctx, BookRentalCommand{BookID: "book1", UserID: "user2", BranchID: "north"})
+	if !stderrors.Is(err, errors.ErrBookOnHold) {
+		t.Errorf("expected held copy to be kept for user1, got %v", err)
+	}
+	if err := f.rent.Handle(ctx, BookRentalCommand{BookID: "book1", UserID: "user1", BranchID: "north"}); err != nil {
+		t.Errorf("expected user1 to pick up the held copy, got %v", err)
+	}
+}
+
+func TestStartTransfer(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(t *testing.T, f *branchFixture)
+		toBranch  string
+		expectErr error
+	}{
+		{
+			name:     "copy on the shelf",
+			toBranch: "north",
+		},
+		{
+			name:      "already at the branch",
+			toBranch:  "main",
+			expectErr: errors.ErrCopyAlreadyAtBranch,
+		},
+		{
+			name:      "unknown branch",
+			toBranch:  "east",
+			expectErr: stderrors.New("branch not found"),
+		},
+		{
+			name: "copy on loan",
+			setup: func(t *testing.T, f *branchFixture) {
+				_ = f.rent.Handle(context.Background(), BookRentalCommand{BookID: "book1", UserID: "user1"})
+			},
+			toBranch:  "north",
+			expectErr: errors.ErrCopyOnLoan,
+		},
+		{
+			name: "copy in transit",
+			setup: func(t *testing.T, f *branchFixture) {
+				_ = f.start.Handle(context.Background(), StartTransferCommand{CopyID: f.onlyCopy(t).ID, ToBranchID: "north"})
+			},
+			toBranch:  "north",
+			expectErr: errors.ErrCopyNotInHouse,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newBranchFixture(t, "main")
+			if tc.setup != nil {
+				tc.setup(t, f)
+			}
+
+			copyID := f.onlyCopy(t).ID
+			err := f.start.Handle(context.Background(), StartTransferCommand{CopyID: copyID, ToBranchID: tc.toBranch})
+			if tc.expectErr != nil {
+				if err == nil || err.Error() != tc.expectErr.Error() {
+					t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if transfer := f.openTransfer(t, copyID); transfer.Reason != models.TransferManual {
+				t.Errorf("unexpected transfer %+v", transfer)
+			}
+		})
+	}
+}
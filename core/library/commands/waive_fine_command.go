@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/library/models"
+	"books/core/library/repositories"
+)
+
+type WaiveFineCommand struct {
+	UserID      string
+	AmountCents int64
+	Note        string
+}
+
+type WaiveFineCommandHandler struct {
+	ledger repositories.LedgerRepository
+}
+
+func NewWaiveFineCommandHandler(ledger repositories.LedgerRepository) *WaiveFineCommandHandler {
+	return &WaiveFineCommandHandler{ledger: ledger}
+}
+
+func (h *WaiveFineCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(WaiveFineCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	return settleBalance(ctx, h.ledger, models.NewLedgerEntry(
+		command.UserID, models.LedgerEntryWaiver, command.AmountCents, command.Note,
+	))
+}
@@ -8,4 +8,64 @@ var (
 	ErrNotFound  = errors.New("not found")
 	ErrDatabase  = errors.New("database error")
 	ErrInvalidID = errors.New("invalid ID")
+
+	ErrBookNotInStorage    = errors.New("book not found in storage")
+	ErrBookAlreadyBorrowed = errors.New("book is already borrowed by someone else")
+	ErrBookAlreadyRented   = errors.New("you already have this book, please return it before renting again")
+	ErrBookNotBorrowed     = errors.New("book is not currently borrowed")
+	ErrRentalNotOwned      = errors.New("book is borrowed by another user")
+	ErrRentalClosed        = errors.New("rental is already closed")
+	ErrLoanLimitReached    = errors.New("loan limit reached, please return a book before renting another")
+	ErrRenewalLimitReached = errors.New("renewal limit reached for this rental")
+	ErrRentalOverdue       = errors.New("overdue rentals cannot be renewed")
+	ErrBookOnHold          = errors.New("book is on hold for another patron")
+
+	ErrBookAvailable     = errors.New("book is available, rent it instead of placing a hold")
+	ErrHoldAlreadyPlaced = errors.New("you already have a hold on this book")
+	ErrHoldNotOwned      = errors.New("hold belongs to another user")
+	ErrHoldClosed        = errors.New("hold is no longer active")
+
+	ErrFinesOutstanding     = errors.New("unpaid fines are over the limit, please pay before renting")
+	ErrInvalidAmount        = errors.New("amount must be positive")
+	ErrAmountExceedsBalance = errors.New("amount exceeds the outstanding balance")
+
+	ErrRentalNotLost = errors.New("rental is not marked as lost")
+
+	ErrNotAtBranch         = errors.New("no copy of this book is on the shelf at this branch")
+	ErrCopyOnLoan          = errors.New("copy is on loan")
+	ErrCopyNotInHouse      = errors.New("copy is lost, withdrawn or already in transit")
+	ErrCopyAlreadyAtBranch = errors.New("copy is already at this branch")
+	ErrCopyWithoutBranch   = errors.New("copy has no branch, set its home branch first")
+	ErrTransferReceived    = errors.New("transfer has already been received")
+)
+
+// BorrowingBlockReason names the rule that stops a patron from borrowing.
+type BorrowingBlockReason string
+
+const (
+	BlockedByLoanLimit    BorrowingBlockReason = "loan_limit_reached"
+	BlockedByOverdueItems BorrowingBlockReason = "overdue_items"
 )
+
+var ErrOverdueItems = errors.New("overdue items must be returned before renting another")
+
+// BorrowingBlockedError is returned when a patron may not take out another
+// loan. It unwraps to ErrLoanLimitReached or ErrOverdueItems, so callers can
+// match the sentinel or read the counts behind it.
+type BorrowingBlockedError struct {
+	Reason       BorrowingBlockReason
+	Limit        int
+	OpenLoans    int
+	OverdueLoans int
+}
+
+func (e *BorrowingBlockedError) Error() string {
+	return e.Unwrap().Error()
+}
+
+func (e *BorrowingBlockedError) Unwrap() error {
+	if e.Reason == BlockedByOverdueItems {
+		return ErrOverdueItems
+	}
+	return ErrLoanLimitReached
+}
@@ -1,20 +1,52 @@
 package models
 
 import (
+	"math"
 	"time"
+
+	calendar_models "books/core/calendar/models"
+)
+
+// RentalCloseReason says how a closed rental ended. An empty reason is an
+// ordinary return.
+type RentalCloseReason string
+
+const (
+	RentalReturned RentalCloseReason = ""
+	RentalLost     RentalCloseReason = "lost"
+	RentalDamaged  RentalCloseReason = "damaged"
+	// RentalFound is a lost rental whose copy turned up again.
+	RentalFound RentalCloseReason = "found"
 )
 
 type BookRental struct {
-	BookID         string     `json:"book_id"`
-	UserID         string     `json:"user_id"`
-	BorrowedAt     time.Time  `json:"borrowed_at"`
-	ReturnDeadline time.Time  `json:"return_deadline"`
-	ReturnedAt     *time.Time `json:"returned_at,omitempty"`
+	ID             string            `json:"id"`
+	BookID         string            `json:"book_id"`
+	CopyID         string            `json:"copy_id"`
+	UserID         string            `json:"user_id"`
+	PatronType     string            `json:"patron_type,omitempty"`
+	BorrowedAt     time.Time         `json:"borrowed_at"`
+	ReturnDeadline time.Time         `json:"return_deadline"`
+	ReturnedAt     *time.Time        `json:"returned_at,omitempty"`
+	ReturnedLate   bool              `json:"returned_late"`
+	RenewalCount   int               `json:"renewal_count"`
+	CloseReason    RentalCloseReason `json:"close_reason,omitempty"`
 }
 
+// DefaultLoanPeriod is the loan period used by NewBookRental.
+const DefaultLoanPeriod = 14 * 24 * time.Hour
+
+// NewBookRental starts a rental of the default loan period at a library
+// that never closes.
 func NewBookRental(bookID, userID string) *BookRental {
+	return NewBookRentalForPeriod(bookID, userID, DefaultLoanPeriod, calendar_models.Calendar{})
+}
+
+// NewBookRentalForPeriod starts a rental that is due after the given loan
+// period, or on the next day the library opens after it.
+func NewBookRentalForPeriod(bookID, userID string, loanPeriod time.Duration, calendar calendar_models.Calendar) *BookRental {
 	now := time.Now()
-	deadline := now.Add(14 * 24 * time.Hour)
+	deadline := calendar.DueDate(now, loanPeriod)
 
 	return &BookRental{
 		BookID:         bookID,
@@ -28,9 +60,36 @@ func (b *BookRental) IsReturned() bool {
 	return b.ReturnedAt != nil
 }
 
+// MarkAsReturned closes the rental and records whether it came back after
+// the deadline. Returning an already closed rental has no effect.
 func (b *BookRental) MarkAsReturned() {
+	if b.IsReturned() {
+		return
+	}
+
+	b.ReturnedLate = b.IsOverdue()
+	now := time.Now()
+	b.ReturnedAt = &now
+}
+
+// MarkAsWrittenOff closes the rental without the copy coming back, because
+// it was lost or returned damaged. No overdue fine applies; the replacement
+// fee covers it. Closing an already closed rental has no effect.
+func (b *BookRental) MarkAsWrittenOff(reason RentalCloseReason) {
+	if b.IsReturned() {
+		return
+	}
+
 	now := time.Now()
 	b.ReturnedAt = &now
+	b.CloseReason = reason
+}
+
+// Renew pushes the deadline forward by the given loan period, moving it off
+// the days the library is closed.
+func (b *BookRental) Renew(loanPeriod time.Duration, calendar calendar_models.Calendar) {
+	b.ReturnDeadline = calendar.DueDate(b.ReturnDeadline, loanPeriod)
+	b.RenewalCount++
 }
 
 func (b *BookRental) IsOverdue() bool {
@@ -49,6 +108,31 @@ func (b *BookRental) DaysUntilDue() int {
 	return int(duration.Hours() / 24)
 }
 
+// OpenDaysUntilDue counts the days the library opens between now and the
+// deadline, which is negative once the rental is overdue.
+func (b *BookRental) OpenDaysUntilDue(calendar calendar_models.Calendar) int {
+	if b.IsReturned() {
+		return 0
+	}
+
+	return calendar.OpenDaysBetween(time.Now(), b.ReturnDeadline)
+}
+
+// DaysOverdue counts the started days between the deadline and the return,
+// or now while the book is still out.
+func (b *BookRental) DaysOverdue() int {
+	end := time.Now()
+	if b.IsReturned() {
+		end = *b.ReturnedAt
+	}
+
+	late := end.Sub(b.ReturnDeadline)
+	if late <= 0 {
+		return 0
+	}
+	return int(math.Ceil(late.Hours() / 24))
+}
+
 func BookIsAvailable(bookID string, rentals []*BookRental) bool {
 	for _, rental := range rentals {
 		if rental.BookID == bookID && !rental.IsReturned() {
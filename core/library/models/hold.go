@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"books/core/storage/models"
+)
+
+type HoldStatus string
+
+const (
+	// HoldStatusWaiting means the patron is queued behind a loan or an earlier hold.
+	HoldStatusWaiting HoldStatus = "waiting"
+	// HoldStatusReady means the book is set aside until the pickup deadline.
+	HoldStatusReady     HoldStatus = "ready"
+	HoldStatusFulfilled HoldStatus = "fulfilled"
+	HoldStatusCancelled HoldStatus = "cancelled"
+	HoldStatusExpired   HoldStatus = "expired"
+)
+
+type Hold struct {
+	ID     string `json:"id"`
+	BookID string `json:"book_id"`
+	UserID string `json:"user_id"`
+	// PickupBranchID is where the patron collects the book. A hold without
+	// one is served by a copy at any branch.
+	PickupBranchID string     `json:"pickup_branch_id,omitempty"`
+	Status         HoldStatus `json:"status"`
+	PlacedAt       time.Time  `json:"placed_at"`
+	ReadyAt        *time.Time `json:"ready_at,omitempty"`
+	PickupDeadline *time.Time `json:"pickup_deadline,omitempty"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty"`
+}
+
+func NewHold(bookID, userID string) *Hold {
+	return &Hold{
+		BookID:   bookID,
+		UserID:   userID,
+		Status:   HoldStatusWaiting,
+		PlacedAt: time.Now(),
+	}
+}
+
+// IsActive reports whether the hold is still in the queue.
+func (h *Hold) IsActive() bool {
+	return h.Status == HoldStatusWaiting || h.Status == HoldStatusReady
+}
+
+func (h *Hold) IsReady() bool {
+	return h.Status == HoldStatusReady
+}
+
+// IsPickupExpired reports whether a ready hold was not collected in time.
+func (h *Hold) IsPickupExpired() bool {
+	return h.IsReady() && h.PickupDeadline != nil && time.Now().After(*h.PickupDeadline)
+}
+
+// MarkReady sets the book aside for the patron until the pickup window ends.
+func (h *Hold) MarkReady(pickupWindow time.Duration) {
+	now := time.Now()
+	deadline := now.Add(pickupWindow)
+
+	h.Status = HoldStatusReady
+	h.ReadyAt = &now
+	h.PickupDeadline = &deadline
+}
+
+// CanBeServedBy reports whether the copy is at the hold's pickup branch.
+func (h *Hold) CanBeServedBy(bookCopy *models.Copy) bool {
+	return h.PickupBranchID == "" || bookCopy.IsAt(h.PickupBranchID)
+}
+
+func (h *Hold) Fulfill() {
+	h.close(HoldStatusFulfilled)
+}
+
+func (h *Hold) Cancel() {
+	h.close(HoldStatusCancelled)
+}
+
+func (h *Hold) Expire() {
+	h.close(HoldStatusExpired)
+}
+
+func (h *Hold) close(status HoldStatus) {
+	now := time.Now()
+	h.Status = status
+	h.ClosedAt = &now
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+)
+
+type LedgerEntryType string
+
+const (
+	LedgerEntryCharge  LedgerEntryType = "charge"
+	LedgerEntryPayment LedgerEntryType = "payment"
+	LedgerEntryWaiver  LedgerEntryType = "waiver"
+	// LedgerEntryReversal cancels an earlier charge, such as a replacement
+	// fee for a lost book that was found.
+	LedgerEntryReversal LedgerEntryType = "reversal"
+)
+
+// ReplacementFeeNote marks the charges made for lost and damaged copies.
+const ReplacementFeeNote = "replacement fee"
+
+// FineNote marks the overdue fine of a rental, which is charged once.
+const FineNote = "overdue fine"
+
+// LedgerEntry is one line of a patron's fines ledger. Amounts are in cents and
+// always positive; the entry type decides whether it adds to or settles the
+// balance.
+type LedgerEntry struct {
+	ID          string          `json:"id"`
+	UserID      string          `json:"user_id"`
+	Type        LedgerEntryType `json:"type"`
+	AmountCents int64           `json:"amount_cents"`
+	RentalID    string          `json:"rental_id,omitempty"`
+	Note        string          `json:"note,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+func NewLedgerEntry(userID string, entryType LedgerEntryType, amountCents int64, note string) *LedgerEntry {
+	return &LedgerEntry{
+		UserID:      userID,
+		Type:        entryType,
+		AmountCents: amountCents,
+		Note:        note,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// NewFineCharge charges the patron for a rental returned after its deadline.
+func NewFineCharge(rental *BookRental, amountCents int64) *LedgerEntry {
+	entry := NewLedgerEntry(rental.UserID, LedgerEntryCharge, amountCents, FineNote)
+	entry.RentalID = rental.ID
+	return entry
+}
+
+// NewReplacementCharge charges the patron for a copy that was lost or
+// returned damaged.
+func NewReplacementCharge(rental *BookRental, amountCents int64) *LedgerEntry {
+	entry := NewLedgerEntry(rental.UserID, LedgerEntryCharge, amountCents, ReplacementFeeNote)
+	entry.RentalID = rental.ID
+	return entry
+}
+
+// SignedAmount returns the entry's effect on the balance.
+func (e *LedgerEntry) SignedAmount() int64 {
+	if e.Type == LedgerEntryCharge {
+		return e.AmountCents
+	}
+	return -e.AmountCents
+}
+
+// FineAccount is a patron's ledger together with the unpaid balance.
+type FineAccount struct {
+	UserID       string         `json:"user_id"`
+	BalanceCents int64          `json:"balance_cents"`
+	Entries      []*LedgerEntry `json:"entries"`
+}
+
+func NewFineAccount(userID string, entries []*LedgerEntry) *FineAccount {
+	var balance int64
+	for _, entry := range entries {
+		balance += entry.SignedAmount()
+	}
+
+	return &FineAccount{
+		UserID:       userID,
+		BalanceCents: balance,
+		Entries:      entries,
+	}
+}
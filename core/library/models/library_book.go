@@ -3,40 +3,99 @@ package models
 import (
 	"time"
 
+	calendar_models "books/core/calendar/models"
 	"books/core/storage/models"
 )
 
 type LibraryBook struct {
-	ISBN        string    `json:"isbn"`
-	Title       string    `json:"title"`
-	Author      string    `json:"author"`
-	PublishedAt time.Time `json:"published_at"`
+	ISBN        string     `json:"isbn"`
+	Title       string     `json:"title"`
+	Author      string     `json:"author"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	Category    string     `json:"category"`
 
+	BranchID        string     `json:"branch_id,omitempty"`
+	TotalCopies     int        `json:"total_copies"`
+	AvailableCopies int        `json:"available_copies"`
+	InTransitCopies int        `json:"in_transit_copies"`
 	IsAvailable     bool       `json:"is_available"`
 	CurrentBorrower string     `json:"current_borrower,omitempty"`
 	DueDate         *time.Time `json:"due_date,omitempty"`
 	IsOverdue       bool       `json:"is_overdue,omitempty"`
 }
 
-func NewLibraryBookFromStorageBook(book *models.Book, rentals []*BookRental) *LibraryBook {
+// NewLibraryBookFromStorageBook counts the book's copies in circulation and
+// those of them not on loan. Copies in transit between branches count towards
+// the total but are not available. When every copy is out, the borrower and
+// due date are those of the copy expected back first.
+func NewLibraryBookFromStorageBook(book *models.Book, copies []*models.Copy, rentals []*BookRental) *LibraryBook {
 	libraryBook := &LibraryBook{
 		ISBN:        book.ISBN,
 		Title:       book.Title,
 		Author:      book.Author,
 		PublishedAt: book.PublishedAt,
-		IsAvailable: true,
+		Category:    book.Category,
 	}
 
+	onLoan := make(map[string]*BookRental)
 	for _, rental := range rentals {
 		if rental.BookID == book.ISBN && !rental.IsReturned() {
-			libraryBook.IsAvailable = false
-			libraryBook.CurrentBorrower = rental.UserID
-			libraryBook.DueDate = &rental.ReturnDeadline
-			libraryBook.IsOverdue = rental.IsOverdue()
-			break
+			onLoan[rental.CopyID] = rental
 		}
 	}
 
+	var nextDue *BookRental
+	for _, bookCopy := range copies {
+		if bookCopy.ISBN != book.ISBN {
+			continue
+		}
+		if bookCopy.InTransit() {
+			libraryBook.TotalCopies++
+			libraryBook.InTransitCopies++
+			continue
+		}
+		if !bookCopy.InCirculation() {
+			continue
+		}
+		libraryBook.TotalCopies++
+
+		rental, out := onLoan[bookCopy.ID]
+		if !out {
+			libraryBook.AvailableCopies++
+			continue
+		}
+		if nextDue == nil || rental.ReturnDeadline.Before(nextDue.ReturnDeadline) {
+			nextDue = rental
+		}
+	}
+
+	libraryBook.IsAvailable = libraryBook.AvailableCopies > 0
+	if !libraryBook.IsAvailable && nextDue != nil {
+		libraryBook.CurrentBorrower = nextDue.UserID
+		libraryBook.DueDate = &nextDue.ReturnDeadline
+		libraryBook.IsOverdue = nextDue.IsOverdue()
+	}
+
+	return libraryBook
+}
+
+// NewLibraryBookAtBranch is NewLibraryBookFromStorageBook counting only the
+// copies at the given branch. Copies in transit count at the branch they
+// left until they are received. An empty branch counts every copy.
+func NewLibraryBookAtBranch(book *models.Book, copies []*models.Copy, rentals []*BookRental, branchID string) *LibraryBook {
+	if branchID == "" {
+		return NewLibraryBookFromStorageBook(book, copies, rentals)
+	}
+
+	atBranch := make([]*models.Copy, 0, len(copies))
+	for _, bookCopy := range copies {
+		if bookCopy.IsAt(branchID) {
+			atBranch = append(atBranch, bookCopy)
+		}
+	}
+
+	libraryBook := NewLibraryBookFromStorageBook(book, atBranch, rentals)
+	libraryBook.BranchID = branchID
 	return libraryBook
 }
 
@@ -48,3 +107,12 @@ func (lb *LibraryBook) DaysUntilDue() int {
 	duration := time.Until(*lb.DueDate)
 	return int(duration.Hours() / 24)
 }
+
+// OpenDaysUntilDue counts the days the library opens before the next copy is due back.
+func (lb *LibraryBook) OpenDaysUntilDue(calendar calendar_models.Calendar) int {
+	if lb.IsAvailable || lb.DueDate == nil {
+		return 0
+	}
+
+	return calendar.OpenDaysBetween(time.Now(), *lb.DueDate)
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// TransferReason says why a copy was sent to another branch.
+type TransferReason string
+
+const (
+	// TransferManual is a transfer started by staff.
+	TransferManual TransferReason = "manual"
+	// TransferReturn sends a copy returned at another branch back home.
+	TransferReturn TransferReason = "return"
+	// TransferHold sends a copy to the pickup branch of a hold.
+	TransferHold TransferReason = "hold"
+)
+
+// Transfer is the trip of a copy from one branch to another. The copy is in
+// transit from the start of the transfer until it is received.
+type Transfer struct {
+	ID           string         `json:"id"`
+	CopyID       string         `json:"copy_id"`
+	BookID       string         `json:"book_id"`
+	FromBranchID string         `json:"from_branch_id"`
+	ToBranchID   string         `json:"to_branch_id"`
+	Reason       TransferReason `json:"reason"`
+	StartedAt    time.Time      `json:"started_at"`
+	ReceivedAt   *time.Time     `json:"received_at,omitempty"`
+}
+
+func NewTransfer(copyID, bookID, fromBranchID, toBranchID string, reason TransferReason) *Transfer {
+	return &Transfer{
+		CopyID:       copyID,
+		BookID:       bookID,
+		FromBranchID: fromBranchID,
+		ToBranchID:   toBranchID,
+		Reason:       reason,
+		StartedAt:    time.Now(),
+	}
+}
+
+// IsOpen reports whether the copy is still on its way.
+func (t *Transfer) IsOpen() bool {
+	return t.ReceivedAt == nil
+}
+
+// Receive records the copy's arrival. Receiving a closed transfer has no
+// effect.
+func (t *Transfer) Receive() {
+	if !t.IsOpen() {
+		return
+	}
+
+	now := time.Now()
+	t.ReceivedAt = &now
+}
@@ -0,0 +1,55 @@
+package policies
+
+import (
+	"time"
+)
+
+// DefaultHoldPickupWindow is how long a returned book waits on the hold shelf
+// for the next patron in the queue.
+const DefaultHoldPickupWindow = 3 * 24 * time.Hour
+
+// DefaultMembershipPeriod is how long a patron registration or renewal lasts.
+const DefaultMembershipPeriod = 365 * 24 * time.Hour
+
+// DefaultMaxUnpaidFinesCents is the unpaid balance above which patrons may not borrow.
+const DefaultMaxUnpaidFinesCents = 500
+
+// BorrowingLimits caps how many loans a patron may have open at once,
+// whatever the books. Patron types without a limit are only held to the
+// loan policy's MaxConcurrentLoans.
+type BorrowingLimits map[string]int
+
+// DefaultBorrowingLimits is used when no configuration is provided.
+func DefaultBorrowingLimits() BorrowingLimits {
+	return BorrowingLimits{
+		"adult": 5,
+		"child": 2,
+	}
+}
+
+// Limit returns the cap for a patron type and whether there is one.
+func (l BorrowingLimits) Limit(patronType string) (int, bool) {
+	limit, ok := l[patronType]
+	return limit, ok && limit > 0
+}
+
+// LendingRules groups the configurable rules enforced by the library commands.
+type LendingRules struct {
+	Loans            *LoanPolicies
+	HoldPickupWindow time.Duration
+	// MaxUnpaidFinesCents blocks new loans for patrons who owe more than this.
+	MaxUnpaidFinesCents int64
+	MembershipPeriod    time.Duration
+	BorrowingLimits     BorrowingLimits
+}
+
+// DefaultLendingRules is used when no configuration is provided.
+func DefaultLendingRules() LendingRules {
+	return LendingRules{
+		Loans:               DefaultLoanPolicies(),
+		HoldPickupWindow:    DefaultHoldPickupWindow,
+		MaxUnpaidFinesCents: DefaultMaxUnpaidFinesCents,
+		MembershipPeriod:    DefaultMembershipPeriod,
+		BorrowingLimits:     DefaultBorrowingLimits(),
+	}
+}
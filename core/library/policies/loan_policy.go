@@ -0,0 +1,176 @@
+package policies
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Wildcard matches any book category or patron type.
+const Wildcard = "*"
+
+// LoanPolicy describes how long a book may be borrowed, how many loans a
+// patron may hold and what a late return costs. Empty Category or PatronType
+// fields match anything. Fines are in cents; a zero MaxFineCents means the
+// fine is not capped. ReplacementFeeCents is charged for a lost or damaged
+// copy; a policy that leaves it at zero charges the default policy's fee.
+type LoanPolicy struct {
+	Name                string `json:"name"`
+	Category            string `json:"category"`
+	PatronType          string `json:"patron_type"`
+	LoanPeriodDays      int    `json:"loan_period_days"`
+	MaxRenewals         int    `json:"max_renewals"`
+	MaxConcurrentLoans  int    `json:"max_concurrent_loans"`
+	FinePerDayCents     int64  `json:"fine_per_day_cents"`
+	MaxFineCents        int64  `json:"max_fine_cents"`
+	ReplacementFeeCents int64  `json:"replacement_fee_cents"`
+}
+
+// LoanPeriod returns the loan period as a duration.
+func (p LoanPolicy) LoanPeriod() time.Duration {
+	return time.Duration(p.LoanPeriodDays) * 24 * time.Hour
+}
+
+// Fine returns the overdue fine for a loan returned the given number of days late.
+func (p LoanPolicy) Fine(daysOverdue int) int64 {
+	if daysOverdue <= 0 {
+		return 0
+	}
+
+	fine := int64(daysOverdue) * p.FinePerDayCents
+	if p.MaxFineCents > 0 && fine > p.MaxFineCents {
+		return p.MaxFineCents
+	}
+	return fine
+}
+
+func (p LoanPolicy) matches(category, patronType string) bool {
+	return matchesField(p.Category, category) && matchesField(p.PatronType, patronType)
+}
+
+// specificity ranks policies so that a category match beats a patron type
+// match, and a match on both beats either one.
+func (p LoanPolicy) specificity() int {
+	score := 0
+	if !isWildcard(p.Category) {
+		score += 2
+	}
+	if !isWildcard(p.PatronType) {
+		score++
+	}
+	return score
+}
+
+func (p LoanPolicy) validate() error {
+	if p.LoanPeriodDays <= 0 {
+		return fmt.Errorf("loan policy %q: loan period must be positive", p.Name)
+	}
+	if p.MaxRenewals < 0 {
+		return fmt.Errorf("loan policy %q: max renewals cannot be negative", p.Name)
+	}
+	if p.MaxConcurrentLoans <= 0 {
+		return fmt.Errorf("loan policy %q: max concurrent loans must be positive", p.Name)
+	}
+	if p.FinePerDayCents < 0 || p.MaxFineCents < 0 || p.ReplacementFeeCents < 0 {
+		return fmt.Errorf("loan policy %q: fines cannot be negative", p.Name)
+	}
+	return nil
+}
+
+// LoanPolicies picks the most specific policy for a book category and patron
+// type, falling back to a default policy when nothing matches.
+type LoanPolicies struct {
+	fallback LoanPolicy
+	policies []LoanPolicy
+}
+
+type loanPoliciesFile struct {
+	Default  LoanPolicy   `json:"default"`
+	Policies []LoanPolicy `json:"policies"`
+}
+
+// DefaultLoanPolicy is used when no configuration is provided.
+var DefaultLoanPolicy = LoanPolicy{
+	Name:                "default",
+	LoanPeriodDays:      14,
+	MaxRenewals:         2,
+	MaxConcurrentLoans:  5,
+	FinePerDayCents:     25,
+	MaxFineCents:        1000,
+	ReplacementFeeCents: 2500,
+}
+
+func NewLoanPolicies(fallback LoanPolicy, policies []LoanPolicy) (*LoanPolicies, error) {
+	if err := fallback.validate(); err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if err := policy.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &LoanPolicies{
+		fallback: fallback,
+		policies: policies,
+	}, nil
+}
+
+// DefaultLoanPolicies returns a policy set containing only DefaultLoanPolicy.
+func DefaultLoanPolicies() *LoanPolicies {
+	return &LoanPolicies{fallback: DefaultLoanPolicy}
+}
+
+// LoadLoanPolicies reads a JSON policy file of the form
+// {"default": {...}, "policies": [{...}, ...]}.
+func LoadLoanPolicies(path string) (*LoanPolicies, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read loan policies: %w", err)
+	}
+
+	var file loanPoliciesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse loan policies: %w", err)
+	}
+
+	if file.Default == (LoanPolicy{}) {
+		return nil, errors.New("loan policies: default policy is required")
+	}
+
+	return NewLoanPolicies(file.Default, file.Policies)
+}
+
+// Resolve returns the policy for a book category and patron type. When two
+// policies are equally specific, the one listed first wins. A policy without
+// a replacement fee takes the default policy's, so every lost copy is
+// charged.
+func (p *LoanPolicies) Resolve(category, patronType string) LoanPolicy {
+	best := p.fallback
+	bestScore := -1
+
+	for _, policy := range p.policies {
+		if !policy.matches(category, patronType) {
+			continue
+		}
+		if score := policy.specificity(); score > bestScore {
+			best = policy
+			bestScore = score
+		}
+	}
+
+	if best.ReplacementFeeCents == 0 {
+		best.ReplacementFeeCents = p.fallback.ReplacementFeeCents
+	}
+	return best
+}
+
+func isWildcard(value string) bool {
+	return value == "" || value == Wildcard
+}
+
+func matchesField(policyValue, value string) bool {
+	return isWildcard(policyValue) || policyValue == value
+}
@@ -0,0 +1,151 @@
+package policies
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testPolicies(t *testing.T) *LoanPolicies {
+	policies, err := NewLoanPolicies(DefaultLoanPolicy, []LoanPolicy{
+		{Name: "new-release", Category: "new_release", LoanPeriodDays: 7, MaxRenewals: 0, MaxConcurrentLoans: 5},
+		{Name: "reference", Category: "reference", LoanPeriodDays: 3, MaxRenewals: 0, MaxConcurrentLoans: 1},
+		{Name: "staff", PatronType: "staff", LoanPeriodDays: 28, MaxRenewals: 5, MaxConcurrentLoans: 20},
+		{Name: "staff-reference", Category: "reference", PatronType: "staff", LoanPeriodDays: 7, MaxRenewals: 1, MaxConcurrentLoans: 3},
+	})
+	if err != nil {
+		t.Fatalf("failed to build policies: %v", err)
+	}
+	return policies
+}
+
+func TestLoanPolicies_Resolve(t *testing.T) {
+	policies := testPolicies(t)
+
+	tests := []struct {
+		name         string
+		category     string
+		patronType   string
+		expectedName string
+	}{
+		{name: "fallback", category: "standard", patronType: "adult", expectedName: "default"},
+		{name: "category only", category: "new_release", patronType: "adult", expectedName: "new-release"},
+		{name: "patron type only", category: "standard", patronType: "staff", expectedName: "staff"},
+		{name: "category beats patron type", category: "new_release", patronType: "staff", expectedName: "new-release"},
+		{name: "category and patron type", category: "reference", patronType: "staff", expectedName: "staff-reference"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := policies.Resolve(tc.category, tc.patronType)
+			if policy.Name != tc.expectedName {
+				t.Errorf("expected policy '%s', got '%s'", tc.expectedName, policy.Name)
+			}
+		})
+	}
+}
+
+func TestLoanPolicies_ResolveReplacementFee(t *testing.T) {
+	policies, err := NewLoanPolicies(DefaultLoanPolicy, []LoanPolicy{
+		{Name: "reference", Category: "reference", LoanPeriodDays: 3, MaxConcurrentLoans: 1, ReplacementFeeCents: 6000},
+		{Name: "staff", PatronType: "staff", LoanPeriodDays: 28, MaxConcurrentLoans: 20},
+	})
+	if err != nil {
+		t.Fatalf("failed to build policies: %v", err)
+	}
+
+	if fee := policies.Resolve("reference", "adult").ReplacementFeeCents; fee != 6000 {
+		t.Errorf("expected the policy's own fee of 6000, got %d", fee)
+	}
+	if fee := policies.Resolve("standard", "staff").ReplacementFeeCents; fee != DefaultLoanPolicy.ReplacementFeeCents {
+		t.Errorf("expected the default fee of %d, got %d", DefaultLoanPolicy.ReplacementFeeCents, fee)
+	}
+}
+
+func TestLoanPolicy_LoanPeriod(t *testing.T) {
+	policy := LoanPolicy{LoanPeriodDays: 3}
+	if policy.LoanPeriod() != 72*time.Hour {
+		t.Errorf("expected 72h, got %v", policy.LoanPeriod())
+	}
+}
+
+func TestLoanPolicy_Fine(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      LoanPolicy
+		daysOverdue int
+		expected    int64
+	}{
+		{name: "on time", policy: LoanPolicy{FinePerDayCents: 25, MaxFineCents: 1000}, daysOverdue: 0, expected: 0},
+		{name: "per day", policy: LoanPolicy{FinePerDayCents: 25, MaxFineCents: 1000}, daysOverdue: 3, expected: 75},
+		{name: "capped", policy: LoanPolicy{FinePerDayCents: 25, MaxFineCents: 1000}, daysOverdue: 100, expected: 1000},
+		{name: "uncapped", policy: LoanPolicy{FinePerDayCents: 25}, daysOverdue: 100, expected: 2500},
+		{name: "no fine", policy: LoanPolicy{}, daysOverdue: 10, expected: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if fine := tc.policy.Fine(tc.daysOverdue); fine != tc.expected {
+				t.Errorf("expected %d, got %d", tc.expected, fine)
+			}
+		})
+	}
+}
+
+func TestNewLoanPolicies_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy LoanPolicy
+	}{
+		{name: "zero loan period", policy: LoanPolicy{Name: "bad", LoanPeriodDays: 0, MaxConcurrentLoans: 1}},
+		{name: "negative renewals", policy: LoanPolicy{Name: "bad", LoanPeriodDays: 7, MaxRenewals: -1, MaxConcurrentLoans: 1}},
+		{name: "zero concurrent loans", policy: LoanPolicy{Name: "bad", LoanPeriodDays: 7}},
+		{name: "negative fine", policy: LoanPolicy{Name: "bad", LoanPeriodDays: 7, MaxConcurrentLoans: 1, FinePerDayCents: -5}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewLoanPolicies(DefaultLoanPolicy, []LoanPolicy{tc.policy})
+			if err == nil {
+				t.Errorf("expected validation error")
+			}
+		})
+	}
+}
+
+func TestLoadLoanPolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "loan_policies.json")
+	content := `{
+		"default": {"name": "default", "loan_period_days": 21, "max_renewals": 1, "max_concurrent_loans": 4},
+		"policies": [
+			{"name": "reference", "category": "reference", "loan_period_days": 3, "max_renewals": 0, "max_concurrent_loans": 1}
+		]
+	}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write policy file: %v", err)
+	}
+
+	policies, err := LoadLoanPolicies(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if policy := policies.Resolve("standard", "adult"); policy.LoanPeriodDays != 21 {
+		t.Errorf("expected default loan period 21, got %d", policy.LoanPeriodDays)
+	}
+	if policy := policies.Resolve("reference", "adult"); policy.LoanPeriodDays != 3 {
+		t.Errorf("expected reference loan period 3, got %d", policy.LoanPeriodDays)
+	}
+}
+
+func TestLoadLoanPolicies_MissingDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "loan_policies.json")
+	if err := os.WriteFile(path, []byte(`{"policies": []}`), 0o600); err != nil {
+		t.Fatalf("failed to write policy file: %v", err)
+	}
+
+	if _, err := LoadLoanPolicies(path); err == nil {
+		t.Errorf("expected error for missing default policy")
+	}
+}
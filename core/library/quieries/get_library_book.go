@@ -0,0 +1,107 @@
+package queries
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/isbn"
+	"books/core/library/models"
+	"books/core/library/repositories"
+	"books/core/storage/repositories/interfaces"
+)
+
+// GetLibraryBookQuery builds the lending view of a book, counting only the
+// copies at BranchID when it is set.
+type GetLibraryBookQuery struct {
+	ISBN     string
+	BranchID string
+}
+
+type ListLibraryBooksQuery struct {
+	BranchID string
+}
+
+type GetLibraryBookQueryHandler struct {
+	books   interfaces.BookRepository
+	copies  interfaces.CopyRepository
+	rentals repositories.BookRepository
+}
+
+func NewGetLibraryBookQueryHandler(books interfaces.BookRepository, copies interfaces.CopyRepository, rentals repositories.BookRepository) *GetLibraryBookQueryHandler {
+	return &GetLibraryBookQueryHandler{
+		books:   books,
+		copies:  copies,
+		rentals: rentals,
+	}
+}
+
+// Handle builds the lending view of a single catalog book.
+func (h *GetLibraryBookQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	query, ok := q.(GetLibraryBookQuery)
+	if !ok {
+		return nil, stderrors.New("invalid query type")
+	}
+	query.ISBN = isbn.Key(query.ISBN)
+
+	book, err := h.books.FindByISBN(ctx, query.ISBN)
+	if err != nil {
+		return nil, err
+	}
+
+	copies, err := h.copies.FindCopiesByISBN(ctx, query.ISBN)
+	if err != nil {
+		return nil, err
+	}
+
+	rentals, err := h.rentals.GetActiveBookRentalsByBookID(ctx, query.ISBN)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewLibraryBookAtBranch(book, copies, rentals, query.BranchID), nil
+}
+
+type ListLibraryBooksQueryHandler struct {
+	books   interfaces.BookRepository
+	copies  interfaces.CopyRepository
+	rentals repositories.BookRepository
+}
+
+func NewListLibraryBooksQueryHandler(books interfaces.BookRepository, copies interfaces.CopyRepository, rentals repositories.BookRepository) *ListLibraryBooksQueryHandler {
+	return &ListLibraryBooksQueryHandler{
+		books:   books,
+		copies:  copies,
+		rentals: rentals,
+	}
+}
+
+// Handle builds the lending view of the whole catalog with one read per
+// repository rather than one per book.
+func (h *ListLibraryBooksQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	query, ok := q.(ListLibraryBooksQuery)
+	if !ok {
+		return nil, stderrors.New("invalid query type")
+	}
+
+	books, err := h.books.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	copies, err := h.copies.FindAllCopies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rentals, err := h.rentals.GetActiveBookRentals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	libraryBooks := make([]*models.LibraryBook, 0, len(books))
+	for _, book := range books {
+		libraryBooks = append(libraryBooks, models.NewLibraryBookAtBranch(book, copies, rentals, query.BranchID))
+	}
+
+	return libraryBooks, nil
+}
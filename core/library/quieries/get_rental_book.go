@@ -0,0 +1,32 @@
+package queries
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/library/repositories"
+)
+
+type GetRentalBookQuery struct {
+	RentalID string
+}
+
+type GetRentalBookQueryHandler struct {
+	repo repositories.BookRepository
+}
+
+func NewGetRentalBookQueryHandler(repo repositories.BookRepository) *GetRentalBookQueryHandler {
+	return &GetRentalBookQueryHandler{
+		repo: repo,
+	}
+}
+
+// Handle returns the rental with the given ID, open or closed.
+func (h *GetRentalBookQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	query, ok := q.(GetRentalBookQuery)
+	if !ok {
+		return nil, stderrors.New("invalid query type")
+	}
+
+	return h.repo.GetBookRentalByID(ctx, query.RentalID)
+}
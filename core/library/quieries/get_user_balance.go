@@ -0,0 +1,38 @@
+package queries
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/library/models"
+	"books/core/library/repositories"
+)
+
+type GetUserBalanceQuery struct {
+	UserID string
+}
+
+type GetUserBalanceQueryHandler struct {
+	ledger repositories.LedgerRepository
+}
+
+func NewGetUserBalanceQueryHandler(ledger repositories.LedgerRepository) *GetUserBalanceQueryHandler {
+	return &GetUserBalanceQueryHandler{
+		ledger: ledger,
+	}
+}
+
+// Handle returns the user's fines ledger and unpaid balance.
+func (h *GetUserBalanceQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	query, ok := q.(GetUserBalanceQuery)
+	if !ok {
+		return nil, stderrors.New("invalid query type")
+	}
+
+	entries, err := h.ledger.GetUserLedgerEntries(ctx, query.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewFineAccount(query.UserID, entries), nil
+}
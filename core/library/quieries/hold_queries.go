@@ -0,0 +1,56 @@
+package queries
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/isbn"
+	"books/core/library/models"
+	"books/core/library/repositories"
+)
+
+type GetHoldQuery struct {
+	HoldID string
+}
+
+// ListBookHoldsQuery returns the active holds of a book in queue order, after
+// expiring the ready holds not picked up in time and promoting the next ones.
+type ListBookHoldsQuery struct {
+	BookID string
+}
+
+type ListUserHoldsQuery struct {
+	UserID string
+}
+
+// HoldSyncer brings a book's hold queue up to date and returns its active
+// holds, as the commands' HoldQueue does.
+type HoldSyncer interface {
+	Sync(ctx context.Context, bookID string) ([]*models.Hold, error)
+}
+
+type HoldQueryHandler struct {
+	repo  repositories.HoldRepository
+	queue HoldSyncer
+}
+
+func NewHoldQueryHandler(repo repositories.HoldRepository, queue HoldSyncer) *HoldQueryHandler {
+	return &HoldQueryHandler{
+		repo:  repo,
+		queue: queue,
+	}
+}
+
+// Handle answers every hold query, so it is registered once per query type.
+func (h *HoldQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	switch query := q.(type) {
+	case GetHoldQuery:
+		return h.repo.GetHoldByID(ctx, query.HoldID)
+	case ListBookHoldsQuery:
+		return h.queue.Sync(ctx, isbn.Key(query.BookID))
+	case ListUserHoldsQuery:
+		return h.repo.GetUserHolds(ctx, query.UserID)
+	default:
+		return nil, stderrors.New("invalid query type")
+	}
+}
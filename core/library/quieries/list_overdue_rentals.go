@@ -0,0 +1,47 @@
+package queries
+
+import (
+	"context"
+	stderrors "errors"
+	"sort"
+
+	"books/core/library/models"
+	"books/core/library/repositories"
+)
+
+type ListOverdueRentalsQuery struct{}
+
+type ListOverdueRentalsQueryHandler struct {
+	repo repositories.BookRepository
+}
+
+func NewListOverdueRentalsQueryHandler(repo repositories.BookRepository) *ListOverdueRentalsQueryHandler {
+	return &ListOverdueRentalsQueryHandler{
+		repo: repo,
+	}
+}
+
+// Handle returns the open rentals past their deadline, longest overdue first.
+func (h *ListOverdueRentalsQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	if _, ok := q.(ListOverdueRentalsQuery); !ok {
+		return nil, stderrors.New("invalid query type")
+	}
+
+	rentals, err := h.repo.GetActiveBookRentals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	overdue := make([]*models.BookRental, 0)
+	for _, rental := range rentals {
+		if rental.IsOverdue() {
+			overdue = append(overdue, rental)
+		}
+	}
+
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return overdue[i].ReturnDeadline.Before(overdue[j].ReturnDeadline)
+	})
+
+	return overdue, nil
+}
@@ -0,0 +1,32 @@
+package queries
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/library/repositories"
+)
+
+type ListUserRentalsQuery struct {
+	UserID string
+}
+
+type ListUserRentalsQueryHandler struct {
+	repo repositories.BookRepository
+}
+
+func NewListUserRentalsQueryHandler(repo repositories.BookRepository) *ListUserRentalsQueryHandler {
+	return &ListUserRentalsQueryHandler{
+		repo: repo,
+	}
+}
+
+// Handle returns every rental of the user, returned ones included.
+func (h *ListUserRentalsQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	query, ok := q.(ListUserRentalsQuery)
+	if !ok {
+		return nil, stderrors.New("invalid query type")
+	}
+
+	return h.repo.GetAllUserRentals(ctx, query.UserID)
+}
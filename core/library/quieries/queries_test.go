@@ -0,0 +1,126 @@
+package queries
+
+import (
+	branch_repositories "books/core/branches/repositories"
+	"books/core/library/commands"
+	"books/core/library/models"
+	"books/core/library/repositories"
+	storage_models "books/core/storage/models"
+	storage_repositories "books/core/storage/repositories"
+	"context"
+	"testing"
+	"time"
+)
+
+type queryFixture struct {
+	books   *storage_repositories.BookStorageInMemoryRepository
+	copies  *storage_repositories.CopyStorageInMemoryRepository
+	rentals *repositories.BookRentalInMemoryRepository
+}
+
+// newQueryFixture stocks one copy of each ISBN and lends it to user1, due
+// after the matching deadline offset.
+func newQueryFixture(t *testing.T, dueIn map[string]time.Duration) *queryFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	f := &queryFixture{
+		books:  storage_repositories.NewBookStorageInMemoryRepository(),
+		copies: storage_repositories.NewCopyStorageInMemoryRepository(),
+	}
+	f.rentals = repositories.NewBookRentalInMemoryRepository(f.books, f.copies)
+
+	for isbn, due := range dueIn {
+		if err := f.books.Save(ctx, &storage_models.Book{ISBN: isbn, Title: "Book " + isbn}); err != nil {
+			t.Fatalf("failed to save book: %v", err)
+		}
+		bookCopy, err := storage_models.NewCopy(isbn, storage_models.DefaultBarcode(isbn, 1), "", "")
+		if err != nil {
+			t.Fatalf("failed to create copy: %v", err)
+		}
+		if err := f.copies.SaveCopy(ctx, bookCopy); err != nil {
+			t.Fatalf("failed to save copy: %v", err)
+		}
+
+		rental := models.NewBookRental(isbn, "user1")
+		rental.CopyID = bookCopy.ID
+		rental.ReturnDeadline = time.Now().Add(due)
+		if err := f.rentals.SaveBookRental(ctx, rental); err != nil {
+			t.Fatalf("failed to save rental: %v", err)
+		}
+	}
+
+	return f
+}
+
+func TestListOverdueRentalsQueryHandler_Handle(t *testing.T) {
+	f := newQueryFixture(t, map[string]time.Duration{
+		"book1": -24 * time.Hour,
+		"book2": 24 * time.Hour,
+		"book3": -72 * time.Hour,
+	})
+	handler := NewListOverdueRentalsQueryHandler(f.rentals)
+
+	result, err := handler.Handle(context.Background(), ListOverdueRentalsQuery{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	overdue := result.([]*models.BookRental)
+	if len(overdue) != 2 || overdue[0].BookID != "book3" || overdue[1].BookID != "book1" {
+		t.Errorf("expected book3 then book1, got %+v", overdue)
+	}
+
+	if _, err := handler.Handle(context.Background(), ListUserRentalsQuery{}); err == nil {
+		t.Errorf("expected invalid query type to be rejected")
+	}
+}
+
+func TestGetLibraryBookQueryHandler_Handle(t *testing.T) {
+	f := newQueryFixture(t, map[string]time.Duration{"book1": 24 * time.Hour})
+	handler := NewGetLibraryBookQueryHandler(f.books, f.copies, f.rentals)
+
+	result, err := handler.Handle(context.Background(), GetLibraryBookQuery{ISBN: "book1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	book := result.(*models.LibraryBook)
+	if book.IsAvailable || book.TotalCopies != 1 || book.CurrentBorrower != "user1" {
+		t.Errorf("expected the only copy to be out with user1, got %+v", book)
+	}
+
+	if _, err := handler.Handle(context.Background(), GetLibraryBookQuery{ISBN: "missing"}); err == nil {
+		t.Errorf("expected unknown book to be rejected")
+	}
+}
+
+func TestListBookHoldsQueryExpiresLapsedPickups(t *testing.T) {
+	ctx := context.Background()
+	f := newQueryFixture(t, map[string]time.Duration{"book1": 24 * time.Hour})
+	holds := repositories.NewHoldInMemoryRepository()
+	mover := commands.NewCopyMover(f.copies, repositories.NewTransferInMemoryRepository(), branch_repositories.NewBranchInMemoryRepository())
+	handler := NewHoldQueryHandler(holds, commands.NewHoldQueue(holds, f.rentals, mover, time.Hour))
+
+	lapsed := models.NewHold("book1", "user2")
+	lapsed.MarkReady(-time.Hour)
+	waiting := models.NewHold("book1", "user3")
+	for _, hold := range []*models.Hold{lapsed, waiting} {
+		if err := holds.SaveHold(ctx, hold); err != nil {
+			t.Fatalf("failed to save hold: %v", err)
+		}
+	}
+
+	result, err := handler.Handle(ctx, ListBookHoldsQuery{BookID: "book1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	queue := result.([]*models.Hold)
+	if len(queue) != 1 || queue[0].UserID != "user3" {
+		t.Errorf("expected only the waiting hold to be listed, got %+v", queue)
+	}
+	if stored, _ := holds.GetHoldByID(ctx, lapsed.ID); stored.Status != models.HoldStatusExpired {
+		t.Errorf("expected the lapsed hold to be expired, got %s", stored.Status)
+	}
+}
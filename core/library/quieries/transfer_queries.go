@@ -0,0 +1,47 @@
+package queries
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/library/repositories"
+)
+
+type GetTransferQuery struct {
+	TransferID string
+}
+
+// GetCopyTransferQuery returns the transfer of a copy in transit.
+type GetCopyTransferQuery struct {
+	CopyID string
+}
+
+// ListInboundTransfersQuery returns the copies on their way to a branch,
+// oldest first.
+type ListInboundTransfersQuery struct {
+	BranchID string
+}
+
+type TransferQueryHandler struct {
+	repo repositories.TransferRepository
+}
+
+func NewTransferQueryHandler(repo repositories.TransferRepository) *TransferQueryHandler {
+	return &TransferQueryHandler{
+		repo: repo,
+	}
+}
+
+// Handle answers every transfer query, so it is registered once per query type.
+func (h *TransferQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	switch query := q.(type) {
+	case GetTransferQuery:
+		return h.repo.GetTransferByID(ctx, query.TransferID)
+	case GetCopyTransferQuery:
+		return h.repo.GetOpenTransferByCopyID(ctx, query.CopyID)
+	case ListInboundTransfersQuery:
+		return h.repo.GetOpenTransfersToBranch(ctx, query.BranchID)
+	default:
+		return nil, stderrors.New("invalid query type")
+	}
+}
@@ -0,0 +1,235 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	stderrors "errors"
+	"sort"
+	"sync"
+
+	"books/core/library/errors"
+	"books/core/library/models"
+	storage_models "books/core/storage/models"
+	"books/core/storage/repositories/interfaces"
+)
+
+type BookRentalInMemoryRepository struct {
+	books   interfaces.BookRepository
+	copies  interfaces.CopyRepository
+	rentals []*models.BookRental
+	mutex   sync.RWMutex
+}
+
+func NewBookRentalInMemoryRepository(books interfaces.BookRepository, copies interfaces.CopyRepository) *BookRentalInMemoryRepository {
+	return &BookRentalInMemoryRepository{
+		books:   books,
+		copies:  copies,
+		rentals: make([]*models.BookRental, 0),
+	}
+}
+
+func (r *BookRentalInMemoryRepository) GetBookByISBN(ctx context.Context, isbn string) (*storage_models.Book, error) {
+	book, err := r.books.FindByISBN(ctx, isbn)
+	if stderrors.Is(err, interfaces.ErrBookNotFound) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+func (r *BookRentalInMemoryRepository) BookExists(ctx context.Context, isbn string) (bool, error) {
+	_, err := r.GetBookByISBN(ctx, isbn)
+	if stderrors.Is(err, errors.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *BookRentalInMemoryRepository) GetCopiesByISBN(ctx context.Context, isbn string) ([]*storage_models.Copy, error) {
+	return r.copies.FindCopiesByISBN(ctx, isbn)
+}
+
+func (r *BookRentalInMemoryRepository) GetBookRentalByID(ctx context.Context, id string) (*models.BookRental, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, rental := range r.rentals {
+		if rental.ID == id {
+			return copyRental(rental), nil
+		}
+	}
+
+	return nil, errors.ErrNotFound
+}
+
+func (r *BookRentalInMemoryRepository) GetActiveBookRentalsByBookID(ctx context.Context, bookID string) ([]*models.BookRental, error) {
+	rentals := r.filterRentals(func(rental *models.BookRental) bool {
+		return rental.BookID == bookID && !rental.IsReturned()
+	})
+
+	// Soonest due first, matching the Postgres ordering.
+	sort.SliceStable(rentals, func(i, j int) bool {
+		return rentals[i].ReturnDeadline.Before(rentals[j].ReturnDeadline)
+	})
+	return rentals, nil
+}
+
+func (r *BookRentalInMemoryRepository) GetActiveBookRentals(ctx context.Context) ([]*models.BookRental, error) {
+	return r.filterRentals(func(rental *models.BookRental) bool {
+		return !rental.IsReturned()
+	}), nil
+}
+
+func (r *BookRentalInMemoryRepository) GetAllBookRentals(ctx context.Context, bookID string) ([]*models.BookRental, error) {
+	return r.filterRentals(func(rental *models.BookRental) bool {
+		return rental.BookID == bookID
+	}), nil
+}
+
+func (r *BookRentalInMemoryRepository) GetAllUserRentals(ctx context.Context, userID string) ([]*models.BookRental, error) {
+	return r.filterRentals(func(rental *models.BookRental) bool {
+		return rental.UserID == userID
+	}), nil
+}
+
+func (r *BookRentalInMemoryRepository) GetActiveUserRentals(ctx context.Context, userID string) ([]*models.BookRental, error) {
+	return r.filterRentals(func(rental *models.BookRental) bool {
+		return rental.UserID == userID && !rental.IsReturned()
+	}), nil
+}
+
+func (r *BookRentalInMemoryRepository) SaveBookRental(ctx context.Context, rental *models.BookRental) error {
+	bookCopy, err := r.copies.FindCopyByID(ctx, rental.CopyID)
+	if stderrors.Is(err, interfaces.ErrCopyNotFound) {
+		return errors.ErrBookNotInStorage
+	}
+	if err != nil {
+		return err
+	}
+	if bookCopy.ISBN != rental.BookID {
+		return errors.ErrBookNotInStorage
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !rental.IsReturned() {
+		for _, existing := range r.rentals {
+			if existing.CopyID == rental.CopyID && !existing.IsReturned() {
+				return errors.ErrBookAlreadyBorrowed
+			}
+		}
+	}
+
+	if rental.ID == "" {
+		rental.ID = newID()
+	}
+
+	r.rentals = append(r.rentals, copyRental(rental))
+	return nil
+}
+
+func (r *BookRentalInMemoryRepository) UpdateBookRental(ctx context.Context, rental *models.BookRental) error {
+	if rental.ID == "" {
+		return errors.ErrInvalidID
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, existing := range r.rentals {
+		if existing.ID == rental.ID {
+			if existing.IsReturned() {
+				return errors.ErrRentalClosed
+			}
+			r.rentals[i] = copyRental(rental)
+			return nil
+		}
+	}
+
+	return errors.ErrNotFound
+}
+
+func (r *BookRentalInMemoryRepository) MarkLostRentalFound(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.rentals {
+		if existing.ID == id {
+			if existing.CloseReason != models.RentalLost {
+				return errors.ErrRentalNotLost
+			}
+			existing.CloseReason = models.RentalFound
+			return nil
+		}
+	}
+
+	return errors.ErrNotFound
+}
+
+// AvailableISBNs finds the books with a copy in circulation that is not
+// lent out, the way the Postgres book repository does.
+func (r *BookRentalInMemoryRepository) AvailableISBNs(ctx context.Context) (map[string]bool, error) {
+	copies, err := r.copies.FindAllCopies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	onLoan := make(map[string]bool)
+	for _, rental := range r.rentals {
+		if !rental.IsReturned() {
+			onLoan[rental.CopyID] = true
+		}
+	}
+
+	available := make(map[string]bool)
+	for _, bookCopy := range copies {
+		if bookCopy.InCirculation() && !onLoan[bookCopy.ID] {
+			available[bookCopy.ISBN] = true
+		}
+	}
+	return available, nil
+}
+
+func (r *BookRentalInMemoryRepository) filterRentals(match func(*models.BookRental) bool) []*models.BookRental {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.BookRental, 0)
+	// Newest first, matching the Postgres ordering.
+	for i := len(r.rentals) - 1; i >= 0; i-- {
+		if match(r.rentals[i]) {
+			result = append(result, copyRental(r.rentals[i]))
+		}
+	}
+	return result
+}
+
+func copyRental(rental *models.BookRental) *models.BookRental {
+	c := *rental
+	if rental.ReturnedAt != nil {
+		returnedAt := *rental.ReturnedAt
+		c.ReturnedAt = &returnedAt
+	}
+	return &c
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+var (
+	_ BookRepository              = (*BookRentalInMemoryRepository)(nil)
+	_ interfaces.BookAvailability = (*BookRentalInMemoryRepository)(nil)
+)
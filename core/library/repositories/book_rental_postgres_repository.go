@@ -0,0 +1,382 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+
+	isbns "books/core/isbn"
+	"books/core/library/errors"
+	"books/core/library/models"
+	storage_models "books/core/storage/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+	pqInvalidText         = "22P02"
+
+	activeRentalIndex = "idx_book_rentals_active_copy"
+)
+
+type BookRentalPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewBookRentalPostgresRepository(db *sql.DB) *BookRentalPostgresRepository {
+	return &BookRentalPostgresRepository{
+		db: db,
+	}
+}
+
+func (r *BookRentalPostgresRepository) GetBookByISBN(ctx context.Context, isbn string) (*storage_models.Book, error) {
+	isbn = isbns.Key(isbn)
+
+	query := `SELECT isbn, title, author, published_at, category FROM books WHERE isbn = $1 AND deleted_at IS NULL`
+
+	book := &storage_models.Book{}
+	err := r.db.QueryRowContext(ctx, query, isbn).Scan(
+		&book.ISBN,
+		&book.Title,
+		&book.Author,
+		&book.PublishedAt,
+		&book.Category,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find book by ISBN: %w", err)
+	}
+
+	return book, nil
+}
+
+func (r *BookRentalPostgresRepository) BookExists(ctx context.Context, isbn string) (bool, error) {
+	isbn = isbns.Key(isbn)
+
+	query := `SELECT EXISTS (SELECT 1 FROM books WHERE isbn = $1 AND deleted_at IS NULL)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, isbn).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check book existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *BookRentalPostgresRepository) GetCopiesByISBN(ctx context.Context, isbn string) ([]*storage_models.Copy, error) {
+	isbn = isbns.Key(isbn)
+
+	query := `
+		SELECT id, isbn, barcode, shelf_location, condition, status,
+			COALESCE(home_branch_id::text, ''), COALESCE(current_branch_id::text, ''), added_at
+		FROM book_copies
+		WHERE isbn = $1
+		ORDER BY added_at, barcode
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, isbn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query copies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	copies := make([]*storage_models.Copy, 0)
+	for rows.Next() {
+		bookCopy := &storage_models.Copy{}
+		err := rows.Scan(
+			&bookCopy.ID,
+			&bookCopy.ISBN,
+			&bookCopy.Barcode,
+			&bookCopy.ShelfLocation,
+			&bookCopy.Condition,
+			&bookCopy.Status,
+			&bookCopy.HomeBranchID,
+			&bookCopy.CurrentBranchID,
+			&bookCopy.AddedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan copy: %w", err)
+		}
+		copies = append(copies, bookCopy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate copies: %w", err)
+	}
+
+	return copies, nil
+}
+
+func (r *BookRentalPostgresRepository) GetBookRentalByID(ctx context.Context, id string) (*models.BookRental, error) {
+	query := `
+		SELECT id, book_id, copy_id, user_id, patron_type, borrowed_at, return_deadline, returned_at, returned_late, renewal_count, close_reason
+		FROM book_rentals
+		WHERE id = $1
+	`
+
+	rental, err := scanBookRental(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows || isInvalidUUID(err) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rental: %w", err)
+	}
+
+	return rental, nil
+}
+
+func (r *BookRentalPostgresRepository) GetActiveBookRentalsByBookID(ctx context.Context, bookID string) ([]*models.BookRental, error) {
+	query := `
+		SELECT id, book_id, copy_id, user_id, patron_type, borrowed_at, return_deadline, returned_at, returned_late, renewal_count, close_reason
+		FROM book_rentals
+		WHERE book_id = $1 AND returned_at IS NULL
+		ORDER BY return_deadline
+	`
+
+	return r.queryRentals(ctx, query, bookID)
+}
+
+func (r *BookRentalPostgresRepository) GetActiveBookRentals(ctx context.Context) ([]*models.BookRental, error) {
+	query := `
+		SELECT id, book_id, copy_id, user_id, patron_type, borrowed_at, return_deadline, returned_at, returned_late, renewal_count, close_reason
+		FROM book_rentals
+		WHERE returned_at IS NULL
+	`
+
+	return r.queryRentals(ctx, query)
+}
+
+func (r *BookRentalPostgresRepository) GetAllBookRentals(ctx context.Context, bookID string) ([]*models.BookRental, error) {
+	query := `
+		SELECT id, book_id, copy_id, user_id, patron_type, borrowed_at, return_deadline, returned_at, returned_late, renewal_count, close_reason
+		FROM book_rentals
+		WHERE book_id = $1
+		ORDER BY borrowed_at DESC
+	`
+
+	return r.queryRentals(ctx, query, bookID)
+}
+
+func (r *BookRentalPostgresRepository) GetAllUserRentals(ctx context.Context, userID string) ([]*models.BookRental, error) {
+	query := `
+		SELECT id, book_id, copy_id, user_id, patron_type, borrowed_at, return_deadline, returned_at, returned_late, renewal_count, close_reason
+		FROM book_rentals
+		WHERE user_id = $1
+		ORDER BY borrowed_at DESC
+	`
+
+	return r.queryRentals(ctx, query, userID)
+}
+
+func (r *BookRentalPostgresRepository) GetActiveUserRentals(ctx context.Context, userID string) ([]*models.BookRental, error) {
+	query := `
+		SELECT id, book_id, copy_id, user_id, patron_type, borrowed_at, return_deadline, returned_at, returned_late, renewal_count, close_reason
+		FROM book_rentals
+		WHERE user_id = $1 AND returned_at IS NULL
+		ORDER BY return_deadline
+	`
+
+	return r.queryRentals(ctx, query, userID)
+}
+
+func (r *BookRentalPostgresRepository) SaveBookRental(ctx context.Context, rental *models.BookRental) error {
+	// The rental is only written when the copy belongs to the rented book.
+	query := `
+		INSERT INTO book_rentals (
+			id, book_id, copy_id, user_id, patron_type, borrowed_at, return_deadline, returned_at, returned_late, renewal_count, close_reason
+		)
+		SELECT COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), c.isbn, c.id, $4, $5, $6, $7, $8, $9, $10, $11
+		FROM book_copies c
+		WHERE c.id = NULLIF($3, '')::uuid AND c.isbn = $2
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		rental.ID,
+		rental.BookID,
+		rental.CopyID,
+		rental.UserID,
+		rental.PatronType,
+		rental.BorrowedAt,
+		rental.ReturnDeadline,
+		rental.ReturnedAt,
+		rental.ReturnedLate,
+		rental.RenewalCount,
+		rental.CloseReason,
+	).Scan(&rental.ID)
+	if err == sql.ErrNoRows {
+		return errors.ErrBookNotInStorage
+	}
+	if err != nil {
+		return mapRentalWriteError(err)
+	}
+
+	return nil
+}
+
+func (r *BookRentalPostgresRepository) UpdateBookRental(ctx context.Context, rental *models.BookRental) error {
+	if rental.ID == "" {
+		return errors.ErrInvalidID
+	}
+
+	// Closed rentals are permanent history, so only open ones can change.
+	query := `
+		UPDATE book_rentals
+		SET return_deadline = $2, returned_at = $3, returned_late = $4, renewal_count = $5, close_reason = $6
+		WHERE id = $1 AND returned_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		rental.ID,
+		rental.ReturnDeadline,
+		rental.ReturnedAt,
+		rental.ReturnedLate,
+		rental.RenewalCount,
+		rental.CloseReason,
+	)
+	if isInvalidUUID(err) {
+		return errors.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update rental: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return r.missingRentalError(ctx, rental.ID)
+	}
+
+	return nil
+}
+
+func (r *BookRentalPostgresRepository) MarkLostRentalFound(ctx context.Context, id string) error {
+	query := `
+		UPDATE book_rentals
+		SET close_reason = $2
+		WHERE id = $1 AND close_reason = $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, models.RentalFound, models.RentalLost)
+	if isInvalidUUID(err) {
+		return errors.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark rental found: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		if _, err := r.GetBookRentalByID(ctx, id); err != nil {
+			return err
+		}
+		return errors.ErrRentalNotLost
+	}
+
+	return nil
+}
+
+func (r *BookRentalPostgresRepository) missingRentalError(ctx context.Context, id string) error {
+	query := `SELECT EXISTS (SELECT 1 FROM book_rentals WHERE id = $1)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check rental existence: %w", err)
+	}
+
+	if exists {
+		return errors.ErrRentalClosed
+	}
+	return errors.ErrNotFound
+}
+
+func (r *BookRentalPostgresRepository) queryRentals(ctx context.Context, query string, args ...any) ([]*models.BookRental, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rentals: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	rentals := make([]*models.BookRental, 0)
+	for rows.Next() {
+		rental, err := scanBookRental(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rental: %w", err)
+		}
+		rentals = append(rentals, rental)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rentals: %w", err)
+	}
+
+	return rentals, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBookRental(row rowScanner) (*models.BookRental, error) {
+	rental := &models.BookRental{}
+	var returnedAt sql.NullTime
+
+	err := row.Scan(
+		&rental.ID,
+		&rental.BookID,
+		&rental.CopyID,
+		&rental.UserID,
+		&rental.PatronType,
+		&rental.BorrowedAt,
+		&rental.ReturnDeadline,
+		&returnedAt,
+		&rental.ReturnedLate,
+		&rental.RenewalCount,
+		&rental.CloseReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if returnedAt.Valid {
+		rental.ReturnedAt = &returnedAt.Time
+	}
+
+	return rental, nil
+}
+
+// isInvalidUUID reports whether Postgres rejected a rental ID that is not a UUID.
+func isInvalidUUID(err error) bool {
+	var pqErr *pq.Error
+	return stderrors.As(err, &pqErr) && pqErr.Code == pqInvalidText
+}
+
+func mapRentalWriteError(err error) error {
+	var pqErr *pq.Error
+	if stderrors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			if pqErr.Constraint == activeRentalIndex {
+				return errors.ErrBookAlreadyBorrowed
+			}
+		case pqForeignKeyViolation, pqInvalidText:
+			return errors.ErrBookNotInStorage
+		}
+	}
+	return fmt.Errorf("failed to save rental: %w", err)
+}
+
+var _ BookRepository = (*BookRentalPostgresRepository)(nil)
@@ -0,0 +1,424 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	calendar_models "books/core/calendar/models"
+	"books/core/library/errors"
+	"books/core/library/models"
+	"books/internal/testdb"
+)
+
+var db *sql.DB
+var repo *BookRentalPostgresRepository
+
+func TestMain(m *testing.M) {
+	testdb.Run(m, func(conn *sql.DB) {
+		db = conn
+		repo = NewBookRentalPostgresRepository(db)
+	})
+}
+
+func cleanupDB(t *testing.T) {
+	_, err := db.Exec("DELETE FROM copy_transfers")
+	if err != nil {
+		t.Fatalf("Failed to cleanup transfers: %v", err)
+	}
+	_, err = db.Exec("DELETE FROM ledger_entries")
+	if err != nil {
+		t.Fatalf("Failed to cleanup ledger: %v", err)
+	}
+	_, err = db.Exec("DELETE FROM book_holds")
+	if err != nil {
+		t.Fatalf("Failed to cleanup holds: %v", err)
+	}
+	_, err = db.Exec("DELETE FROM book_rentals")
+	if err != nil {
+		t.Fatalf("Failed to cleanup rentals: %v", err)
+	}
+	_, err = db.Exec("DELETE FROM book_copies")
+	if err != nil {
+		t.Fatalf("Failed to cleanup copies: %v", err)
+	}
+	_, err = db.Exec("DELETE FROM books")
+	if err != nil {
+		t.Fatalf("Failed to cleanup books: %v", err)
+	}
+	_, err = db.Exec("DELETE FROM branches")
+	if err != nil {
+		t.Fatalf("Failed to cleanup branches: %v", err)
+	}
+}
+
+// insertBook stores a book with a single copy and returns the copy's ID.
+func insertBook(t *testing.T, isbn, title string) string {
+	_, err := db.Exec(
+		"INSERT INTO books (isbn, title, author, published_at) VALUES ($1, $2, $3, $4)",
+		isbn, title, "Test Author", time.Now(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to insert book: %v", err)
+	}
+
+	return insertCopy(t, isbn, isbn+"-1")
+}
+
+func insertCopy(t *testing.T, isbn, barcode string) string {
+	var copyID string
+	err := db.QueryRow(
+		"INSERT INTO book_copies (isbn, barcode) VALUES ($1, $2) RETURNING id",
+		isbn, barcode,
+	).Scan(&copyID)
+	if err != nil {
+		t.Fatalf("Failed to insert copy: %v", err)
+	}
+	return copyID
+}
+
+func newCopyRental(isbn, copyID, userID string) *models.BookRental {
+	rental := models.NewBookRental(isbn, userID)
+	rental.CopyID = copyID
+	return rental
+}
+
+func TestGetBookByISBN(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	insertBook(t, validISBN, "Test Book")
+
+	book, err := repo.GetBookByISBN(context.Background(), validISBN)
+	if err != nil {
+		t.Fatalf("Failed to find book: %v", err)
+	}
+	if book.Title != "Test Book" {
+		t.Errorf("expected title 'Test Book', got %s", book.Title)
+	}
+
+	_, err = repo.GetBookByISBN(context.Background(), "9780306406157")
+	if err != errors.ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestBookExists(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	insertBook(t, validISBN, "Test Book")
+
+	exists, err := repo.BookExists(context.Background(), validISBN)
+	if err != nil {
+		t.Fatalf("Failed to check book: %v", err)
+	}
+	if !exists {
+		t.Errorf("expected book %s to exist", validISBN)
+	}
+
+	exists, err = repo.BookExists(context.Background(), "9780306406157")
+	if err != nil {
+		t.Fatalf("Failed to check book: %v", err)
+	}
+	if exists {
+		t.Errorf("expected book 9780306406157 not to exist")
+	}
+}
+
+func TestSaveBookRental(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	copyID := insertBook(t, validISBN, "Test Book")
+
+	rental := newCopyRental(validISBN, copyID, "user1")
+	err := repo.SaveBookRental(context.Background(), rental)
+	if err != nil {
+		t.Fatalf("Failed to save rental: %v", err)
+	}
+	if rental.ID == "" {
+		t.Errorf("expected rental ID to be assigned")
+	}
+
+	rentals, err := repo.GetActiveBookRentalsByBookID(context.Background(), validISBN)
+	if err != nil {
+		t.Fatalf("Failed to find active rentals: %v", err)
+	}
+	if len(rentals) != 1 {
+		t.Fatalf("expected 1 active rental, got %d", len(rentals))
+	}
+	active := rentals[0]
+	if active.CopyID != copyID {
+		t.Errorf("expected copy %s, got %s", copyID, active.CopyID)
+	}
+	if active.ID != rental.ID {
+		t.Errorf("expected rental ID %s, got %s", rental.ID, active.ID)
+	}
+	if active.UserID != "user1" {
+		t.Errorf("expected user 'user1', got %s", active.UserID)
+	}
+	if active.IsReturned() {
+		t.Errorf("expected rental to be open")
+	}
+}
+
+func TestSaveBookRentalOnlyOneActivePerCopy(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	copyID := insertBook(t, validISBN, "Test Book")
+
+	err := repo.SaveBookRental(context.Background(), newCopyRental(validISBN, copyID, "user1"))
+	if err != nil {
+		t.Fatalf("Failed to save rental: %v", err)
+	}
+
+	err = repo.SaveBookRental(context.Background(), newCopyRental(validISBN, copyID, "user2"))
+	if err != errors.ErrBookAlreadyBorrowed {
+		t.Errorf("expected ErrBookAlreadyBorrowed, got %v", err)
+	}
+
+	secondCopy := insertCopy(t, validISBN, validISBN+"-2")
+	if err := repo.SaveBookRental(context.Background(), newCopyRental(validISBN, secondCopy, "user2")); err != nil {
+		t.Fatalf("expected second copy to be lent, got %v", err)
+	}
+
+	rentals, err := repo.GetActiveBookRentalsByBookID(context.Background(), validISBN)
+	if err != nil {
+		t.Fatalf("Failed to find active rentals: %v", err)
+	}
+	if len(rentals) != 2 {
+		t.Errorf("expected 2 active rentals, got %d", len(rentals))
+	}
+
+	copies, err := repo.GetCopiesByISBN(context.Background(), validISBN)
+	if err != nil {
+		t.Fatalf("Failed to find copies: %v", err)
+	}
+	if len(copies) != 2 {
+		t.Errorf("expected 2 copies, got %d", len(copies))
+	}
+}
+
+func TestSaveBookRentalAllowsReturnedHistory(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	copyID := insertBook(t, validISBN, "Test Book")
+
+	returned := newCopyRental(validISBN, copyID, "user1")
+	returned.MarkAsReturned()
+	if err := repo.SaveBookRental(context.Background(), returned); err != nil {
+		t.Fatalf("Failed to save returned rental: %v", err)
+	}
+
+	if err := repo.SaveBookRental(context.Background(), newCopyRental(validISBN, copyID, "user2")); err != nil {
+		t.Fatalf("expected new rental after a returned one, got %v", err)
+	}
+}
+
+func TestSaveBookRentalUnknownBook(t *testing.T) {
+	cleanupDB(t)
+
+	err := repo.SaveBookRental(context.Background(), models.NewBookRental("9780306406157", "user1"))
+	if err != errors.ErrBookNotInStorage {
+		t.Errorf("expected ErrBookNotInStorage, got %v", err)
+	}
+
+	copyID := insertBook(t, "9783161484100", "Test Book")
+	err = repo.SaveBookRental(context.Background(), newCopyRental("9780306406157", copyID, "user1"))
+	if err != errors.ErrBookNotInStorage {
+		t.Errorf("expected ErrBookNotInStorage for a copy of another book, got %v", err)
+	}
+}
+
+func TestGetActiveBookRentalsByBookIDEmpty(t *testing.T) {
+	cleanupDB(t)
+
+	rentals, err := repo.GetActiveBookRentalsByBookID(context.Background(), "9783161484100")
+	if err != nil {
+		t.Fatalf("Failed to find active rentals: %v", err)
+	}
+	if len(rentals) != 0 {
+		t.Errorf("expected no active rentals, got %d", len(rentals))
+	}
+}
+
+func TestGetAllUserRentals(t *testing.T) {
+	cleanupDB(t)
+
+	isbns := []string{"9783161484100", "9780306406157", "9780596517748"}
+	copyIDs := make([]string, len(isbns))
+	for i, isbn := range isbns {
+		copyIDs[i] = insertBook(t, isbn, fmt.Sprintf("Book %d", i+1))
+	}
+
+	_ = repo.SaveBookRental(context.Background(), newCopyRental(isbns[0], copyIDs[0], "user1"))
+	_ = repo.SaveBookRental(context.Background(), newCopyRental(isbns[1], copyIDs[1], "user1"))
+	_ = repo.SaveBookRental(context.Background(), newCopyRental(isbns[2], copyIDs[2], "user2"))
+
+	rentals, err := repo.GetAllUserRentals(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("Failed to find user rentals: %v", err)
+	}
+	if len(rentals) != 2 {
+		t.Errorf("expected 2 rentals, got %d", len(rentals))
+	}
+
+	rentals, err = repo.GetAllUserRentals(context.Background(), "nobody")
+	if err != nil {
+		t.Fatalf("Failed to find user rentals: %v", err)
+	}
+	if len(rentals) != 0 {
+		t.Errorf("expected 0 rentals, got %d", len(rentals))
+	}
+}
+
+func TestGetActiveUserRentals(t *testing.T) {
+	cleanupDB(t)
+
+	isbns := []string{"9783161484100", "9780306406157"}
+	copyIDs := make([]string, len(isbns))
+	for i, isbn := range isbns {
+		copyIDs[i] = insertBook(t, isbn, fmt.Sprintf("Book %d", i+1))
+	}
+
+	returned := newCopyRental(isbns[0], copyIDs[0], "user1")
+	_ = repo.SaveBookRental(context.Background(), returned)
+	returned.MarkAsReturned()
+	_ = repo.UpdateBookRental(context.Background(), returned)
+	_ = repo.SaveBookRental(context.Background(), newCopyRental(isbns[1], copyIDs[1], "user1"))
+
+	rentals, err := repo.GetActiveUserRentals(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("Failed to find open user rentals: %v", err)
+	}
+	if len(rentals) != 1 || rentals[0].BookID != isbns[1] {
+		t.Errorf("expected only the open rental, got %d", len(rentals))
+	}
+}
+
+func TestUpdateBookRental(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	copyID := insertBook(t, validISBN, "Test Book")
+
+	rental := newCopyRental(validISBN, copyID, "user1")
+	_ = repo.SaveBookRental(context.Background(), rental)
+
+	rental.MarkAsReturned()
+	if err := repo.UpdateBookRental(context.Background(), rental); err != nil {
+		t.Fatalf("Failed to update rental: %v", err)
+	}
+
+	rentals, err := repo.GetActiveBookRentalsByBookID(context.Background(), validISBN)
+	if err != nil || len(rentals) != 0 {
+		t.Errorf("expected no active rental after return, got %d (err %v)", len(rentals), err)
+	}
+
+	active, err := repo.GetActiveBookRentals(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to find active rentals: %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("expected 0 active rentals, got %d", len(active))
+	}
+}
+
+func TestUpdateBookRentalNotFound(t *testing.T) {
+	cleanupDB(t)
+
+	rental := models.NewBookRental("9783161484100", "user1")
+	rental.ID = "00000000-0000-0000-0000-000000000000"
+
+	err := repo.UpdateBookRental(context.Background(), rental)
+	if err != errors.ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestClosedRentalsAreKeptAsHistory(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	copyID := insertBook(t, validISBN, "Test Book")
+
+	late := newCopyRental(validISBN, copyID, "user1")
+	late.ReturnDeadline = time.Now().Add(-24 * time.Hour)
+	_ = repo.SaveBookRental(context.Background(), late)
+
+	late.MarkAsReturned()
+	if err := repo.UpdateBookRental(context.Background(), late); err != nil {
+		t.Fatalf("Failed to close rental: %v", err)
+	}
+
+	current := newCopyRental(validISBN, copyID, "user1")
+	_ = repo.SaveBookRental(context.Background(), current)
+
+	rentals, err := repo.GetAllUserRentals(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("Failed to find user rentals: %v", err)
+	}
+	if len(rentals) != 2 {
+		t.Fatalf("expected 2 rentals in history, got %d", len(rentals))
+	}
+
+	var closed *models.BookRental
+	for _, rental := range rentals {
+		if rental.ID == late.ID {
+			closed = rental
+		}
+	}
+	if closed == nil || !closed.IsReturned() {
+		t.Fatalf("expected closed rental %s in history", late.ID)
+	}
+	if !closed.ReturnedLate {
+		t.Errorf("expected closed rental to be recorded as late")
+	}
+
+	bookRentals, err := repo.GetAllBookRentals(context.Background(), validISBN)
+	if err != nil || len(bookRentals) != 2 {
+		t.Errorf("expected 2 rentals in the book's history, got %d (%v)", len(bookRentals), err)
+	}
+
+	err = repo.UpdateBookRental(context.Background(), late)
+	if err != errors.ErrRentalClosed {
+		t.Errorf("expected ErrRentalClosed when updating a closed rental, got %v", err)
+	}
+}
+
+func TestGetBookRentalByID(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	copyID := insertBook(t, validISBN, "Test Book")
+
+	rental := newCopyRental(validISBN, copyID, "user1")
+	rental.PatronType = "staff"
+	_ = repo.SaveBookRental(context.Background(), rental)
+
+	rental.Renew(7*24*time.Hour, calendar_models.Calendar{})
+	if err := repo.UpdateBookRental(context.Background(), rental); err != nil {
+		t.Fatalf("Failed to renew rental: %v", err)
+	}
+
+	found, err := repo.GetBookRentalByID(context.Background(), rental.ID)
+	if err != nil {
+		t.Fatalf("Failed to find rental: %v", err)
+	}
+	if found.PatronType != "staff" {
+		t.Errorf("expected patron type 'staff', got %s", found.PatronType)
+	}
+	if found.RenewalCount != 1 {
+		t.Errorf("expected renewal count 1, got %d", found.RenewalCount)
+	}
+
+	_, err = repo.GetBookRentalByID(context.Background(), "not-a-uuid")
+	if err != errors.ErrNotFound {
+		t.Errorf("expected ErrNotFound for malformed ID, got %v", err)
+	}
+}
@@ -10,8 +10,18 @@ import (
 type BookRepository interface {
 	GetBookByISBN(ctx context.Context, isbn string) (*storage_models.Book, error)
 	BookExists(ctx context.Context, isbn string) (bool, error)
+	GetCopiesByISBN(ctx context.Context, isbn string) ([]*storage_models.Copy, error)
 
-	GetActiveBookRentalByBookID(ctx context.Context, bookID string) (*models.BookRental, error)
+	GetBookRentalByID(ctx context.Context, id string) (*models.BookRental, error)
+	GetActiveBookRentalsByBookID(ctx context.Context, bookID string) ([]*models.BookRental, error)
+	GetActiveBookRentals(ctx context.Context) ([]*models.BookRental, error)
+	// GetAllBookRentals returns every rental of a book, closed ones included.
+	GetAllBookRentals(ctx context.Context, bookID string) ([]*models.BookRental, error)
 	GetAllUserRentals(ctx context.Context, userID string) ([]*models.BookRental, error)
+	GetActiveUserRentals(ctx context.Context, userID string) ([]*models.BookRental, error)
 	SaveBookRental(ctx context.Context, rental *models.BookRental) error
+	UpdateBookRental(ctx context.Context, rental *models.BookRental) error
+	// MarkLostRentalFound records that the copy of a lost rental turned up.
+	// It is the only change a closed rental accepts.
+	MarkLostRentalFound(ctx context.Context, id string) error
 }
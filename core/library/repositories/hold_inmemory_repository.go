@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"books/core/library/errors"
+	"books/core/library/models"
+)
+
+type HoldInMemoryRepository struct {
+	holds []*models.Hold
+	mutex sync.RWMutex
+}
+
+func NewHoldInMemoryRepository() *HoldInMemoryRepository {
+	return &HoldInMemoryRepository{
+		holds: make([]*models.Hold, 0),
+	}
+}
+
+func (r *HoldInMemoryRepository) GetHoldByID(ctx context.Context, id string) (*models.Hold, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, hold := range r.holds {
+		if hold.ID == id {
+			return copyHold(hold), nil
+		}
+	}
+
+	return nil, errors.ErrNotFound
+}
+
+func (r *HoldInMemoryRepository) GetActiveHoldsByBookID(ctx context.Context, bookID string) ([]*models.Hold, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	// Holds are appended as they are placed, so the slice is already in queue order.
+	result := make([]*models.Hold, 0)
+	for _, hold := range r.holds {
+		if hold.BookID == bookID && hold.IsActive() {
+			result = append(result, copyHold(hold))
+		}
+	}
+	return result, nil
+}
+
+func (r *HoldInMemoryRepository) GetBookHolds(ctx context.Context, bookID string) ([]*models.Hold, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.Hold, 0)
+	// Newest first, matching the Postgres ordering.
+	for i := len(r.holds) - 1; i >= 0; i-- {
+		if r.holds[i].BookID == bookID {
+			result = append(result, copyHold(r.holds[i]))
+		}
+	}
+	return result, nil
+}
+
+func (r *HoldInMemoryRepository) GetUserHolds(ctx context.Context, userID string) ([]*models.Hold, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.Hold, 0)
+	// Newest first, matching the Postgres ordering.
+	for i := len(r.holds) - 1; i >= 0; i-- {
+		if r.holds[i].UserID == userID {
+			result = append(result, copyHold(r.holds[i]))
+		}
+	}
+	return result, nil
+}
+
+func (r *HoldInMemoryRepository) SaveHold(ctx context.Context, hold *models.Hold) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if hold.IsActive() {
+		for _, existing := range r.holds {
+			if existing.BookID == hold.BookID && existing.UserID == hold.UserID && existing.IsActive() {
+				return errors.ErrHoldAlreadyPlaced
+			}
+		}
+	}
+
+	if hold.ID == "" {
+		hold.ID = newID()
+	}
+
+	r.holds = append(r.holds, copyHold(hold))
+	return nil
+}
+
+func (r *HoldInMemoryRepository) UpdateHold(ctx context.Context, hold *models.Hold) error {
+	if hold.ID == "" {
+		return errors.ErrInvalidID
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, existing := range r.holds {
+		if existing.ID == hold.ID {
+			if !existing.IsActive() {
+				return errors.ErrHoldClosed
+			}
+			r.holds[i] = copyHold(hold)
+			return nil
+		}
+	}
+
+	return errors.ErrNotFound
+}
+
+func copyHold(hold *models.Hold) *models.Hold {
+	c := *hold
+	c.ReadyAt = copyTime(hold.ReadyAt)
+	c.PickupDeadline = copyTime(hold.PickupDeadline)
+	c.ClosedAt = copyTime(hold.ClosedAt)
+	return &c
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+var _ HoldRepository = (*HoldInMemoryRepository)(nil)
@@ -0,0 +1,233 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"books/core/library/errors"
+	"books/core/library/models"
+
+	"github.com/lib/pq"
+)
+
+const activeHoldIndex = "idx_book_holds_active_user"
+
+type HoldPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewHoldPostgresRepository(db *sql.DB) *HoldPostgresRepository {
+	return &HoldPostgresRepository{
+		db: db,
+	}
+}
+
+func (r *HoldPostgresRepository) GetHoldByID(ctx context.Context, id string) (*models.Hold, error) {
+	query := `
+		SELECT id, book_id, user_id, COALESCE(pickup_branch_id::text, ''), status, placed_at, ready_at, pickup_deadline, closed_at
+		FROM book_holds
+		WHERE id = $1
+	`
+
+	hold, err := scanHold(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows || isInvalidUUID(err) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find hold: %w", err)
+	}
+
+	return hold, nil
+}
+
+func (r *HoldPostgresRepository) GetActiveHoldsByBookID(ctx context.Context, bookID string) ([]*models.Hold, error) {
+	query := `
+		SELECT id, book_id, user_id, COALESCE(pickup_branch_id::text, ''), status, placed_at, ready_at, pickup_deadline, closed_at
+		FROM book_holds
+		WHERE book_id = $1 AND status IN ('waiting', 'ready')
+		ORDER BY queue_position
+	`
+
+	return r.queryHolds(ctx, query, bookID)
+}
+
+func (r *HoldPostgresRepository) GetBookHolds(ctx context.Context, bookID string) ([]*models.Hold, error) {
+	query := `
+		SELECT id, book_id, user_id, COALESCE(pickup_branch_id::text, ''), status, placed_at, ready_at, pickup_deadline, closed_at
+		FROM book_holds
+		WHERE book_id = $1
+		ORDER BY queue_position DESC
+	`
+
+	return r.queryHolds(ctx, query, bookID)
+}
+
+func (r *HoldPostgresRepository) GetUserHolds(ctx context.Context, userID string) ([]*models.Hold, error) {
+	query := `
+		SELECT id, book_id, user_id, COALESCE(pickup_branch_id::text, ''), status, placed_at, ready_at, pickup_deadline, closed_at
+		FROM book_holds
+		WHERE user_id = $1
+		ORDER BY queue_position DESC
+	`
+
+	return r.queryHolds(ctx, query, userID)
+}
+
+func (r *HoldPostgresRepository) SaveHold(ctx context.Context, hold *models.Hold) error {
+	// The hold is only written when the book is in storage.
+	query := `
+		INSERT INTO book_holds (id, book_id, user_id, pickup_branch_id, status, placed_at, ready_at, pickup_deadline, closed_at)
+		SELECT COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), b.isbn, $3, NULLIF($4, '')::uuid, $5, $6, $7, $8, $9
+		FROM books b
+		WHERE b.isbn = $2
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		hold.ID,
+		hold.BookID,
+		hold.UserID,
+		hold.PickupBranchID,
+		hold.Status,
+		hold.PlacedAt,
+		hold.ReadyAt,
+		hold.PickupDeadline,
+		hold.ClosedAt,
+	).Scan(&hold.ID)
+	if err == sql.ErrNoRows {
+		return errors.ErrBookNotInStorage
+	}
+	if err != nil {
+		return mapHoldWriteError(err)
+	}
+
+	return nil
+}
+
+func (r *HoldPostgresRepository) UpdateHold(ctx context.Context, hold *models.Hold) error {
+	if hold.ID == "" {
+		return errors.ErrInvalidID
+	}
+
+	// Closed holds are history, so only waiting and ready ones can change.
+	query := `
+		UPDATE book_holds
+		SET status = $2, ready_at = $3, pickup_deadline = $4, closed_at = $5
+		WHERE id = $1 AND status IN ('waiting', 'ready')
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		hold.ID,
+		hold.Status,
+		hold.ReadyAt,
+		hold.PickupDeadline,
+		hold.ClosedAt,
+	)
+	if isInvalidUUID(err) {
+		return errors.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update hold: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return r.missingHoldError(ctx, hold.ID)
+	}
+
+	return nil
+}
+
+func (r *HoldPostgresRepository) missingHoldError(ctx context.Context, id string) error {
+	query := `SELECT EXISTS (SELECT 1 FROM book_holds WHERE id = $1)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check hold existence: %w", err)
+	}
+
+	if exists {
+		return errors.ErrHoldClosed
+	}
+	return errors.ErrNotFound
+}
+
+func (r *HoldPostgresRepository) queryHolds(ctx context.Context, query string, args ...any) ([]*models.Hold, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holds: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	holds := make([]*models.Hold, 0)
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan hold: %w", err)
+		}
+		holds = append(holds, hold)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+func scanHold(row rowScanner) (*models.Hold, error) {
+	hold := &models.Hold{}
+	var readyAt, pickupDeadline, closedAt sql.NullTime
+
+	err := row.Scan(
+		&hold.ID,
+		&hold.BookID,
+		&hold.UserID,
+		&hold.PickupBranchID,
+		&hold.Status,
+		&hold.PlacedAt,
+		&readyAt,
+		&pickupDeadline,
+		&closedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	hold.ReadyAt = nullTimePtr(readyAt)
+	hold.PickupDeadline = nullTimePtr(pickupDeadline)
+	hold.ClosedAt = nullTimePtr(closedAt)
+
+	return hold, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func mapHoldWriteError(err error) error {
+	var pqErr *pq.Error
+	if stderrors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			if pqErr.Constraint == activeHoldIndex {
+				return errors.ErrHoldAlreadyPlaced
+			}
+		case pqForeignKeyViolation:
+			return errors.ErrBookNotInStorage
+		}
+	}
+	return fmt.Errorf("failed to save hold: %w", err)
+}
+
+var _ HoldRepository = (*HoldPostgresRepository)(nil)
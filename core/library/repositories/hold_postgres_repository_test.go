@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"books/core/library/errors"
+	"books/core/library/models"
+)
+
+func TestSaveHoldKeepsQueueOrder(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	insertBook(t, validISBN, "Test Book")
+
+	holdRepo := NewHoldPostgresRepository(db)
+	for _, userID := range []string{"user1", "user2", "user3"} {
+		if err := holdRepo.SaveHold(context.Background(), models.NewHold(validISBN, userID)); err != nil {
+			t.Fatalf("Failed to save hold: %v", err)
+		}
+	}
+
+	holds, err := holdRepo.GetActiveHoldsByBookID(context.Background(), validISBN)
+	if err != nil {
+		t.Fatalf("Failed to find holds: %v", err)
+	}
+	if len(holds) != 3 {
+		t.Fatalf("expected 3 holds, got %d", len(holds))
+	}
+	for i, userID := range []string{"user1", "user2", "user3"} {
+		if holds[i].UserID != userID {
+			t.Errorf("expected %s at position %d, got %s", userID, i+1, holds[i].UserID)
+		}
+	}
+}
+
+func TestSaveHoldOnlyOneActivePerPatron(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	insertBook(t, validISBN, "Test Book")
+
+	holdRepo := NewHoldPostgresRepository(db)
+	_ = holdRepo.SaveHold(context.Background(), models.NewHold(validISBN, "user1"))
+
+	err := holdRepo.SaveHold(context.Background(), models.NewHold(validISBN, "user1"))
+	if err != errors.ErrHoldAlreadyPlaced {
+		t.Errorf("expected ErrHoldAlreadyPlaced, got %v", err)
+	}
+}
+
+func TestSaveHoldUnknownBook(t *testing.T) {
+	cleanupDB(t)
+
+	holdRepo := NewHoldPostgresRepository(db)
+	err := holdRepo.SaveHold(context.Background(), models.NewHold("9780306406157", "user1"))
+	if err != errors.ErrBookNotInStorage {
+		t.Errorf("expected ErrBookNotInStorage, got %v", err)
+	}
+}
+
+func TestUpdateHold(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	insertBook(t, validISBN, "Test Book")
+
+	holdRepo := NewHoldPostgresRepository(db)
+	hold := models.NewHold(validISBN, "user1")
+	_ = holdRepo.SaveHold(context.Background(), hold)
+
+	hold.MarkReady(72 * time.Hour)
+	if err := holdRepo.UpdateHold(context.Background(), hold); err != nil {
+		t.Fatalf("Failed to update hold: %v", err)
+	}
+
+	found, err := holdRepo.GetHoldByID(context.Background(), hold.ID)
+	if err != nil {
+		t.Fatalf("Failed to find hold: %v", err)
+	}
+	if !found.IsReady() || found.PickupDeadline == nil {
+		t.Errorf("expected ready hold with pickup deadline, got %+v", found)
+	}
+
+	hold.Fulfill()
+	if err := holdRepo.UpdateHold(context.Background(), hold); err != nil {
+		t.Fatalf("Failed to close hold: %v", err)
+	}
+
+	holds, _ := holdRepo.GetActiveHoldsByBookID(context.Background(), validISBN)
+	if len(holds) != 0 {
+		t.Errorf("expected no active holds, got %d", len(holds))
+	}
+
+	hold.Cancel()
+	if err := holdRepo.UpdateHold(context.Background(), hold); err != errors.ErrHoldClosed {
+		t.Errorf("expected ErrHoldClosed for a closed hold, got %v", err)
+	}
+
+	userHolds, _ := holdRepo.GetUserHolds(context.Background(), "user1")
+	if len(userHolds) != 1 || userHolds[0].Status != models.HoldStatusFulfilled {
+		t.Errorf("expected fulfilled hold in history, got %+v", userHolds)
+	}
+
+	bookHolds, _ := holdRepo.GetBookHolds(context.Background(), validISBN)
+	if len(bookHolds) != 1 || bookHolds[0].ID != hold.ID {
+		t.Errorf("expected the fulfilled hold in the book's history, got %+v", bookHolds)
+	}
+}
+
+func TestGetHoldByIDNotFound(t *testing.T) {
+	cleanupDB(t)
+
+	holdRepo := NewHoldPostgresRepository(db)
+	for _, id := range []string{"not-a-uuid", "00000000-0000-0000-0000-000000000000"} {
+		if _, err := holdRepo.GetHoldByID(context.Background(), id); err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound for %q, got %v", id, err)
+		}
+	}
+}
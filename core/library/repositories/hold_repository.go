@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"books/core/library/models"
+)
+
+type HoldRepository interface {
+	GetHoldByID(ctx context.Context, id string) (*models.Hold, error)
+	// GetActiveHoldsByBookID returns the waiting and ready holds of a book in queue order.
+	GetActiveHoldsByBookID(ctx context.Context, bookID string) ([]*models.Hold, error)
+	// GetBookHolds returns every hold of a book, closed ones included.
+	GetBookHolds(ctx context.Context, bookID string) ([]*models.Hold, error)
+	GetUserHolds(ctx context.Context, userID string) ([]*models.Hold, error)
+	SaveHold(ctx context.Context, hold *models.Hold) error
+	UpdateHold(ctx context.Context, hold *models.Hold) error
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+
+	"books/core/library/models"
+)
+
+type LedgerInMemoryRepository struct {
+	entries []*models.LedgerEntry
+	mutex   sync.RWMutex
+}
+
+func NewLedgerInMemoryRepository() *LedgerInMemoryRepository {
+	return &LedgerInMemoryRepository{
+		entries: make([]*models.LedgerEntry, 0),
+	}
+}
+
+func (r *LedgerInMemoryRepository) GetUserLedgerEntries(ctx context.Context, userID string) ([]*models.LedgerEntry, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.LedgerEntry, 0)
+	for _, entry := range r.entries {
+		if entry.UserID == userID {
+			c := *entry
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+func (r *LedgerInMemoryRepository) SaveLedgerEntry(ctx context.Context, entry *models.LedgerEntry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if entry.ID == "" {
+		entry.ID = newID()
+	}
+
+	c := *entry
+	r.entries = append(r.entries, &c)
+	return nil
+}
+
+func (r *LedgerInMemoryRepository) SaveFineCharge(ctx context.Context, entry *models.LedgerEntry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.entries {
+		if existing.RentalID == entry.RentalID && existing.Note == models.FineNote {
+			entry.ID = existing.ID
+			return nil
+		}
+	}
+
+	if entry.ID == "" {
+		entry.ID = newID()
+	}
+
+	c := *entry
+	r.entries = append(r.entries, &c)
+	return nil
+}
+
+var _ LedgerRepository = (*LedgerInMemoryRepository)(nil)
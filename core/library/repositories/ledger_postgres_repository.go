@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"books/core/library/models"
+)
+
+type LedgerPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewLedgerPostgresRepository(db *sql.DB) *LedgerPostgresRepository {
+	return &LedgerPostgresRepository{
+		db: db,
+	}
+}
+
+func (r *LedgerPostgresRepository) GetUserLedgerEntries(ctx context.Context, userID string) ([]*models.LedgerEntry, error) {
+	query := `
+		SELECT id, user_id, entry_type, amount_cents, COALESCE(rental_id::text, ''), note, created_at
+		FROM ledger_entries
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ledger entries: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	entries := make([]*models.LedgerEntry, 0)
+	for rows.Next() {
+		entry := &models.LedgerEntry{}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Type,
+			&entry.AmountCents,
+			&entry.RentalID,
+			&entry.Note,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ledger entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate ledger entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *LedgerPostgresRepository) SaveLedgerEntry(ctx context.Context, entry *models.LedgerEntry) error {
+	query := `
+		INSERT INTO ledger_entries (id, user_id, entry_type, amount_cents, rental_id, note, created_at)
+		VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), $2, $3, $4, NULLIF($5, '')::uuid, $6, $7)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		entry.ID,
+		entry.UserID,
+		entry.Type,
+		entry.AmountCents,
+		entry.RentalID,
+		entry.Note,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save ledger entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *LedgerPostgresRepository) SaveFineCharge(ctx context.Context, entry *models.LedgerEntry) error {
+	// idx_ledger_entries_rental_fine allows one fine per rental; a fine saved
+	// again returns the one already charged.
+	query := `
+		WITH inserted AS (
+			INSERT INTO ledger_entries (id, user_id, entry_type, amount_cents, rental_id, note, created_at)
+			VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), $2, $3, $4, $5::uuid, $6, $7)
+			ON CONFLICT (rental_id) WHERE note = 'overdue fine' DO NOTHING
+			RETURNING id
+		)
+		SELECT id FROM inserted
+		UNION ALL
+		SELECT id FROM ledger_entries WHERE rental_id = $5::uuid AND note = $6
+		LIMIT 1
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		entry.ID,
+		entry.UserID,
+		entry.Type,
+		entry.AmountCents,
+		entry.RentalID,
+		entry.Note,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save fine: %w", err)
+	}
+
+	return nil
+}
+
+var _ LedgerRepository = (*LedgerPostgresRepository)(nil)
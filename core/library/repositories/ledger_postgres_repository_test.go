@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"books/core/library/models"
+)
+
+func TestLedgerEntries(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	copyID := insertBook(t, validISBN, "Test Book")
+
+	rental := newCopyRental(validISBN, copyID, "user1")
+	if err := repo.SaveBookRental(context.Background(), rental); err != nil {
+		t.Fatalf("Failed to save rental: %v", err)
+	}
+
+	ledger := NewLedgerPostgresRepository(db)
+	entries := []*models.LedgerEntry{
+		models.NewFineCharge(rental, 300),
+		models.NewLedgerEntry("user1", models.LedgerEntryPayment, 100, "cash"),
+		models.NewLedgerEntry("user1", models.LedgerEntryWaiver, 50, ""),
+		models.NewLedgerEntry("user2", models.LedgerEntryCharge, 75, ""),
+	}
+	for _, entry := range entries {
+		if err := ledger.SaveLedgerEntry(context.Background(), entry); err != nil {
+			t.Fatalf("Failed to save ledger entry: %v", err)
+		}
+		if entry.ID == "" {
+			t.Errorf("expected generated ledger entry ID")
+		}
+	}
+
+	found, err := ledger.GetUserLedgerEntries(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("Failed to find ledger entries: %v", err)
+	}
+	if len(found) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(found))
+	}
+	if found[0].Type != models.LedgerEntryCharge || found[0].RentalID != rental.ID {
+		t.Errorf("expected the rental charge first, got %+v", found[0])
+	}
+	if found[1].RentalID != "" || found[1].Note != "cash" {
+		t.Errorf("expected payment without rental, got %+v", found[1])
+	}
+
+	if balance := models.NewFineAccount("user1", found).BalanceCents; balance != 150 {
+		t.Errorf("expected balance 150, got %d", balance)
+	}
+}
+
+func TestSaveFineChargeOncePerRental(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	copyID := insertBook(t, validISBN, "Test Book")
+
+	rental := newCopyRental(validISBN, copyID, "user1")
+	if err := repo.SaveBookRental(context.Background(), rental); err != nil {
+		t.Fatalf("Failed to save rental: %v", err)
+	}
+
+	ledger := NewLedgerPostgresRepository(db)
+	for name, repository := range map[string]LedgerRepository{"Postgres": ledger, "memory": NewLedgerInMemoryRepository()} {
+		first := models.NewFineCharge(rental, 300)
+		if err := repository.SaveFineCharge(context.Background(), first); err != nil {
+			t.Fatalf("%s: failed to save fine: %v", name, err)
+		}
+		again := models.NewFineCharge(rental, 400)
+		if err := repository.SaveFineCharge(context.Background(), again); err != nil {
+			t.Fatalf("%s: failed to save fine again: %v", name, err)
+		}
+		if again.ID != first.ID {
+			t.Errorf("%s: expected the fine already charged, got %s and %s", name, first.ID, again.ID)
+		}
+
+		found, _ := repository.GetUserLedgerEntries(context.Background(), "user1")
+		if len(found) != 1 || found[0].AmountCents != 300 {
+			t.Errorf("%s: expected one fine of 300, got %+v", name, found)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"books/core/library/models"
+)
+
+type LedgerRepository interface {
+	// GetUserLedgerEntries returns the user's ledger oldest entry first.
+	GetUserLedgerEntries(ctx context.Context, userID string) ([]*models.LedgerEntry, error)
+	SaveLedgerEntry(ctx context.Context, entry *models.LedgerEntry) error
+	// SaveFineCharge saves the overdue fine of a rental unless the rental was
+	// already fined, so a return that failed after charging can be retried.
+	SaveFineCharge(ctx context.Context, entry *models.LedgerEntry) error
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+
+	"books/core/library/errors"
+	"books/core/library/models"
+)
+
+type TransferInMemoryRepository struct {
+	transfers []*models.Transfer
+	mutex     sync.RWMutex
+}
+
+func NewTransferInMemoryRepository() *TransferInMemoryRepository {
+	return &TransferInMemoryRepository{
+		transfers: make([]*models.Transfer, 0),
+	}
+}
+
+func (r *TransferInMemoryRepository) GetTransferByID(ctx context.Context, id string) (*models.Transfer, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, transfer := range r.transfers {
+		if transfer.ID == id {
+			return copyTransfer(transfer), nil
+		}
+	}
+
+	return nil, errors.ErrNotFound
+}
+
+func (r *TransferInMemoryRepository) GetOpenTransferByCopyID(ctx context.Context, copyID string) (*models.Transfer, error) {
+	transfers := r.openTransfers(func(t *models.Transfer) bool {
+		return t.CopyID == copyID
+	})
+	if len(transfers) == 0 {
+		return nil, errors.ErrNotFound
+	}
+	return transfers[0], nil
+}
+
+func (r *TransferInMemoryRepository) GetOpenTransfersByBookID(ctx context.Context, bookID string) ([]*models.Transfer, error) {
+	return r.openTransfers(func(t *models.Transfer) bool {
+		return t.BookID == bookID
+	}), nil
+}
+
+func (r *TransferInMemoryRepository) GetOpenTransfersToBranch(ctx context.Context, branchID string) ([]*models.Transfer, error) {
+	return r.openTransfers(func(t *models.Transfer) bool {
+		return t.ToBranchID == branchID
+	}), nil
+}
+
+func (r *TransferInMemoryRepository) SaveTransfer(ctx context.Context, transfer *models.Transfer) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if transfer.ID == "" {
+		transfer.ID = newID()
+	}
+
+	r.transfers = append(r.transfers, copyTransfer(transfer))
+	return nil
+}
+
+func (r *TransferInMemoryRepository) UpdateTransfer(ctx context.Context, transfer *models.Transfer) error {
+	if transfer.ID == "" {
+		return errors.ErrInvalidID
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, existing := range r.transfers {
+		if existing.ID == transfer.ID {
+			if !existing.IsOpen() {
+				return errors.ErrTransferReceived
+			}
+			r.transfers[i] = copyTransfer(transfer)
+			return nil
+		}
+	}
+
+	return errors.ErrNotFound
+}
+
+// openTransfers returns the matching open transfers in the order they were
+// started.
+func (r *TransferInMemoryRepository) openTransfers(match func(*models.Transfer) bool) []*models.Transfer {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.Transfer, 0)
+	for _, transfer := range r.transfers {
+		if transfer.IsOpen() && match(transfer) {
+			result = append(result, copyTransfer(transfer))
+		}
+	}
+	return result
+}
+
+func copyTransfer(transfer *models.Transfer) *models.Transfer {
+	c := *transfer
+	c.ReceivedAt = copyTime(transfer.ReceivedAt)
+	return &c
+}
+
+var _ TransferRepository = (*TransferInMemoryRepository)(nil)
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+
+	"books/core/library/errors"
+	"books/core/library/models"
+
+	"github.com/lib/pq"
+)
+
+type TransferPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewTransferPostgresRepository(db *sql.DB) *TransferPostgresRepository {
+	return &TransferPostgresRepository{
+		db: db,
+	}
+}
+
+func (r *TransferPostgresRepository) GetTransferByID(ctx context.Context, id string) (*models.Transfer, error) {
+	query := `
+		SELECT id, copy_id, book_id, from_branch_id, to_branch_id, reason, started_at, received_at
+		FROM copy_transfers
+		WHERE id = $1
+	`
+
+	transfer, err := scanTransfer(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows || isInvalidUUID(err) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+func (r *TransferPostgresRepository) GetOpenTransferByCopyID(ctx context.Context, copyID string) (*models.Transfer, error) {
+	query := `
+		SELECT id, copy_id, book_id, from_branch_id, to_branch_id, reason, started_at, received_at
+		FROM copy_transfers
+		WHERE copy_id = $1 AND received_at IS NULL
+	`
+
+	transfer, err := scanTransfer(r.db.QueryRowContext(ctx, query, copyID))
+	if err == sql.ErrNoRows || isInvalidUUID(err) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+func (r *TransferPostgresRepository) GetOpenTransfersByBookID(ctx context.Context, bookID string) ([]*models.Transfer, error) {
+	query := `
+		SELECT id, copy_id, book_id, from_branch_id, to_branch_id, reason, started_at, received_at
+		FROM copy_transfers
+		WHERE book_id = $1 AND received_at IS NULL
+		ORDER BY started_at
+	`
+
+	return r.queryTransfers(ctx, query, bookID)
+}
+
+func (r *TransferPostgresRepository) GetOpenTransfersToBranch(ctx context.Context, branchID string) ([]*models.Transfer, error) {
+	query := `
+		SELECT id, copy_id, book_id, from_branch_id, to_branch_id, reason, started_at, received_at
+		FROM copy_transfers
+		WHERE to_branch_id = $1 AND received_at IS NULL
+		ORDER BY started_at
+	`
+
+	transfers, err := r.queryTransfers(ctx, query, branchID)
+	if isInvalidUUID(err) {
+		return make([]*models.Transfer, 0), nil
+	}
+	return transfers, err
+}
+
+func (r *TransferPostgresRepository) SaveTransfer(ctx context.Context, transfer *models.Transfer) error {
+	query := `
+		INSERT INTO copy_transfers (id, copy_id, book_id, from_branch_id, to_branch_id, reason, started_at, received_at)
+		VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		transfer.ID,
+		transfer.CopyID,
+		transfer.BookID,
+		transfer.FromBranchID,
+		transfer.ToBranchID,
+		transfer.Reason,
+		transfer.StartedAt,
+		transfer.ReceivedAt,
+	).Scan(&transfer.ID)
+	if err != nil {
+		var pqErr *pq.Error
+		if stderrors.As(err, &pqErr) && pqErr.Code == pqForeignKeyViolation {
+			return errors.ErrNotFound
+		}
+		return fmt.Errorf("failed to save transfer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TransferPostgresRepository) UpdateTransfer(ctx context.Context, transfer *models.Transfer) error {
+	if transfer.ID == "" {
+		return errors.ErrInvalidID
+	}
+
+	// Received transfers are history, so only open ones can change.
+	query := `
+		UPDATE copy_transfers
+		SET received_at = $2
+		WHERE id = $1 AND received_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, transfer.ID, transfer.ReceivedAt)
+	if isInvalidUUID(err) {
+		return errors.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update transfer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		if _, err := r.GetTransferByID(ctx, transfer.ID); err != nil {
+			return err
+		}
+		return errors.ErrTransferReceived
+	}
+
+	return nil
+}
+
+func (r *TransferPostgresRepository) queryTransfers(ctx context.Context, query string, args ...any) ([]*models.Transfer, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	transfers := make([]*models.Transfer, 0)
+	for rows.Next() {
+		transfer, err := scanTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transfer: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+func scanTransfer(row rowScanner) (*models.Transfer, error) {
+	transfer := &models.Transfer{}
+	var receivedAt sql.NullTime
+
+	err := row.Scan(
+		&transfer.ID,
+		&transfer.CopyID,
+		&transfer.BookID,
+		&transfer.FromBranchID,
+		&transfer.ToBranchID,
+		&transfer.Reason,
+		&transfer.StartedAt,
+		&receivedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer.ReceivedAt = nullTimePtr(receivedAt)
+
+	return transfer, nil
+}
+
+var _ TransferRepository = (*TransferPostgresRepository)(nil)
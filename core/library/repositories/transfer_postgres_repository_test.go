@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"books/core/library/errors"
+	"books/core/library/models"
+)
+
+func insertBranch(t *testing.T, code string) string {
+	var branchID string
+	err := db.QueryRow(
+		"INSERT INTO branches (code, name) VALUES ($1, $2) RETURNING id",
+		code, code+" branch",
+	).Scan(&branchID)
+	if err != nil {
+		t.Fatalf("Failed to insert branch: %v", err)
+	}
+	return branchID
+}
+
+func TestSaveTransfer(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	copyID := insertBook(t, validISBN, "Test Book")
+	main := insertBranch(t, "main")
+	north := insertBranch(t, "north")
+
+	transferRepo := NewTransferPostgresRepository(db)
+	transfer := models.NewTransfer(copyID, validISBN, main, north, models.TransferManual)
+	if err := transferRepo.SaveTransfer(context.Background(), transfer); err != nil {
+		t.Fatalf("Failed to save transfer: %v", err)
+	}
+	if transfer.ID == "" {
+		t.Fatalf("expected transfer ID to be assigned")
+	}
+
+	found, err := transferRepo.GetOpenTransferByCopyID(context.Background(), copyID)
+	if err != nil {
+		t.Fatalf("Failed to find transfer: %v", err)
+	}
+	if found.ID != transfer.ID || found.FromBranchID != main || found.ToBranchID != north || found.Reason != models.TransferManual {
+		t.Errorf("unexpected transfer %+v", found)
+	}
+
+	inbound, _ := transferRepo.GetOpenTransfersToBranch(context.Background(), north)
+	if len(inbound) != 1 {
+		t.Errorf("expected 1 inbound transfer, got %d", len(inbound))
+	}
+	byBook, _ := transferRepo.GetOpenTransfersByBookID(context.Background(), validISBN)
+	if len(byBook) != 1 {
+		t.Errorf("expected 1 open transfer for the book, got %d", len(byBook))
+	}
+}
+
+func TestSaveTransferUnknownBranch(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	copyID := insertBook(t, validISBN, "Test Book")
+	main := insertBranch(t, "main")
+
+	transferRepo := NewTransferPostgresRepository(db)
+	transfer := models.NewTransfer(copyID, validISBN, main, "00000000-0000-0000-0000-000000000000", models.TransferManual)
+	if err := transferRepo.SaveTransfer(context.Background(), transfer); err != errors.ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpdateTransfer(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	copyID := insertBook(t, validISBN, "Test Book")
+	main := insertBranch(t, "main")
+	north := insertBranch(t, "north")
+
+	transferRepo := NewTransferPostgresRepository(db)
+	transfer := models.NewTransfer(copyID, validISBN, main, north, models.TransferReturn)
+	_ = transferRepo.SaveTransfer(context.Background(), transfer)
+
+	transfer.Receive()
+	if err := transferRepo.UpdateTransfer(context.Background(), transfer); err != nil {
+		t.Fatalf("Failed to update transfer: %v", err)
+	}
+
+	found, err := transferRepo.GetTransferByID(context.Background(), transfer.ID)
+	if err != nil {
+		t.Fatalf("Failed to find transfer: %v", err)
+	}
+	if found.IsOpen() {
+		t.Errorf("expected transfer to be received, got %+v", found)
+	}
+
+	if _, err := transferRepo.GetOpenTransferByCopyID(context.Background(), copyID); err != errors.ErrNotFound {
+		t.Errorf("expected no open transfer, got %v", err)
+	}
+	if err := transferRepo.UpdateTransfer(context.Background(), transfer); err != errors.ErrTransferReceived {
+		t.Errorf("expected ErrTransferReceived, got %v", err)
+	}
+}
+
+func TestGetTransferByIDNotFound(t *testing.T) {
+	cleanupDB(t)
+
+	transferRepo := NewTransferPostgresRepository(db)
+	for _, id := range []string{"00000000-0000-0000-0000-000000000000", "not-a-uuid"} {
+		if _, err := transferRepo.GetTransferByID(context.Background(), id); err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound for %q, got %v", id, err)
+		}
+	}
+}
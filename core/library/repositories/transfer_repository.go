@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+
+	"books/core/library/models"
+)
+
+type TransferRepository interface {
+	GetTransferByID(ctx context.Context, id string) (*models.Transfer, error)
+	// GetOpenTransferByCopyID returns the transfer of a copy in transit.
+	GetOpenTransferByCopyID(ctx context.Context, copyID string) (*models.Transfer, error)
+	// GetOpenTransfersByBookID returns the copies of a book still in transit,
+	// oldest first.
+	GetOpenTransfersByBookID(ctx context.Context, bookID string) ([]*models.Transfer, error)
+	// GetOpenTransfersToBranch returns the copies on their way to a branch,
+	// oldest first.
+	GetOpenTransfersToBranch(ctx context.Context, branchID string) ([]*models.Transfer, error)
+	SaveTransfer(ctx context.Context, transfer *models.Transfer) error
+	UpdateTransfer(ctx context.Context, transfer *models.Transfer) error
+}
@@ -0,0 +1,11 @@
+package errors
+
+import (
+	"errors"
+)
+
+var (
+	ErrNoticeAlreadySent = errors.New("notice has already been sent")
+	ErrNoticeNotFound    = errors.New("notice not found")
+	ErrNoRecipient       = errors.New("patron has no address to notify")
+)
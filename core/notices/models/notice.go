@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type NoticeKind string
+
+const (
+	// NoticeDueSoon reminds the patron that a loan is due in a few days.
+	NoticeDueSoon NoticeKind = "due_soon"
+	// NoticeOverdue tells the patron a loan is past its deadline.
+	NoticeOverdue NoticeKind = "overdue"
+)
+
+// Notice records that a reminder or overdue notice went out for a rental.
+// Days is the schedule step it was sent for: days before the deadline for
+// reminders, days past it for overdue notices. A renewal moves DueAt, so
+// the same step is sent again for the new deadline.
+type Notice struct {
+	ID       string     `json:"id"`
+	RentalID string     `json:"rental_id"`
+	UserID   string     `json:"user_id"`
+	Kind     NoticeKind `json:"kind"`
+	Days     int        `json:"days"`
+	DueAt    time.Time  `json:"due_at"`
+	SentAt   time.Time  `json:"sent_at"`
+}
+
+func NewNotice(message Message) *Notice {
+	return &Notice{
+		RentalID: message.RentalID,
+		UserID:   message.UserID,
+		Kind:     message.Kind,
+		Days:     message.Step,
+		DueAt:    message.DueAt,
+		SentAt:   time.Now(),
+	}
+}
+
+// Message is what a Notifier delivers to the patron. Step is the schedule
+// step the notice is sent for, which keeps it from going out twice; Days is
+// how many days the loan actually has left, or is late, and is what the
+// patron is told.
+type Message struct {
+	Kind       NoticeKind `json:"kind"`
+	Step       int        `json:"step"`
+	Days       int        `json:"days"`
+	RentalID   string     `json:"rental_id"`
+	UserID     string     `json:"user_id"`
+	PatronName string     `json:"patron_name"`
+	Email      string     `json:"email"`
+	ISBN       string     `json:"isbn"`
+	Title      string     `json:"title"`
+	DueAt      time.Time  `json:"due_at"`
+}
+
+func (m Message) Subject() string {
+	if m.Kind == NoticeOverdue {
+		return fmt.Sprintf("Overdue: %s", m.Title)
+	}
+	return fmt.Sprintf("Reminder: %s is due in %s", m.Title, pluralDays(m.Days))
+}
+
+func (m Message) Body() string {
+	due := m.DueAt.Format("Monday, 2 January 2006")
+
+	if m.Kind == NoticeOverdue {
+		return fmt.Sprintf("Dear %s,\n\n%q (ISBN %s) was due back on %s and is now %s overdue. "+
+			"Please return it as soon as possible; late fines are added for every day it is kept.\n",
+			m.PatronName, m.Title, m.ISBN, due, pluralDays(m.Days))
+	}
+	return fmt.Sprintf("Dear %s,\n\n%q (ISBN %s) is due back on %s. "+
+		"Please return or renew it before then to avoid late fines.\n",
+		m.PatronName, m.Title, m.ISBN, due)
+}
+
+func pluralDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,12 @@
+package notifiers
+
+import (
+	"context"
+
+	"books/core/notices/models"
+)
+
+// Notifier delivers a reminder or overdue notice to a patron.
+type Notifier interface {
+	Notify(ctx context.Context, message models.Message) error
+}
@@ -0,0 +1,220 @@
+package notifiers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"books/core/notices/errors"
+	"books/core/notices/models"
+)
+
+func testMessage() models.Message {
+	return models.Message{
+		Kind:       models.NoticeOverdue,
+		Step:       7,
+		Days:       7,
+		RentalID:   "rental1",
+		UserID:     "user1",
+		PatronName: "Ada Lovelace",
+		Email:      "ada@example.com",
+		ISBN:       "9783161484100",
+		Title:      "Test Book",
+		DueAt:      time.Now().Add(-7 * 24 * time.Hour),
+	}
+}
+
+// fakeSMTPServer accepts one mail session and hands back the recipients and
+// the message data.
+type fakeSMTPServer struct {
+	listener   net.Listener
+	recipients chan string
+	data       chan string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	server := &fakeSMTPServer{
+		listener:   listener,
+		recipients: make(chan string, 10),
+		data:       make(chan string, 1),
+	}
+	go server.serve()
+	return server
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 fake.smtp ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 fake.smtp")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.recipients <- strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>")
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data <- data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	notifier := NewSMTPNotifier(SMTPConfig{Host: "127.0.0.1", Port: server.port(), From: "library@example.com"})
+
+	if err := notifier.Notify(context.Background(), testMessage()); err != nil {
+		t.Fatalf("failed to send notice: %v", err)
+	}
+
+	if recipient := <-server.recipients; recipient != "ada@example.com" {
+		t.Errorf("expected mail to ada@example.com, got %s", recipient)
+	}
+
+	data := <-server.data
+	if !strings.Contains(data, "Subject: Overdue: Test Book") {
+		t.Errorf("expected overdue subject, got:\n%s", data)
+	}
+	if !strings.Contains(data, "7 days overdue") {
+		t.Errorf("expected body to say how late the book is, got:\n%s", data)
+	}
+}
+
+func TestSMTPNotifierWithDisplayName(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	notifier := NewSMTPNotifier(SMTPConfig{Host: "127.0.0.1", Port: server.port(), From: "Library <library@example.com>"})
+
+	message := testMessage()
+	message.Email = "Ada Lovelace <ada@example.com>"
+	if err := notifier.Notify(context.Background(), message); err != nil {
+		t.Fatalf("failed to send notice: %v", err)
+	}
+
+	if recipient := <-server.recipients; recipient != "ada@example.com" {
+		t.Errorf("expected the bare address as recipient, got %s", recipient)
+	}
+	data := <-server.data
+	if !strings.Contains(data, "To: \"Ada Lovelace\" <ada@example.com>\r\n") {
+		t.Errorf("expected the display name in the To header, got:\n%s", data)
+	}
+	if !strings.Contains(data, "From: \"Library\" <library@example.com>\r\n") {
+		t.Errorf("expected the display name in the From header, got:\n%s", data)
+	}
+}
+
+func TestSMTPNotifierStalledServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	// The server accepts the connection and never greets.
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+
+	notifier := NewSMTPNotifier(SMTPConfig{Host: "127.0.0.1", Port: listener.Addr().(*net.TCPAddr).Port, From: "library@example.com"})
+	notifier.timeout = 100 * time.Millisecond
+
+	started := time.Now()
+	// The connection deadline and the context end together; either may be
+	// reported.
+	err = notifier.Notify(context.Background(), testMessage())
+	if !stderrors.Is(err, context.DeadlineExceeded) && !stderrors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("expected the stalled session to time out, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("expected the notifier to give up quickly, took %s", elapsed)
+	}
+
+	_ = (<-accepted).Close()
+}
+
+func TestSMTPNotifierWithoutEmail(t *testing.T) {
+	notifier := NewSMTPNotifier(SMTPConfig{Host: "127.0.0.1", Port: 1, From: "library@example.com"})
+
+	message := testMessage()
+	message.Email = ""
+	if err := notifier.Notify(context.Background(), message); !stderrors.Is(err, errors.ErrNoRecipient) {
+		t.Errorf("expected ErrNoRecipient, got %v", err)
+	}
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var received map[string]interface{}
+	status := http.StatusNoContent
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+
+	if err := notifier.Notify(context.Background(), testMessage()); err != nil {
+		t.Fatalf("failed to post notice: %v", err)
+	}
+	if received["kind"] != "overdue" || received["rental_id"] != "rental1" || received["subject"] != "Overdue: Test Book" {
+		t.Errorf("unexpected payload %v", received)
+	}
+
+	status = http.StatusBadGateway
+	if err := notifier.Notify(context.Background(), testMessage()); err == nil {
+		t.Errorf("expected a failed delivery to be reported")
+	}
+}
@@ -0,0 +1,148 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"books/core/notices/errors"
+	"books/core/notices/models"
+)
+
+// SMTPConfig holds the mail server the SMTP notifier sends through.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type SMTPNotifier struct {
+	config  SMTPConfig
+	timeout time.Duration
+}
+
+func NewSMTPNotifier(config SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{
+		config:  config,
+		timeout: 10 * time.Second,
+	}
+}
+
+// Notify emails the notice to the patron. Patrons without an email address
+// cannot be reached and get ErrNoRecipient. The whole mail session is bounded
+// by the context and the notifier's timeout, so a stalled mail server cannot
+// hold up a scan.
+func (n *SMTPNotifier) Notify(ctx context.Context, message models.Message) error {
+	if strings.TrimSpace(message.Email) == "" {
+		return errors.ErrNoRecipient
+	}
+
+	// Addresses may carry a display name, which only belongs in the headers.
+	to, err := mail.ParseAddress(message.Email)
+	if err != nil {
+		return fmt.Errorf("invalid notice recipient: %w", err)
+	}
+	from, err := mail.ParseAddress(n.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid notice sender: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to reach mail server: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("failed to set mail deadline: %w", err)
+		}
+	}
+	// Cancelling the context cuts the session short as well.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if err := n.send(conn, from, to, message); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("failed to send notice email: %w", ctxErr)
+		}
+		return fmt.Errorf("failed to send notice email: %w", err)
+	}
+
+	return nil
+}
+
+// send runs the mail session the way smtp.SendMail does, over a connection
+// whose deadline the caller controls.
+func (n *SMTPNotifier) send(conn net.Conn, from, to *mail.Address, message models.Message) error {
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.config.Host}); err != nil {
+			return err
+		}
+	}
+
+	if n.config.Username != "" {
+		auth := smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	data, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := data.Write(n.buildMail(from, to, message)); err != nil {
+		return err
+	}
+	if err := data.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (n *SMTPNotifier) buildMail(from, to *mail.Address, message models.Message) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject()))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(message.Body(), "\n", "\r\n"))
+
+	return buf.Bytes()
+}
+
+var _ Notifier = (*SMTPNotifier)(nil)
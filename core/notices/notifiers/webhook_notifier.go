@@ -0,0 +1,63 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"books/core/notices/models"
+)
+
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type webhookPayload struct {
+	models.Message
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Notify posts the notice as JSON. Any status outside 2xx counts as a
+// failed delivery, so the notice is tried again on the next scan.
+func (n *WebhookNotifier) Notify(ctx context.Context, message models.Message) error {
+	payload, err := json.Marshal(webhookPayload{
+		Message: message,
+		Subject: message.Subject(),
+		Body:    message.Body(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode notice: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build notice request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notice: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notice webhook answered %s", resp.Status)
+	}
+
+	return nil
+}
+
+var _ Notifier = (*WebhookNotifier)(nil)
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	"books/core/notices/errors"
+	"books/core/notices/models"
+)
+
+type NoticeInMemoryRepository struct {
+	notices []*models.Notice
+	mutex   sync.RWMutex
+}
+
+func NewNoticeInMemoryRepository() *NoticeInMemoryRepository {
+	return &NoticeInMemoryRepository{
+		notices: make([]*models.Notice, 0),
+	}
+}
+
+func (r *NoticeInMemoryRepository) SaveNotice(ctx context.Context, notice *models.Notice) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.notices {
+		if existing.RentalID == notice.RentalID &&
+			existing.Kind == notice.Kind &&
+			existing.Days == notice.Days &&
+			existing.DueAt.Equal(notice.DueAt) {
+			return errors.ErrNoticeAlreadySent
+		}
+	}
+
+	if notice.ID == "" {
+		notice.ID = newID()
+	}
+
+	c := *notice
+	r.notices = append(r.notices, &c)
+	return nil
+}
+
+func (r *NoticeInMemoryRepository) DeleteNotice(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, notice := range r.notices {
+		if notice.ID == id {
+			r.notices = append(r.notices[:i], r.notices[i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.ErrNoticeNotFound
+}
+
+func (r *NoticeInMemoryRepository) GetRentalNotices(ctx context.Context, rentalID string) ([]*models.Notice, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.Notice, 0)
+	for _, notice := range r.notices {
+		if notice.RentalID == rentalID {
+			c := *notice
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+var _ NoticeRepository = (*NoticeInMemoryRepository)(nil)
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+
+	"books/core/notices/errors"
+	"books/core/notices/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	pqUniqueViolation = "23505"
+	pqInvalidText     = "22P02"
+)
+
+type NoticePostgresRepository struct {
+	db *sql.DB
+}
+
+func NewNoticePostgresRepository(db *sql.DB) *NoticePostgresRepository {
+	return &NoticePostgresRepository{
+		db: db,
+	}
+}
+
+func (r *NoticePostgresRepository) SaveNotice(ctx context.Context, notice *models.Notice) error {
+	query := `
+		INSERT INTO rental_notices (id, rental_id, user_id, kind, days, due_at, sent_at)
+		VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		notice.ID,
+		notice.RentalID,
+		notice.UserID,
+		notice.Kind,
+		notice.Days,
+		notice.DueAt,
+		notice.SentAt,
+	).Scan(&notice.ID)
+
+	var pqErr *pq.Error
+	if stderrors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+		return errors.ErrNoticeAlreadySent
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save notice: %w", err)
+	}
+
+	return nil
+}
+
+func (r *NoticePostgresRepository) DeleteNotice(ctx context.Context, id string) error {
+	query := `DELETE FROM rental_notices WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+
+	var pqErr *pq.Error
+	if stderrors.As(err, &pqErr) && pqErr.Code == pqInvalidText {
+		return errors.ErrNoticeNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete notice: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.ErrNoticeNotFound
+	}
+
+	return nil
+}
+
+func (r *NoticePostgresRepository) GetRentalNotices(ctx context.Context, rentalID string) ([]*models.Notice, error) {
+	query := `
+		SELECT id, rental_id, user_id, kind, days, due_at, sent_at
+		FROM rental_notices
+		WHERE rental_id = $1
+		ORDER BY sent_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, rentalID)
+
+	var pqErr *pq.Error
+	if stderrors.As(err, &pqErr) && pqErr.Code == pqInvalidText {
+		return []*models.Notice{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notices: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	notices := make([]*models.Notice, 0)
+	for rows.Next() {
+		notice := &models.Notice{}
+		err := rows.Scan(
+			&notice.ID,
+			&notice.RentalID,
+			&notice.UserID,
+			&notice.Kind,
+			&notice.Days,
+			&notice.DueAt,
+			&notice.SentAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notice: %w", err)
+		}
+		notices = append(notices, notice)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate notices: %w", err)
+	}
+
+	return notices, nil
+}
+
+var _ NoticeRepository = (*NoticePostgresRepository)(nil)
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"books/core/notices/errors"
+	"books/core/notices/models"
+	"books/internal/testdb"
+)
+
+var db *sql.DB
+var repo *NoticePostgresRepository
+
+func TestMain(m *testing.M) {
+	testdb.Run(m, func(conn *sql.DB) {
+		db = conn
+		repo = NewNoticePostgresRepository(db)
+	})
+}
+
+func cleanupDB(t *testing.T) {
+	for _, table := range []string{"rental_notices", "book_rentals", "book_copies", "books"} {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("Failed to cleanup %s: %v", table, err)
+		}
+	}
+}
+
+// insertRental lends the only copy of a fresh book to user1 and returns the
+// rental ID.
+func insertRental(t *testing.T) string {
+	t.Helper()
+
+	_, err := db.Exec(
+		"INSERT INTO books (isbn, title, author, published_at, category) VALUES ($1, $2, $3, $4, $5)",
+		"9783161484100", "Test Book", "Test Author", time.Now(), "standard",
+	)
+	if err != nil {
+		t.Fatalf("Failed to insert book: %v", err)
+	}
+
+	var copyID string
+	err = db.QueryRow("INSERT INTO book_copies (isbn, barcode) VALUES ($1, $2) RETURNING id", "9783161484100", "9783161484100-1").Scan(&copyID)
+	if err != nil {
+		t.Fatalf("Failed to insert copy: %v", err)
+	}
+
+	var rentalID string
+	err = db.QueryRow(
+		"INSERT INTO book_rentals (book_id, copy_id, user_id, borrowed_at, return_deadline) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		"9783161484100", copyID, "user1", time.Now(), time.Now().Add(72*time.Hour),
+	).Scan(&rentalID)
+	if err != nil {
+		t.Fatalf("Failed to insert rental: %v", err)
+	}
+
+	return rentalID
+}
+
+func TestSaveNoticeOnlyOncePerStep(t *testing.T) {
+	cleanupDB(t)
+	rentalID := insertRental(t)
+
+	dueAt := time.Now().Add(72 * time.Hour).Truncate(time.Microsecond)
+	message := models.Message{Kind: models.NoticeDueSoon, Step: 3, Days: 3, RentalID: rentalID, UserID: "user1", DueAt: dueAt}
+
+	notice := models.NewNotice(message)
+	if err := repo.SaveNotice(context.Background(), notice); err != nil {
+		t.Fatalf("Failed to save notice: %v", err)
+	}
+	if notice.ID == "" {
+		t.Errorf("expected generated notice ID")
+	}
+
+	if err := repo.SaveNotice(context.Background(), models.NewNotice(message)); err != errors.ErrNoticeAlreadySent {
+		t.Errorf("expected ErrNoticeAlreadySent, got %v", err)
+	}
+
+	// A renewal moves the deadline, so the same step can be sent again.
+	message.DueAt = dueAt.Add(14 * 24 * time.Hour)
+	if err := repo.SaveNotice(context.Background(), models.NewNotice(message)); err != nil {
+		t.Errorf("expected notice for the new deadline to be saved, got %v", err)
+	}
+
+	notices, err := repo.GetRentalNotices(context.Background(), rentalID)
+	if err != nil {
+		t.Fatalf("Failed to load notices: %v", err)
+	}
+	if len(notices) != 2 || notices[0].Kind != models.NoticeDueSoon || notices[0].Days != 3 {
+		t.Errorf("unexpected notices %+v", notices)
+	}
+}
+
+func TestDeleteNotice(t *testing.T) {
+	cleanupDB(t)
+	rentalID := insertRental(t)
+
+	notice := models.NewNotice(models.Message{Kind: models.NoticeOverdue, Step: 1, Days: 1, RentalID: rentalID, UserID: "user1", DueAt: time.Now()})
+	if err := repo.SaveNotice(context.Background(), notice); err != nil {
+		t.Fatalf("Failed to save notice: %v", err)
+	}
+
+	if err := repo.DeleteNotice(context.Background(), notice.ID); err != nil {
+		t.Fatalf("Failed to delete notice: %v", err)
+	}
+	if err := repo.DeleteNotice(context.Background(), notice.ID); err != errors.ErrNoticeNotFound {
+		t.Errorf("expected ErrNoticeNotFound, got %v", err)
+	}
+	if err := repo.DeleteNotice(context.Background(), "not-a-uuid"); err != errors.ErrNoticeNotFound {
+		t.Errorf("expected ErrNoticeNotFound for an invalid ID, got %v", err)
+	}
+}
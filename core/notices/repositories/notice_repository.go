@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"books/core/notices/models"
+)
+
+type NoticeRepository interface {
+	// SaveNotice records a notice, failing with ErrNoticeAlreadySent when the
+	// same step was already recorded for the rental's deadline.
+	SaveNotice(ctx context.Context, notice *models.Notice) error
+	DeleteNotice(ctx context.Context, id string) error
+	// GetRentalNotices returns the notices of a rental oldest first.
+	GetRentalNotices(ctx context.Context, rentalID string) ([]*models.Notice, error)
+}
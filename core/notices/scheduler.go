@@ -0,0 +1,210 @@
+package notices
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	library_models "books/core/library/models"
+	library_repositories "books/core/library/repositories"
+	"books/core/notices/errors"
+	"books/core/notices/models"
+	"books/core/notices/notifiers"
+	"books/core/notices/repositories"
+	patron_errors "books/core/patrons/errors"
+	patron_repositories "books/core/patrons/repositories"
+)
+
+// Schedule says when notices go out. ReminderDays are counted back from the
+// deadline, OverdueDays forward from it, so an overdue schedule of 1, 7 and
+// 14 escalates from a first notice to weekly ones.
+type Schedule struct {
+	ReminderDays []int
+	OverdueDays  []int
+}
+
+func DefaultSchedule() Schedule {
+	return Schedule{
+		ReminderDays: []int{3, 1},
+		OverdueDays:  []int{1, 7, 14, 30},
+	}
+}
+
+// Scheduler scans open rentals and sends the reminders and overdue notices
+// that are due. Every notice is recorded before it is sent, so a restart
+// never sends the same step twice.
+type Scheduler struct {
+	rentals  library_repositories.BookRepository
+	patrons  patron_repositories.PatronRepository
+	notices  repositories.NoticeRepository
+	notifier notifiers.Notifier
+	schedule Schedule
+}
+
+func NewScheduler(rentals library_repositories.BookRepository, patrons patron_repositories.PatronRepository, notices repositories.NoticeRepository, notifier notifiers.Notifier, schedule Schedule) *Scheduler {
+	return &Scheduler{
+		rentals:  rentals,
+		patrons:  patrons,
+		notices:  notices,
+		notifier: notifier,
+		schedule: schedule,
+	}
+}
+
+// Run scans once straight away and then every interval until ctx is done.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := s.Scan(ctx)
+		if err != nil {
+			log.Printf("Notice scan error: %v", err)
+		} else if sent > 0 {
+			log.Printf("Sent %d rental notices", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan sends the notices due now and returns how many went out. A failed
+// delivery is logged and retried on the next scan; it does not stop the
+// other rentals from being notified.
+func (s *Scheduler) Scan(ctx context.Context) (int, error) {
+	rentals, err := s.rentals.GetActiveBookRentals(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load open rentals: %w", err)
+	}
+
+	now := time.Now()
+	titles := make(map[string]string)
+	sent := 0
+
+	for _, rental := range rentals {
+		kind, step, days, due := s.step(rental, now)
+		if !due {
+			continue
+		}
+
+		message, err := s.buildMessage(ctx, rental, kind, step, days, titles)
+		if stderrors.Is(err, patron_errors.ErrPatronNotFound) {
+			continue
+		}
+		if err != nil {
+			return sent, err
+		}
+
+		delivered, err := s.send(ctx, message)
+		if err != nil {
+			log.Printf("Failed to send %s notice for rental %s: %v", kind, rental.ID, err)
+			continue
+		}
+		if delivered {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// step picks the notice a rental is due for: the closest reminder before the
+// deadline, or the latest overdue step after it. Steps missed while the
+// server was down are skipped rather than sent in a burst. Along with the
+// step it returns the days actually left, or late, which the step may
+// round up or down.
+func (s *Scheduler) step(rental *library_models.BookRental, now time.Time) (models.NoticeKind, int, int, bool) {
+	remaining := rental.ReturnDeadline.Sub(now)
+
+	if remaining > 0 {
+		daysLeft := int(math.Ceil(remaining.Hours() / 24))
+		reminders := sortedDays(s.schedule.ReminderDays)
+		for _, days := range reminders {
+			if daysLeft <= days {
+				return models.NoticeDueSoon, days, daysLeft, true
+			}
+		}
+		return "", 0, 0, false
+	}
+
+	daysLate := int(math.Ceil(-remaining.Hours() / 24))
+	overdue := sortedDays(s.schedule.OverdueDays)
+	for i := len(overdue) - 1; i >= 0; i-- {
+		if daysLate >= overdue[i] {
+			return models.NoticeOverdue, overdue[i], daysLate, true
+		}
+	}
+	return "", 0, 0, false
+}
+
+func (s *Scheduler) buildMessage(ctx context.Context, rental *library_models.BookRental, kind models.NoticeKind, step, days int, titles map[string]string) (models.Message, error) {
+	patron, err := s.patrons.GetPatronByID(ctx, rental.UserID)
+	if err != nil {
+		return models.Message{}, err
+	}
+
+	title, known := titles[rental.BookID]
+	if !known {
+		book, err := s.rentals.GetBookByISBN(ctx, rental.BookID)
+		if err != nil {
+			return models.Message{}, err
+		}
+		title = book.Title
+		titles[rental.BookID] = title
+	}
+
+	return models.Message{
+		Kind:       kind,
+		Step:       step,
+		Days:       days,
+		RentalID:   rental.ID,
+		UserID:     rental.UserID,
+		PatronName: patron.Name,
+		Email:      patron.Email,
+		ISBN:       rental.BookID,
+		Title:      title,
+		DueAt:      rental.ReturnDeadline,
+	}, nil
+}
+
+// send claims the notice in the repository before delivering it. The claim
+// is released when delivery fails, unless the patron simply cannot be
+// reached, in which case retrying would not help.
+func (s *Scheduler) send(ctx context.Context, message models.Message) (bool, error) {
+	notice := models.NewNotice(message)
+
+	err := s.notices.SaveNotice(ctx, notice)
+	if stderrors.Is(err, errors.ErrNoticeAlreadySent) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = s.notifier.Notify(ctx, message)
+	if stderrors.Is(err, errors.ErrNoRecipient) {
+		return false, nil
+	}
+	if err != nil {
+		if releaseErr := s.notices.DeleteNotice(ctx, notice.ID); releaseErr != nil {
+			log.Printf("Failed to release notice %s: %v", notice.ID, releaseErr)
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func sortedDays(days []int) []int {
+	sorted := append([]int(nil), days...)
+	sort.Ints(sorted)
+	return sorted
+}
@@ -0,0 +1,155 @@
+package notices
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	library_models "books/core/library/models"
+	library_repositories "books/core/library/repositories"
+	"books/core/notices/models"
+	"books/core/notices/repositories"
+	patron_models "books/core/patrons/models"
+	patron_repositories "books/core/patrons/repositories"
+	storage_models "books/core/storage/models"
+	storage_repositories "books/core/storage/repositories"
+)
+
+type recordingNotifier struct {
+	mutex    sync.Mutex
+	messages []models.Message
+	err      error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, message models.Message) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.err != nil {
+		return n.err
+	}
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+type schedulerFixture struct {
+	rentals  *library_repositories.BookRentalInMemoryRepository
+	notices  *repositories.NoticeInMemoryRepository
+	notifier *recordingNotifier
+	patrons  *patron_repositories.PatronInMemoryRepository
+	books    *storage_repositories.BookStorageInMemoryRepository
+	copies   *storage_repositories.CopyStorageInMemoryRepository
+}
+
+func newSchedulerFixture(t *testing.T) *schedulerFixture {
+	t.Helper()
+
+	f := &schedulerFixture{
+		books:    storage_repositories.NewBookStorageInMemoryRepository(),
+		copies:   storage_repositories.NewCopyStorageInMemoryRepository(),
+		notices:  repositories.NewNoticeInMemoryRepository(),
+		notifier: &recordingNotifier{},
+		patrons:  patron_repositories.NewPatronInMemoryRepository(),
+	}
+	f.rentals = library_repositories.NewBookRentalInMemoryRepository(f.books, f.copies)
+
+	_ = f.patrons.SavePatron(context.Background(), &patron_models.Patron{
+		ID:     "user1",
+		Name:   "Ada Lovelace",
+		Email:  "ada@example.com",
+		Status: patron_models.PatronStatusActive,
+	})
+	return f
+}
+
+func (f *schedulerFixture) scheduler() *Scheduler {
+	return NewScheduler(f.rentals, f.patrons, f.notices, f.notifier, DefaultSchedule())
+}
+
+// lend stocks a book and lends it to the user, due after the given offset.
+func (f *schedulerFixture) lend(t *testing.T, isbn, userID string, dueIn time.Duration) *library_models.BookRental {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := f.books.Save(ctx, &storage_models.Book{ISBN: isbn, Title: "Book " + isbn}); err != nil {
+		t.Fatalf("failed to save book: %v", err)
+	}
+	bookCopy, _ := storage_models.NewCopy(isbn, storage_models.DefaultBarcode(isbn, 1), "", "")
+	if err := f.copies.SaveCopy(ctx, bookCopy); err != nil {
+		t.Fatalf("failed to save copy: %v", err)
+	}
+
+	rental := library_models.NewBookRental(isbn, userID)
+	rental.CopyID = bookCopy.ID
+	rental.ReturnDeadline = time.Now().Add(dueIn)
+	if err := f.rentals.SaveBookRental(ctx, rental); err != nil {
+		t.Fatalf("failed to save rental: %v", err)
+	}
+	return rental
+}
+
+func TestSchedulerSendsEachStepOnce(t *testing.T) {
+	f := newSchedulerFixture(t)
+	f.lend(t, "book1", "user1", 2*24*time.Hour-time.Hour)
+	f.lend(t, "book2", "user1", -8*24*time.Hour+time.Hour)
+	f.lend(t, "book3", "user1", 10*24*time.Hour)
+
+	sent, err := f.scheduler().Scan(context.Background())
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if sent != 2 {
+		t.Fatalf("expected 2 notices, got %d", sent)
+	}
+
+	byBook := make(map[string]models.Message)
+	for _, message := range f.notifier.messages {
+		byBook[message.ISBN] = message
+	}
+	// Two days left falls in the 3-day step, and the patron is told 2 days.
+	if reminder := byBook["book1"]; reminder.Kind != models.NoticeDueSoon || reminder.Step != 3 || reminder.Days != 2 || reminder.Email != "ada@example.com" {
+		t.Errorf("expected 3-day reminder for book1, got %+v", reminder)
+	}
+	if subject := byBook["book1"].Subject(); subject != "Reminder: Book book1 is due in 2 days" {
+		t.Errorf("expected the reminder to give the days left, got %q", subject)
+	}
+	// Eight days late is past the 7-day step; the 1-day notice is skipped.
+	if overdue := byBook["book2"]; overdue.Kind != models.NoticeOverdue || overdue.Step != 7 || overdue.Days != 8 || overdue.Title != "Book book2" {
+		t.Errorf("expected 7-day overdue notice for book2, got %+v", overdue)
+	}
+	if body := byBook["book2"].Body(); !strings.Contains(body, "is now 8 days overdue") {
+		t.Errorf("expected the overdue notice to give the days late, got %q", body)
+	}
+
+	// A restarted scheduler shares the recorded notices and sends nothing new.
+	sent, err = f.scheduler().Scan(context.Background())
+	if err != nil || sent != 0 {
+		t.Errorf("expected no repeat notices, got %d (%v)", sent, err)
+	}
+}
+
+func TestSchedulerRetriesFailedDeliveries(t *testing.T) {
+	f := newSchedulerFixture(t)
+	rental := f.lend(t, "book1", "user1", -2*24*time.Hour)
+	f.lend(t, "book2", "stranger", -2*24*time.Hour)
+
+	f.notifier.err = stderrors.New("mail server down")
+	sent, err := f.scheduler().Scan(context.Background())
+	if err != nil || sent != 0 {
+		t.Fatalf("expected failed delivery to be skipped, got %d (%v)", sent, err)
+	}
+
+	notices, _ := f.notices.GetRentalNotices(context.Background(), rental.ID)
+	if len(notices) != 0 {
+		t.Fatalf("expected failed notice to be released, got %+v", notices)
+	}
+
+	f.notifier.err = nil
+	sent, err = f.scheduler().Scan(context.Background())
+	if err != nil || sent != 1 {
+		t.Errorf("expected notice to go out on the next scan, got %d (%v)", sent, err)
+	}
+}
@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/patrons/repositories"
+)
+
+type DeletePatronCommand struct {
+	ID string
+}
+
+type DeletePatronCommandHandler struct {
+	repo repositories.PatronRepository
+}
+
+func NewDeletePatronCommandHandler(repo repositories.PatronRepository) *DeletePatronCommandHandler {
+	return &DeletePatronCommandHandler{repo: repo}
+}
+
+func (h *DeletePatronCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(DeletePatronCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	return h.repo.DeletePatron(ctx, command.ID)
+}
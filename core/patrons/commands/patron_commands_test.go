@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"books/core/patrons/errors"
+	"books/core/patrons/models"
+	"books/core/patrons/repositories"
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+)
+
+const testMembershipPeriod = 30 * 24 * time.Hour
+
+type registerPatronTestCase struct {
+	name      string
+	command   interface{}
+	expectErr error
+}
+
+func getRegisterPatronTestCases() []registerPatronTestCase {
+	return []registerPatronTestCase{
+		{
+			name:    "Register a patron",
+			command: RegisterPatronCommand{CardNumber: "C-2", Name: "Grace Hopper", Email: "grace@example.com", PatronType: "staff"},
+		},
+		{
+			name:      "Duplicate card number",
+			command:   RegisterPatronCommand{CardNumber: "C-1", Name: "Someone Else"},
+			expectErr: errors.ErrDuplicateCardNumber,
+		},
+		{
+			name:      "Missing name",
+			command:   RegisterPatronCommand{CardNumber: "C-3"},
+			expectErr: stderrors.New("name cannot be empty"),
+		},
+		{
+			name:      "Invalid email",
+			command:   RegisterPatronCommand{CardNumber: "C-3", Name: "Grace Hopper", Email: "not-an-email"},
+			expectErr: stderrors.New("invalid email address"),
+		},
+		{
+			name:      "Invalid command type",
+			command:   "not a command",
+			expectErr: stderrors.New("invalid command type"),
+		},
+	}
+}
+
+func newTestRepository(t *testing.T) (*repositories.PatronInMemoryRepository, *models.Patron) {
+	t.Helper()
+	repo := repositories.NewPatronInMemoryRepository()
+
+	patron, err := models.NewPatron("C-1", "Ada Lovelace", "", "", testMembershipPeriod)
+	if err != nil {
+		t.Fatalf("failed to create patron: %v", err)
+	}
+	if err := repo.SavePatron(context.Background(), patron); err != nil {
+		t.Fatalf("failed to save patron: %v", err)
+	}
+	return repo, patron
+}
+
+func TestRegisterPatronCommandHandler_Handle(t *testing.T) {
+	for _, tc := range getRegisterPatronTestCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := newTestRepository(t)
+			handler := NewRegisterPatronCommandHandler(repo, testMembershipPeriod)
+
+			err := handler.Handle(context.Background(), tc.command)
+
+			if tc.expectErr != nil {
+				if err == nil || err.Error() != tc.expectErr.Error() {
+					t.Errorf("expected error '%v', got '%v'", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			patron, err := repo.GetPatronByCardNumber(context.Background(), "C-2")
+			if err != nil {
+				t.Fatalf("expected patron to be saved: %v", err)
+			}
+			if patron.Status != models.PatronStatusActive || patron.PatronType != "staff" {
+				t.Errorf("unexpected patron %+v", patron)
+			}
+			if until := time.Until(patron.ExpiresAt); until < testMembershipPeriod-time.Minute {
+				t.Errorf("expected membership to last %v, expires in %v", testMembershipPeriod, until)
+			}
+		})
+	}
+}
+
+func TestUpdatePatronCommandHandler_Handle(t *testing.T) {
+	repo, patron := newTestRepository(t)
+	handler := NewUpdatePatronCommandHandler(repo)
+
+	err := handler.Handle(context.Background(), UpdatePatronCommand{ID: patron.ID, Email: "ada@example.com", PatronType: "child"})
+	if err != nil {
+		t.Fatalf("failed to update patron: %v", err)
+	}
+
+	updated, _ := repo.GetPatronByID(context.Background(), patron.ID)
+	if updated.Email != "ada@example.com" || updated.PatronType != "child" || updated.Name != "Ada Lovelace" {
+		t.Errorf("unexpected patron after update %+v", updated)
+	}
+
+	if err := handler.Handle(context.Background(), UpdatePatronCommand{ID: patron.ID}); err == nil {
+		t.Errorf("expected empty update to be rejected")
+	}
+
+	err = handler.Handle(context.Background(), UpdatePatronCommand{ID: "missing", Name: "Nobody"})
+	if !stderrors.Is(err, errors.ErrPatronNotFound) {
+		t.Errorf("expected ErrPatronNotFound, got %v", err)
+	}
+}
+
+func TestSuspendAndReinstatePatron(t *testing.T) {
+	repo, patron := newTestRepository(t)
+	suspend := NewSuspendPatronCommandHandler(repo)
+	reinstate := NewReinstatePatronCommandHandler(repo)
+
+	err := reinstate.Handle(context.Background(), ReinstatePatronCommand{ID: patron.ID})
+	if !stderrors.Is(err, errors.ErrPatronNotSuspended) {
+		t.Errorf("expected ErrPatronNotSuspended, got %v", err)
+	}
+
+	if err := suspend.Handle(context.Background(), SuspendPatronCommand{ID: patron.ID}); err != nil {
+		t.Fatalf("failed to suspend patron: %v", err)
+	}
+	suspended, _ := repo.GetPatronByID(context.Background(), patron.ID)
+	if suspended.CurrentStatus() != models.PatronStatusSuspended {
+		t.Errorf("expected suspended patron, got %s", suspended.CurrentStatus())
+	}
+
+	if err := reinstate.Handle(context.Background(), ReinstatePatronCommand{ID: patron.ID}); err != nil {
+		t.Fatalf("failed to reinstate patron: %v", err)
+	}
+	reinstated, _ := repo.GetPatronByID(context.Background(), patron.ID)
+	if reinstated.CurrentStatus() != models.PatronStatusActive {
+		t.Errorf("expected active patron, got %s", reinstated.CurrentStatus())
+	}
+}
+
+func TestRenewPatronCommandHandler_Handle(t *testing.T) {
+	repo, patron := newTestRepository(t)
+
+	patron.ExpiresAt = time.Now().Add(-48 * time.Hour)
+	_ = repo.UpdatePatron(context.Background(), patron)
+
+	expired, _ := repo.GetPatronByID(context.Background(), patron.ID)
+	if expired.CurrentStatus() != models.PatronStatusExpired {
+		t.Fatalf("expected expired patron, got %s", expired.CurrentStatus())
+	}
+
+	handler := NewRenewPatronCommandHandler(repo, testMembershipPeriod)
+	if err := handler.Handle(context.Background(), RenewPatronCommand{ID: patron.ID}); err != nil {
+		t.Fatalf("failed to renew patron: %v", err)
+	}
+
+	renewed, _ := repo.GetPatronByID(context.Background(), patron.ID)
+	if renewed.CurrentStatus() != models.PatronStatusActive {
+		t.Errorf("expected active patron after renewal, got %s", renewed.CurrentStatus())
+	}
+	// A lapsed membership is renewed from today, not from the old expiry.
+	if until := time.Until(renewed.ExpiresAt); until < testMembershipPeriod-time.Minute {
+		t.Errorf("expected renewal to run from today, expires in %v", until)
+	}
+
+	err := handler.Handle(context.Background(), RenewPatronCommand{ID: "missing"})
+	if !stderrors.Is(err, errors.ErrPatronNotFound) {
+		t.Errorf("expected ErrPatronNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"books/core/patrons/models"
+	"books/core/patrons/repositories"
+)
+
+type RegisterPatronCommand struct {
+	ID         string
+	CardNumber string
+	Name       string
+	Email      string
+	PatronType string
+}
+
+type RegisterPatronCommandHandler struct {
+	repo             repositories.PatronRepository
+	membershipPeriod time.Duration
+}
+
+func NewRegisterPatronCommandHandler(repo repositories.PatronRepository, membershipPeriod time.Duration) *RegisterPatronCommandHandler {
+	return &RegisterPatronCommandHandler{
+		repo:             repo,
+		membershipPeriod: membershipPeriod,
+	}
+}
+
+func (h *RegisterPatronCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(RegisterPatronCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	patron, err := models.NewPatron(command.CardNumber, command.Name, command.Email, command.PatronType, h.membershipPeriod)
+	if err != nil {
+		return err
+	}
+	patron.ID = command.ID
+
+	return h.repo.SavePatron(ctx, patron)
+}
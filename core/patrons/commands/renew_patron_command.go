@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"books/core/patrons/repositories"
+)
+
+type RenewPatronCommand struct {
+	ID string
+}
+
+type RenewPatronCommandHandler struct {
+	repo             repositories.PatronRepository
+	membershipPeriod time.Duration
+}
+
+func NewRenewPatronCommandHandler(repo repositories.PatronRepository, membershipPeriod time.Duration) *RenewPatronCommandHandler {
+	return &RenewPatronCommandHandler{
+		repo:             repo,
+		membershipPeriod: membershipPeriod,
+	}
+}
+
+func (h *RenewPatronCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(RenewPatronCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	patron, err := h.repo.GetPatronByID(ctx, command.ID)
+	if err != nil {
+		return err
+	}
+
+	patron.Renew(h.membershipPeriod)
+	return h.repo.UpdatePatron(ctx, patron)
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/patrons/errors"
+	"books/core/patrons/models"
+	"books/core/patrons/repositories"
+)
+
+type SuspendPatronCommand struct {
+	ID string
+}
+
+type SuspendPatronCommandHandler struct {
+	repo repositories.PatronRepository
+}
+
+func NewSuspendPatronCommandHandler(repo repositories.PatronRepository) *SuspendPatronCommandHandler {
+	return &SuspendPatronCommandHandler{repo: repo}
+}
+
+func (h *SuspendPatronCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(SuspendPatronCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	patron, err := h.repo.GetPatronByID(ctx, command.ID)
+	if err != nil {
+		return err
+	}
+
+	patron.Suspend()
+	return h.repo.UpdatePatron(ctx, patron)
+}
+
+type ReinstatePatronCommand struct {
+	ID string
+}
+
+type ReinstatePatronCommandHandler struct {
+	repo repositories.PatronRepository
+}
+
+func NewReinstatePatronCommandHandler(repo repositories.PatronRepository) *ReinstatePatronCommandHandler {
+	return &ReinstatePatronCommandHandler{repo: repo}
+}
+
+func (h *ReinstatePatronCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(ReinstatePatronCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	patron, err := h.repo.GetPatronByID(ctx, command.ID)
+	if err != nil {
+		return err
+	}
+
+	if patron.Status != models.PatronStatusSuspended {
+		return errors.ErrPatronNotSuspended
+	}
+
+	patron.Reinstate()
+	return h.repo.UpdatePatron(ctx, patron)
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+
+	"books/core/patrons/repositories"
+)
+
+// UpdatePatronCommand changes a patron's details. Empty fields are left as
+// they are.
+type UpdatePatronCommand struct {
+	ID         string
+	CardNumber string
+	Name       string
+	Email      string
+	PatronType string
+}
+
+type UpdatePatronCommandHandler struct {
+	repo repositories.PatronRepository
+}
+
+func NewUpdatePatronCommandHandler(repo repositories.PatronRepository) *UpdatePatronCommandHandler {
+	return &UpdatePatronCommandHandler{repo: repo}
+}
+
+func (h *UpdatePatronCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	command, ok := cmd.(UpdatePatronCommand)
+	if !ok {
+		return stderrors.New("invalid command type")
+	}
+
+	if command.CardNumber == "" && command.Name == "" && command.Email == "" && command.PatronType == "" {
+		return stderrors.New("at least one field must be provided for update")
+	}
+
+	patron, err := h.repo.GetPatronByID(ctx, command.ID)
+	if err != nil {
+		return err
+	}
+
+	if command.CardNumber != "" {
+		patron.CardNumber = strings.TrimSpace(command.CardNumber)
+	}
+	if command.Name != "" {
+		patron.Name = strings.TrimSpace(command.Name)
+	}
+	if command.Email != "" {
+		patron.Email = strings.TrimSpace(command.Email)
+	}
+	if command.PatronType != "" {
+		patron.PatronType = strings.TrimSpace(command.PatronType)
+	}
+
+	if err := patron.Validate(); err != nil {
+		return err
+	}
+
+	return h.repo.UpdatePatron(ctx, patron)
+}
@@ -0,0 +1,13 @@
+package errors
+
+import (
+	"errors"
+)
+
+var (
+	ErrPatronNotFound      = errors.New("patron not found")
+	ErrDuplicateCardNumber = errors.New("a patron with this card number already exists")
+	ErrPatronSuspended     = errors.New("patron account is suspended")
+	ErrPatronExpired       = errors.New("patron membership has expired, please renew it")
+	ErrPatronNotSuspended  = errors.New("patron account is not suspended")
+)
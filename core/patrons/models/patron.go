@@ -0,0 +1,104 @@
+package models
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+type PatronStatus string
+
+const (
+	PatronStatusActive    PatronStatus = "active"
+	PatronStatusSuspended PatronStatus = "suspended"
+	PatronStatusExpired   PatronStatus = "expired"
+)
+
+// DefaultPatronType is used when a patron is registered without a type.
+const DefaultPatronType = "adult"
+
+// Patron is a registered library user. Rentals, holds and fines refer to the
+// patron by ID.
+type Patron struct {
+	ID           string       `json:"id"`
+	CardNumber   string       `json:"card_number"`
+	Name         string       `json:"name"`
+	Email        string       `json:"email"`
+	PatronType   string       `json:"patron_type"`
+	Status       PatronStatus `json:"status"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	RegisteredAt time.Time    `json:"registered_at"`
+}
+
+func NewPatron(cardNumber, name, email, patronType string, membershipPeriod time.Duration) (*Patron, error) {
+	if strings.TrimSpace(patronType) == "" {
+		patronType = DefaultPatronType
+	}
+
+	now := time.Now()
+	patron := &Patron{
+		CardNumber:   strings.TrimSpace(cardNumber),
+		Name:         strings.TrimSpace(name),
+		Email:        strings.TrimSpace(email),
+		PatronType:   strings.TrimSpace(patronType),
+		Status:       PatronStatusActive,
+		ExpiresAt:    now.Add(membershipPeriod),
+		RegisteredAt: now,
+	}
+
+	if err := patron.Validate(); err != nil {
+		return nil, err
+	}
+	return patron, nil
+}
+
+func (p *Patron) Validate() error {
+	if p.CardNumber == "" {
+		return errors.New("card number cannot be empty")
+	}
+	if p.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if p.Email != "" {
+		if _, err := mail.ParseAddress(p.Email); err != nil {
+			return errors.New("invalid email address")
+		}
+	}
+	if p.PatronType == "" {
+		return errors.New("patron type cannot be empty")
+	}
+	return nil
+}
+
+// CurrentStatus reports the patron as expired once the membership has run
+// out, even if the stored status has not caught up yet.
+func (p *Patron) CurrentStatus() PatronStatus {
+	if p.Status == PatronStatusActive && time.Now().After(p.ExpiresAt) {
+		return PatronStatusExpired
+	}
+	return p.Status
+}
+
+func (p *Patron) Suspend() {
+	p.Status = PatronStatusSuspended
+}
+
+// Reinstate lifts a suspension.
+func (p *Patron) Reinstate() {
+	p.Status = PatronStatusActive
+}
+
+// Renew extends the membership by the given period, counting from today when
+// it has already run out. A suspended patron stays suspended.
+func (p *Patron) Renew(membershipPeriod time.Duration) {
+	start := p.ExpiresAt
+	if now := time.Now(); start.Before(now) {
+		start = now
+	}
+	p.ExpiresAt = start.Add(membershipPeriod)
+
+	if p.Status == PatronStatusExpired {
+		p.Status = PatronStatusActive
+	}
+}
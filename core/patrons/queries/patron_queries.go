@@ -0,0 +1,42 @@
+package queries
+
+import (
+	"context"
+	stderrors "errors"
+
+	"books/core/patrons/repositories"
+)
+
+type GetPatronQuery struct {
+	ID string
+}
+
+type GetPatronByCardNumberQuery struct {
+	CardNumber string
+}
+
+type ListPatronsQuery struct{}
+
+type PatronQueryHandler struct {
+	repo repositories.PatronRepository
+}
+
+func NewPatronQueryHandler(repo repositories.PatronRepository) *PatronQueryHandler {
+	return &PatronQueryHandler{
+		repo: repo,
+	}
+}
+
+// Handle answers every patron query, so it is registered once per query type.
+func (h *PatronQueryHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	switch query := q.(type) {
+	case GetPatronQuery:
+		return h.repo.GetPatronByID(ctx, query.ID)
+	case GetPatronByCardNumberQuery:
+		return h.repo.GetPatronByCardNumber(ctx, query.CardNumber)
+	case ListPatronsQuery:
+		return h.repo.GetAllPatrons(ctx)
+	default:
+		return nil, stderrors.New("invalid query type")
+	}
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"books/core/patrons/errors"
+	"books/core/patrons/models"
+)
+
+type PatronInMemoryRepository struct {
+	patrons map[string]*models.Patron
+	mutex   sync.RWMutex
+}
+
+func NewPatronInMemoryRepository() *PatronInMemoryRepository {
+	return &PatronInMemoryRepository{
+		patrons: make(map[string]*models.Patron),
+	}
+}
+
+func (r *PatronInMemoryRepository) GetPatronByID(ctx context.Context, id string) (*models.Patron, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	patron, exists := r.patrons[id]
+	if !exists {
+		return nil, errors.ErrPatronNotFound
+	}
+
+	c := *patron
+	return &c, nil
+}
+
+func (r *PatronInMemoryRepository) GetPatronByCardNumber(ctx context.Context, cardNumber string) (*models.Patron, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, patron := range r.patrons {
+		if patron.CardNumber == cardNumber {
+			c := *patron
+			return &c, nil
+		}
+	}
+
+	return nil, errors.ErrPatronNotFound
+}
+
+func (r *PatronInMemoryRepository) GetAllPatrons(ctx context.Context) ([]*models.Patron, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.Patron, 0, len(r.patrons))
+	for _, patron := range r.patrons {
+		c := *patron
+		result = append(result, &c)
+	}
+
+	// Alphabetical by name, matching the Postgres ordering.
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].CardNumber < result[j].CardNumber
+	})
+	return result, nil
+}
+
+func (r *PatronInMemoryRepository) SavePatron(ctx context.Context, patron *models.Patron) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.cardNumberTaken(patron.CardNumber, "") {
+		return errors.ErrDuplicateCardNumber
+	}
+
+	if patron.ID == "" {
+		patron.ID = newID()
+	}
+
+	c := *patron
+	r.patrons[patron.ID] = &c
+	return nil
+}
+
+func (r *PatronInMemoryRepository) UpdatePatron(ctx context.Context, patron *models.Patron) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.patrons[patron.ID]; !exists {
+		return errors.ErrPatronNotFound
+	}
+	if r.cardNumberTaken(patron.CardNumber, patron.ID) {
+		return errors.ErrDuplicateCardNumber
+	}
+
+	c := *patron
+	r.patrons[patron.ID] = &c
+	return nil
+}
+
+func (r *PatronInMemoryRepository) DeletePatron(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.patrons[id]; !exists {
+		return errors.ErrPatronNotFound
+	}
+
+	delete(r.patrons, id)
+	return nil
+}
+
+func (r *PatronInMemoryRepository) cardNumberTaken(cardNumber, exceptID string) bool {
+	for id, existing := range r.patrons {
+		if id != exceptID && existing.CardNumber == cardNumber {
+			return true
+		}
+	}
+	return false
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+var _ PatronRepository = (*PatronInMemoryRepository)(nil)
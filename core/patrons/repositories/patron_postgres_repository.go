@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+
+	"books/core/patrons/errors"
+	"books/core/patrons/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	pqUniqueViolation = "23505"
+	pqInvalidText     = "22P02"
+)
+
+type PatronPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewPatronPostgresRepository(db *sql.DB) *PatronPostgresRepository {
+	return &PatronPostgresRepository{
+		db: db,
+	}
+}
+
+func (r *PatronPostgresRepository) GetPatronByID(ctx context.Context, id string) (*models.Patron, error) {
+	query := `
+		SELECT id, card_number, name, email, patron_type, status, expires_at, registered_at
+		FROM patrons
+		WHERE id = $1
+	`
+
+	return r.getPatron(ctx, query, id)
+}
+
+func (r *PatronPostgresRepository) GetPatronByCardNumber(ctx context.Context, cardNumber string) (*models.Patron, error) {
+	query := `
+		SELECT id, card_number, name, email, patron_type, status, expires_at, registered_at
+		FROM patrons
+		WHERE card_number = $1
+	`
+
+	return r.getPatron(ctx, query, cardNumber)
+}
+
+func (r *PatronPostgresRepository) GetAllPatrons(ctx context.Context) ([]*models.Patron, error) {
+	query := `
+		SELECT id, card_number, name, email, patron_type, status, expires_at, registered_at
+		FROM patrons
+		ORDER BY name, card_number
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query patrons: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	patrons := make([]*models.Patron, 0)
+	for rows.Next() {
+		patron, err := scanPatron(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan patron: %w", err)
+		}
+		patrons = append(patrons, patron)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate patrons: %w", err)
+	}
+
+	return patrons, nil
+}
+
+func (r *PatronPostgresRepository) SavePatron(ctx context.Context, patron *models.Patron) error {
+	query := `
+		INSERT INTO patrons (id, card_number, name, email, patron_type, status, expires_at, registered_at)
+		VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		patron.ID,
+		patron.CardNumber,
+		patron.Name,
+		patron.Email,
+		patron.PatronType,
+		patron.Status,
+		patron.ExpiresAt,
+		patron.RegisteredAt,
+	).Scan(&patron.ID)
+	if err != nil {
+		return mapPatronWriteError(err)
+	}
+
+	return nil
+}
+
+func (r *PatronPostgresRepository) UpdatePatron(ctx context.Context, patron *models.Patron) error {
+	query := `
+		UPDATE patrons
+		SET card_number = $2, name = $3, email = $4, patron_type = $5, status = $6, expires_at = $7
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		patron.ID,
+		patron.CardNumber,
+		patron.Name,
+		patron.Email,
+		patron.PatronType,
+		patron.Status,
+		patron.ExpiresAt,
+	)
+	if isInvalidUUID(err) {
+		return errors.ErrPatronNotFound
+	}
+	if err != nil {
+		return mapPatronWriteError(err)
+	}
+
+	return requireAffected(result)
+}
+
+func (r *PatronPostgresRepository) DeletePatron(ctx context.Context, id string) error {
+	query := `DELETE FROM patrons WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if isInvalidUUID(err) {
+		return errors.ErrPatronNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete patron: %w", err)
+	}
+
+	return requireAffected(result)
+}
+
+func (r *PatronPostgresRepository) getPatron(ctx context.Context, query string, arg string) (*models.Patron, error) {
+	patron, err := scanPatron(r.db.QueryRowContext(ctx, query, arg))
+	if err == sql.ErrNoRows || isInvalidUUID(err) {
+		return nil, errors.ErrPatronNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find patron: %w", err)
+	}
+
+	return patron, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPatron(row rowScanner) (*models.Patron, error) {
+	patron := &models.Patron{}
+
+	err := row.Scan(
+		&patron.ID,
+		&patron.CardNumber,
+		&patron.Name,
+		&patron.Email,
+		&patron.PatronType,
+		&patron.Status,
+		&patron.ExpiresAt,
+		&patron.RegisteredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return patron, nil
+}
+
+func requireAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.ErrPatronNotFound
+	}
+
+	return nil
+}
+
+func isInvalidUUID(err error) bool {
+	var pqErr *pq.Error
+	return stderrors.As(err, &pqErr) && pqErr.Code == pqInvalidText
+}
+
+func mapPatronWriteError(err error) error {
+	var pqErr *pq.Error
+	if stderrors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+		return errors.ErrDuplicateCardNumber
+	}
+	return fmt.Errorf("failed to save patron: %w", err)
+}
+
+var _ PatronRepository = (*PatronPostgresRepository)(nil)
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"books/core/patrons/errors"
+	"books/core/patrons/models"
+	"books/internal/testdb"
+)
+
+var db *sql.DB
+var repo *PatronPostgresRepository
+
+func TestMain(m *testing.M) {
+	testdb.Run(m, func(conn *sql.DB) {
+		db = conn
+		repo = NewPatronPostgresRepository(db)
+	})
+}
+
+func cleanupDB(t *testing.T) {
+	_, err := db.Exec("DELETE FROM patrons")
+	if err != nil {
+		t.Fatalf("Failed to cleanup patrons: %v", err)
+	}
+}
+
+func TestSavePatron(t *testing.T) {
+	cleanupDB(t)
+
+	patron, _ := models.NewPatron("C-100", "Ada Lovelace", "ada@example.com", "", 24*time.Hour)
+	if err := repo.SavePatron(context.Background(), patron); err != nil {
+		t.Fatalf("Failed to save patron: %v", err)
+	}
+	if patron.ID == "" {
+		t.Fatalf("expected patron ID to be assigned")
+	}
+
+	found, err := repo.GetPatronByCardNumber(context.Background(), "C-100")
+	if err != nil {
+		t.Fatalf("Failed to find patron: %v", err)
+	}
+	if found.ID != patron.ID || found.PatronType != "adult" || found.Status != models.PatronStatusActive {
+		t.Errorf("unexpected patron %+v", found)
+	}
+
+	duplicate, _ := models.NewPatron("C-100", "Someone Else", "", "", 24*time.Hour)
+	if err := repo.SavePatron(context.Background(), duplicate); err != errors.ErrDuplicateCardNumber {
+		t.Errorf("expected ErrDuplicateCardNumber, got %v", err)
+	}
+}
+
+func TestUpdatePatron(t *testing.T) {
+	cleanupDB(t)
+
+	patron, _ := models.NewPatron("C-100", "Ada Lovelace", "", "", 24*time.Hour)
+	_ = repo.SavePatron(context.Background(), patron)
+
+	patron.Suspend()
+	patron.PatronType = "staff"
+	if err := repo.UpdatePatron(context.Background(), patron); err != nil {
+		t.Fatalf("Failed to update patron: %v", err)
+	}
+
+	found, err := repo.GetPatronByID(context.Background(), patron.ID)
+	if err != nil {
+		t.Fatalf("Failed to find patron: %v", err)
+	}
+	if found.Status != models.PatronStatusSuspended || found.PatronType != "staff" {
+		t.Errorf("unexpected patron after update %+v", found)
+	}
+
+	missing := *patron
+	missing.ID = "00000000-0000-0000-0000-000000000000"
+	if err := repo.UpdatePatron(context.Background(), &missing); err != errors.ErrPatronNotFound {
+		t.Errorf("expected ErrPatronNotFound, got %v", err)
+	}
+}
+
+func TestGetAllAndDeletePatrons(t *testing.T) {
+	cleanupDB(t)
+
+	for _, name := range []string{"Grace Hopper", "Ada Lovelace"} {
+		patron, _ := models.NewPatron("card-"+name, name, "", "", 24*time.Hour)
+		_ = repo.SavePatron(context.Background(), patron)
+	}
+
+	patrons, err := repo.GetAllPatrons(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list patrons: %v", err)
+	}
+	if len(patrons) != 2 || patrons[0].Name != "Ada Lovelace" {
+		t.Fatalf("expected patrons sorted by name, got %+v", patrons)
+	}
+
+	if err := repo.DeletePatron(context.Background(), patrons[0].ID); err != nil {
+		t.Fatalf("Failed to delete patron: %v", err)
+	}
+	if _, err := repo.GetPatronByID(context.Background(), patrons[0].ID); err != errors.ErrPatronNotFound {
+		t.Errorf("expected ErrPatronNotFound, got %v", err)
+	}
+	if err := repo.DeletePatron(context.Background(), "not-a-uuid"); err != errors.ErrPatronNotFound {
+		t.Errorf("expected ErrPatronNotFound for malformed ID, got %v", err)
+	}
+}
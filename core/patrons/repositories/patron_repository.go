@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"books/core/patrons/models"
+)
+
+type PatronRepository interface {
+	GetPatronByID(ctx context.Context, id string) (*models.Patron, error)
+	GetPatronByCardNumber(ctx context.Context, cardNumber string) (*models.Patron, error)
+	GetAllPatrons(ctx context.Context) ([]*models.Patron, error)
+	SavePatron(ctx context.Context, patron *models.Patron) error
+	UpdatePatron(ctx context.Context, patron *models.Patron) error
+	DeletePatron(ctx context.Context, id string) error
+}
@@ -5,24 +5,42 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"time"
 
+	author_repositories "books/core/authors/repositories"
+	"books/core/isbn"
 	"books/core/storage/models"
 	"books/core/storage/repositories/interfaces"
 )
 
+// AddBookCommand catalogues a book. Its author may be left out when the
+// contributors are given, and must otherwise be their primary author.
+// Contributors linked to an author may leave out their name to be credited
+// under the author's.
 type AddBookCommand struct {
-	ISBN  string
-	Title string
-	Author string
+	ISBN     string
+	Title    string
+	Author   string
+	Category string
+	// Barcode of the first copy; DefaultBarcode is used when empty.
+	Barcode string
+	Details models.BookDetails
 }
 
 type AddBookCommandHandler struct {
-	repo interfaces.BookRepository
+	repo    interfaces.BookRepository
+	copies  interfaces.CopyRepository
+	authors author_repositories.AuthorRepository
+	// ranges refuses ISBNs in ranges that have not been assigned; without
+	// them any valid ISBN is accepted.
+	ranges *isbn.Ranges
 }
-func NewAddBookCommandHandler(repo interfaces.BookRepository) *AddBookCommandHandler {
+
+func NewAddBookCommandHandler(repo interfaces.BookRepository, copies interfaces.CopyRepository, authors author_repositories.AuthorRepository, ranges *isbn.Ranges) *AddBookCommandHandler {
 	return &AddBookCommandHandler{
-		repo: repo,
+		repo:    repo,
+		copies:  copies,
+		authors: authors,
+		ranges:  ranges,
 	}
 }
 
@@ -40,7 +58,7 @@ func (h *AddBookCommandHandler) Handle(ctx context.Context, cmd interface{}) err
 		return errors.New("title cannot be empty")
 	}
 
-	if strings.TrimSpace(command.Author) == "" {
+	if strings.TrimSpace(command.Author) == "" && len(command.Details.Contributors) == 0 {
 		return errors.New("author cannot be empty")
 	}
 
@@ -48,18 +66,104 @@ func (h *AddBookCommandHandler) Handle(ctx context.Context, cmd interface{}) err
 		return errors.New("ISBN cannot be empty")
 	}
 
+	if h.ranges != nil {
+		if _, err := h.ranges.Split(command.ISBN); err != nil {
+			return err
+		}
+	}
+
 	existingBook, err := h.repo.FindByISBN(ctx, command.ISBN)
 	if err == nil && existingBook != nil {
-		return fmt.Errorf("failed to save book: book with ISBN %s already exists", command.ISBN)
+		return fmt.Errorf("failed to save book: book with ISBN %s already exists", existingBook.ISBN)
+	}
+	if err != nil && !errors.Is(err, interfaces.ErrBookNotFound) {
+		return err
+	}
+
+	// A deleted book keeps its ISBN, and its rental history, until it is
+	// purged.
+	deletedBook, err := h.repo.FindDeletedByISBN(ctx, command.ISBN)
+	if err == nil && deletedBook != nil {
+		return interfaces.ErrBookDeleted
 	}
 	if err != nil && !errors.Is(err, interfaces.ErrBookNotFound) {
 		return err
 	}
 
-	book, err := models.NewBook(command.ISBN, command.Title, command.Author, time.Now())
+	details := command.Details
+	details.Contributors, err = linkAuthors(ctx, h.authors, details.Contributors)
+	if err != nil {
+		return err
+	}
+
+	author, err := primaryAuthor(command.Author, details.Contributors)
+	if err != nil {
+		return err
+	}
+
+	book, err := models.NewBook(command.ISBN, command.Title, author)
+	if err != nil {
+		return err
+	}
+	if err := book.SetDetails(details); err != nil {
+		return err
+	}
+	if command.Category != "" {
+		book.Category = command.Category
+	}
+
+	barcode := command.Barcode
+	if barcode == "" {
+		barcode = models.DefaultBarcode(book.ISBN, 1)
+	}
+	firstCopy, err := models.NewCopy(book.ISBN, barcode, "", models.CopyConditionGood)
 	if err != nil {
 		return err
 	}
 
-	return h.repo.Save(ctx, book)
-}
\ No newline at end of file
+	if err := h.repo.Save(ctx, book); err != nil {
+		return err
+	}
+
+	// A newly catalogued book comes with the copy that was acquired.
+	return h.copies.SaveCopy(ctx, firstCopy)
+}
+
+// primaryAuthor picks the author of a book from the author and contributors
+// given for it, refusing an author that is not the primary one.
+func primaryAuthor(author string, contributors []models.Contributor) (string, error) {
+	author = strings.TrimSpace(author)
+	if len(contributors) == 0 {
+		return author, nil
+	}
+
+	primary := models.PrimaryAuthor(contributors)
+	if author != "" && !strings.EqualFold(author, primary) {
+		return "", fmt.Errorf("invalid author %q: it must be the primary author of the contributors", author)
+	}
+	return primary, nil
+}
+
+// linkAuthors checks that the authors contributors are linked to exist, and
+// fills in the names left out with the authors' names.
+func linkAuthors(ctx context.Context, authors author_repositories.AuthorRepository, contributors []models.Contributor) ([]models.Contributor, error) {
+	if contributors == nil {
+		return nil, nil
+	}
+
+	linked := make([]models.Contributor, 0, len(contributors))
+	for _, contributor := range contributors {
+		contributor.AuthorID = strings.TrimSpace(contributor.AuthorID)
+		if contributor.AuthorID != "" {
+			author, err := authors.GetAuthorByID(ctx, contributor.AuthorID)
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(contributor.Name) == "" {
+				contributor.Name = author.Name
+			}
+		}
+		linked = append(linked, contributor)
+	}
+	return linked, nil
+}
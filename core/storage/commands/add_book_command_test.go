@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	author_repositories "books/core/authors/repositories"
+	"books/core/isbn"
 	"books/core/storage/models"
 	"books/core/storage/repositories"
 )
@@ -22,6 +24,7 @@ type testCase struct {
 func getTestCases() []testCase {
 	// Valid ISBN-13: 978-3-16-148410-0 (checksum valid)
 	validISBN := "9783161484100"
+	publishedAt := time.Date(1999, time.March, 4, 0, 0, 0, 0, time.UTC)
 
 	return []testCase{
 		{
@@ -49,7 +52,7 @@ func getTestCases() []testCase {
 		{
 			name: "duplicate ISBN",
 			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {
-				book, _ := models.NewBook(validISBN, "Existing Book", "Existing Author", time.Now())
+				book, _ := models.NewBook(validISBN, "Existing Book", "Existing Author")
 				_ = repo.Save(context.Background(), book)
 			},
 			command: &AddBookCommand{
@@ -60,6 +63,50 @@ func getTestCases() []testCase {
 			wantErr:     true,
 			expectedErr: errors.New("failed to save book: book with ISBN " + validISBN + " already exists"),
 		},
+		{
+			name: "duplicate ISBN in another form",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {
+				book, _ := models.NewBook(validISBN, "Existing Book", "Existing Author")
+				_ = repo.Save(context.Background(), book)
+			},
+			command: &AddBookCommand{
+				ISBN:   "3-16-148410-X",
+				Title:  "Test Book",
+				Author: "Test Author",
+			},
+			wantErr:     true,
+			expectedErr: errors.New("failed to save book: book with ISBN " + validISBN + " already exists"),
+		},
+		{
+			name:      "hyphenated ISBN-10 stored as ISBN-13",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:   "3-16-148410-x",
+				Title:  "Test Book",
+				Author: "Test Author",
+			},
+			wantErr: false,
+			validateResult: func(t *testing.T, repo *repositories.BookStorageInMemoryRepository) {
+				book, err := repo.FindByISBN(context.Background(), "978-3-16-148410-0")
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if book.ISBN != validISBN {
+					t.Errorf("expected ISBN '%s', got '%s'", validISBN, book.ISBN)
+				}
+			},
+		},
+		{
+			name:      "ISBN in an unassigned range",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:   "978-2-00-000000-6",
+				Title:  "Test Book",
+				Author: "Test Author",
+			},
+			wantErr:     true,
+			expectedErr: isbn.ErrUnassignedRange,
+		},
 		{
 			name:      "empty ISBN",
 			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
@@ -111,12 +158,180 @@ func getTestCases() []testCase {
 			wantErr:     true,
 			expectedErr: errors.New("ISBN must be 10 or 13 characters (excluding hyphens)"),
 		},
+		{
+			name:      "add with details",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:   validISBN,
+				Title:  "Test Book",
+				Author: "Test Author",
+				Details: models.BookDetails{
+					Subtitle:    " A Subtitle ",
+					Publisher:   "Test Publisher",
+					PublishedAt: &publishedAt,
+					Edition:     "2nd",
+					Language:    "EN",
+					PageCount:   320,
+					Subjects:    []string{"Fiction", "fiction ", "History"},
+				},
+			},
+			validateResult: func(t *testing.T, repo *repositories.BookStorageInMemoryRepository) {
+				book, _ := repo.FindByISBN(context.Background(), validISBN)
+				if book.Subtitle != "A Subtitle" || book.Language != "en" || book.PublicationYear != 1999 || book.PageCount != 320 {
+					t.Errorf("unexpected details %+v", book.BookDetails)
+				}
+				if len(book.Subjects) != 2 {
+					t.Errorf("expected duplicate subjects to be dropped, got %v", book.Subjects)
+				}
+			},
+		},
+		{
+			name:      "unknown language",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:    validISBN,
+				Title:   "Test Book",
+				Author:  "Test Author",
+				Details: models.BookDetails{Language: "english"},
+			},
+			wantErr:     true,
+			expectedErr: errors.New(`invalid language "english": use an ISO 639-1 code such as "en"`),
+		},
+		{
+			name:      "publication year after the coming year",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:    validISBN,
+				Title:   "Test Book",
+				Author:  "Test Author",
+				Details: models.BookDetails{PublicationYear: 3000},
+			},
+			wantErr:     true,
+			expectedErr: errors.New("invalid publication year 3000"),
+		},
+		{
+			name:      "publication year not matching the date",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:    validISBN,
+				Title:   "Test Book",
+				Author:  "Test Author",
+				Details: models.BookDetails{PublishedAt: &publishedAt, PublicationYear: 2001},
+			},
+			wantErr:     true,
+			expectedErr: errors.New("invalid publication year: it does not match the publication date"),
+		},
+		{
+			name:      "negative page count",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:    validISBN,
+				Title:   "Test Book",
+				Author:  "Test Author",
+				Details: models.BookDetails{PageCount: -1},
+			},
+			wantErr:     true,
+			expectedErr: errors.New("invalid page count -1"),
+		},
+		{
+			name:      "empty subject",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:    validISBN,
+				Title:   "Test Book",
+				Author:  "Test Author",
+				Details: models.BookDetails{Subjects: []string{"Fiction", " "}},
+			},
+			wantErr:     true,
+			expectedErr: errors.New("invalid subject: subjects cannot be empty"),
+		},
+		{
+			name:      "add with contributors",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:  validISBN,
+				Title: "Test Book",
+				Details: models.BookDetails{Contributors: []models.Contributor{
+					{Name: "Test Editor", Role: models.RoleEditor},
+					{Name: " First Author "},
+					{Name: "Second Author", Role: "Author"},
+					{Name: "Test Translator", Role: models.RoleTranslator},
+				}},
+			},
+			validateResult: func(t *testing.T, repo *repositories.BookStorageInMemoryRepository) {
+				book, _ := repo.FindByISBN(context.Background(), validISBN)
+				if book.Author != "First Author" {
+					t.Errorf("expected the primary author to be the author, got %q", book.Author)
+				}
+				if len(book.Contributors) != 4 || book.Contributors[1] != (models.Contributor{Name: "First Author", Role: models.RoleAuthor}) ||
+					book.Contributors[2].Role != models.RoleAuthor {
+					t.Errorf("expected the contributors in order, got %+v", book.Contributors)
+				}
+			},
+		},
+		{
+			name:      "author alone is the only contributor",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:   validISBN,
+				Title:  "Test Book",
+				Author: "Test Author",
+			},
+			validateResult: func(t *testing.T, repo *repositories.BookStorageInMemoryRepository) {
+				book, _ := repo.FindByISBN(context.Background(), validISBN)
+				if len(book.Contributors) != 1 || book.Contributors[0] != (models.Contributor{Name: "Test Author", Role: models.RoleAuthor}) {
+					t.Errorf("expected the author to be credited, got %+v", book.Contributors)
+				}
+			},
+		},
+		{
+			name:      "author not the primary author",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:    validISBN,
+				Title:   "Test Book",
+				Author:  "Someone Else",
+				Details: models.BookDetails{Contributors: []models.Contributor{{Name: "Test Author", Role: models.RoleAuthor}}},
+			},
+			wantErr:     true,
+			expectedErr: errors.New(`invalid author "Someone Else": it must be the primary author of the contributors`),
+		},
+		{
+			name:      "unknown contributor role",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:    validISBN,
+				Title:   "Test Book",
+				Details: models.BookDetails{Contributors: []models.Contributor{{Name: "Test Author", Role: "narrator"}}},
+			},
+			wantErr:     true,
+			expectedErr: errors.New(`invalid contributor role "narrator": use author, editor, translator or illustrator`),
+		},
+		{
+			name:      "contributor listed twice",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
+			command: &AddBookCommand{
+				ISBN:  validISBN,
+				Title: "Test Book",
+				Details: models.BookDetails{Contributors: []models.Contributor{
+					{Name: "Test Author", Role: models.RoleAuthor},
+					{Name: "test author", Role: models.RoleAuthor},
+				}},
+			},
+			wantErr:     true,
+			expectedErr: errors.New("invalid contributors: test author is listed twice as author"),
+		},
 	}
 }
 
 func TestAddBookCommandHandler_Handle(t *testing.T) {
 	tests := getTestCases()
 
+	ranges, err := isbn.LoadRanges("../../isbn/testdata/RangeMessage.xml")
+	if err != nil {
+		t.Fatalf("failed to load ISBN ranges: %v", err)
+	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := repositories.NewBookStorageInMemoryRepository()
@@ -124,7 +339,7 @@ func TestAddBookCommandHandler_Handle(t *testing.T) {
 				tt.setupRepo(mockRepo)
 			}
 
-			handler := NewAddBookCommandHandler(mockRepo)
+			handler := NewAddBookCommandHandler(mockRepo, repositories.NewCopyStorageInMemoryRepository(), author_repositories.NewAuthorInMemoryRepository(), ranges)
 			err := handler.Handle(context.Background(), tt.command)
 
 			if tt.wantErr {
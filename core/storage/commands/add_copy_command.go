@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	branch_repositories "books/core/branches/repositories"
+	"books/core/storage/models"
+	"books/core/storage/repositories/interfaces"
+)
+
+type AddCopyCommand struct {
+	ISBN string
+	// Barcode of the new copy; the next free DefaultBarcode is used, and
+	// written back here, when empty.
+	Barcode       string
+	ShelfLocation string
+	Condition     string
+	// HomeBranchID is the branch the copy belongs to. The copy starts out
+	// there.
+	HomeBranchID string
+}
+
+type AddCopyCommandHandler struct {
+	repo     interfaces.BookRepository
+	copies   interfaces.CopyRepository
+	branches branch_repositories.BranchRepository
+}
+
+func NewAddCopyCommandHandler(repo interfaces.BookRepository, copies interfaces.CopyRepository, branches branch_repositories.BranchRepository) *AddCopyCommandHandler {
+	return &AddCopyCommandHandler{
+		repo:     repo,
+		copies:   copies,
+		branches: branches,
+	}
+}
+
+func (h *AddCopyCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	if cmd == nil {
+		return ErrInvalidCommandType
+	}
+
+	command, ok := cmd.(*AddCopyCommand)
+	if !ok {
+		return ErrInvalidCommandType
+	}
+
+	if strings.TrimSpace(command.ISBN) == "" {
+		return errors.New("ISBN cannot be empty")
+	}
+
+	book, err := h.repo.FindByISBN(ctx, command.ISBN)
+	if err != nil {
+		return err
+	}
+	// The copy is filed under the book's ISBN, whichever form was given.
+	command.ISBN = book.ISBN
+
+	if command.HomeBranchID != "" {
+		if _, err := h.branches.GetBranchByID(ctx, command.HomeBranchID); err != nil {
+			return err
+		}
+	}
+
+	barcode := command.Barcode
+	if strings.TrimSpace(barcode) == "" {
+		existing, err := h.copies.FindCopiesByISBN(ctx, command.ISBN)
+		if err != nil {
+			return err
+		}
+		barcode = nextDefaultBarcode(command.ISBN, existing)
+		command.Barcode = barcode
+	}
+
+	bookCopy, err := models.NewCopy(command.ISBN, barcode, command.ShelfLocation, models.CopyCondition(command.Condition))
+	if err != nil {
+		return err
+	}
+	bookCopy.HomeBranchID = command.HomeBranchID
+	bookCopy.CurrentBranchID = command.HomeBranchID
+
+	return h.copies.SaveCopy(ctx, bookCopy)
+}
+
+func nextDefaultBarcode(isbn string, existing []*models.Copy) string {
+	used := make(map[string]bool, len(existing))
+	for _, c := range existing {
+		used[c.Barcode] = true
+	}
+
+	for n := len(existing) + 1; ; n++ {
+		if barcode := models.DefaultBarcode(isbn, n); !used[barcode] {
+			return barcode
+		}
+	}
+}
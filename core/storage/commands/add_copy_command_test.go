@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	author_repositories "books/core/authors/repositories"
+	branch_errors "books/core/branches/errors"
+	branch_repositories "books/core/branches/repositories"
+	"books/core/storage/models"
+	"books/core/storage/repositories"
+	"books/core/storage/repositories/interfaces"
+)
+
+type addCopyTestCase struct {
+	name            string
+	command         interface{}
+	expectedErr     error
+	expectedBarcode string
+}
+
+func getAddCopyTestCases() []addCopyTestCase {
+	validISBN := "9783161484100"
+
+	return []addCopyTestCase{
+		{
+			name:            "copy with barcode",
+			command:         &AddCopyCommand{ISBN: validISBN, Barcode: "BC-7", ShelfLocation: "A1", Condition: "new"},
+			expectedBarcode: "BC-7",
+		},
+		{
+			name:            "default barcode follows existing copies",
+			command:         &AddCopyCommand{ISBN: validISBN},
+			expectedBarcode: validISBN + "-2",
+		},
+		{
+			name:        "duplicate barcode",
+			command:     &AddCopyCommand{ISBN: validISBN, Barcode: validISBN + "-1"},
+			expectedErr: interfaces.ErrDuplicateBarcode,
+		},
+		{
+			name:        "unknown book",
+			command:     &AddCopyCommand{ISBN: "9780306406157"},
+			expectedErr: interfaces.ErrBookNotFound,
+		},
+		{
+			name:        "invalid condition",
+			command:     &AddCopyCommand{ISBN: validISBN, Condition: "shiny"},
+			expectedErr: errors.New(`invalid copy condition "shiny"`),
+		},
+		{
+			name:        "unknown home branch",
+			command:     &AddCopyCommand{ISBN: validISBN, HomeBranchID: "missing"},
+			expectedErr: branch_errors.ErrBranchNotFound,
+		},
+		{
+			name:        "invalid command type",
+			command:     &DeleteCopyCommand{ID: "copy"},
+			expectedErr: ErrInvalidCommandType,
+		},
+	}
+}
+
+func TestAddCopyCommandHandler_Handle(t *testing.T) {
+	for _, tt := range getAddCopyTestCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			books := repositories.NewBookStorageInMemoryRepository()
+			copies := repositories.NewCopyStorageInMemoryRepository()
+
+			addBook := NewAddBookCommandHandler(books, copies, author_repositories.NewAuthorInMemoryRepository(), nil)
+			err := addBook.Handle(context.Background(), &AddBookCommand{ISBN: "9783161484100", Title: "Test Book", Author: "Test Author"})
+			if err != nil {
+				t.Fatalf("failed to add book: %v", err)
+			}
+
+			handler := NewAddCopyCommandHandler(books, copies, branch_repositories.NewBranchInMemoryRepository())
+			err = handler.Handle(context.Background(), tt.command)
+
+			if tt.expectedErr != nil {
+				if err == nil || err.Error() != tt.expectedErr.Error() {
+					t.Errorf("expected error %v but got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			command := tt.command.(*AddCopyCommand)
+			if command.Barcode != tt.expectedBarcode {
+				t.Errorf("expected barcode %s, got %s", tt.expectedBarcode, command.Barcode)
+			}
+
+			found, _ := copies.FindCopiesByISBN(context.Background(), command.ISBN)
+			if len(found) != 2 {
+				t.Errorf("expected 2 copies, got %d", len(found))
+			}
+		})
+	}
+}
+
+func TestUpdateAndDeleteCopyCommandHandlers(t *testing.T) {
+	copies := repositories.NewCopyStorageInMemoryRepository()
+	bookCopy := &models.Copy{ISBN: "9783161484100", Barcode: "BC-1", Condition: models.CopyConditionGood, AddedAt: time.Now()}
+	if err := copies.SaveCopy(context.Background(), bookCopy); err != nil {
+		t.Fatalf("failed to save copy: %v", err)
+	}
+
+	update := NewUpdateCopyCommandHandler(copies, branch_repositories.NewBranchInMemoryRepository())
+	err := update.Handle(context.Background(), &UpdateCopyCommand{ID: bookCopy.ID, Condition: "damaged", ShelfLocation: "Repair"})
+	if err != nil {
+		t.Fatalf("failed to update copy: %v", err)
+	}
+
+	updated, _ := copies.FindCopyByID(context.Background(), bookCopy.ID)
+	if updated.Condition != models.CopyConditionDamaged || updated.ShelfLocation != "Repair" || updated.Barcode != "BC-1" {
+		t.Errorf("unexpected copy after update: %+v", updated)
+	}
+
+	if err := update.Handle(context.Background(), &UpdateCopyCommand{ID: bookCopy.ID}); err == nil {
+		t.Errorf("expected empty update to be rejected")
+	}
+	if err := update.Handle(context.Background(), &UpdateCopyCommand{ID: "missing", Barcode: "X"}); err != interfaces.ErrCopyNotFound {
+		t.Errorf("expected ErrCopyNotFound, got %v", err)
+	}
+
+	remove := NewDeleteCopyCommandHandler(copies)
+	if err := remove.Handle(context.Background(), &DeleteCopyCommand{ID: bookCopy.ID}); err != nil {
+		t.Fatalf("failed to delete copy: %v", err)
+	}
+	if err := remove.Handle(context.Background(), &DeleteCopyCommand{ID: bookCopy.ID}); err != interfaces.ErrCopyNotFound {
+		t.Errorf("expected ErrCopyNotFound, got %v", err)
+	}
+}
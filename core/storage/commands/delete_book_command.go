@@ -4,21 +4,32 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
+	library_repositories "books/core/library/repositories"
 	"books/core/storage/repositories/interfaces"
 )
 
+// DeleteBookCommand takes a book out of the catalog. The book is only marked
+// deleted, keeping its copies and rental history, so RestoreBookCommand can
+// bring it back until it is purged. A book on loan cannot be deleted; the
+// waiting and ready holds of a deleted book are cancelled, freeing the copies
+// set aside for them.
 type DeleteBookCommand struct {
-	ISBN string 
+	ISBN string
 }
 
 type DeleteBookCommandHandler struct {
-	repo interfaces.BookRepository
+	repo    interfaces.BookRepository
+	rentals library_repositories.BookRepository
+	holds   library_repositories.HoldRepository
 }
 
-func NewDeleteBookCommandHandler(repo interfaces.BookRepository) *DeleteBookCommandHandler {
+func NewDeleteBookCommandHandler(repo interfaces.BookRepository, rentals library_repositories.BookRepository, holds library_repositories.HoldRepository) *DeleteBookCommandHandler {
 	return &DeleteBookCommandHandler{
-		repo: repo,
+		repo:    repo,
+		rentals: rentals,
+		holds:   holds,
 	}
 }
 
@@ -36,10 +47,30 @@ func (h *DeleteBookCommandHandler) Handle(ctx context.Context, cmd interface{})
 		return errors.New("book ID cannot be empty")
 	}
 
-	_, err := h.repo.FindByISBN(ctx, command.ISBN)
+	book, err := h.repo.FindByISBN(ctx, command.ISBN)
 	if err != nil {
-		return err 
+		return err
 	}
 
-	return h.repo.Delete(ctx, command.ISBN)
-}
\ No newline at end of file
+	rentals, err := h.rentals.GetActiveBookRentalsByBookID(ctx, book.ISBN)
+	if err != nil {
+		return err
+	}
+	if len(rentals) > 0 {
+		return interfaces.ErrBookOnLoan
+	}
+
+	// The holds are cancelled first, so a failed delete can be retried.
+	holds, err := h.holds.GetActiveHoldsByBookID(ctx, book.ISBN)
+	if err != nil {
+		return err
+	}
+	for _, hold := range holds {
+		hold.Cancel()
+		if err := h.holds.UpdateHold(ctx, hold); err != nil {
+			return err
+		}
+	}
+
+	return h.repo.Delete(ctx, book.ISBN, time.Now())
+}
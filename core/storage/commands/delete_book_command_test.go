@@ -2,9 +2,12 @@ package commands
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
+	library_models "books/core/library/models"
+	library_repositories "books/core/library/repositories"
 	"books/core/storage/models"
 	"books/core/storage/repositories"
 	"books/core/storage/repositories/interfaces"
@@ -25,7 +28,7 @@ func getDeleteBookTestCases() []deleteBookTestCase {
 	validISBN := "9783161484100"
 
 	// Create a test book with valid ISBN
-	testBook, _ := models.NewBook(validISBN, "Test Book", "Test Author", time.Now())
+	testBook, _ := models.NewBook(validISBN, "Test Book", "Test Author")
 
 	return []deleteBookTestCase{
 		{
@@ -42,6 +45,10 @@ func getDeleteBookTestCases() []deleteBookTestCase {
 				if err != interfaces.ErrBookNotFound {
 					t.Errorf("expected book to be deleted, got error: %v", err)
 				}
+				deleted, err := repo.FindDeletedByISBN(context.Background(), testBook.ISBN)
+				if err != nil || deleted.DeletedAt == nil {
+					t.Errorf("expected the deleted book to be kept, got %+v (%v)", deleted, err)
+				}
 			},
 		},
 		{
@@ -84,7 +91,8 @@ func TestDeleteBookCommandHandler_Handle(t *testing.T) {
 				tt.setupRepo(mockRepo)
 			}
 
-			handler := NewDeleteBookCommandHandler(mockRepo)
+			rentals := library_repositories.NewBookRentalInMemoryRepository(mockRepo, repositories.NewCopyStorageInMemoryRepository())
+			handler := NewDeleteBookCommandHandler(mockRepo, rentals, library_repositories.NewHoldInMemoryRepository())
 			err := handler.Handle(context.Background(), tt.command)
 
 			if tt.wantErr {
@@ -106,4 +114,178 @@ func TestDeleteBookCommandHandler_Handle(t *testing.T) {
 			}
 		})
 	}
+}
+
+func TestDeleteBookOnLoan(t *testing.T) {
+	ctx := context.Background()
+	books := repositories.NewBookStorageInMemoryRepository()
+	copies := repositories.NewCopyStorageInMemoryRepository()
+	rentals := library_repositories.NewBookRentalInMemoryRepository(books, copies)
+
+	book, _ := models.NewBook("9783161484100", "Test Book", "Test Author")
+	_ = books.Save(ctx, book)
+	bookCopy, _ := models.NewCopy(book.ISBN, "BC-1", "", "")
+	_ = copies.SaveCopy(ctx, bookCopy)
+	rental := library_models.NewBookRental(book.ISBN, "user1")
+	rental.CopyID = bookCopy.ID
+	if err := rentals.SaveBookRental(ctx, rental); err != nil {
+		t.Fatalf("failed to rent the book: %v", err)
+	}
+
+	handler := NewDeleteBookCommandHandler(books, rentals, library_repositories.NewHoldInMemoryRepository())
+	if err := handler.Handle(ctx, &DeleteBookCommand{ISBN: book.ISBN}); !errors.Is(err, interfaces.ErrBookOnLoan) {
+		t.Fatalf("expected ErrBookOnLoan, got %v", err)
+	}
+
+	now := time.Now()
+	rental.ReturnedAt = &now
+	_ = rentals.UpdateBookRental(ctx, rental)
+	if err := handler.Handle(ctx, &DeleteBookCommand{ISBN: book.ISBN}); err != nil {
+		t.Fatalf("expected the returned book to be deleted, got %v", err)
+	}
+	if err := handler.Handle(ctx, &DeleteBookCommand{ISBN: book.ISBN}); err != interfaces.ErrBookNotFound {
+		t.Errorf("expected a deleted book not to be found, got %v", err)
+	}
+	if found, _ := copies.FindCopiesByISBN(ctx, book.ISBN); len(found) != 1 {
+		t.Errorf("expected the copies of a deleted book to be kept, got %d", len(found))
+	}
+}
+
+func TestDeleteBookCancelsHolds(t *testing.T) {
+	ctx := context.Background()
+	books := repositories.NewBookStorageInMemoryRepository()
+	rentals := library_repositories.NewBookRentalInMemoryRepository(books, repositories.NewCopyStorageInMemoryRepository())
+	holds := library_repositories.NewHoldInMemoryRepository()
+
+	book, _ := models.NewBook("9783161484100", "Test Book", "Test Author")
+	_ = books.Save(ctx, book)
+	ready := library_models.NewHold(book.ISBN, "user1")
+	ready.MarkReady(time.Hour)
+	waiting := library_models.NewHold(book.ISBN, "user2")
+	for _, hold := range []*library_models.Hold{ready, waiting} {
+		if err := holds.SaveHold(ctx, hold); err != nil {
+			t.Fatalf("failed to place the hold: %v", err)
+		}
+	}
+
+	handler := NewDeleteBookCommandHandler(books, rentals, holds)
+	if err := handler.Handle(ctx, &DeleteBookCommand{ISBN: book.ISBN}); err != nil {
+		t.Fatalf("failed to delete the book: %v", err)
+	}
+
+	if active, _ := holds.GetActiveHoldsByBookID(ctx, book.ISBN); len(active) != 0 {
+		t.Errorf("expected the holds of a deleted book to be cancelled, got %d open", len(active))
+	}
+	for _, placed := range []*library_models.Hold{ready, waiting} {
+		hold, _ := holds.GetHoldByID(ctx, placed.ID)
+		if hold.Status != library_models.HoldStatusCancelled || hold.ClosedAt == nil {
+			t.Errorf("expected hold %s to be cancelled, got %+v", placed.ID, hold)
+		}
+	}
+}
+
+func TestRestoreBookCommandHandler(t *testing.T) {
+	ctx := context.Background()
+	books := repositories.NewBookStorageInMemoryRepository()
+	book, _ := models.NewBook("9783161484100", "Test Book", "Test Author")
+	_ = books.Save(ctx, book)
+
+	handler := NewRestoreBookCommandHandler(books)
+	if err := handler.Handle(ctx, &RestoreBookCommand{ISBN: book.ISBN}); err != interfaces.ErrBookNotFound {
+		t.Errorf("expected a book that is not deleted not to be restored, got %v", err)
+	}
+
+	_ = books.Delete(ctx, book.ISBN, time.Now())
+	if err := handler.Handle(ctx, &RestoreBookCommand{ISBN: "978-3-16-148410-0"}); err != nil {
+		t.Fatalf("failed to restore the book: %v", err)
+	}
+	restored, err := books.FindByISBN(ctx, book.ISBN)
+	if err != nil || restored.DeletedAt != nil {
+		t.Errorf("expected the book back in the catalog, got %+v (%v)", restored, err)
+	}
+
+	if err := handler.Handle(ctx, &RestoreBookCommand{ISBN: " "}); err == nil {
+		t.Errorf("expected an empty ISBN to be refused")
+	}
+}
+
+func TestPurgeDeletedBooksCommandHandler(t *testing.T) {
+	ctx := context.Background()
+	books := repositories.NewBookStorageInMemoryRepository()
+	copies := repositories.NewCopyStorageInMemoryRepository()
+
+	for isbn, deletedDaysAgo := range map[string]int{"9783161484100": 100, "9780306406157": 10, "9780596517748": 0, "9780131103627": 100, "9780262033848": 100, "9780201633610": 100, "9780132350884": 100} {
+		book, _ := models.NewBook(isbn, "Test Book", "Test Author")
+		_ = books.Save(ctx, book)
+		bookCopy, _ := models.NewCopy(isbn, isbn+"-1", "", "")
+		_ = copies.SaveCopy(ctx, bookCopy)
+		if deletedDaysAgo > 0 {
+			_ = books.Delete(ctx, isbn, time.Now().AddDate(0, 0, -deletedDaysAgo))
+		}
+	}
+
+	// A book on loan and one with a waiting hold are kept, however long ago
+	// they were deleted; a returned rental or a cancelled hold is only
+	// history and does not stop the purge.
+	rentals := library_repositories.NewBookRentalInMemoryRepository(books, copies)
+	for _, isbn := range []string{"9780131103627", "9780201633610"} {
+		rented, _ := copies.FindCopiesByISBN(ctx, isbn)
+		rental := library_models.NewBookRental(isbn, "user1")
+		rental.CopyID = rented[0].ID
+		if isbn == "9780201633610" {
+			returnedAt := time.Now().AddDate(0, 0, -101)
+			rental.ReturnedAt = &returnedAt
+		}
+		if err := rentals.SaveBookRental(ctx, rental); err != nil {
+			t.Fatalf("failed to record the rental: %v", err)
+		}
+	}
+	holds := library_repositories.NewHoldInMemoryRepository()
+	if err := holds.SaveHold(ctx, library_models.NewHold("9780262033848", "user1")); err != nil {
+		t.Fatalf("failed to record the hold: %v", err)
+	}
+	cancelled := library_models.NewHold("9780132350884", "user1")
+	cancelled.Cancel()
+	if err := holds.SaveHold(ctx, cancelled); err != nil {
+		t.Fatalf("failed to record the hold: %v", err)
+	}
+
+	handler := NewPurgeDeletedBooksCommandHandler(books, copies, rentals, holds)
+	command := &PurgeDeletedBooksCommand{}
+	if err := handler.Handle(ctx, command); err != nil {
+		t.Fatalf("failed to purge: %v", err)
+	}
+	if strings.Join(command.Purged, "|") != "9780132350884|9780201633610|9783161484100" {
+		t.Errorf("expected the books deleted past the default retention to be purged, got %v", command.Purged)
+	}
+	if len(command.Kept) != 2 || command.Kept[0] != "9780131103627" || command.Kept[1] != "9780262033848" {
+		t.Errorf("expected the book on loan and the held book to be kept, got %v", command.Kept)
+	}
+	if history, _ := rentals.GetAllBookRentals(ctx, "9780201633610"); len(history) != 1 {
+		t.Errorf("expected the returned rental to outlive its book, got %d", len(history))
+	}
+	if _, err := books.FindDeletedByISBN(ctx, "9780131103627"); err != nil {
+		t.Errorf("expected the rented book to stay deleted, got %v", err)
+	}
+	if found, _ := copies.FindCopiesByISBN(ctx, "9780131103627"); len(found) != 1 {
+		t.Errorf("expected the copies of the kept book to stay, got %d", len(found))
+	}
+	if _, err := books.FindDeletedByISBN(ctx, "9783161484100"); err != interfaces.ErrBookNotFound {
+		t.Errorf("expected the purged book to be gone, got %v", err)
+	}
+	if found, _ := copies.FindCopiesByISBN(ctx, "9783161484100"); len(found) != 0 {
+		t.Errorf("expected the copies of the purged book to be gone, got %d", len(found))
+	}
+
+	command = &PurgeDeletedBooksCommand{Retention: 7 * 24 * time.Hour}
+	if err := handler.Handle(ctx, command); err != nil || len(command.Purged) != 1 || command.Purged[0] != "9780306406157" {
+		t.Errorf("expected the book deleted 10 days ago to be purged, got %v (%v)", command.Purged, err)
+	}
+	if _, err := books.FindByISBN(ctx, "9780596517748"); err != nil {
+		t.Errorf("expected the book that was not deleted to be kept, got %v", err)
+	}
+
+	if err := handler.Handle(ctx, &PurgeDeletedBooksCommand{Retention: -time.Hour}); err == nil {
+		t.Errorf("expected a negative retention to be refused")
+	}
 }
\ No newline at end of file
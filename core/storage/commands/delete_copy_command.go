@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"books/core/storage/repositories/interfaces"
+)
+
+type DeleteCopyCommand struct {
+	ID string
+}
+
+type DeleteCopyCommandHandler struct {
+	copies interfaces.CopyRepository
+}
+
+func NewDeleteCopyCommandHandler(copies interfaces.CopyRepository) *DeleteCopyCommandHandler {
+	return &DeleteCopyCommandHandler{
+		copies: copies,
+	}
+}
+
+func (h *DeleteCopyCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	if cmd == nil {
+		return ErrInvalidCommandType
+	}
+
+	command, ok := cmd.(*DeleteCopyCommand)
+	if !ok {
+		return ErrInvalidCommandType
+	}
+
+	if strings.TrimSpace(command.ID) == "" {
+		return errors.New("copy ID cannot be empty")
+	}
+
+	return h.copies.DeleteCopy(ctx, command.ID)
+}
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"time"
 
 	"books/core/storage/commands"
 	"books/core/storage/models"
@@ -31,7 +30,7 @@ func (h *CreateBookHandler) Handle(ctx context.Context, command interface{}) err
 		return commands.ErrInvalidCommandType
 	}
 
-	book, err := models.NewBook(cmd.ISBN, cmd.Title, cmd.Author, time.Now())
+	book, err := models.NewBook(cmd.ISBN, cmd.Title, cmd.Author)
 	if err != nil {
 		return err
 	}
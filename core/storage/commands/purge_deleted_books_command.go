@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	library_repositories "books/core/library/repositories"
+	"books/core/storage/models"
+	"books/core/storage/repositories/interfaces"
+)
+
+// PurgeDeletedBooksCommand permanently removes the books deleted longer ago
+// than the retention period, models.DefaultDeletedBookRetention when none is
+// given, along with their copies. Returned rentals and closed holds outlive
+// the book, keeping its ISBN; a book with a copy on loan or a waiting or ready
+// hold stays deleted until they are closed.
+type PurgeDeletedBooksCommand struct {
+	Retention time.Duration
+	// Purged is filled in with the ISBNs of the books removed.
+	Purged []string
+	// Kept is filled in with the ISBNs of the books kept for their open
+	// rentals or active holds.
+	Kept []string
+}
+
+type PurgeDeletedBooksCommandHandler struct {
+	repo    interfaces.BookRepository
+	copies  interfaces.CopyRepository
+	rentals library_repositories.BookRepository
+	holds   library_repositories.HoldRepository
+}
+
+func NewPurgeDeletedBooksCommandHandler(repo interfaces.BookRepository, copies interfaces.CopyRepository, rentals library_repositories.BookRepository, holds library_repositories.HoldRepository) *PurgeDeletedBooksCommandHandler {
+	return &PurgeDeletedBooksCommandHandler{
+		repo:    repo,
+		copies:  copies,
+		rentals: rentals,
+		holds:   holds,
+	}
+}
+
+func (h *PurgeDeletedBooksCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	if cmd == nil {
+		return ErrInvalidCommandType
+	}
+
+	command, ok := cmd.(*PurgeDeletedBooksCommand)
+	if !ok {
+		return ErrInvalidCommandType
+	}
+
+	retention := command.Retention
+	if retention == 0 {
+		retention = models.DefaultDeletedBookRetention
+	}
+	if retention < 0 {
+		return fmt.Errorf("invalid retention period %s", retention)
+	}
+
+	deleted, err := h.repo.ListDeletedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return err
+	}
+
+	command.Purged = make([]string, 0)
+	command.Kept = make([]string, 0)
+	for _, isbn := range deleted {
+		inUse, err := h.inUse(ctx, isbn)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			command.Kept = append(command.Kept, isbn)
+			continue
+		}
+
+		if err := h.repo.Purge(ctx, isbn); err != nil {
+			return err
+		}
+		// Postgres removes the copies with their book; copies kept apart
+		// from it, as in memory, are removed here.
+		if err := h.copies.DeleteCopiesByISBN(ctx, isbn); err != nil {
+			return err
+		}
+		command.Purged = append(command.Purged, isbn)
+	}
+	return nil
+}
+
+// inUse reports whether a copy of the book is on loan or a hold on it is
+// still waiting or ready.
+func (h *PurgeDeletedBooksCommandHandler) inUse(ctx context.Context, isbn string) (bool, error) {
+	rentals, err := h.rentals.GetActiveBookRentalsByBookID(ctx, isbn)
+	if err != nil {
+		return false, err
+	}
+	if len(rentals) > 0 {
+		return true, nil
+	}
+
+	holds, err := h.holds.GetActiveHoldsByBookID(ctx, isbn)
+	if err != nil {
+		return false, err
+	}
+	return len(holds) > 0, nil
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"books/core/storage/repositories/interfaces"
+)
+
+// RestoreBookCommand brings a deleted book back into the catalog, with the
+// copies it had.
+type RestoreBookCommand struct {
+	ISBN string
+}
+
+type RestoreBookCommandHandler struct {
+	repo interfaces.BookRepository
+}
+
+func NewRestoreBookCommandHandler(repo interfaces.BookRepository) *RestoreBookCommandHandler {
+	return &RestoreBookCommandHandler{
+		repo: repo,
+	}
+}
+
+func (h *RestoreBookCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	if cmd == nil {
+		return ErrInvalidCommandType
+	}
+
+	command, ok := cmd.(*RestoreBookCommand)
+	if !ok {
+		return ErrInvalidCommandType
+	}
+
+	if strings.TrimSpace(command.ISBN) == "" {
+		return errors.New("book ID cannot be empty")
+	}
+
+	return h.repo.Restore(ctx, command.ISBN)
+}
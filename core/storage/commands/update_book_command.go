@@ -5,23 +5,33 @@ import (
 	"errors"
 	"strings"
 
+	author_repositories "books/core/authors/repositories"
 	"books/core/storage/models"
 	"books/core/storage/repositories/interfaces"
 )
 
+// UpdateBookCommand changes a book's description. Empty fields and details
+// are left as they are; an empty, non-nil Subjects clears the subjects.
+// A new Author renames the primary author, unlinking it from its authority
+// record, unless the contributors are replaced too; an empty, non-nil
+// Contributors credits the book to its author alone.
 type UpdateBookCommand struct {
-	ISBN  string 
-	Title string
-	Author string
+	ISBN     string
+	Title    string
+	Author   string
+	Category string
+	Details  models.BookDetails
 }
 
 type UpdateBookCommandHandler struct {
-	repo interfaces.BookRepository
+	repo    interfaces.BookRepository
+	authors author_repositories.AuthorRepository
 }
 
-func NewUpdateBookCommandHandler(repo interfaces.BookRepository) *UpdateBookCommandHandler {
+func NewUpdateBookCommandHandler(repo interfaces.BookRepository, authors author_repositories.AuthorRepository) *UpdateBookCommandHandler {
 	return &UpdateBookCommandHandler{
-		repo: repo,
+		repo:    repo,
+		authors: authors,
 	}
 }
 
@@ -39,28 +49,113 @@ func (h *UpdateBookCommandHandler) Handle(ctx context.Context, cmd interface{})
 		return errors.New("book ISBN cannot be empty")
 	}
 
-	if command.Title == "" && command.Author == "" {
+	if command.Title == "" && command.Author == "" && command.Category == "" && isEmptyDetails(command.Details) {
 		return errors.New("at least one field must be provided for update")
 	}
 
 	bookToUpdate, err := h.repo.FindByISBN(ctx, command.ISBN)
 	if err != nil {
-		return err 
+		return err
 	}
 
 	newBook := &models.Book{
-		ISBN:        bookToUpdate.ISBN,
-		Title:       bookToUpdate.Title,
-		Author:      bookToUpdate.Author,
-		PublishedAt: bookToUpdate.PublishedAt,
+		ISBN:     bookToUpdate.ISBN,
+		Title:    bookToUpdate.Title,
+		Author:   bookToUpdate.Author,
+		Category: bookToUpdate.Category,
 	}
 
 	if command.Title != "" {
-		newBook.Title = command.Title
+		newBook.Title = models.NormalizeText(command.Title)
 	}
 	if command.Author != "" {
 		newBook.Author = command.Author
 	}
+	if command.Category != "" {
+		newBook.Category = command.Category
+	}
+
+	update := command.Details
+	update.Contributors, err = linkAuthors(ctx, h.authors, update.Contributors)
+	if err != nil {
+		return err
+	}
+
+	details := mergeDetails(bookToUpdate.BookDetails, update)
+	if command.Author != "" {
+		if update.Contributors == nil {
+			details.Contributors = renamePrimaryAuthor(details.Contributors, command.Author)
+		} else if _, err := primaryAuthor(command.Author, update.Contributors); err != nil {
+			return err
+		}
+	}
+	if err := newBook.SetDetails(details); err != nil {
+		return err
+	}
 
 	return h.repo.Save(ctx, newBook)
-}
\ No newline at end of file
+}
+
+// mergeDetails applies the details given in an update over the current ones.
+func mergeDetails(current, update models.BookDetails) models.BookDetails {
+	merged := current.Clone()
+
+	if update.Subtitle != "" {
+		merged.Subtitle = update.Subtitle
+	}
+	if update.Publisher != "" {
+		merged.Publisher = update.Publisher
+	}
+	// A new date or year replaces both, so they cannot disagree.
+	if update.PublishedAt != nil || update.PublicationYear != 0 {
+		merged.PublishedAt = update.PublishedAt
+		merged.PublicationYear = update.PublicationYear
+	}
+	if update.Edition != "" {
+		merged.Edition = update.Edition
+	}
+	if update.Language != "" {
+		merged.Language = update.Language
+	}
+	if update.PageCount != 0 {
+		merged.PageCount = update.PageCount
+	}
+	if update.Description != "" {
+		merged.Description = update.Description
+	}
+	if update.Subjects != nil {
+		merged.Subjects = update.Subjects
+	}
+	if update.Contributors != nil {
+		merged.Contributors = update.Contributors
+	}
+
+	return merged
+}
+
+func isEmptyDetails(details models.BookDetails) bool {
+	return details.Subtitle == "" && details.Publisher == "" && details.PublishedAt == nil &&
+		details.PublicationYear == 0 && details.Edition == "" && details.Language == "" &&
+		details.PageCount == 0 && details.Description == "" && details.Subjects == nil &&
+		details.Contributors == nil
+}
+
+// renamePrimaryAuthor gives the primary author a new name, no longer linked
+// to an author, crediting the book to that author first when no author is
+// listed.
+func renamePrimaryAuthor(contributors []models.Contributor, author string) []models.Contributor {
+	renamed := make([]models.Contributor, 0, len(contributors)+1)
+	found := false
+	for _, contributor := range contributors {
+		if !found && contributor.Role == models.RoleAuthor {
+			contributor.Name = author
+			contributor.AuthorID = ""
+			found = true
+		}
+		renamed = append(renamed, contributor)
+	}
+	if !found {
+		renamed = append([]models.Contributor{{Name: author, Role: models.RoleAuthor}}, renamed...)
+	}
+	return renamed
+}
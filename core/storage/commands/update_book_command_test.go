@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"testing"
-	"time"
 
+	author_repositories "books/core/authors/repositories"
 	"books/core/storage/models"
 	"books/core/storage/repositories"
 	"books/core/storage/repositories/interfaces"
@@ -25,7 +25,7 @@ func getUpdateBookTestCases() []updateBookTestCase {
 		ISBN:        "123",
 		Title:       "Test Book",
 		Author:      "Test Author",
-		PublishedAt: time.Now(),
+		BookDetails: models.BookDetails{Publisher: "Test Publisher", PublicationYear: 2001},
 	}
 
 	return []updateBookTestCase{
@@ -107,6 +107,106 @@ func getUpdateBookTestCases() []updateBookTestCase {
 			wantErr:     true,
 			expectedErr: errors.New("at least one field must be provided for update"),
 		},
+		{
+			name: "Update details only",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {
+				_ = repo.Save(context.Background(), testBook)
+			},
+			command: &UpdateBookCommand{
+				ISBN:    testBook.ISBN,
+				Details: models.BookDetails{Language: "fr", Subjects: []string{"Poetry"}},
+			},
+			validateResult: func(t *testing.T, repo *repositories.BookStorageInMemoryRepository) {
+				book, _ := repo.FindByISBN(context.Background(), testBook.ISBN)
+				if book.Language != "fr" || len(book.Subjects) != 1 {
+					t.Errorf("expected language and subjects to change, got %+v", book.BookDetails)
+				}
+				if book.Publisher != "Test Publisher" || book.PublicationYear != 2001 || book.Title != testBook.Title {
+					t.Errorf("expected other fields to be kept, got %+v", book)
+				}
+			},
+		},
+		{
+			name: "New year replaces the publication date",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {
+				_ = repo.Save(context.Background(), testBook)
+			},
+			command: &UpdateBookCommand{
+				ISBN:    testBook.ISBN,
+				Details: models.BookDetails{PublicationYear: 1987},
+			},
+			validateResult: func(t *testing.T, repo *repositories.BookStorageInMemoryRepository) {
+				book, _ := repo.FindByISBN(context.Background(), testBook.ISBN)
+				if book.PublicationYear != 1987 || book.PublishedAt != nil {
+					t.Errorf("expected only the year to be known, got %+v", book.BookDetails)
+				}
+			},
+		},
+		{
+			name: "Invalid details",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {
+				_ = repo.Save(context.Background(), testBook)
+			},
+			command: &UpdateBookCommand{
+				ISBN:    testBook.ISBN,
+				Details: models.BookDetails{Language: "xx"},
+			},
+			wantErr:     true,
+			expectedErr: errors.New(`invalid language "xx": use an ISO 639-1 code such as "en"`),
+		},
+		{
+			name: "New author renames the primary author",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {
+				book := *testBook
+				book.Contributors = []models.Contributor{
+					{Name: "Test Editor", Role: models.RoleEditor},
+					{Name: "Test Author", Role: models.RoleAuthor},
+				}
+				_ = repo.Save(context.Background(), &book)
+			},
+			command: &UpdateBookCommand{ISBN: testBook.ISBN, Author: "Renamed Author"},
+			validateResult: func(t *testing.T, repo *repositories.BookStorageInMemoryRepository) {
+				book, _ := repo.FindByISBN(context.Background(), testBook.ISBN)
+				if book.Author != "Renamed Author" || len(book.Contributors) != 2 || book.Contributors[1].Name != "Renamed Author" {
+					t.Errorf("expected the primary author to be renamed, got %q %+v", book.Author, book.Contributors)
+				}
+			},
+		},
+		{
+			name: "New contributors replace the author",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {
+				_ = repo.Save(context.Background(), testBook)
+			},
+			command: &UpdateBookCommand{
+				ISBN: testBook.ISBN,
+				Details: models.BookDetails{Contributors: []models.Contributor{
+					{Name: "New Author", Role: models.RoleAuthor},
+					{Name: "New Illustrator", Role: models.RoleIllustrator},
+				}},
+			},
+			validateResult: func(t *testing.T, repo *repositories.BookStorageInMemoryRepository) {
+				book, _ := repo.FindByISBN(context.Background(), testBook.ISBN)
+				if book.Author != "New Author" || len(book.Contributors) != 2 {
+					t.Errorf("expected the contributors to be replaced, got %q %+v", book.Author, book.Contributors)
+				}
+				if book.Publisher != "Test Publisher" {
+					t.Errorf("expected other details to be kept, got %+v", book.BookDetails)
+				}
+			},
+		},
+		{
+			name: "Author not the primary author of new contributors",
+			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {
+				_ = repo.Save(context.Background(), testBook)
+			},
+			command: &UpdateBookCommand{
+				ISBN:    testBook.ISBN,
+				Author:  "Test Author",
+				Details: models.BookDetails{Contributors: []models.Contributor{{Name: "New Author"}}},
+			},
+			wantErr:     true,
+			expectedErr: errors.New(`invalid author "Test Author": it must be the primary author of the contributors`),
+		},
 		{
 			name:      "Invalid command type",
 			setupRepo: func(repo *repositories.BookStorageInMemoryRepository) {},
@@ -122,7 +222,7 @@ func getUpdateBookTestCases() []updateBookTestCase {
 			command: &UpdateBookCommand{ISBN: testBook.ISBN, Title: "New Title"},
 			wantErr: false,
 			validateResult: func(t *testing.T, repo *repositories.BookStorageInMemoryRepository) {
-				handler := NewUpdateBookCommandHandler(repo)
+				handler := NewUpdateBookCommandHandler(repo, author_repositories.NewAuthorInMemoryRepository())
 				err := handler.Handle(context.Background(), &UpdateBookCommand{
 					ISBN:  testBook.ISBN,
 					Title: "New Title",
@@ -149,7 +249,7 @@ func TestUpdateBookCommandHandler_Handle(t *testing.T) {
 				tt.setupRepo(mockRepo)
 			}
 
-			handler := NewUpdateBookCommandHandler(mockRepo)
+			handler := NewUpdateBookCommandHandler(mockRepo, author_repositories.NewAuthorInMemoryRepository())
 			err := handler.Handle(context.Background(), tt.command)
 
 			if tt.wantErr {
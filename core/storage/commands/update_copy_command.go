@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	branch_repositories "books/core/branches/repositories"
+	"books/core/storage/models"
+	"books/core/storage/repositories/interfaces"
+)
+
+type UpdateCopyCommand struct {
+	ID            string
+	Barcode       string
+	ShelfLocation string
+	Condition     string
+	// HomeBranchID moves the copy's home. Where the copy is stays as it is,
+	// except that a copy without a branch is taken to be at its new home.
+	HomeBranchID string
+}
+
+type UpdateCopyCommandHandler struct {
+	copies   interfaces.CopyRepository
+	branches branch_repositories.BranchRepository
+}
+
+func NewUpdateCopyCommandHandler(copies interfaces.CopyRepository, branches branch_repositories.BranchRepository) *UpdateCopyCommandHandler {
+	return &UpdateCopyCommandHandler{
+		copies:   copies,
+		branches: branches,
+	}
+}
+
+func (h *UpdateCopyCommandHandler) Handle(ctx context.Context, cmd interface{}) error {
+	if cmd == nil {
+		return ErrInvalidCommandType
+	}
+
+	command, ok := cmd.(*UpdateCopyCommand)
+	if !ok {
+		return ErrInvalidCommandType
+	}
+
+	if strings.TrimSpace(command.ID) == "" {
+		return errors.New("copy ID cannot be empty")
+	}
+
+	if command.Barcode == "" && command.ShelfLocation == "" && command.Condition == "" && command.HomeBranchID == "" {
+		return errors.New("at least one field must be provided for update")
+	}
+
+	bookCopy, err := h.copies.FindCopyByID(ctx, command.ID)
+	if err != nil {
+		return err
+	}
+
+	if command.Barcode != "" {
+		bookCopy.Barcode = strings.TrimSpace(command.Barcode)
+	}
+	if command.ShelfLocation != "" {
+		bookCopy.ShelfLocation = strings.TrimSpace(command.ShelfLocation)
+	}
+	if command.Condition != "" {
+		bookCopy.Condition = models.CopyCondition(command.Condition)
+	}
+	if command.HomeBranchID != "" {
+		if _, err := h.branches.GetBranchByID(ctx, command.HomeBranchID); err != nil {
+			return err
+		}
+		bookCopy.HomeBranchID = command.HomeBranchID
+		if bookCopy.CurrentBranchID == "" {
+			bookCopy.CurrentBranchID = command.HomeBranchID
+		}
+	}
+
+	if err := bookCopy.Validate(); err != nil {
+		return err
+	}
+
+	return h.copies.UpdateCopy(ctx, bookCopy)
+}
@@ -4,106 +4,81 @@ import (
 	"errors"
 	"strings"
 	"time"
+
+	isbns "books/core/isbn"
 )
 
+// DefaultCategory is assigned to books added without a category.
+const DefaultCategory = "standard"
+
+// DefaultDeletedBookRetention is how long a deleted book can be restored
+// before it may be purged.
+const DefaultDeletedBookRetention = 90 * 24 * time.Hour
+
 type Book struct {
-	ISBN        string    `json:"isbn"`
-	Title       string    `json:"title"`
-	Author      string    `json:"author"`
-	PublishedAt time.Time `json:"published_at"`
+	ISBN     string `json:"isbn"`
+	Title    string `json:"title"`
+	Author   string `json:"author"`
+	Category string `json:"category"`
+	// DeletedAt is when the book was deleted. A deleted book is left out of
+	// the catalog until it is restored or purged, and keeps its rentals.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	BookDetails
+}
+
+// IsDeleted reports whether the book was deleted.
+func (b *Book) IsDeleted() bool {
+	return b.DeletedAt != nil
 }
 
-func NewBook(isbn, title, author string, publishedAt time.Time) (*Book, error) {
+// NewBook catalogues a book with its title and author, who is its only
+// contributor. The book is identified by the canonical ISBN-13 of isbn. The
+// rest of its description is added with SetDetails.
+func NewBook(isbn, title, author string) (*Book, error) {
 	if err := validateBook(title, author, isbn); err != nil {
 		return nil, err
 	}
 
 	return &Book{
-		ISBN:        isbn,
-		Title:       title,
-		Author:      author,
-		PublishedAt: publishedAt,
+		ISBN:     isbns.Key(isbn),
+		Title:    NormalizeText(title),
+		Author:   NormalizeText(author),
+		Category: DefaultCategory,
+		BookDetails: BookDetails{
+			Contributors: []Contributor{{Name: NormalizeText(author), Role: RoleAuthor}},
+		},
 	}, nil
 }
 
-func validateBook(title string, author string, isbn string) error {
-	if strings.TrimSpace(title) == "" {
-		return errors.New("title cannot be empty")
+// SetDetails replaces the book's bibliographic details once they are valid.
+// A book given no contributors is credited to its author alone; otherwise
+// its author becomes the primary author of the contributors.
+func (b *Book) SetDetails(details BookDetails) error {
+	details.normalize()
+	if len(details.Contributors) == 0 {
+		details.Contributors = []Contributor{{Name: strings.TrimSpace(b.Author), Role: RoleAuthor}}
 	}
-
-	if strings.TrimSpace(author) == "" {
-		return errors.New("author cannot be empty")
-	}
-
-	if strings.TrimSpace(isbn) == "" {
-		return errors.New("ISBN cannot be empty")
-	}
-
-	if err := validateISBN(isbn); err != nil {
+	if err := details.Validate(); err != nil {
 		return err
 	}
 
+	b.BookDetails = details
+	b.Author = PrimaryAuthor(details.Contributors)
 	return nil
 }
 
-func validateISBN(isbn string) error {
-	cleaned := strings.ReplaceAll(isbn, "-", "")
-	cleaned = strings.ReplaceAll(cleaned, " ", "")
-
-	switch len(cleaned) {
-	case 10:
-		return validateISBN10(cleaned)
-	case 13:
-		return validateISBN13(cleaned)
-	default:
-		return errors.New("ISBN must be 10 or 13 characters (excluding hyphens)")
-	}
-}
-
-func validateISBN10(isbn string) error {
-	sum := 0
-	for i := 0; i < 9; i++ {
-		digit := int(isbn[i] - '0')
-		if digit < 0 || digit > 9 {
-			return errors.New("ISBN-10 must contain only digits (and optionally X as last character)")
-		}
-		sum += digit * (10 - i)
-	}
-
-	lastChar := isbn[9]
-	var lastDigit int
-	if lastChar == 'X' || lastChar == 'x' {
-		lastDigit = 10
-	} else {
-		lastDigit = int(lastChar - '0')
-		if lastDigit < 0 || lastDigit > 9 {
-			return errors.New("ISBN-10 must contain only digits (and optionally X as last character)")
-		}
+func validateBook(title string, author string, isbn string) error {
+	if strings.TrimSpace(title) == "" {
+		return errors.New("title cannot be empty")
 	}
-	sum += lastDigit
 
-	if sum%11 != 0 {
-		return errors.New("invalid ISBN-10 checksum")
+	if strings.TrimSpace(author) == "" {
+		return errors.New("author cannot be empty")
 	}
-	return nil
-}
 
-func validateISBN13(isbn string) error {
-	sum := 0
-	for i := 0; i < 13; i++ {
-		digit := int(isbn[i] - '0')
-		if digit < 0 || digit > 9 {
-			return errors.New("ISBN-13 must contain only digits")
-		}
-		if i%2 == 0 {
-			sum += digit
-		} else {
-			sum += digit * 3
-		}
+	if strings.TrimSpace(isbn) == "" {
+		return errors.New("ISBN cannot be empty")
 	}
 
-	if sum%10 != 0 {
-		return errors.New("invalid ISBN-13 checksum")
-	}
-	return nil
+	return isbns.Validate(isbn)
 }
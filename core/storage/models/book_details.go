@@ -0,0 +1,175 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxSubtitleLength    = 255
+	maxPublisherLength   = 255
+	maxEditionLength     = 50
+	maxDescriptionLength = 5000
+	maxPageCount         = 100000
+	maxSubjects          = 25
+	maxSubjectLength     = 100
+)
+
+// PublicationDateLayout is the form of a full publication date.
+const PublicationDateLayout = "2006-01-02"
+
+// BookDetails is the bibliographic description of a book beyond its title.
+// Zero values mean the detail is not known.
+type BookDetails struct {
+	// Contributors are credited in order. The book's Author is the primary
+	// author among them.
+	Contributors []Contributor `json:"contributors,omitempty"`
+	Subtitle     string        `json:"subtitle,omitempty"`
+	Publisher    string        `json:"publisher,omitempty"`
+	// PublishedAt is the publication date when the full date is known.
+	// PublicationYear is always set along with it, and is set on its own
+	// when only the year is known.
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	PublicationYear int        `json:"publication_year,omitempty"`
+	Edition         string     `json:"edition,omitempty"`
+	// Language is an ISO 639-1 code such as "en".
+	Language    string   `json:"language,omitempty"`
+	PageCount   int      `json:"page_count,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Subjects    []string `json:"subjects,omitempty"`
+}
+
+// ParsePublicationDate reads a date in PublicationDateLayout. An empty string
+// is no date.
+func ParsePublicationDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(PublicationDateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publication date %q: use YYYY-MM-DD", value)
+	}
+	return &date, nil
+}
+
+// Validate checks every detail. Details are expected to be normalized.
+func (d BookDetails) Validate() error {
+	if err := validateContributors(d.Contributors); err != nil {
+		return err
+	}
+
+	if utf8.RuneCountInString(d.Subtitle) > maxSubtitleLength {
+		return fmt.Errorf("invalid subtitle: longer than %d characters", maxSubtitleLength)
+	}
+	if utf8.RuneCountInString(d.Publisher) > maxPublisherLength {
+		return fmt.Errorf("invalid publisher: longer than %d characters", maxPublisherLength)
+	}
+	if utf8.RuneCountInString(d.Edition) > maxEditionLength {
+		return fmt.Errorf("invalid edition: longer than %d characters", maxEditionLength)
+	}
+	if utf8.RuneCountInString(d.Description) > maxDescriptionLength {
+		return fmt.Errorf("invalid description: longer than %d characters", maxDescriptionLength)
+	}
+
+	if err := d.validatePublication(); err != nil {
+		return err
+	}
+
+	if d.Language != "" && !IsLanguageCode(d.Language) {
+		return fmt.Errorf("invalid language %q: use an ISO 639-1 code such as \"en\"", d.Language)
+	}
+
+	if d.PageCount < 0 || d.PageCount > maxPageCount {
+		return fmt.Errorf("invalid page count %d", d.PageCount)
+	}
+
+	if len(d.Subjects) > maxSubjects {
+		return fmt.Errorf("invalid subjects: at most %d are allowed", maxSubjects)
+	}
+	for _, subject := range d.Subjects {
+		if subject == "" {
+			return errors.New("invalid subject: subjects cannot be empty")
+		}
+		if utf8.RuneCountInString(subject) > maxSubjectLength {
+			return fmt.Errorf("invalid subject %q: longer than %d characters", subject, maxSubjectLength)
+		}
+	}
+
+	return nil
+}
+
+// validatePublication refuses dates after the coming year, which leaves room
+// for forthcoming titles.
+func (d BookDetails) validatePublication() error {
+	latest := time.Now().Year() + 1
+
+	if d.PublicationYear < 0 || d.PublicationYear > latest {
+		return fmt.Errorf("invalid publication year %d", d.PublicationYear)
+	}
+	if d.PublishedAt == nil {
+		return nil
+	}
+	if d.PublishedAt.Year() < 1 || d.PublishedAt.Year() > latest {
+		return fmt.Errorf("invalid publication date %s", d.PublishedAt.Format(PublicationDateLayout))
+	}
+	if d.PublicationYear != d.PublishedAt.Year() {
+		return errors.New("invalid publication year: it does not match the publication date")
+	}
+	return nil
+}
+
+// normalize trims the text fields and puts them in normalization form C,
+// lowercases the language, drops duplicate subjects and fills in the year of
+// a full publication date.
+func (d *BookDetails) normalize() {
+	d.Contributors = normalizeContributors(d.Contributors)
+	d.Subtitle = NormalizeText(strings.TrimSpace(d.Subtitle))
+	d.Publisher = NormalizeText(strings.TrimSpace(d.Publisher))
+	d.Edition = strings.TrimSpace(d.Edition)
+	d.Language = strings.ToLower(strings.TrimSpace(d.Language))
+	d.Description = NormalizeText(strings.TrimSpace(d.Description))
+
+	if d.PublishedAt != nil {
+		date := time.Date(d.PublishedAt.Year(), d.PublishedAt.Month(), d.PublishedAt.Day(), 0, 0, 0, 0, time.UTC)
+		d.PublishedAt = &date
+		if d.PublicationYear == 0 {
+			d.PublicationYear = date.Year()
+		}
+	}
+
+	if d.Subjects == nil {
+		return
+	}
+	subjects := make([]string, 0, len(d.Subjects))
+	seen := make(map[string]bool, len(d.Subjects))
+	for _, subject := range d.Subjects {
+		subject = strings.TrimSpace(subject)
+		key := strings.ToLower(subject)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		subjects = append(subjects, subject)
+	}
+	d.Subjects = subjects
+}
+
+// Clone returns a copy that shares no memory with d.
+func (d BookDetails) Clone() BookDetails {
+	if d.PublishedAt != nil {
+		date := *d.PublishedAt
+		d.PublishedAt = &date
+	}
+	if d.Contributors != nil {
+		d.Contributors = append([]Contributor{}, d.Contributors...)
+	}
+	if d.Subjects != nil {
+		d.Subjects = append([]string{}, d.Subjects...)
+	}
+	return d
+}
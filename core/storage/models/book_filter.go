@@ -0,0 +1,244 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultBookPageSize = 20
+	MaxBookPageSize     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor: it does not continue this listing")
+
+// BookSortField is a field books can be listed in the order of.
+type BookSortField string
+
+const (
+	SortByTitle     BookSortField = "title"
+	SortByAuthor    BookSortField = "author"
+	SortByPublisher BookSortField = "publisher"
+	SortByYear      BookSortField = "year"
+	SortByISBN      BookSortField = "isbn"
+)
+
+// IsValid reports whether books can be sorted on the field.
+func (f BookSortField) IsValid() bool {
+	switch f {
+	case SortByTitle, SortByAuthor, SortByPublisher, SortByYear, SortByISBN:
+		return true
+	}
+	return false
+}
+
+// SortValue is the value a book is ordered by on the field. Text compares
+// by code point once its ASCII letters are lowercased, as Postgres lowers
+// text in the C collation whatever the database's locale, so "Émile" sorts
+// after "zazie" and before "émile". Books of an unknown year sort as the
+// year 0.
+func (f BookSortField) SortValue(book *Book) interface{} {
+	switch f {
+	case SortByTitle:
+		return lowerASCII(book.Title)
+	case SortByAuthor:
+		return lowerASCII(book.Author)
+	case SortByPublisher:
+		return lowerASCII(book.Publisher)
+	case SortByYear:
+		return book.PublicationYear
+	default:
+		return book.ISBN
+	}
+}
+
+// lowerASCII lowercases the ASCII letters of text and leaves the others be.
+func lowerASCII(text string) string {
+	b := []byte(text)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// BookSort orders a listing on one field.
+type BookSort struct {
+	Field      BookSortField
+	Descending bool
+}
+
+// ParseBookSort reads a comma-separated list of fields, each prefixed with
+// "-" to sort it in descending order, such as "-year,title".
+func ParseBookSort(value string) ([]BookSort, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	var sorts []BookSort
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		sort := BookSort{}
+		if strings.HasPrefix(part, "-") {
+			sort.Descending = true
+			part = part[1:]
+		}
+		sort.Field = BookSortField(strings.ToLower(part))
+		if !sort.Field.IsValid() {
+			return nil, fmt.Errorf("invalid sort field %q: use title, author, publisher, year or isbn", part)
+		}
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
+
+func formatBookSort(sorts []BookSort) string {
+	parts := make([]string, 0, len(sorts))
+	for _, sort := range sorts {
+		if sort.Descending {
+			parts = append(parts, "-"+string(sort.Field))
+		} else {
+			parts = append(parts, string(sort.Field))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// BookFilter selects a page of the catalog. Title, Author and Publisher
+// match the books containing them, ignoring case; Author matches any
+// contributor credited as an author. The year range is inclusive and leaves
+// out books of an unknown year. Zero values do not filter. Deleted books are
+// left out unless IncludeDeleted is set.
+type BookFilter struct {
+	Title          string
+	Author         string
+	Publisher      string
+	Language       string
+	YearFrom       int
+	YearTo         int
+	IncludeDeleted bool
+	// Sort defaults to the title. The ISBN always breaks ties, so every
+	// book has one place in the listing.
+	Sort  []BookSort
+	Limit int
+	// Cursor continues the listing after the page it was returned with.
+	Cursor string
+}
+
+// BookPage is a page of a listing. NextCursor is empty on the last page.
+type BookPage struct {
+	Books      []*Book
+	NextCursor string
+}
+
+// Normalize trims the filter, fills in its defaults and checks it.
+func (f BookFilter) Normalize() (BookFilter, error) {
+	f.Title = strings.TrimSpace(f.Title)
+	f.Author = strings.TrimSpace(f.Author)
+	f.Publisher = strings.TrimSpace(f.Publisher)
+	f.Language = strings.ToLower(strings.TrimSpace(f.Language))
+	f.Cursor = strings.TrimSpace(f.Cursor)
+
+	if f.Language != "" && !IsLanguageCode(f.Language) {
+		return f, fmt.Errorf("invalid language %q: use an ISO 639-1 code", f.Language)
+	}
+	if f.YearFrom < 0 || f.YearTo < 0 || (f.YearTo != 0 && f.YearFrom > f.YearTo) {
+		return f, fmt.Errorf("invalid year range %d to %d", f.YearFrom, f.YearTo)
+	}
+
+	switch {
+	case f.Limit == 0:
+		f.Limit = DefaultBookPageSize
+	case f.Limit < 0 || f.Limit > MaxBookPageSize:
+		return f, fmt.Errorf("invalid page size %d: use 1 to %d", f.Limit, MaxBookPageSize)
+	}
+
+	sorts := make([]BookSort, 0, len(f.Sort)+1)
+	seen := make(map[BookSortField]bool, len(f.Sort))
+	for _, sort := range f.Sort {
+		if !sort.Field.IsValid() {
+			return f, fmt.Errorf("invalid sort field %q", sort.Field)
+		}
+		if seen[sort.Field] {
+			continue
+		}
+		seen[sort.Field] = true
+		sorts = append(sorts, sort)
+	}
+	if len(sorts) == 0 {
+		sorts = append(sorts, BookSort{Field: SortByTitle})
+	}
+	if !seen[SortByISBN] {
+		sorts = append(sorts, BookSort{Field: SortByISBN})
+	}
+	f.Sort = sorts
+
+	if _, err := f.After(); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// bookCursor is what a cursor holds: the sort it was made for and the sort
+// values of the last book of its page.
+type bookCursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+// CursorAfter makes the cursor that continues a listing after book. The
+// filter must be normalized.
+func (f BookFilter) CursorAfter(book *Book) string {
+	cursor := bookCursor{Sort: formatBookSort(f.Sort)}
+	for _, sort := range f.Sort {
+		switch value := sort.Field.SortValue(book).(type) {
+		case int:
+			cursor.Values = append(cursor.Values, strconv.Itoa(value))
+		case string:
+			cursor.Values = append(cursor.Values, value)
+		}
+	}
+
+	encoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// After returns the sort values the cursor continues after, one for each
+// sort field, or nil without a cursor. The filter must be normalized.
+func (f BookFilter) After() ([]interface{}, error) {
+	if f.Cursor == "" {
+		return nil, nil
+	}
+
+	encoded, err := base64.RawURLEncoding.DecodeString(f.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor bookCursor
+	if err := json.Unmarshal(encoded, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Sort != formatBookSort(f.Sort) || len(cursor.Values) != len(f.Sort) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]interface{}, 0, len(f.Sort))
+	for i, sort := range f.Sort {
+		if sort.Field != SortByYear {
+			values = append(values, cursor.Values[i])
+			continue
+		}
+		year, err := strconv.Atoi(cursor.Values[i])
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		values = append(values, year)
+	}
+	return values, nil
+}
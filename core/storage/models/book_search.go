@@ -0,0 +1,283 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	DefaultSearchPageSize = 20
+	MaxSearchPageSize     = 100
+	maxSearchTerms        = 16
+	// MaxFacetValues is how many of the most frequent authors and subjects
+	// a search counts.
+	MaxFacetValues = 10
+)
+
+// BookSearch looks books up by the words of their title, author and
+// description. Query holds words that must all be found; words in double
+// quotes must be found as a phrase, and a word ending in "*" matches the
+// words it starts, as in `"lord of the" ring*`.
+//
+// The filters narrow the hits down to the values of the search's facets:
+// Author is the exact name of a contributor credited as an author, Subject
+// one of the book's subjects, Decade the first year of the decade the book
+// was published in, and Available keeps the books with a copy on the shelf
+// now. Zero values do not filter.
+type BookSearch struct {
+	Query     string
+	Author    string
+	Language  string
+	Decade    int
+	Subject   string
+	Available bool
+	Limit     int
+	Offset    int
+}
+
+// SearchTerm is one part of a search query: a word, or the words of a
+// phrase, which must be found one after the other. With Prefix the last
+// word matches the words it starts. Similar holds, for each word, the
+// title and author words that are found in its place to tolerate typos.
+type SearchTerm struct {
+	Words   []string
+	Prefix  bool
+	Similar [][]string
+}
+
+// String writes the term back as it is written in a query.
+func (t SearchTerm) String() string {
+	text := strings.Join(t.Words, " ")
+	if len(t.Words) > 1 {
+		text = `"` + text + `"`
+	}
+	if t.Prefix {
+		text += "*"
+	}
+	return text
+}
+
+// FormatSearchTerms writes terms back as a query.
+func FormatSearchTerms(terms []SearchTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, term.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// BookHit is a book found by a search. TitleHighlight is its title and
+// Snippet the part of its description that matches best, with the words
+// found marked as <mark>word</mark>.
+type BookHit struct {
+	Book           *Book
+	TitleHighlight string
+	Snippet        string
+}
+
+// BookSearchResult is a page of the hits of a search, from the most
+// relevant: matches in the title weigh most, then in the author, then in
+// the description. Total counts the hits on every page, and Facets break
+// them down. A search that finds nothing may come with a Suggestion,
+// another query that finds books.
+type BookSearchResult struct {
+	Hits       []BookHit
+	Total      int
+	Facets     BookFacets
+	Suggestion string
+}
+
+// FacetCount is how many hits have a value.
+type FacetCount struct {
+	Value string
+	Count int
+}
+
+// DecadeCount is how many hits were published in the decade starting with
+// the year Decade.
+type DecadeCount struct {
+	Decade int
+	Count  int
+}
+
+// BookFacets count the hits of a search, on every page, by the values they
+// can be filtered on. Authors, languages and subjects run from the most
+// frequent, and only the MaxFacetValues most frequent authors and subjects
+// are counted; decades run in order. Books of an unknown language or year
+// are left out of those counts. A book is Available when one of its copies
+// in circulation is not lent out.
+type BookFacets struct {
+	Authors     []FacetCount
+	Languages   []FacetCount
+	Decades     []DecadeCount
+	Subjects    []FacetCount
+	Available   int
+	Unavailable int
+}
+
+// Decade is the first year of the decade of a year.
+func Decade(year int) int {
+	return year / 10 * 10
+}
+
+// Normalize fills in the defaults of the search, checks it and returns the
+// terms of its query.
+func (s BookSearch) Normalize() (BookSearch, []SearchTerm, error) {
+	s.Query = strings.TrimSpace(s.Query)
+	s.Author = strings.TrimSpace(s.Author)
+	s.Language = strings.ToLower(strings.TrimSpace(s.Language))
+	s.Subject = strings.TrimSpace(s.Subject)
+
+	if s.Language != "" && !IsLanguageCode(s.Language) {
+		return s, nil, fmt.Errorf("invalid language %q: use an ISO 639-1 code", s.Language)
+	}
+	if s.Decade < 0 || s.Decade != Decade(s.Decade) {
+		return s, nil, fmt.Errorf("invalid decade %d: use its first year, such as 1990", s.Decade)
+	}
+
+	switch {
+	case s.Limit == 0:
+		s.Limit = DefaultSearchPageSize
+	case s.Limit < 0 || s.Limit > MaxSearchPageSize:
+		return s, nil, fmt.Errorf("invalid page size %d: use 1 to %d", s.Limit, MaxSearchPageSize)
+	}
+	if s.Offset < 0 {
+		return s, nil, fmt.Errorf("invalid offset %d", s.Offset)
+	}
+
+	terms, err := ParseSearchQuery(s.Query)
+	if err != nil {
+		return s, nil, err
+	}
+	return s, terms, nil
+}
+
+// ParseSearchQuery splits a query into its terms. Punctuation separates
+// words, so a hyphenated word, like a quoted phrase, is searched as a phrase
+// of its parts.
+func ParseSearchQuery(query string) ([]SearchTerm, error) {
+	var terms []SearchTerm
+	add := func(text string) {
+		words := SearchWords(text)
+		if len(words) > 0 {
+			terms = append(terms, SearchTerm{Words: words, Prefix: strings.HasSuffix(strings.TrimSpace(text), "*")})
+		}
+	}
+
+	rest := query
+	for rest != "" {
+		start := strings.IndexByte(rest, '"')
+		if start < 0 {
+			for _, field := range strings.Fields(rest) {
+				add(field)
+			}
+			break
+		}
+		for _, field := range strings.Fields(rest[:start]) {
+			add(field)
+		}
+
+		rest = rest[start+1:]
+		end := strings.IndexByte(rest, '"')
+		if end < 0 {
+			end = len(rest)
+		}
+		phrase := rest[:end]
+		rest = rest[min(end+1, len(rest)):]
+		// A star right after the closing quote makes the phrase a prefix.
+		if strings.HasPrefix(rest, "*") {
+			phrase += "*"
+			rest = rest[1:]
+		}
+		add(phrase)
+	}
+
+	if len(terms) == 0 {
+		return nil, errors.New("search query cannot be empty")
+	}
+	if len(terms) > maxSearchTerms {
+		return nil, fmt.Errorf("invalid search query: more than %d terms", maxSearchTerms)
+	}
+	return terms, nil
+}
+
+// SearchWord is a word of a text with where it is in the text.
+type SearchWord struct {
+	Word  string
+	Start int
+	End   int
+}
+
+// SplitSearchWords splits text into its words, the runs of letters and
+// digits, the way the Postgres simple text search configuration does. The
+// words are folded with FoldText.
+func SplitSearchWords(text string) []SearchWord {
+	var words []SearchWord
+	add := func(start, end int) {
+		if word := FoldText(text[start:end]); word != "" {
+			words = append(words, SearchWord{Word: word, Start: start, End: end})
+		}
+	}
+
+	start := -1
+	for i, r := range text {
+		// Combining marks belong to the letter before them.
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+		if inWord && start < 0 {
+			start = i
+		}
+		if !inWord && start >= 0 {
+			add(start, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		add(start, len(text))
+	}
+	return words
+}
+
+// foldedLetters are the letters whose base letter is not found by
+// decomposing them.
+var foldedLetters = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe", 'ø': "o", 'Ø': "o",
+	'ł': "l", 'Ł': "l", 'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d", 'þ': "th", 'Þ': "th", 'ı': "i",
+}
+
+// FoldText lowercases text and drops its diacritics, as the Postgres
+// unaccent dictionary does, so that "Camús" is found as "camus".
+func FoldText(text string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(text) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if folded, ok := foldedLetters[r]; ok {
+			b.WriteString(folded)
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// NormalizeText puts text in Unicode normalization form C, so that a letter
+// written with a combining accent is stored the same as its precomposed
+// form.
+func NormalizeText(text string) string {
+	return norm.NFC.String(text)
+}
+
+// SearchWords returns the folded words of text.
+func SearchWords(text string) []string {
+	split := SplitSearchWords(text)
+	words := make([]string, 0, len(split))
+	for _, word := range split {
+		words = append(words, word.Word)
+	}
+	return words
+}
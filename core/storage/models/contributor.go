@@ -0,0 +1,117 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxContributors          = 50
+	maxContributorNameLength = 255
+)
+
+// ContributorRole is the part a contributor had in a book.
+type ContributorRole string
+
+const (
+	RoleAuthor      ContributorRole = "author"
+	RoleEditor      ContributorRole = "editor"
+	RoleTranslator  ContributorRole = "translator"
+	RoleIllustrator ContributorRole = "illustrator"
+)
+
+// IsValid reports whether the role is one the catalog knows.
+func (r ContributorRole) IsValid() bool {
+	switch r {
+	case RoleAuthor, RoleEditor, RoleTranslator, RoleIllustrator:
+		return true
+	}
+	return false
+}
+
+// ParseContributorRole reads a role, ignoring case and surrounding spaces.
+func ParseContributorRole(value string) (ContributorRole, error) {
+	role := ContributorRole(strings.ToLower(strings.TrimSpace(value)))
+	if !role.IsValid() {
+		return "", errInvalidRole(value)
+	}
+	return role, nil
+}
+
+func errInvalidRole(value string) error {
+	return fmt.Errorf("invalid contributor role %q: use author, editor, translator or illustrator", value)
+}
+
+// Contributor is a person credited on a book. Co-authors are each listed
+// as an author. AuthorID links the contributor to its authority record,
+// when it is known; Name stays the form printed in the book.
+type Contributor struct {
+	Name     string          `json:"name"`
+	Role     ContributorRole `json:"role"`
+	AuthorID string          `json:"author_id,omitempty"`
+}
+
+// PrimaryAuthor is the name of the first author credited. Books credited to
+// no author, such as edited anthologies, fall back to their first
+// contributor.
+func PrimaryAuthor(contributors []Contributor) string {
+	for _, contributor := range contributors {
+		if contributor.Role == RoleAuthor {
+			return strings.TrimSpace(contributor.Name)
+		}
+	}
+	if len(contributors) > 0 {
+		return strings.TrimSpace(contributors[0].Name)
+	}
+	return ""
+}
+
+func validateContributors(contributors []Contributor) error {
+	if len(contributors) > maxContributors {
+		return fmt.Errorf("invalid contributors: at most %d are allowed", maxContributors)
+	}
+
+	seen := make(map[Contributor]bool, len(contributors))
+	for _, contributor := range contributors {
+		if contributor.Name == "" {
+			return errors.New("invalid contributor: names cannot be empty")
+		}
+		if utf8.RuneCountInString(contributor.Name) > maxContributorNameLength {
+			return fmt.Errorf("invalid contributor %q: longer than %d characters", contributor.Name, maxContributorNameLength)
+		}
+		if !contributor.Role.IsValid() {
+			return errInvalidRole(string(contributor.Role))
+		}
+
+		key := Contributor{Name: strings.ToLower(contributor.Name), Role: contributor.Role}
+		if seen[key] {
+			return fmt.Errorf("invalid contributors: %s is listed twice as %s", contributor.Name, contributor.Role)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// normalizeContributors trims names and roles, puts names in normalization
+// form C and credits contributors given without a role as authors.
+func normalizeContributors(contributors []Contributor) []Contributor {
+	if contributors == nil {
+		return nil
+	}
+
+	normalized := make([]Contributor, 0, len(contributors))
+	for _, contributor := range contributors {
+		role := ContributorRole(strings.ToLower(strings.TrimSpace(string(contributor.Role))))
+		if role == "" {
+			role = RoleAuthor
+		}
+		normalized = append(normalized, Contributor{
+			Name:     NormalizeText(strings.TrimSpace(contributor.Name)),
+			Role:     role,
+			AuthorID: strings.TrimSpace(contributor.AuthorID),
+		})
+	}
+	return normalized
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type CopyCondition string
+
+const (
+	CopyConditionNew     CopyCondition = "new"
+	CopyConditionGood    CopyCondition = "good"
+	CopyConditionFair    CopyCondition = "fair"
+	CopyConditionPoor    CopyCondition = "poor"
+	CopyConditionDamaged CopyCondition = "damaged"
+)
+
+// CopyStatus tells whether a copy can be lent. Lost and withdrawn copies
+// keep their record and rental history but are out of circulation. A copy in
+// transit is on its way between branches and cannot be lent until received.
+type CopyStatus string
+
+const (
+	CopyStatusCirculating CopyStatus = "circulating"
+	CopyStatusInTransit   CopyStatus = "in_transit"
+	CopyStatusLost        CopyStatus = "lost"
+	CopyStatusWithdrawn   CopyStatus = "withdrawn"
+)
+
+// Copy is a physical item of a book. A book can have several copies, each
+// with its own barcode. A copy belongs to its home branch and sits at its
+// current branch; a copy without a branch is treated as being at every
+// branch, as all copies were before the library had branches.
+type Copy struct {
+	ID              string        `json:"id"`
+	ISBN            string        `json:"isbn"`
+	Barcode         string        `json:"barcode"`
+	ShelfLocation   string        `json:"shelf_location"`
+	Condition       CopyCondition `json:"condition"`
+	Status          CopyStatus    `json:"status"`
+	HomeBranchID    string        `json:"home_branch_id,omitempty"`
+	CurrentBranchID string        `json:"current_branch_id,omitempty"`
+	AddedAt         time.Time     `json:"added_at"`
+}
+
+func NewCopy(isbn, barcode, shelfLocation string, condition CopyCondition) (*Copy, error) {
+	if condition == "" {
+		condition = CopyConditionGood
+	}
+
+	c := &Copy{
+		ISBN:          isbn,
+		Barcode:       strings.TrimSpace(barcode),
+		ShelfLocation: strings.TrimSpace(shelfLocation),
+		Condition:     condition,
+		Status:        CopyStatusCirculating,
+		AddedAt:       time.Now(),
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Copy) Validate() error {
+	if strings.TrimSpace(c.ISBN) == "" {
+		return errors.New("ISBN cannot be empty")
+	}
+	if c.Barcode == "" {
+		return errors.New("barcode cannot be empty")
+	}
+	if !c.Condition.IsValid() {
+		return fmt.Errorf("invalid copy condition %q", c.Condition)
+	}
+	if !c.Status.IsValid() {
+		return fmt.Errorf("invalid copy status %q", c.Status)
+	}
+	return nil
+}
+
+// InCirculation reports whether the copy can be lent.
+func (c *Copy) InCirculation() bool {
+	return c.Status == CopyStatusCirculating
+}
+
+func (c *Copy) InTransit() bool {
+	return c.Status == CopyStatusInTransit
+}
+
+// IsAt reports whether the copy is at the given branch.
+func (c *Copy) IsAt(branchID string) bool {
+	return c.CurrentBranchID == "" || c.CurrentBranchID == branchID
+}
+
+// AwayFromHome reports whether the copy is at a branch other than the one it
+// belongs to.
+func (c *Copy) AwayFromHome() bool {
+	return c.HomeBranchID != "" && c.CurrentBranchID != "" && c.CurrentBranchID != c.HomeBranchID
+}
+
+func (c CopyCondition) IsValid() bool {
+	switch c {
+	case CopyConditionNew, CopyConditionGood, CopyConditionFair, CopyConditionPoor, CopyConditionDamaged:
+		return true
+	}
+	return false
+}
+
+func (s CopyStatus) IsValid() bool {
+	switch s {
+	case CopyStatusCirculating, CopyStatusInTransit, CopyStatusLost, CopyStatusWithdrawn:
+		return true
+	}
+	return false
+}
+
+// DefaultBarcode is the barcode given to the nth copy of a book when none is
+// supplied.
+func DefaultBarcode(isbn string, n int) string {
+	return fmt.Sprintf("%s-%d", isbn, n)
+}
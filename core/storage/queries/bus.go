@@ -0,0 +1,76 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// QueryHandler is the signature for all query handlers
+type QueryHandler interface {
+	Handle(ctx context.Context, query interface{}) (interface{}, error)
+}
+
+// QueryBus is the interface for dispatching queries
+type QueryBus interface {
+	Dispatch(ctx context.Context, query interface{}) (interface{}, error)
+}
+
+// DefaultQueryBus is a simple implementation of QueryBus
+type DefaultQueryBus struct {
+	handlers map[string]QueryHandler
+}
+
+// NewQueryBus creates a new query bus
+func NewQueryBus() *DefaultQueryBus {
+	return &DefaultQueryBus{
+		handlers: make(map[string]QueryHandler),
+	}
+}
+
+// RegisterHandler registers a query handler for a specific query type
+func (b *DefaultQueryBus) RegisterHandler(queryType string, handler QueryHandler) {
+	b.handlers[queryType] = handler
+}
+
+// Dispatch sends a query to its appropriate handler and returns its result
+func (b *DefaultQueryBus) Dispatch(ctx context.Context, query interface{}) (interface{}, error) {
+	if query == nil {
+		return nil, ErrInvalidQueryType
+	}
+
+	handler, exists := b.handlers[getQueryType(query)]
+	if !exists {
+		return nil, ErrHandlerNotFound
+	}
+	return handler.Handle(ctx, query)
+}
+
+// Ask dispatches a query and returns its result as T, so callers do not
+// have to assert the type themselves.
+func Ask[T any](ctx context.Context, bus QueryBus, query interface{}) (T, error) {
+	var zero T
+
+	result, err := bus.Dispatch(ctx, query)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := result.(T)
+	if !ok {
+		return zero, ErrUnexpectedResult
+	}
+	return typed, nil
+}
+
+// getQueryType returns the name of the query type
+func getQueryType(query interface{}) string {
+	return reflect.TypeOf(query).String()
+}
+
+// Error definitions
+var (
+	ErrHandlerNotFound  = errors.New("handler not found for query")
+	ErrInvalidQueryType = errors.New("invalid query type")
+	ErrUnexpectedResult = errors.New("query returned an unexpected result type")
+)
@@ -0,0 +1,100 @@
+package queries
+
+import (
+	"books/core/storage/models"
+	"books/core/storage/repositories"
+	"books/core/storage/repositories/interfaces"
+	"context"
+	"errors"
+	"testing"
+)
+
+func newTestBus(t *testing.T) *DefaultQueryBus {
+	t.Helper()
+	books := repositories.NewBookStorageInMemoryRepository()
+	copies := repositories.NewCopyStorageInMemoryRepository()
+
+	book, err := models.NewBook("9783161484100", "Test Book", "Test Author")
+	if err != nil {
+		t.Fatalf("failed to create book: %v", err)
+	}
+	if err := books.Save(context.Background(), book); err != nil {
+		t.Fatalf("failed to save book: %v", err)
+	}
+
+	bus := NewQueryBus()
+	NewQueries(books, copies).Register(bus)
+	return bus
+}
+
+func TestQueryBus_Ask(t *testing.T) {
+	bus := newTestBus(t)
+	ctx := context.Background()
+
+	book, err := Ask[*models.Book](ctx, bus, GetBookByISBNQuery{ISBN: "9783161484100"})
+	if err != nil {
+		t.Fatalf("expected book, got %v", err)
+	}
+	if book.Title != "Test Book" {
+		t.Errorf("unexpected book %+v", book)
+	}
+
+	page, err := Ask[*models.BookPage](ctx, bus, ListBooksQuery{})
+	if err != nil || len(page.Books) != 1 || page.NextCursor != "" {
+		t.Errorf("expected a single page of 1 book, got %+v (%v)", page, err)
+	}
+
+	if _, err := Ask[*models.BookPage](ctx, bus, ListBooksQuery{Filter: models.BookFilter{YearFrom: 2000, YearTo: 1990}}); err == nil {
+		t.Errorf("expected an inverted year range to be refused")
+	}
+
+	found, err := Ask[*models.BookSearchResult](ctx, bus, SearchBooksQuery{Search: models.BookSearch{Query: "test BOO*"}})
+	if err != nil || found.Total != 1 || found.Hits[0].TitleHighlight != "<mark>Test</mark> <mark>Book</mark>" {
+		t.Errorf("expected the book to be found, got %+v (%v)", found, err)
+	}
+
+	books, err := Ask[[]*models.Book](ctx, bus, FindBooksByContributorQuery{Name: "test AUTHOR", Role: "Author"})
+	if err != nil || len(books) != 1 {
+		t.Errorf("expected 1 book by its author, got %d (%v)", len(books), err)
+	}
+
+	books, err = Ask[[]*models.Book](ctx, bus, FindBooksByContributorQuery{Name: "Test Author", Role: models.RoleEditor})
+	if err != nil || len(books) != 0 {
+		t.Errorf("expected no book edited by the author, got %d (%v)", len(books), err)
+	}
+
+	if _, err := Ask[[]*models.Book](ctx, bus, FindBooksByContributorQuery{Name: " "}); err == nil {
+		t.Errorf("expected an empty contributor name to be refused")
+	}
+
+	_, err = Ask[*models.Book](ctx, bus, GetBookQuery{ID: "9780306406157"})
+	if !errors.Is(err, interfaces.ErrBookNotFound) {
+		t.Errorf("expected ErrBookNotFound, got %v", err)
+	}
+
+	_, err = Ask[[]*models.Copy](ctx, bus, ListBookCopiesQuery{ISBN: "9780306406157"})
+	if !errors.Is(err, interfaces.ErrBookNotFound) {
+		t.Errorf("expected copies of an unknown book to be refused, got %v", err)
+	}
+}
+
+func TestQueryBus_Errors(t *testing.T) {
+	bus := newTestBus(t)
+	ctx := context.Background()
+
+	if _, err := bus.Dispatch(ctx, struct{}{}); !errors.Is(err, ErrHandlerNotFound) {
+		t.Errorf("expected ErrHandlerNotFound, got %v", err)
+	}
+	if _, err := bus.Dispatch(ctx, nil); !errors.Is(err, ErrInvalidQueryType) {
+		t.Errorf("expected ErrInvalidQueryType, got %v", err)
+	}
+
+	_, err := Ask[*models.Copy](ctx, bus, GetBookByISBNQuery{ISBN: "9783161484100"})
+	if !errors.Is(err, ErrUnexpectedResult) {
+		t.Errorf("expected ErrUnexpectedResult, got %v", err)
+	}
+
+	if _, err := GetBookHandler(nil).Handle(ctx, ListBooksQuery{}); !errors.Is(err, ErrInvalidQueryType) {
+		t.Errorf("expected ErrInvalidQueryType, got %v", err)
+	}
+}
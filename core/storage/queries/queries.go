@@ -2,9 +2,13 @@ package queries
 
 import (
 	"books/core/storage/models"
+	"books/core/storage/repositories/interfaces"
 	"context"
+	"errors"
+	"strings"
 )
 
+// GetBookQuery looks a book up by its identifier, which is its ISBN.
 type GetBookQuery struct {
 	ID string
 }
@@ -13,10 +17,177 @@ type GetBookByISBNQuery struct {
 	ISBN string
 }
 
+// ListBooksQuery returns a page of the catalog.
+type ListBooksQuery struct {
+	Filter models.BookFilter
+}
+
+// SearchBooksQuery looks books up by the words of their title, author and
+// description.
+type SearchBooksQuery struct {
+	Search models.BookSearch
+}
+
+// FindBooksByContributorQuery searches the books by the name of any of their
+// contributors, optionally only those credited in the given role.
+type FindBooksByContributorQuery struct {
+	Name string
+	Role models.ContributorRole
+}
+
+// FindBooksByAuthorQuery returns the books crediting an author record.
+type FindBooksByAuthorQuery struct {
+	AuthorID string
+}
+
+type GetCopyQuery struct {
+	ID string
+}
+
+// ListBookCopiesQuery returns the copies of a book, failing when the book
+// is not in the catalog.
+type ListBookCopiesQuery struct {
+	ISBN string
+}
+
 type GetBookHandler func(ctx context.Context, query GetBookQuery) (*models.Book, error)
 type GetBookByISBNHandler func(ctx context.Context, query GetBookByISBNQuery) (*models.Book, error)
+type ListBooksHandler func(ctx context.Context, query ListBooksQuery) (*models.BookPage, error)
+type SearchBooksHandler func(ctx context.Context, query SearchBooksQuery) (*models.BookSearchResult, error)
+type FindBooksByContributorHandler func(ctx context.Context, query FindBooksByContributorQuery) ([]*models.Book, error)
+type FindBooksByAuthorHandler func(ctx context.Context, query FindBooksByAuthorQuery) ([]*models.Book, error)
+type GetCopyHandler func(ctx context.Context, query GetCopyQuery) (*models.Copy, error)
+type ListBookCopiesHandler func(ctx context.Context, query ListBookCopiesQuery) ([]*models.Copy, error)
 
 type Queries struct {
-	GetBook       GetBookHandler
-	GetBookByISBN GetBookByISBNHandler
+	GetBook        GetBookHandler
+	GetBookByISBN  GetBookByISBNHandler
+	ListBooks      ListBooksHandler
+	SearchBooks    SearchBooksHandler
+	GetCopy        GetCopyHandler
+	ListBookCopies ListBookCopiesHandler
+
+	FindBooksByContributor FindBooksByContributorHandler
+	FindBooksByAuthor      FindBooksByAuthorHandler
+}
+
+// NewQueries builds the catalog queries on top of the book and copy repositories.
+func NewQueries(books interfaces.BookRepository, copies interfaces.CopyRepository) Queries {
+	return Queries{
+		GetBook: func(ctx context.Context, query GetBookQuery) (*models.Book, error) {
+			return books.FindByISBN(ctx, query.ID)
+		},
+		GetBookByISBN: func(ctx context.Context, query GetBookByISBNQuery) (*models.Book, error) {
+			return books.FindByISBN(ctx, query.ISBN)
+		},
+		ListBooks: func(ctx context.Context, query ListBooksQuery) (*models.BookPage, error) {
+			return books.ListBooks(ctx, query.Filter)
+		},
+		SearchBooks: func(ctx context.Context, query SearchBooksQuery) (*models.BookSearchResult, error) {
+			return books.Search(ctx, query.Search)
+		},
+		GetCopy: func(ctx context.Context, query GetCopyQuery) (*models.Copy, error) {
+			return copies.FindCopyByID(ctx, query.ID)
+		},
+		ListBookCopies: func(ctx context.Context, query ListBookCopiesQuery) ([]*models.Copy, error) {
+			if _, err := books.FindByISBN(ctx, query.ISBN); err != nil {
+				return nil, err
+			}
+			return copies.FindCopiesByISBN(ctx, query.ISBN)
+		},
+		FindBooksByContributor: func(ctx context.Context, query FindBooksByContributorQuery) ([]*models.Book, error) {
+			name := strings.TrimSpace(query.Name)
+			if name == "" {
+				return nil, errors.New("contributor name cannot be empty")
+			}
+			role := query.Role
+			if role != "" {
+				parsed, err := models.ParseContributorRole(string(role))
+				if err != nil {
+					return nil, err
+				}
+				role = parsed
+			}
+			return books.FindByContributor(ctx, name, role)
+		},
+		FindBooksByAuthor: func(ctx context.Context, query FindBooksByAuthorQuery) ([]*models.Book, error) {
+			return books.FindByAuthorID(ctx, query.AuthorID)
+		},
+	}
+}
+
+// Register adds every catalog query handler to the bus.
+func (q Queries) Register(bus *DefaultQueryBus) {
+	bus.RegisterHandler("queries.GetBookQuery", q.GetBook)
+	bus.RegisterHandler("queries.GetBookByISBNQuery", q.GetBookByISBN)
+	bus.RegisterHandler("queries.ListBooksQuery", q.ListBooks)
+	bus.RegisterHandler("queries.SearchBooksQuery", q.SearchBooks)
+	bus.RegisterHandler("queries.GetCopyQuery", q.GetCopy)
+	bus.RegisterHandler("queries.ListBookCopiesQuery", q.ListBookCopies)
+	bus.RegisterHandler("queries.FindBooksByContributorQuery", q.FindBooksByContributor)
+	bus.RegisterHandler("queries.FindBooksByAuthorQuery", q.FindBooksByAuthor)
+}
+
+func (h GetBookHandler) Handle(ctx context.Context, query interface{}) (interface{}, error) {
+	q, ok := query.(GetBookQuery)
+	if !ok {
+		return nil, ErrInvalidQueryType
+	}
+	return h(ctx, q)
+}
+
+func (h GetBookByISBNHandler) Handle(ctx context.Context, query interface{}) (interface{}, error) {
+	q, ok := query.(GetBookByISBNQuery)
+	if !ok {
+		return nil, ErrInvalidQueryType
+	}
+	return h(ctx, q)
+}
+
+func (h ListBooksHandler) Handle(ctx context.Context, query interface{}) (interface{}, error) {
+	q, ok := query.(ListBooksQuery)
+	if !ok {
+		return nil, ErrInvalidQueryType
+	}
+	return h(ctx, q)
+}
+
+func (h SearchBooksHandler) Handle(ctx context.Context, query interface{}) (interface{}, error) {
+	q, ok := query.(SearchBooksQuery)
+	if !ok {
+		return nil, ErrInvalidQueryType
+	}
+	return h(ctx, q)
+}
+
+func (h GetCopyHandler) Handle(ctx context.Context, query interface{}) (interface{}, error) {
+	q, ok := query.(GetCopyQuery)
+	if !ok {
+		return nil, ErrInvalidQueryType
+	}
+	return h(ctx, q)
+}
+
+func (h ListBookCopiesHandler) Handle(ctx context.Context, query interface{}) (interface{}, error) {
+	q, ok := query.(ListBookCopiesQuery)
+	if !ok {
+		return nil, ErrInvalidQueryType
+	}
+	return h(ctx, q)
+}
+
+func (h FindBooksByContributorHandler) Handle(ctx context.Context, query interface{}) (interface{}, error) {
+	q, ok := query.(FindBooksByContributorQuery)
+	if !ok {
+		return nil, ErrInvalidQueryType
+	}
+	return h(ctx, q)
+}
+
+func (h FindBooksByAuthorHandler) Handle(ctx context.Context, query interface{}) (interface{}, error) {
+	q, ok := query.(FindBooksByAuthorQuery)
+	if !ok {
+		return nil, ErrInvalidQueryType
+	}
+	return h(ctx, q)
 }
@@ -0,0 +1,216 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"unicode/utf8"
+
+	"books/core/storage/models"
+)
+
+const (
+	// trigramThreshold is the similarity the pg_trgm % operator requires
+	// by default.
+	trigramThreshold = 0.3
+	// typoSimilarity is how similar a title or author word must be to a
+	// searched word to be found in its place.
+	typoSimilarity = 0.4
+	// maxSimilarWords is how many similar words each searched word finds.
+	maxSimilarWords = 3
+	// minTypoWordLength keeps short words, which share too few trigrams to
+	// tell typos from other words, to exact matches.
+	minTypoWordLength = 4
+)
+
+// similarWord is a title or author word with its trigram similarity to a
+// searched word.
+type similarWord struct {
+	word       string
+	similarity float32
+}
+
+// similarWordsFunc returns up to limit title and author words with a
+// trigram similarity of at least trigramThreshold to a word, from the most
+// similar.
+type similarWordsFunc func(ctx context.Context, word string, limit int) ([]similarWord, error)
+
+// searchTermsFunc runs a search for the given terms.
+type searchTermsFunc func(terms []models.SearchTerm) (*models.BookSearchResult, error)
+
+// typoTolerantSearch runs a search in which every word also matches the
+// title and author words similar to it. When the first page finds nothing,
+// it suggests the query made of the words most similar to the searched
+// ones, if that query finds books. Both repositories search this way.
+func typoTolerantSearch(ctx context.Context, search models.BookSearch, terms []models.SearchTerm, similar similarWordsFunc, run searchTermsFunc) (*models.BookSearchResult, error) {
+	tolerant, err := withSimilarWords(ctx, terms, similar)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := run(tolerant)
+	if err != nil || result.Total > 0 || search.Offset > 0 {
+		return result, err
+	}
+
+	suggested, changed, err := suggestTerms(ctx, terms, similar)
+	if err != nil || !changed {
+		return result, err
+	}
+	found, err := run(suggested)
+	if err != nil {
+		return nil, err
+	}
+	if found.Total > 0 {
+		result.Suggestion = models.FormatSearchTerms(suggested)
+	}
+	return result, nil
+}
+
+// withSimilarWords fills in the words similar enough to each searched word
+// to be found in its place.
+func withSimilarWords(ctx context.Context, terms []models.SearchTerm, similar similarWordsFunc) ([]models.SearchTerm, error) {
+	tolerant := make([]models.SearchTerm, 0, len(terms))
+	for _, term := range terms {
+		term.Similar = make([][]string, len(term.Words))
+		for i, word := range term.Words {
+			if !typoTolerant(term, i) {
+				continue
+			}
+			found, err := similar(ctx, word, maxSimilarWords)
+			if err != nil {
+				return nil, err
+			}
+			for _, candidate := range found {
+				if candidate.similarity >= typoSimilarity && candidate.word != word {
+					term.Similar[i] = append(term.Similar[i], candidate.word)
+				}
+			}
+		}
+		tolerant = append(tolerant, term)
+	}
+	return tolerant, nil
+}
+
+// suggestTerms replaces every searched word with the title or author word
+// most similar to it, reporting whether any word was replaced.
+func suggestTerms(ctx context.Context, terms []models.SearchTerm, similar similarWordsFunc) ([]models.SearchTerm, bool, error) {
+	changed := false
+	suggested := make([]models.SearchTerm, 0, len(terms))
+	for _, term := range terms {
+		words := append([]string{}, term.Words...)
+		for i, word := range words {
+			if !typoTolerant(term, i) {
+				continue
+			}
+			found, err := similar(ctx, word, 1)
+			if err != nil {
+				return nil, false, err
+			}
+			if len(found) > 0 && found[0].word != word {
+				words[i] = found[0].word
+				changed = true
+			}
+		}
+		suggested = append(suggested, models.SearchTerm{Words: words, Prefix: term.Prefix})
+	}
+	return suggested, changed, nil
+}
+
+// typoTolerant reports whether the i-th word of a term may match similar
+// words; the word a prefix ends with is already loose.
+func typoTolerant(term models.SearchTerm, i int) bool {
+	if term.Prefix && i == len(term.Words)-1 {
+		return false
+	}
+	return utf8.RuneCountInString(term.Words[i]) >= minTypoWordLength
+}
+
+// facetCounter counts the hits of a search by the values of their facets.
+// Both repositories hand it their counts so the facets come out in the same
+// order.
+type facetCounter struct {
+	authors   map[string]int
+	languages map[string]int
+	decades   map[int]int
+	subjects  map[string]int
+	facets    models.BookFacets
+}
+
+func newFacetCounter() *facetCounter {
+	return &facetCounter{
+		authors:   make(map[string]int),
+		languages: make(map[string]int),
+		decades:   make(map[int]int),
+		subjects:  make(map[string]int),
+	}
+}
+
+// add counts a hit.
+func (c *facetCounter) add(book *models.Book, available bool) {
+	authors := make(map[string]bool)
+	for _, contributor := range book.Contributors {
+		if contributor.Role == models.RoleAuthor {
+			authors[contributor.Name] = true
+		}
+	}
+	for author := range authors {
+		c.authors[author]++
+	}
+
+	if book.Language != "" {
+		c.languages[book.Language]++
+	}
+	if book.PublicationYear > 0 {
+		c.decades[models.Decade(book.PublicationYear)]++
+	}
+
+	subjects := make(map[string]bool)
+	for _, subject := range book.Subjects {
+		subjects[subject] = true
+	}
+	for subject := range subjects {
+		c.subjects[subject]++
+	}
+
+	if available {
+		c.facets.Available++
+	} else {
+		c.facets.Unavailable++
+	}
+}
+
+// result orders the counts.
+func (c *facetCounter) result() models.BookFacets {
+	facets := c.facets
+	facets.Authors = mostFrequent(c.authors, models.MaxFacetValues)
+	facets.Languages = mostFrequent(c.languages, len(c.languages))
+	facets.Subjects = mostFrequent(c.subjects, models.MaxFacetValues)
+
+	facets.Decades = make([]models.DecadeCount, 0, len(c.decades))
+	for decade, count := range c.decades {
+		facets.Decades = append(facets.Decades, models.DecadeCount{Decade: decade, Count: count})
+	}
+	sort.Slice(facets.Decades, func(i, j int) bool {
+		return facets.Decades[i].Decade < facets.Decades[j].Decade
+	})
+	return facets
+}
+
+// mostFrequent returns up to limit values from the most frequent, ties in
+// the order of their bytes.
+func mostFrequent(counts map[string]int, limit int) []models.FacetCount {
+	values := make([]models.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, models.FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+	if len(values) > limit {
+		values = values[:limit]
+	}
+	return values
+}
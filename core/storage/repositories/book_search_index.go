@@ -0,0 +1,307 @@
+package repositories
+
+import (
+	"sort"
+	"strings"
+
+	"books/core/storage/models"
+)
+
+// Weights of a match in each field, the ones ts_rank gives the A, B and C
+// weights of the Postgres search vector.
+const (
+	titleWeight       = 1.0
+	authorWeight      = 0.4
+	descriptionWeight = 0.2
+)
+
+// Snippets are cut like ts_headline's MinWords and MaxWords.
+const (
+	snippetMinWords = 15
+	snippetMaxWords = 30
+)
+
+// searchIndex is an inverted index of the words of books, for the
+// in-memory repository. Like the Postgres search vector, the words of the
+// title, author and description are numbered in one run, so a phrase can
+// run from one field into the next.
+type searchIndex struct {
+	// postings lists where each word is, by the ISBN of the book.
+	postings  map[string]map[string][]int
+	documents map[string]searchDocument
+	// vocabulary counts the books using each word in their title or
+	// author, the words typos are matched against.
+	vocabulary map[string]int
+}
+
+// searchDocument is the words of a book in order, with where its author and
+// description start.
+type searchDocument struct {
+	words            []string
+	authorStart      int
+	descriptionStart int
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		postings:   make(map[string]map[string][]int),
+		documents:  make(map[string]searchDocument),
+		vocabulary: make(map[string]int),
+	}
+}
+
+// add indexes a book, replacing what was indexed for it before.
+func (x *searchIndex) add(book *models.Book) {
+	x.remove(book.ISBN)
+
+	document := searchDocument{words: models.SearchWords(book.Title)}
+	document.authorStart = len(document.words)
+	document.words = append(document.words, models.SearchWords(book.Author)...)
+	document.descriptionStart = len(document.words)
+	document.words = append(document.words, models.SearchWords(book.Description)...)
+
+	for position, word := range document.words {
+		if x.postings[word] == nil {
+			x.postings[word] = make(map[string][]int)
+		}
+		x.postings[word][book.ISBN] = append(x.postings[word][book.ISBN], position)
+	}
+	for word := range document.titleAndAuthorWords() {
+		x.vocabulary[word]++
+	}
+	x.documents[book.ISBN] = document
+}
+
+func (x *searchIndex) remove(isbn string) {
+	document, ok := x.documents[isbn]
+	if !ok {
+		return
+	}
+	for _, word := range document.words {
+		delete(x.postings[word], isbn)
+		if len(x.postings[word]) == 0 {
+			delete(x.postings, word)
+		}
+	}
+	for word := range document.titleAndAuthorWords() {
+		x.vocabulary[word]--
+		if x.vocabulary[word] == 0 {
+			delete(x.vocabulary, word)
+		}
+	}
+	delete(x.documents, isbn)
+}
+
+func (d searchDocument) titleAndAuthorWords() map[string]bool {
+	words := make(map[string]bool, d.descriptionStart)
+	for _, word := range d.words[:d.descriptionStart] {
+		words[word] = true
+	}
+	return words
+}
+
+// search scores the books every term matches, by the weight of the words
+// they match.
+func (x *searchIndex) search(terms []models.SearchTerm) map[string]float64 {
+	var scores map[string]float64
+	for _, term := range terms {
+		matches := x.match(term)
+		if scores == nil {
+			scores = matches
+			continue
+		}
+		for isbn, score := range scores {
+			if termScore, ok := matches[isbn]; ok {
+				scores[isbn] = score + termScore
+			} else {
+				delete(scores, isbn)
+			}
+		}
+	}
+	return scores
+}
+
+// match scores the books a term matches.
+func (x *searchIndex) match(term models.SearchTerm) map[string]float64 {
+	scores := make(map[string]float64)
+	firsts := x.words(term.Words[0], term.Prefix && len(term.Words) == 1)
+	if len(term.Similar) > 0 {
+		firsts = append(firsts, term.Similar[0]...)
+	}
+	for _, first := range firsts {
+		for isbn, positions := range x.postings[first] {
+			document := x.documents[isbn]
+			for _, position := range positions {
+				if document.matchesAt(position, term) {
+					scores[isbn] += document.weight(position, len(term.Words))
+				}
+			}
+		}
+	}
+	return scores
+}
+
+// words returns the indexed words a query word matches.
+func (x *searchIndex) words(word string, prefix bool) []string {
+	if !prefix {
+		return []string{word}
+	}
+	var words []string
+	for indexed := range x.postings {
+		if strings.HasPrefix(indexed, word) {
+			words = append(words, indexed)
+		}
+	}
+	return words
+}
+
+// matchesAt reports whether a term's words follow one another from the
+// position on.
+func (d searchDocument) matchesAt(position int, term models.SearchTerm) bool {
+	if position+len(term.Words) > len(d.words) {
+		return false
+	}
+	for i := range term.Words {
+		if !termWordMatches(term, i, d.words[position+i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// weight sums the weights of count words from the position on.
+func (d searchDocument) weight(position, count int) float64 {
+	total := 0.0
+	for p := position; p < position+count; p++ {
+		switch {
+		case p < d.authorStart:
+			total += titleWeight
+		case p < d.descriptionStart:
+			total += authorWeight
+		default:
+			total += descriptionWeight
+		}
+	}
+	return total
+}
+
+// termWordMatches reports whether a word matches the i-th word of a term,
+// or a word similar to it; the last word of a prefix term matches the words
+// it starts.
+func termWordMatches(term models.SearchTerm, i int, word string) bool {
+	if term.Prefix && i == len(term.Words)-1 {
+		return strings.HasPrefix(word, term.Words[i])
+	}
+	if word == term.Words[i] {
+		return true
+	}
+	if i < len(term.Similar) {
+		for _, similar := range term.Similar[i] {
+			if word == similar {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// similar returns up to limit title and author words similar to a word,
+// from the most similar, like the pg_trgm % operator finds them.
+func (x *searchIndex) similar(word string, limit int) []similarWord {
+	wordTrigrams := trigrams(word)
+	var found []similarWord
+	for indexed := range x.vocabulary {
+		if similarity := trigramSimilarity(wordTrigrams, trigrams(indexed)); similarity >= trigramThreshold {
+			found = append(found, similarWord{word: indexed, similarity: similarity})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].similarity != found[j].similarity {
+			return found[i].similarity > found[j].similarity
+		}
+		return found[i].word < found[j].word
+	})
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found
+}
+
+// trigrams returns the trigrams of a word as pg_trgm takes them: the runs
+// of three letters of the word padded with two spaces before and one after.
+func trigrams(word string) map[string]bool {
+	padded := []rune("  " + word + " ")
+	set := make(map[string]bool, len(padded))
+	for i := 0; i+3 <= len(padded); i++ {
+		set[string(padded[i:i+3])] = true
+	}
+	return set
+}
+
+// trigramSimilarity is the similarity pg_trgm computes: the trigrams two
+// words share over all of their trigrams.
+func trigramSimilarity(a, b map[string]bool) float32 {
+	shared := 0
+	for trigram := range a {
+		if b[trigram] {
+			shared++
+		}
+	}
+	return float32(shared) / float32(len(a)+len(b)-shared)
+}
+
+// highlight marks the words of a text that match any word of the terms,
+// as ts_headline does. Without maxWords the whole text is kept; otherwise
+// it is cut to the words around the first match, or to its first words
+// when nothing matches.
+func highlight(text string, terms []models.SearchTerm, maxWords int) string {
+	words := models.SplitSearchWords(text)
+	if len(words) == 0 {
+		return text
+	}
+
+	matched := make([]bool, len(words))
+	first := -1
+	for i, word := range words {
+		for _, term := range terms {
+			for j := range term.Words {
+				if termWordMatches(term, j, word.Word) {
+					matched[i] = true
+				}
+			}
+		}
+		if matched[i] && first < 0 {
+			first = i
+		}
+	}
+
+	start, end := 0, len(words)
+	if maxWords > 0 {
+		switch {
+		case first < 0:
+			end = min(snippetMinWords, len(words))
+		default:
+			start = max(0, min(first, len(words)-maxWords))
+			end = min(start+maxWords, len(words))
+		}
+	}
+
+	var b strings.Builder
+	from := 0
+	if start > 0 {
+		from = words[start].Start
+	}
+	for i := start; i < end; i++ {
+		b.WriteString(text[from:words[i].Start])
+		if matched[i] {
+			b.WriteString("<mark>" + text[words[i].Start:words[i].End] + "</mark>")
+		} else {
+			b.WriteString(text[words[i].Start:words[i].End])
+		}
+		from = words[i].End
+	}
+	if end == len(words) {
+		b.WriteString(text[from:])
+	}
+	return b.String()
+}
@@ -2,29 +2,54 @@ package repositories
 
 import (
 	"context"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	isbns "books/core/isbn"
 	"books/core/storage/models"
 	"books/core/storage/repositories/interfaces"
 )
 
 type BookStorageInMemoryRepository struct {
 	books []*models.Book
-	mutex sync.RWMutex
+	index *searchIndex
+	// availability tells which books can be borrowed now; without it none
+	// can.
+	availability interfaces.BookAvailability
+	mutex        sync.RWMutex
 }
 
 func NewBookStorageInMemoryRepository() *BookStorageInMemoryRepository {
 	return &BookStorageInMemoryRepository{
 		books: make([]*models.Book, 0),
+		index: newSearchIndex(),
 	}
 }
 
+// SetAvailability sets where searches learn which books can be borrowed,
+// which are the library's rentals. The rentals are made on top of the
+// books, so they are set once both exist.
+func (r *BookStorageInMemoryRepository) SetAvailability(availability interfaces.BookAvailability) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.availability = availability
+}
+
 func (r *BookStorageInMemoryRepository) Save(ctx context.Context, book *models.Book) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	r.index.add(book)
 	for i, existingBook := range r.books {
 		if existingBook.ISBN == book.ISBN {
+			if existingBook.IsDeleted() {
+				book = cloneBook(book)
+				book.DeletedAt = existingBook.DeletedAt
+			}
 			r.books[i] = book
 			return nil
 		}
@@ -38,37 +63,311 @@ func (r *BookStorageInMemoryRepository) FindAll(ctx context.Context) ([]*models.
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	result := make([]*models.Book, len(r.books))
-	copy(result, r.books)
+	result := make([]*models.Book, 0, len(r.books))
+	for _, book := range r.books {
+		if !book.IsDeleted() {
+			result = append(result, book)
+		}
+	}
 	return result, nil
 }
 
+func (r *BookStorageInMemoryRepository) ListBooks(ctx context.Context, filter models.BookFilter) (*models.BookPage, error) {
+	filter, err := filter.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	after, err := filter.After()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	matches := make([]*models.Book, 0)
+	for _, book := range r.books {
+		if matchesFilter(book, filter) {
+			matches = append(matches, book)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return compareSortValues(sortValues(matches[i], filter.Sort), sortValues(matches[j], filter.Sort), filter.Sort) < 0
+	})
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(matches), func(i int) bool {
+			return compareSortValues(sortValues(matches[i], filter.Sort), after, filter.Sort) > 0
+		})
+	}
+
+	page := &models.BookPage{Books: make([]*models.Book, 0, filter.Limit)}
+	for _, book := range matches[start:] {
+		if len(page.Books) == filter.Limit {
+			page.NextCursor = filter.CursorAfter(page.Books[len(page.Books)-1])
+			break
+		}
+		page.Books = append(page.Books, cloneBook(book))
+	}
+	return page, nil
+}
+
+// matchesFilter applies the filters of a listing the way the Postgres
+// repository does.
+func matchesFilter(book *models.Book, filter models.BookFilter) bool {
+	if book.IsDeleted() && !filter.IncludeDeleted {
+		return false
+	}
+	if !containsFold(book.Title, filter.Title) || !containsFold(book.Publisher, filter.Publisher) {
+		return false
+	}
+	if filter.Language != "" && book.Language != filter.Language {
+		return false
+	}
+	if filter.YearFrom != 0 && book.PublicationYear < filter.YearFrom {
+		return false
+	}
+	if filter.YearTo != 0 && (book.PublicationYear == 0 || book.PublicationYear > filter.YearTo) {
+		return false
+	}
+	if filter.Author == "" {
+		return true
+	}
+	for _, contributor := range book.Contributors {
+		if contributor.Role == models.RoleAuthor && containsFold(contributor.Name, filter.Author) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func sortValues(book *models.Book, sorts []models.BookSort) []interface{} {
+	values := make([]interface{}, 0, len(sorts))
+	for _, order := range sorts {
+		values = append(values, order.Field.SortValue(book))
+	}
+	return values
+}
+
+// compareSortValues orders two rows of sort values, field by field.
+func compareSortValues(a, b []interface{}, sorts []models.BookSort) int {
+	for i, order := range sorts {
+		result := 0
+		switch value := a[i].(type) {
+		case int:
+			result = value - b[i].(int)
+		case string:
+			result = strings.Compare(value, b[i].(string))
+		}
+		if result == 0 {
+			continue
+		}
+		if order.Descending {
+			return -result
+		}
+		return result
+	}
+	return 0
+}
+
+func (r *BookStorageInMemoryRepository) Search(ctx context.Context, search models.BookSearch) (*models.BookSearchResult, error) {
+	search, terms, err := search.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	availability := r.availability
+	r.mutex.RUnlock()
+
+	available := map[string]bool{}
+	if availability != nil {
+		if available, err = availability.AvailableISBNs(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	similar := func(ctx context.Context, word string, limit int) ([]similarWord, error) {
+		return r.index.similar(word, limit), nil
+	}
+	return typoTolerantSearch(ctx, search, terms, similar, func(terms []models.SearchTerm) (*models.BookSearchResult, error) {
+		return r.search(search, terms, available), nil
+	})
+}
+
+// search finds the books matching the terms and the filters of the search,
+// counting their facets. The caller holds the lock.
+func (r *BookStorageInMemoryRepository) search(search models.BookSearch, terms []models.SearchTerm, available map[string]bool) *models.BookSearchResult {
+	scores := r.index.search(terms)
+	matches := make([]*models.Book, 0, len(scores))
+	facets := newFacetCounter()
+	for _, book := range r.books {
+		if _, ok := scores[book.ISBN]; ok && !book.IsDeleted() && matchesSearchFilters(book, search, available[book.ISBN]) {
+			matches = append(matches, book)
+			facets.add(book, available[book.ISBN])
+		}
+	}
+	// Ties are ordered like the Postgres search orders them.
+	sort.Slice(matches, func(i, j int) bool {
+		if scores[matches[i].ISBN] != scores[matches[j].ISBN] {
+			return scores[matches[i].ISBN] > scores[matches[j].ISBN]
+		}
+		return compareSortValues(sortValues(matches[i], titleOrder), sortValues(matches[j], titleOrder), titleOrder) < 0
+	})
+
+	result := &models.BookSearchResult{Hits: []models.BookHit{}, Total: len(matches), Facets: facets.result()}
+	if search.Offset >= len(matches) {
+		return result
+	}
+	for _, book := range matches[search.Offset:min(search.Offset+search.Limit, len(matches))] {
+		result.Hits = append(result.Hits, models.BookHit{
+			Book:           cloneBook(book),
+			TitleHighlight: highlight(book.Title, terms, 0),
+			Snippet:        highlight(book.Description, terms, snippetMaxWords),
+		})
+	}
+	return result
+}
+
+// matchesSearchFilters applies the filters of a search the way the Postgres
+// repository does.
+func matchesSearchFilters(book *models.Book, search models.BookSearch, available bool) bool {
+	if search.Language != "" && book.Language != search.Language {
+		return false
+	}
+	if search.Decade != 0 && (book.PublicationYear == 0 || models.Decade(book.PublicationYear) != search.Decade) {
+		return false
+	}
+	if search.Available && !available {
+		return false
+	}
+	if search.Subject != "" && !slices.Contains(book.Subjects, search.Subject) {
+		return false
+	}
+	if search.Author == "" {
+		return true
+	}
+	for _, contributor := range book.Contributors {
+		if contributor.Role == models.RoleAuthor && contributor.Name == search.Author {
+			return true
+		}
+	}
+	return false
+}
+
+// titleOrder is the default order of listings.
+var titleOrder = []models.BookSort{{Field: models.SortByTitle}, {Field: models.SortByISBN}}
+
 func (r *BookStorageInMemoryRepository) FindByISBN(ctx context.Context, isbn string) (*models.Book, error) {
+	isbn = isbns.Key(isbn)
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, book := range r.books {
+		if book.ISBN == isbn && !book.IsDeleted() {
+			return cloneBook(book), nil
+		}
+	}
+
+	return nil, interfaces.ErrBookNotFound
+}
+
+func (r *BookStorageInMemoryRepository) FindDeletedByISBN(ctx context.Context, isbn string) (*models.Book, error) {
+	isbn = isbns.Key(isbn)
+
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
 	for _, book := range r.books {
-		if book.ISBN == isbn {
-			return &models.Book{
-				ISBN:        book.ISBN,
-				Title:       book.Title,
-				Author:      book.Author,
-				PublishedAt: book.PublishedAt,
-			}, nil
+		if book.ISBN == isbn && book.IsDeleted() {
+			return cloneBook(book), nil
 		}
 	}
 
 	return nil, interfaces.ErrBookNotFound
 }
 
-func (r *BookStorageInMemoryRepository) Delete(ctx context.Context, isbn string) error {
+func (r *BookStorageInMemoryRepository) FindByContributor(ctx context.Context, name string, role models.ContributorRole) ([]*models.Book, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	name = strings.ToLower(name)
+	result := make([]*models.Book, 0)
+	for _, book := range r.books {
+		if book.IsDeleted() {
+			continue
+		}
+		for _, contributor := range book.Contributors {
+			if (role == "" || contributor.Role == role) && strings.Contains(strings.ToLower(contributor.Name), name) {
+				result = append(result, cloneBook(book))
+				break
+			}
+		}
+	}
+
+	sortByTitle(result)
+	return result, nil
+}
+
+func (r *BookStorageInMemoryRepository) FindByAuthorID(ctx context.Context, authorID string) ([]*models.Book, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.Book, 0)
+	for _, book := range r.books {
+		if book.IsDeleted() {
+			continue
+		}
+		for _, contributor := range book.Contributors {
+			if contributor.AuthorID == authorID {
+				result = append(result, cloneBook(book))
+				break
+			}
+		}
+	}
+
+	sortByTitle(result)
+	return result, nil
+}
+
+// sortByTitle orders books by title, matching the Postgres ordering.
+func sortByTitle(books []*models.Book) {
+	sort.Slice(books, func(i, j int) bool {
+		if books[i].Title != books[j].Title {
+			return books[i].Title < books[j].Title
+		}
+		return books[i].ISBN < books[j].ISBN
+	})
+}
+
+func (r *BookStorageInMemoryRepository) Delete(ctx context.Context, isbn string, at time.Time) error {
+	return r.setDeletedAt(isbns.Key(isbn), false, &at)
+}
+
+func (r *BookStorageInMemoryRepository) Restore(ctx context.Context, isbn string) error {
+	return r.setDeletedAt(isbns.Key(isbn), true, nil)
+}
+
+// setDeletedAt replaces the book with a copy deleted at the given time, or
+// restored without one, when the book is deleted as expected.
+func (r *BookStorageInMemoryRepository) setDeletedAt(isbn string, deleted bool, at *time.Time) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
 	for i, book := range r.books {
-		if book.ISBN == isbn {
-			r.books[i] = r.books[len(r.books)-1]
-			r.books = r.books[:len(r.books)-1]
+		if book.ISBN == isbn && book.IsDeleted() == deleted {
+			changed := cloneBook(book)
+			changed.DeletedAt = at
+			r.books[i] = changed
 			return nil
 		}
 	}
@@ -76,4 +375,49 @@ func (r *BookStorageInMemoryRepository) Delete(ctx context.Context, isbn string)
 	return interfaces.ErrBookNotFound
 }
 
-var _ interfaces.BookRepository = (*BookStorageInMemoryRepository)(nil)
\ No newline at end of file
+func (r *BookStorageInMemoryRepository) ListDeletedBefore(ctx context.Context, before time.Time) ([]string, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	deleted := make([]string, 0)
+	for _, book := range r.books {
+		if book.IsDeleted() && book.DeletedAt.Before(before) {
+			deleted = append(deleted, book.ISBN)
+		}
+	}
+	// In ISBN order, matching the Postgres ordering.
+	sort.Strings(deleted)
+	return deleted, nil
+}
+
+// Purge removes a deleted book. Its copies are kept apart, in the copy
+// repository.
+func (r *BookStorageInMemoryRepository) Purge(ctx context.Context, isbn string) error {
+	isbn = isbns.Key(isbn)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, book := range r.books {
+		if book.ISBN == isbn && book.IsDeleted() {
+			r.books = append(r.books[:i], r.books[i+1:]...)
+			r.index.remove(isbn)
+			return nil
+		}
+	}
+
+	return interfaces.ErrBookNotFound
+}
+
+func cloneBook(book *models.Book) *models.Book {
+	return &models.Book{
+		ISBN:        book.ISBN,
+		Title:       book.Title,
+		Author:      book.Author,
+		Category:    book.Category,
+		DeletedAt:   book.DeletedAt,
+		BookDetails: book.BookDetails.Clone(),
+	}
+}
+
+var _ interfaces.BookRepository = (*BookStorageInMemoryRepository)(nil)
@@ -4,11 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	isbns "books/core/isbn"
 	"books/core/storage/models"
 	"books/core/storage/repositories/interfaces"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type BookStoragePostgresRepository struct {
@@ -21,62 +25,481 @@ func NewBookStoragePostgresRepository(db *sql.DB) *BookStoragePostgresRepository
 	}
 }
 
+// bookColumns lists the columns read by scanBook, in order.
+const bookColumns = `isbn, title, author, category, subtitle, publisher, published_at, publication_year,
+	edition, language, page_count, description, subjects, deleted_at`
+
 func (r *BookStoragePostgresRepository) Save(ctx context.Context, book *models.Book) error {
 	query := `
-		INSERT INTO books (isbn, title, author, published_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO books (isbn, title, author, category, subtitle, publisher, published_at, publication_year,
+			edition, language, page_count, description, subjects)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		ON CONFLICT (isbn) DO UPDATE
-		SET title = $2, author = $3, published_at = $4
+		SET title = $2, author = $3, category = $4, subtitle = $5, publisher = $6, published_at = $7,
+			publication_year = $8, edition = $9, language = $10, page_count = $11, description = $12,
+			subjects = $13
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	category := book.Category
+	if category == "" {
+		category = models.DefaultCategory
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, query,
 		book.ISBN,
 		book.Title,
 		book.Author,
+		category,
+		book.Subtitle,
+		book.Publisher,
 		book.PublishedAt,
+		book.PublicationYear,
+		book.Edition,
+		book.Language,
+		book.PageCount,
+		book.Description,
+		pq.Array(subjects(book.Subjects)),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save book: %w", err)
 	}
+
+	if err := saveContributors(ctx, tx, book); err != nil {
+		return err
+	}
+
+	if err := saveSearchWords(ctx, tx, book); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit book: %w", err)
+	}
+	return nil
+}
+
+// saveContributors replaces the credits of a book, keeping their order.
+func saveContributors(ctx context.Context, tx *sql.Tx, book *models.Book) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM book_contributors WHERE isbn = $1`, book.ISBN); err != nil {
+		return fmt.Errorf("failed to clear contributors: %w", err)
+	}
+
+	query := `
+		INSERT INTO book_contributors (isbn, position, name, role, author_id)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid)
+	`
+	for position, contributor := range book.Contributors {
+		if _, err := tx.ExecContext(ctx, query, book.ISBN, position, contributor.Name, contributor.Role, contributor.AuthorID); err != nil {
+			return fmt.Errorf("failed to save contributor: %w", err)
+		}
+	}
+	return nil
+}
+
+// saveSearchWords replaces the title and author words of a book that typos
+// in searches are matched against. They are split and folded by the same
+// text search configuration as search_vector.
+func saveSearchWords(ctx context.Context, tx *sql.Tx, book *models.Book) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM book_words WHERE isbn = $1`, book.ISBN); err != nil {
+		return fmt.Errorf("failed to clear search words: %w", err)
+	}
+
+	query := `
+		INSERT INTO book_words (isbn, word)
+		SELECT DISTINCT isbn, unnest(tsvector_to_array(to_tsvector('books_search', title || ' ' || author)))
+		FROM books WHERE isbn = $1
+	`
+	if _, err := tx.ExecContext(ctx, query, book.ISBN); err != nil {
+		return fmt.Errorf("failed to save search words: %w", err)
+	}
 	return nil
 }
 
 func (r *BookStoragePostgresRepository) FindAll(ctx context.Context) ([]*models.Book, error) {
-	query := `SELECT isbn, title, author, published_at FROM books`
+	query := `SELECT ` + bookColumns + ` FROM books WHERE deleted_at IS NULL`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	books, err := r.queryBooks(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query books: %w", err)
+		return nil, err
+	}
+	return books, nil
+}
+
+// sortExpressions are what the Postgres listing orders on for each sort
+// field. lower() in the C collation lowercases ASCII letters only, whatever
+// the database's locale, and the text then compares by code point, like
+// models.BookSortField.SortValue in the in-memory repository.
+var sortExpressions = map[models.BookSortField]string{
+	models.SortByTitle:     `lower(title COLLATE "C")`,
+	models.SortByAuthor:    `lower(author COLLATE "C")`,
+	models.SortByPublisher: `lower(publisher COLLATE "C")`,
+	models.SortByYear:      `publication_year`,
+	models.SortByISBN:      `isbn COLLATE "C"`,
+}
+
+func (r *BookStoragePostgresRepository) ListBooks(ctx context.Context, filter models.BookFilter) (*models.BookPage, error) {
+	filter, err := filter.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	after, err := filter.After()
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, `deleted_at IS NULL`)
+	}
+	if filter.Title != "" {
+		conditions = append(conditions, `lower(title) LIKE '%' || lower(`+arg(likeEscaper.Replace(filter.Title))+`) || '%' ESCAPE '\'`)
+	}
+	if filter.Publisher != "" {
+		conditions = append(conditions, `lower(publisher) LIKE '%' || lower(`+arg(likeEscaper.Replace(filter.Publisher))+`) || '%' ESCAPE '\'`)
+	}
+	if filter.Author != "" {
+		conditions = append(conditions, `isbn IN (
+			SELECT isbn FROM book_contributors
+			WHERE role = 'author' AND lower(name) LIKE '%' || lower(`+arg(likeEscaper.Replace(filter.Author))+`) || '%' ESCAPE '\'
+		)`)
+	}
+	if filter.Language != "" {
+		conditions = append(conditions, `language = `+arg(filter.Language))
+	}
+	if filter.YearFrom != 0 {
+		conditions = append(conditions, `publication_year >= `+arg(filter.YearFrom))
+	}
+	if filter.YearTo != 0 {
+		conditions = append(conditions, `publication_year BETWEEN 1 AND `+arg(filter.YearTo))
+	}
+	if after != nil {
+		conditions = append(conditions, keysetCondition(filter.Sort, after, arg))
+	}
+
+	orderBy := make([]string, 0, len(filter.Sort))
+	for _, order := range filter.Sort {
+		if order.Descending {
+			orderBy = append(orderBy, sortExpressions[order.Field]+` DESC`)
+		} else {
+			orderBy = append(orderBy, sortExpressions[order.Field])
+		}
+	}
+
+	query := `SELECT ` + bookColumns + ` FROM books`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	// One book more than the page tells whether another page follows.
+	query += ` ORDER BY ` + strings.Join(orderBy, `, `) + ` LIMIT ` + arg(filter.Limit+1)
+
+	books, err := r.queryBooks(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.BookPage{Books: books}
+	if len(books) > filter.Limit {
+		page.Books = books[:filter.Limit]
+		page.NextCursor = filter.CursorAfter(page.Books[filter.Limit-1])
+	}
+	if page.Books == nil {
+		page.Books = []*models.Book{}
+	}
+	return page, nil
+}
+
+// keysetCondition selects the rows that sort after the given values. The
+// fields may sort in different directions, so the row comparison is spelled
+// out field by field.
+func keysetCondition(sorts []models.BookSort, after []interface{}, arg func(interface{}) string) string {
+	alternatives := make([]string, 0, len(sorts))
+	for i, order := range sorts {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, sortExpressions[sorts[j].Field]+` = `+arg(after[j]))
+		}
+		operator := ` > `
+		if order.Descending {
+			operator = ` < `
+		}
+		terms = append(terms, sortExpressions[order.Field]+operator+arg(after[i]))
+		alternatives = append(alternatives, `(`+strings.Join(terms, ` AND `)+`)`)
+	}
+	return `(` + strings.Join(alternatives, ` OR `) + `)`
+}
+
+// headlineOptions mark the words found in a title or description.
+const headlineOptions = `StartSel=<mark>, StopSel=</mark>`
+
+func (r *BookStoragePostgresRepository) Search(ctx context.Context, search models.BookSearch) (*models.BookSearchResult, error) {
+	search, terms, err := search.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	return typoTolerantSearch(ctx, search, terms, r.similarWords, func(terms []models.SearchTerm) (*models.BookSearchResult, error) {
+		return r.search(ctx, search, terms)
+	})
+}
+
+// similarWords looks the title and author words similar to a word up in
+// the trigram index of book_words.
+func (r *BookStoragePostgresRepository) similarWords(ctx context.Context, word string, limit int) ([]similarWord, error) {
+	query := `
+		SELECT word, similarity(word, $1) AS score
+		FROM (SELECT DISTINCT word FROM book_words WHERE word % $1) AS words
+		ORDER BY score DESC, word COLLATE "C"
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, word, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar words: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
+	var words []similarWord
+	for rows.Next() {
+		var found similarWord
+		if err := rows.Scan(&found.word, &found.similarity); err != nil {
+			return nil, fmt.Errorf("failed to scan similar word: %w", err)
+		}
+		words = append(words, found)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate similar words: %w", err)
+	}
+	return words, nil
+}
+
+func (r *BookStoragePostgresRepository) search(ctx context.Context, search models.BookSearch, terms []models.SearchTerm) (*models.BookSearchResult, error) {
+	args := []interface{}{tsQuery(terms)}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	conditions := append([]string{`search_vector @@ query`, `deleted_at IS NULL`}, searchFilters(search, arg)...)
+	hits := `FROM books, to_tsquery('books_search', $1) AS query WHERE ` + strings.Join(conditions, ` AND `)
+
+	// search_vector weighs the title A, the author B and the description C,
+	// which ts_rank counts as 1, 0.4 and 0.2.
+	query := `
+		WITH hits AS (
+			SELECT books.*, ts_rank(search_vector, query) AS rank, count(*) OVER () AS total
+			` + hits + `
+			ORDER BY rank DESC, lower(title COLLATE "C"), isbn COLLATE "C"
+			LIMIT ` + arg(search.Limit) + ` OFFSET ` + arg(search.Offset) + `
+		)
+		SELECT ` + bookColumns + `, total,
+			ts_headline('books_search', title, to_tsquery('books_search', $1), '` + headlineOptions + `, HighlightAll=true'),
+			CASE WHEN description = '' THEN '' ELSE
+				ts_headline('books_search', description, to_tsquery('books_search', $1), '` + headlineOptions + `, MaxWords=30, MinWords=15')
+			END
+		FROM hits
+		ORDER BY rank DESC, lower(title COLLATE "C"), isbn COLLATE "C"
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search books: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	result := &models.BookSearchResult{Hits: []models.BookHit{}}
 	var books []*models.Book
 	for rows.Next() {
-		book := &models.Book{}
-		err := rows.Scan(&book.ISBN, &book.Title, &book.Author, &book.PublishedAt)
+		var hit models.BookHit
+		book, err := scanBook(withColumns{rows, []interface{}{&result.Total, &hit.TitleHighlight, &hit.Snippet}})
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan book: %w", err)
 		}
+		hit.Book = book
 		books = append(books, book)
+		result.Hits = append(result.Hits, hit)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("failed to iterate books: %w", err)
 	}
 
-	return books, nil
+	if err := r.loadContributors(ctx, books); err != nil {
+		return nil, err
+	}
+
+	// The facets count the hits on every page, so they also give the total
+	// of a page past the last hit, which has no row to count it with. The
+	// limit and offset, the last arguments, only page the hits.
+	result.Facets, err = r.searchFacets(ctx, hits, args[:len(args)-2])
+	if err != nil {
+		return nil, err
+	}
+	result.Total = result.Facets.Available + result.Facets.Unavailable
+	return result, nil
+}
+
+// availableCondition selects the books that can be borrowed now: those with
+// a copy in circulation that is not lent out.
+const availableCondition = `EXISTS (
+	SELECT 1 FROM book_copies
+	WHERE book_copies.isbn = books.isbn AND book_copies.status = 'circulating'
+		AND NOT EXISTS (
+			SELECT 1 FROM book_rentals
+			WHERE book_rentals.copy_id = book_copies.id AND book_rentals.returned_at IS NULL
+		)
+)`
+
+// searchFilters are the conditions of the filters of a search.
+func searchFilters(search models.BookSearch, arg func(interface{}) string) []string {
+	var conditions []string
+	if search.Author != "" {
+		conditions = append(conditions, `isbn IN (
+			SELECT isbn FROM book_contributors WHERE role = 'author' AND name = `+arg(search.Author)+`
+		)`)
+	}
+	if search.Language != "" {
+		conditions = append(conditions, `language = `+arg(search.Language))
+	}
+	if search.Decade != 0 {
+		conditions = append(conditions, `publication_year BETWEEN `+arg(search.Decade)+` AND `+arg(search.Decade+9))
+	}
+	if search.Subject != "" {
+		conditions = append(conditions, `subjects @> ARRAY[`+arg(search.Subject)+`]::TEXT[]`)
+	}
+	if search.Available {
+		conditions = append(conditions, availableCondition)
+	}
+	return conditions
+}
+
+// searchFacets counts the hits of a search by the values of their facets.
+// hits selects them from books, with args.
+func (r *BookStoragePostgresRepository) searchFacets(ctx context.Context, hits string, args []interface{}) (models.BookFacets, error) {
+	query := `
+		WITH hits AS (
+			SELECT isbn, language, publication_year, subjects, ` + availableCondition + ` AS available
+			` + hits + `
+		)
+		SELECT 'author', name, count(DISTINCT hits.isbn)
+		FROM hits JOIN book_contributors ON book_contributors.isbn = hits.isbn AND role = 'author'
+		GROUP BY name
+		UNION ALL
+		SELECT 'language', language, count(*) FROM hits WHERE language <> '' GROUP BY language
+		UNION ALL
+		SELECT 'decade', (publication_year / 10 * 10)::TEXT, count(*) FROM hits WHERE publication_year > 0
+		GROUP BY publication_year / 10 * 10
+		UNION ALL
+		SELECT 'subject', subject, count(DISTINCT isbn) FROM hits, unnest(subjects) AS subject GROUP BY subject
+		UNION ALL
+		SELECT 'available', available::TEXT, count(*) FROM hits GROUP BY available
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return models.BookFacets{}, fmt.Errorf("failed to count search facets: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	counter := newFacetCounter()
+	for rows.Next() {
+		var facet, value string
+		var count int
+		if err := rows.Scan(&facet, &value, &count); err != nil {
+			return models.BookFacets{}, fmt.Errorf("failed to scan search facet: %w", err)
+		}
+		switch facet {
+		case "author":
+			counter.authors[value] = count
+		case "language":
+			counter.languages[value] = count
+		case "decade":
+			decade, err := strconv.Atoi(value)
+			if err != nil {
+				return models.BookFacets{}, fmt.Errorf("failed to scan search facet: %w", err)
+			}
+			counter.decades[decade] = count
+		case "subject":
+			counter.subjects[value] = count
+		case "available":
+			if value == "true" {
+				counter.facets.Available = count
+			} else {
+				counter.facets.Unavailable = count
+			}
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.BookFacets{}, fmt.Errorf("failed to iterate search facets: %w", err)
+	}
+	return counter.result(), nil
+}
+
+// tsQuery writes search terms as a tsquery: the words of a phrase follow
+// each other, each word may be one of the words similar to it, a prefix
+// ends in :*, and every term must match.
+func tsQuery(terms []models.SearchTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		words := make([]string, 0, len(term.Words))
+		for i, word := range term.Words {
+			// Words are letters and digits only, so they need no escaping.
+			if term.Prefix && i == len(term.Words)-1 {
+				words = append(words, `'`+word+`':*`)
+				continue
+			}
+			alternatives := []string{`'` + word + `'`}
+			if i < len(term.Similar) {
+				for _, similar := range term.Similar[i] {
+					alternatives = append(alternatives, `'`+similar+`'`)
+				}
+			}
+			words = append(words, `(`+strings.Join(alternatives, ` | `)+`)`)
+		}
+		parts = append(parts, `(`+strings.Join(words, ` <-> `)+`)`)
+	}
+	return strings.Join(parts, ` & `)
+}
+
+// withColumns scans the columns that follow a book's into extra.
+type withColumns struct {
+	row   rowScanner
+	extra []interface{}
+}
+
+func (w withColumns) Scan(dest ...interface{}) error {
+	return w.row.Scan(append(dest, w.extra...)...)
 }
 
 func (r *BookStoragePostgresRepository) FindByISBN(ctx context.Context, isbn string) (*models.Book, error) {
-	query := `SELECT isbn, title, author, published_at FROM books WHERE isbn = $1`
+	isbn = isbns.Key(isbn)
 
-	book := &models.Book{}
-	err := r.db.QueryRowContext(ctx, query, isbn).Scan(
-		&book.ISBN,
-		&book.Title,
-		&book.Author,
-		&book.PublishedAt,
-	)
+	return r.findByISBN(ctx, isbn, `deleted_at IS NULL`)
+}
+
+func (r *BookStoragePostgresRepository) FindDeletedByISBN(ctx context.Context, isbn string) (*models.Book, error) {
+	isbn = isbns.Key(isbn)
+
+	return r.findByISBN(ctx, isbn, `deleted_at IS NOT NULL`)
+}
+
+// findByISBN reads a book that also meets the condition.
+func (r *BookStoragePostgresRepository) findByISBN(ctx context.Context, isbn, condition string) (*models.Book, error) {
+	query := `SELECT ` + bookColumns + ` FROM books WHERE isbn = $1 AND ` + condition
+
+	book, err := scanBook(r.db.QueryRowContext(ctx, query, isbn))
 
 	if err == sql.ErrNoRows {
 		return nil, interfaces.ErrBookNotFound
@@ -85,15 +508,190 @@ func (r *BookStoragePostgresRepository) FindByISBN(ctx context.Context, isbn str
 		return nil, fmt.Errorf("failed to find book by ISBN: %w", err)
 	}
 
+	if err := r.loadContributors(ctx, []*models.Book{book}); err != nil {
+		return nil, err
+	}
 	return book, nil
 }
 
-func (r *BookStoragePostgresRepository) Delete(ctx context.Context, isbn string) error {
-	query := `DELETE FROM books WHERE isbn = $1`
+func (r *BookStoragePostgresRepository) FindByContributor(ctx context.Context, name string, role models.ContributorRole) ([]*models.Book, error) {
+	query := `
+		SELECT ` + bookColumns + ` FROM books
+		WHERE deleted_at IS NULL AND isbn IN (
+			SELECT isbn FROM book_contributors
+			WHERE lower(name) LIKE '%' || lower($1) || '%' ESCAPE '\'
+				AND ($2 = '' OR role = $2)
+		)
+		ORDER BY title, isbn
+	`
+
+	books, err := r.queryBooks(ctx, query, likeEscaper.Replace(name), string(role))
+	if err != nil {
+		return nil, err
+	}
+	if books == nil {
+		books = []*models.Book{}
+	}
+	return books, nil
+}
+
+func (r *BookStoragePostgresRepository) FindByAuthorID(ctx context.Context, authorID string) ([]*models.Book, error) {
+	query := `
+		SELECT ` + bookColumns + ` FROM books
+		WHERE deleted_at IS NULL AND isbn IN (SELECT isbn FROM book_contributors WHERE author_id = $1)
+		ORDER BY title, isbn
+	`
+
+	books, err := r.queryBooks(ctx, query, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if books == nil {
+		books = []*models.Book{}
+	}
+	return books, nil
+}
+
+// likeEscaper keeps the LIKE wildcards in a searched name literal.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// queryBooks reads the books a query selects, along with their contributors.
+func (r *BookStoragePostgresRepository) queryBooks(ctx context.Context, query string, args ...interface{}) ([]*models.Book, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query books: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var books []*models.Book
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan book: %w", err)
+		}
+		books = append(books, book)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate books: %w", err)
+	}
+
+	if err := r.loadContributors(ctx, books); err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// loadContributors fills in the contributors of the books in one query.
+func (r *BookStoragePostgresRepository) loadContributors(ctx context.Context, books []*models.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	byISBN := make(map[string]*models.Book, len(books))
+	keys := make([]string, 0, len(books))
+	for _, book := range books {
+		byISBN[book.ISBN] = book
+		keys = append(keys, book.ISBN)
+	}
+
+	query := `
+		SELECT isbn, name, role, COALESCE(author_id::text, '') FROM book_contributors
+		WHERE isbn = ANY($1)
+		ORDER BY isbn, position
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(keys))
+	if err != nil {
+		return fmt.Errorf("failed to query contributors: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var isbn string
+		var contributor models.Contributor
+		if err := rows.Scan(&isbn, &contributor.Name, &contributor.Role, &contributor.AuthorID); err != nil {
+			return fmt.Errorf("failed to scan contributor: %w", err)
+		}
+		book := byISBN[isbn]
+		book.Contributors = append(book.Contributors, contributor)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate contributors: %w", err)
+	}
+	return nil
+}
+
+func (r *BookStoragePostgresRepository) Delete(ctx context.Context, isbn string, at time.Time) error {
+	isbn = isbns.Key(isbn)
+
+	query := `UPDATE books SET deleted_at = $2 WHERE isbn = $1 AND deleted_at IS NULL`
+
+	return r.updateDeletion(ctx, query, isbn, at)
+}
+
+func (r *BookStoragePostgresRepository) Restore(ctx context.Context, isbn string) error {
+	isbn = isbns.Key(isbn)
+
+	query := `UPDATE books SET deleted_at = NULL WHERE isbn = $1 AND deleted_at IS NOT NULL`
+
+	return r.updateDeletion(ctx, query, isbn)
+}
+
+// updateDeletion runs a query deleting or restoring a book, failing with
+// ErrBookNotFound when no book was in the state to change.
+func (r *BookStoragePostgresRepository) updateDeletion(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update book deletion: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return interfaces.ErrBookNotFound
+	}
+
+	return nil
+}
+
+func (r *BookStoragePostgresRepository) ListDeletedBefore(ctx context.Context, before time.Time) ([]string, error) {
+	query := `SELECT isbn FROM books WHERE deleted_at < $1 ORDER BY isbn`
+
+	rows, err := r.db.QueryContext(ctx, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted books: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	deleted := make([]string, 0)
+	for rows.Next() {
+		var isbn string
+		if err := rows.Scan(&isbn); err != nil {
+			return nil, fmt.Errorf("failed to scan deleted book: %w", err)
+		}
+		deleted = append(deleted, isbn)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate deleted books: %w", err)
+	}
+	return deleted, nil
+}
+
+// Purge removes a deleted book. Its copies, contributors and search words go
+// with it.
+func (r *BookStoragePostgresRepository) Purge(ctx context.Context, isbn string) error {
+	isbn = isbns.Key(isbn)
+
+	query := `DELETE FROM books WHERE isbn = $1 AND deleted_at IS NOT NULL`
 
 	result, err := r.db.ExecContext(ctx, query, isbn)
 	if err != nil {
-		return fmt.Errorf("failed to delete book: %w", err)
+		return fmt.Errorf("failed to purge book: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -108,4 +706,51 @@ func (r *BookStoragePostgresRepository) Delete(ctx context.Context, isbn string)
 	return nil
 }
 
-var _ interfaces.BookRepository = (*BookStoragePostgresRepository)(nil)
\ No newline at end of file
+func scanBook(row rowScanner) (*models.Book, error) {
+	book := &models.Book{}
+	var publishedAt, deletedAt sql.NullTime
+	var bookSubjects []string
+
+	err := row.Scan(
+		&book.ISBN,
+		&book.Title,
+		&book.Author,
+		&book.Category,
+		&book.Subtitle,
+		&book.Publisher,
+		&publishedAt,
+		&book.PublicationYear,
+		&book.Edition,
+		&book.Language,
+		&book.PageCount,
+		&book.Description,
+		pq.Array(&bookSubjects),
+		&deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if publishedAt.Valid {
+		date := publishedAt.Time
+		book.PublishedAt = &date
+	}
+	if len(bookSubjects) > 0 {
+		book.Subjects = bookSubjects
+	}
+	if deletedAt.Valid {
+		book.DeletedAt = &deletedAt.Time
+	}
+
+	return book, nil
+}
+
+// subjects stores a book without subjects as an empty array rather than NULL.
+func subjects(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+var _ interfaces.BookRepository = (*BookStoragePostgresRepository)(nil)
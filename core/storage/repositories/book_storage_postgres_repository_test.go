@@ -3,123 +3,823 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"books/core/storage/models"
 	"books/core/storage/repositories/interfaces"
-
-	"github.com/ory/dockertest/v3"
-	"github.com/ory/dockertest/v3/docker"
-
-	_ "github.com/lib/pq"
+	"books/internal/testdb"
 )
 
 var db *sql.DB
 var repo *BookStoragePostgresRepository
 
 func TestMain(m *testing.M) {
-	pool, err := dockertest.NewPool("")
+	testdb.Run(m, func(conn *sql.DB) {
+		db = conn
+		repo = NewBookStoragePostgresRepository(db)
+	})
+}
+
+func cleanupDB(t *testing.T) {
+	_, err := db.Exec("DELETE FROM book_rentals; DELETE FROM books")
 	if err != nil {
-		log.Printf("Could not construct pool: %s - skipping integration tests", err)
-		os.Exit(0)
+		t.Fatalf("Failed to cleanup database: %v", err)
 	}
+}
 
-	err = pool.Client.Ping()
+func TestSave(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	book, _ := models.NewBook(validISBN, "Test Book", "Test Author")
+
+	err := repo.Save(context.Background(), book)
 	if err != nil {
-		log.Printf("Could not connect to Docker: %s - skipping integration tests", err)
-		os.Exit(0)
-	}
-
-	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
-		Repository: "postgres",
-		Tag:        "15-alpine",
-		Env: []string{
-			"POSTGRES_USER=test",
-			"POSTGRES_PASSWORD=test",
-			"POSTGRES_DB=testdb",
-			"listen_addresses = '*'",
-		},
-	}, func(config *docker.HostConfig) {
-		config.AutoRemove = true
-		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
-	})
+		t.Fatalf("Failed to save book: %v", err)
+	}
+
+	savedBook, err := repo.FindByISBN(context.Background(), validISBN)
 	if err != nil {
-		log.Fatalf("Could not start resource: %s", err)
+		t.Fatalf("Failed to find saved book: %v", err)
 	}
 
-	hostAndPort := resource.GetHostPort("5432/tcp")
-	databaseUrl := fmt.Sprintf("postgres://test:test@%s/testdb?sslmode=disable", hostAndPort)
+	if savedBook.Title != book.Title {
+		t.Errorf("expected title %s, got %s", book.Title, savedBook.Title)
+	}
+	if savedBook.Author != book.Author {
+		t.Errorf("expected author %s, got %s", book.Author, savedBook.Author)
+	}
+}
 
-	log.Printf("Connecting to database on url: %s", databaseUrl)
+func TestFindByISBNForms(t *testing.T) {
+	cleanupDB(t)
 
-	_ = resource.Expire(120)
+	book, _ := models.NewBook("0-306-40615-2", "Test Book", "Test Author")
+	if err := repo.Save(context.Background(), book); err != nil {
+		t.Fatalf("Failed to save book: %v", err)
+	}
 
-	pool.MaxWait = 120 * time.Second
-	if err = pool.Retry(func() error {
-		db, err = sql.Open("postgres", databaseUrl)
+	for _, form := range []string{"9780306406157", "978-0-306-40615-7", "0306406152", "0-306-40615-2"} {
+		found, err := repo.FindByISBN(context.Background(), form)
 		if err != nil {
-			return err
+			t.Errorf("Failed to find book by %s: %v", form, err)
+			continue
+		}
+		if found.ISBN != "9780306406157" {
+			t.Errorf("expected the book to be stored under its ISBN-13, got %s", found.ISBN)
 		}
-		return db.Ping()
-	}); err != nil {
-		log.Fatalf("Could not connect to docker: %s", err)
 	}
 
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS books (
-			isbn VARCHAR(13) PRIMARY KEY,
-			title VARCHAR(255) NOT NULL,
-			author VARCHAR(255) NOT NULL,
-			published_at TIMESTAMP NOT NULL
-		);
-	`)
+	if err := repo.Delete(context.Background(), "0306406152", time.Now()); err != nil {
+		t.Fatalf("Failed to delete book by its ISBN-10: %v", err)
+	}
+}
+
+func TestSaveDetails(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	publishedAt := time.Date(1999, time.March, 4, 0, 0, 0, 0, time.UTC)
+	book, _ := models.NewBook(validISBN, "Test Book", "Test Author")
+	err := book.SetDetails(models.BookDetails{
+		Subtitle:    "A Subtitle",
+		Publisher:   "Test Publisher",
+		PublishedAt: &publishedAt,
+		Edition:     "2nd",
+		Language:    "en",
+		PageCount:   320,
+		Description: "A book for testing.",
+		Subjects:    []string{"Fiction", "History"},
+	})
 	if err != nil {
-		log.Fatalf("Could not run migrations: %s", err)
+		t.Fatalf("Failed to set details: %v", err)
 	}
 
-	repo = NewBookStoragePostgresRepository(db)
+	if err := repo.Save(context.Background(), book); err != nil {
+		t.Fatalf("Failed to save book: %v", err)
+	}
 
-	code := m.Run()
+	savedBook, err := repo.FindByISBN(context.Background(), validISBN)
+	if err != nil {
+		t.Fatalf("Failed to find saved book: %v", err)
+	}
 
-	if err := pool.Purge(resource); err != nil {
-		log.Fatalf("Could not purge resource: %s", err)
+	if savedBook.PublishedAt == nil || !savedBook.PublishedAt.Equal(publishedAt) || savedBook.PublicationYear != 1999 {
+		t.Errorf("expected publication date %v, got %v (%d)", publishedAt, savedBook.PublishedAt, savedBook.PublicationYear)
+	}
+	if savedBook.Subtitle != "A Subtitle" || savedBook.Publisher != "Test Publisher" || savedBook.Edition != "2nd" ||
+		savedBook.Language != "en" || savedBook.PageCount != 320 || savedBook.Description != "A book for testing." {
+		t.Errorf("unexpected details %+v", savedBook.BookDetails)
+	}
+	if len(savedBook.Subjects) != 2 || savedBook.Subjects[1] != "History" {
+		t.Errorf("unexpected subjects %v", savedBook.Subjects)
 	}
 
-	os.Exit(code)
-}
+	book.PublishedAt = nil
+	book.Subjects = nil
+	if err := repo.Save(context.Background(), book); err != nil {
+		t.Fatalf("Failed to upsert book: %v", err)
+	}
 
-func cleanupDB(t *testing.T) {
-	_, err := db.Exec("DELETE FROM books")
-	if err != nil {
-		t.Fatalf("Failed to cleanup database: %v", err)
+	savedBook, _ = repo.FindByISBN(context.Background(), validISBN)
+	if savedBook.PublishedAt != nil || savedBook.Subjects != nil || savedBook.PublicationYear != 1999 {
+		t.Errorf("expected the date and subjects to be cleared, got %+v", savedBook.BookDetails)
 	}
 }
 
-func TestSave(t *testing.T) {
+func TestSaveContributors(t *testing.T) {
 	cleanupDB(t)
 
 	validISBN := "9783161484100"
-	book, _ := models.NewBook(validISBN, "Test Book", "Test Author", time.Now())
-
-	err := repo.Save(context.Background(), book)
-	if err != nil {
+	book, _ := models.NewBook(validISBN, "Test Book", "Test Author")
+	contributors := []models.Contributor{
+		{Name: "Test Editor", Role: models.RoleEditor},
+		{Name: "Test Author", Role: models.RoleAuthor},
+		{Name: "Test Translator", Role: models.RoleTranslator},
+	}
+	if err := book.SetDetails(models.BookDetails{Contributors: contributors}); err != nil {
+		t.Fatalf("Failed to set contributors: %v", err)
+	}
+	if err := repo.Save(context.Background(), book); err != nil {
 		t.Fatalf("Failed to save book: %v", err)
 	}
 
-	savedBook, err := repo.FindByISBN(context.Background(), validISBN)
+	savedBook, _ := repo.FindByISBN(context.Background(), validISBN)
+	if len(savedBook.Contributors) != 3 || savedBook.Contributors[0] != contributors[0] || savedBook.Contributors[2] != contributors[2] {
+		t.Errorf("expected the contributors in order, got %+v", savedBook.Contributors)
+	}
+
+	book.Contributors = contributors[1:2]
+	if err := repo.Save(context.Background(), book); err != nil {
+		t.Fatalf("Failed to upsert book: %v", err)
+	}
+
+	books, _ := repo.FindAll(context.Background())
+	if len(books) != 1 || len(books[0].Contributors) != 1 || books[0].Contributors[0] != contributors[1] {
+		t.Errorf("expected the contributors to be replaced, got %+v", books)
+	}
+}
+
+func TestFindByContributor(t *testing.T) {
+	cleanupDB(t)
+
+	for _, b := range []struct {
+		isbn, title  string
+		contributors []models.Contributor
+	}{
+		{"9783161484100", "Zebra Tales", []models.Contributor{{Name: "Ann Writer", Role: models.RoleAuthor}}},
+		{"9780306406157", "Apple Stories", []models.Contributor{
+			{Name: "Bob Author", Role: models.RoleAuthor},
+			{Name: "Ann Writer", Role: models.RoleTranslator},
+		}},
+		{"9780140449136", "100% Poems", []models.Contributor{{Name: "Carla 50% Editor", Role: models.RoleEditor}}},
+	} {
+		book, _ := models.NewBook(b.isbn, b.title, b.contributors[0].Name)
+		_ = book.SetDetails(models.BookDetails{Contributors: b.contributors})
+		if err := repo.Save(context.Background(), book); err != nil {
+			t.Fatalf("Failed to save book: %v", err)
+		}
+	}
+
+	books, err := repo.FindByContributor(context.Background(), "ann", "")
 	if err != nil {
-		t.Fatalf("Failed to find saved book: %v", err)
+		t.Fatalf("Failed to find books: %v", err)
+	}
+	if len(books) != 2 || books[0].Title != "Apple Stories" || books[1].Title != "Zebra Tales" {
+		t.Errorf("expected both of Ann's books by title, got %+v", books)
+	}
+	if len(books[0].Contributors) != 2 {
+		t.Errorf("expected every contributor of a match, got %+v", books[0].Contributors)
 	}
 
-	if savedBook.Title != book.Title {
-		t.Errorf("expected title %s, got %s", book.Title, savedBook.Title)
+	books, _ = repo.FindByContributor(context.Background(), "Ann Writer", models.RoleTranslator)
+	if len(books) != 1 || books[0].ISBN != "9780306406157" {
+		t.Errorf("expected only the translated book, got %+v", books)
 	}
-	if savedBook.Author != book.Author {
-		t.Errorf("expected author %s, got %s", book.Author, savedBook.Author)
+
+	books, _ = repo.FindByContributor(context.Background(), "a 5_%", "")
+	if len(books) != 0 {
+		t.Errorf("expected wildcards to be matched literally, got %+v", books)
+	}
+	books, _ = repo.FindByContributor(context.Background(), "a 50%", "")
+	if len(books) != 1 {
+		t.Errorf("expected a literal percent sign to match, got %+v", books)
+	}
+}
+
+// saveListingBooks catalogues the books TestListBooks lists in each
+// repository.
+func saveListingBooks(t *testing.T, repositories ...interfaces.BookRepository) {
+	t.Helper()
+
+	for _, b := range []struct {
+		isbn, title, author, publisher, language string
+		year                                     int
+		coAuthor                                 string
+	}{
+		{"9783161484100", "The Hobbit", "J. R. R. Tolkien", "Allen & Unwin", "en", 1937, ""},
+		{"9780306406157", "The Lord of the Rings", "J. R. R. Tolkien", "Allen & Unwin", "en", 1954, ""},
+		{"9780596517748", "Dune", "Frank Herbert", "Chilton Books", "en", 1965, ""},
+		{"9780140449136", "Der Process", "Franz Kafka", "Die Schmiede", "de", 1925, ""},
+		{"9780134685991", "das Schloss", "Franz Kafka", "Kurt Wolff", "de", 1926, "Max Brod"},
+		{"9780804429573", "Untitled", "Anonymous", "", "", 0, ""},
+	} {
+		book, _ := models.NewBook(b.isbn, b.title, b.author)
+		contributors := []models.Contributor{{Name: b.author, Role: models.RoleAuthor}}
+		if b.coAuthor != "" {
+			contributors = append(contributors, models.Contributor{Name: b.coAuthor, Role: models.RoleAuthor})
+		}
+		err := book.SetDetails(models.BookDetails{
+			Contributors:    contributors,
+			Publisher:       b.publisher,
+			Language:        b.language,
+			PublicationYear: b.year,
+		})
+		if err != nil {
+			t.Fatalf("Failed to set details: %v", err)
+		}
+		for _, repository := range repositories {
+			if err := repository.Save(context.Background(), book); err != nil {
+				t.Fatalf("Failed to save book: %v", err)
+			}
+		}
+	}
+}
+
+// listAllTitles follows the cursors of a listing to its end.
+func listAllTitles(t *testing.T, repository interfaces.BookRepository, filter models.BookFilter) []string {
+	t.Helper()
+
+	var titles []string
+	for pages := 0; pages < 10; pages++ {
+		page, err := repository.ListBooks(context.Background(), filter)
+		if err != nil {
+			t.Fatalf("Failed to list books: %v", err)
+		}
+		if len(page.Books) > filter.Limit {
+			t.Fatalf("expected at most %d books on a page, got %d", filter.Limit, len(page.Books))
+		}
+		for _, book := range page.Books {
+			titles = append(titles, book.Title)
+		}
+		if page.NextCursor == "" {
+			return titles
+		}
+		filter.Cursor = page.NextCursor
+	}
+	t.Fatalf("expected the listing to end")
+	return nil
+}
+
+func TestListBooks(t *testing.T) {
+	cleanupDB(t)
+	memory := NewBookStorageInMemoryRepository()
+	saveListingBooks(t, repo, memory)
+
+	tests := []struct {
+		name     string
+		filter   models.BookFilter
+		expected string
+	}{
+		{
+			name:     "by title",
+			filter:   models.BookFilter{},
+			expected: "das Schloss|Der Process|Dune|The Hobbit|The Lord of the Rings|Untitled",
+		},
+		{
+			name:     "newest first",
+			filter:   models.BookFilter{Sort: []models.BookSort{{Field: models.SortByYear, Descending: true}}},
+			expected: "Dune|The Lord of the Rings|The Hobbit|das Schloss|Der Process|Untitled",
+		},
+		{
+			name: "by author then newest first",
+			filter: models.BookFilter{Sort: []models.BookSort{
+				{Field: models.SortByAuthor},
+				{Field: models.SortByYear, Descending: true},
+			}},
+			expected: "Untitled|Dune|das Schloss|Der Process|The Lord of the Rings|The Hobbit",
+		},
+		{
+			name:     "by publisher descending",
+			filter:   models.BookFilter{Sort: []models.BookSort{{Field: models.SortByPublisher, Descending: true}}},
+			expected: "das Schloss|Der Process|Dune|The Lord of the Rings|The Hobbit|Untitled",
+		},
+		{
+			name:     "title",
+			filter:   models.BookFilter{Title: "THE "},
+			expected: "The Hobbit|The Lord of the Rings",
+		},
+		{
+			name:     "co-author",
+			filter:   models.BookFilter{Author: "brod"},
+			expected: "das Schloss",
+		},
+		{
+			name:     "publisher and language",
+			filter:   models.BookFilter{Publisher: "unwin", Language: "EN"},
+			expected: "The Hobbit|The Lord of the Rings",
+		},
+		{
+			name:     "year range",
+			filter:   models.BookFilter{YearFrom: 1926, YearTo: 1954},
+			expected: "das Schloss|The Hobbit|The Lord of the Rings",
+		},
+		{
+			name:     "years up to",
+			filter:   models.BookFilter{YearTo: 1926},
+			expected: "das Schloss|Der Process",
+		},
+		{
+			name:     "nothing",
+			filter:   models.BookFilter{Title: "%"},
+			expected: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, limit := range []int{2, 4, models.MaxBookPageSize} {
+				tc.filter.Limit = limit
+				fromPostgres := strings.Join(listAllTitles(t, repo, tc.filter), "|")
+				fromMemory := strings.Join(listAllTitles(t, memory, tc.filter), "|")
+				if fromPostgres != tc.expected || fromMemory != tc.expected {
+					t.Errorf("pages of %d: expected %q, got %q from Postgres and %q in memory", limit, tc.expected, fromPostgres, fromMemory)
+				}
+			}
+		})
+	}
+}
+
+func TestListBooksMixedCaseAndAccents(t *testing.T) {
+	cleanupDB(t)
+	memory := NewBookStorageInMemoryRepository()
+
+	for isbn, title := range map[string]string{
+		"9780000000101": "Zazie",
+		"9780000000200": "émile",
+		"9780000000309": "Émile",
+		"9780000000408": "alice",
+		"9780000000507": "Bob",
+		"9780000000606": "Ölmaler",
+		"9780000000705": "ALICE",
+	} {
+		book, _ := models.NewBook(isbn, title, "Author")
+		for _, repository := range []interfaces.BookRepository{repo, memory} {
+			if err := repository.Save(context.Background(), book); err != nil {
+				t.Fatalf("Failed to save book: %v", err)
+			}
+		}
+	}
+
+	// ASCII letters compare without case, other letters by code point.
+	tests := []struct {
+		name     string
+		filter   models.BookFilter
+		expected string
+	}{
+		{
+			name:     "by title",
+			filter:   models.BookFilter{},
+			expected: "alice|ALICE|Bob|Zazie|Émile|Ölmaler|émile",
+		},
+		{
+			name:     "by title descending",
+			filter:   models.BookFilter{Sort: []models.BookSort{{Field: models.SortByTitle, Descending: true}}},
+			expected: "émile|Ölmaler|Émile|Zazie|Bob|alice|ALICE",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, limit := range []int{1, 3, models.MaxBookPageSize} {
+				tc.filter.Limit = limit
+				fromPostgres := strings.Join(listAllTitles(t, repo, tc.filter), "|")
+				fromMemory := strings.Join(listAllTitles(t, memory, tc.filter), "|")
+				if fromPostgres != tc.expected || fromMemory != tc.expected {
+					t.Errorf("pages of %d: expected %q, got %q from Postgres and %q in memory", limit, tc.expected, fromPostgres, fromMemory)
+				}
+			}
+		})
+	}
+}
+
+func TestListBooksCursors(t *testing.T) {
+	cleanupDB(t)
+	memory := NewBookStorageInMemoryRepository()
+	saveListingBooks(t, repo, memory)
+
+	for _, repository := range []interfaces.BookRepository{repo, memory} {
+		page, err := repository.ListBooks(context.Background(), models.BookFilter{Limit: 2})
+		if err != nil || page.NextCursor == "" {
+			t.Fatalf("expected a first page with a cursor, got %+v (%v)", page, err)
+		}
+
+		// A book added before the cursor's place does not shift the pages.
+		book, _ := models.NewBook("9780000000002", "A First Book", "New Author")
+		_ = repository.Save(context.Background(), book)
+
+		next, err := repository.ListBooks(context.Background(), models.BookFilter{Limit: 2, Cursor: page.NextCursor})
+		if err != nil || len(next.Books) != 2 || next.Books[0].Title != "Dune" {
+			t.Errorf("expected the second page to start at Dune, got %+v (%v)", next, err)
+		}
+
+		sorted := models.BookFilter{Sort: []models.BookSort{{Field: models.SortByYear}}, Cursor: page.NextCursor}
+		if _, err := repository.ListBooks(context.Background(), sorted); !errors.Is(err, models.ErrInvalidCursor) {
+			t.Errorf("expected a cursor of another order to be refused, got %v", err)
+		}
+		if _, err := repository.ListBooks(context.Background(), models.BookFilter{Cursor: "not a cursor"}); !errors.Is(err, models.ErrInvalidCursor) {
+			t.Errorf("expected a malformed cursor to be refused, got %v", err)
+		}
+		if _, err := repository.ListBooks(context.Background(), models.BookFilter{Limit: models.MaxBookPageSize + 1}); err == nil {
+			t.Errorf("expected a page size over the maximum to be refused")
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	cleanupDB(t)
+	memory := NewBookStorageInMemoryRepository()
+
+	for _, b := range []struct {
+		isbn, title, author, description string
+	}{
+		{"9783161484100", "The Hobbit", "J. R. R. Tolkien", "In a hole in the ground there lived a hobbit."},
+		{"9780306406157", "The Lord of the Rings", "J. R. R. Tolkien", "The sequel to The Hobbit, in which the Ring is destroyed."},
+		{"9780596517748", "Dune", "Frank Herbert", "A desert planet, and the spice that rules the universe."},
+	} {
+		book, _ := models.NewBook(b.isbn, b.title, b.author)
+		_ = book.SetDetails(models.BookDetails{Description: b.description})
+		for _, repository := range []interfaces.BookRepository{repo, memory} {
+			if err := repository.Save(context.Background(), book); err != nil {
+				t.Fatalf("Failed to save book: %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{query: "hobbit", expected: "The Hobbit|The Lord of the Rings"},
+		{query: "TOLKIEN", expected: "The Hobbit|The Lord of the Rings"},
+		{query: `"the hobbit"`, expected: "The Hobbit|The Lord of the Rings"},
+		{query: `"lord of the"`, expected: "The Lord of the Rings"},
+		{query: "ring*", expected: "The Lord of the Rings"},
+		{query: `desert "spice that rul"*`, expected: "Dune"},
+		{query: "hobbit dune", expected: ""},
+		{query: `"hobbit the"`, expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			for name, repository := range map[string]interfaces.BookRepository{"Postgres": repo, "memory": memory} {
+				result, err := repository.Search(context.Background(), models.BookSearch{Query: tc.query})
+				if err != nil {
+					t.Fatalf("%s: failed to search: %v", name, err)
+				}
+				titles := make([]string, 0, len(result.Hits))
+				for _, hit := range result.Hits {
+					titles = append(titles, hit.Book.Title)
+				}
+				if strings.Join(titles, "|") != tc.expected || result.Total != len(titles) {
+					t.Errorf("%s: expected %q, got %q of %d", name, tc.expected, strings.Join(titles, "|"), result.Total)
+				}
+			}
+		})
+	}
+
+	for name, repository := range map[string]interfaces.BookRepository{"Postgres": repo, "memory": memory} {
+		result, err := repository.Search(context.Background(), models.BookSearch{Query: "hobbit", Limit: 1, Offset: 1})
+		if err != nil || result.Total != 2 || len(result.Hits) != 1 {
+			t.Fatalf("%s: expected the second of 2 hits, got %+v (%v)", name, result, err)
+		}
+		hit := result.Hits[0]
+		if hit.Book.Title != "The Lord of the Rings" || len(hit.Book.Contributors) != 1 {
+			t.Errorf("%s: unexpected hit %+v", name, hit.Book)
+		}
+		if hit.TitleHighlight != "The Lord of the Rings" || !strings.Contains(hit.Snippet, "The <mark>Hobbit</mark>, in which") {
+			t.Errorf("%s: unexpected highlights %q and %q", name, hit.TitleHighlight, hit.Snippet)
+		}
+
+		if _, err := repository.Search(context.Background(), models.BookSearch{Query: ` "" * `}); err == nil {
+			t.Errorf("%s: expected a query without words to be refused", name)
+		}
+	}
+
+	_ = memory.Delete(context.Background(), "9783161484100", time.Now())
+	_ = memory.Purge(context.Background(), "9783161484100")
+	result, _ := memory.Search(context.Background(), models.BookSearch{Query: "hobbit"})
+	if result.Total != 1 {
+		t.Errorf("expected a purged book to leave the index, got %+v", result)
+	}
+}
+
+func TestTypoTolerantSearch(t *testing.T) {
+	cleanupDB(t)
+	memory := NewBookStorageInMemoryRepository()
+
+	for _, b := range []struct {
+		isbn, title, author string
+	}{
+		{"9783161484100", "The Hobbit", "J. R. R. Tolkien"},
+		{"9780306406157", "Crime and Punishment", "Fyodor Dostoevsky"},
+		// The accent is written as a combining mark, and stored composed.
+		{"9780596517748", "L'Étranger", "Albert Camu\u0301s"},
+	} {
+		book, _ := models.NewBook(b.isbn, b.title, b.author)
+		for _, repository := range []interfaces.BookRepository{repo, memory} {
+			if err := repository.Save(context.Background(), book); err != nil {
+				t.Fatalf("Failed to save book: %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		query      string
+		expected   string
+		suggestion string
+	}{
+		{query: "dostoyevsky", expected: "Crime and Punishment"},
+		{query: `"crime and punishmnet"`, expected: "Crime and Punishment"},
+		{query: "camus", expected: "L'Étranger"},
+		{query: "ÉTRANGER CAMÚS", expected: "L'Étranger"},
+		{query: "tolkein", suggestion: "tolkien"},
+		{query: `"the hobit"`, expected: "The Hobbit"},
+		{query: "tolkein hob*", suggestion: "tolkien hob*"},
+		{query: "tolkein hobit", suggestion: "tolkien hobbit"},
+		{query: "tolkein crime", suggestion: ""},
+		{query: "hob", suggestion: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			for name, repository := range map[string]interfaces.BookRepository{"Postgres": repo, "memory": memory} {
+				result, err := repository.Search(context.Background(), models.BookSearch{Query: tc.query})
+				if err != nil {
+					t.Fatalf("%s: failed to search: %v", name, err)
+				}
+				titles := make([]string, 0, len(result.Hits))
+				for _, hit := range result.Hits {
+					titles = append(titles, hit.Book.Title)
+				}
+				if strings.Join(titles, "|") != tc.expected || result.Suggestion != tc.suggestion {
+					t.Errorf("%s: expected %q suggesting %q, got %q suggesting %q", name, tc.expected, tc.suggestion, strings.Join(titles, "|"), result.Suggestion)
+				}
+			}
+		})
+	}
+
+	for name, repository := range map[string]interfaces.BookRepository{"Postgres": repo, "memory": memory} {
+		result, _ := repository.Search(context.Background(), models.BookSearch{Query: "camus etranger"})
+		if len(result.Hits) != 1 || result.Hits[0].TitleHighlight != "L'<mark>Étranger</mark>" {
+			t.Errorf("%s: expected the folded word to be highlighted, got %+v", name, result.Hits)
+		}
+		if len(result.Hits) == 1 && result.Hits[0].Book.Author != "Albert Camús" {
+			t.Errorf("%s: expected the author in normalization form C, got %q", name, result.Hits[0].Book.Author)
+		}
+	}
+}
+
+// availableBooks stands in for the library's rentals in the in-memory
+// repository.
+type availableBooks map[string]bool
+
+func (a availableBooks) AvailableISBNs(ctx context.Context) (map[string]bool, error) {
+	return a, nil
+}
+
+func TestSearchFacets(t *testing.T) {
+	cleanupDB(t)
+	memory := NewBookStorageInMemoryRepository()
+	copies := NewCopyStoragePostgresRepository(db)
+
+	for _, b := range []struct {
+		isbn, title, description, language string
+		authors                            []string
+		year                               int
+		subjects                           []string
+		copies                             []models.CopyStatus
+		rented                             int
+	}{
+		{"9780596517748", "Dune", "A novel of the desert.", "en", []string{"Frank Herbert"}, 1965, []string{"Fiction", "Science fiction"}, []models.CopyStatus{models.CopyStatusCirculating}, 1},
+		{"9783161484100", "Dune Messiah", "The novel that follows Dune.", "en", []string{"Frank Herbert"}, 1969, []string{"Fiction", "Science fiction"}, []models.CopyStatus{models.CopyStatusCirculating}, 0},
+		{"9780140449136", "Der Process", "A novel of the law.", "de", []string{"Franz Kafka"}, 1925, []string{"Fiction"}, []models.CopyStatus{models.CopyStatusLost}, 0},
+		{"9780134685991", "Das Schloss", "An unfinished novel.", "de", []string{"Franz Kafka", "Max Brod"}, 1926, []string{"Fiction"}, []models.CopyStatus{models.CopyStatusCirculating, models.CopyStatusCirculating}, 1},
+		{"9780804429573", "Untitled", "A novel nobody signed.", "", []string{"Anonymous"}, 0, nil, nil, 0},
+		{"9780306406157", "The Hobbit", "There and back again.", "en", []string{"J. R. R. Tolkien"}, 1937, []string{"Fantasy"}, []models.CopyStatus{models.CopyStatusCirculating}, 0},
+	} {
+		book, _ := models.NewBook(b.isbn, b.title, b.authors[0])
+		details := models.BookDetails{Description: b.description, Language: b.language, PublicationYear: b.year, Subjects: b.subjects}
+		for _, author := range b.authors {
+			details.Contributors = append(details.Contributors, models.Contributor{Name: author, Role: models.RoleAuthor})
+		}
+		if err := book.SetDetails(details); err != nil {
+			t.Fatalf("Failed to set details: %v", err)
+		}
+		for _, repository := range []interfaces.BookRepository{repo, memory} {
+			if err := repository.Save(context.Background(), book); err != nil {
+				t.Fatalf("Failed to save book: %v", err)
+			}
+		}
+
+		for i, status := range b.copies {
+			bookCopy, _ := models.NewCopy(book.ISBN, fmt.Sprintf("%s-%d", book.ISBN, i), "", "")
+			bookCopy.Status = status
+			if err := copies.SaveCopy(context.Background(), bookCopy); err != nil {
+				t.Fatalf("Failed to save copy: %v", err)
+			}
+			if i < b.rented {
+				_, err := db.Exec(`INSERT INTO book_rentals (book_id, copy_id, user_id, borrowed_at, return_deadline)
+					VALUES ($1, $2, 'user1', NOW(), NOW() + INTERVAL '14 days')`, book.ISBN, bookCopy.ID)
+				if err != nil {
+					t.Fatalf("Failed to rent copy: %v", err)
+				}
+			}
+		}
+	}
+	memory.SetAvailability(availableBooks{"9783161484100": true, "9780134685991": true, "9780306406157": true})
+
+	expected := models.BookFacets{
+		Authors: []models.FacetCount{
+			{Value: "Frank Herbert", Count: 2}, {Value: "Franz Kafka", Count: 2},
+			{Value: "Anonymous", Count: 1}, {Value: "Max Brod", Count: 1},
+		},
+		Languages:   []models.FacetCount{{Value: "de", Count: 2}, {Value: "en", Count: 2}},
+		Decades:     []models.DecadeCount{{Decade: 1920, Count: 2}, {Decade: 1960, Count: 2}},
+		Subjects:    []models.FacetCount{{Value: "Fiction", Count: 4}, {Value: "Science fiction", Count: 2}},
+		Available:   2,
+		Unavailable: 3,
+	}
+	for name, repository := range map[string]interfaces.BookRepository{"Postgres": repo, "memory": memory} {
+		result, err := repository.Search(context.Background(), models.BookSearch{Query: "novel", Limit: 1})
+		if err != nil {
+			t.Fatalf("%s: failed to search: %v", name, err)
+		}
+		if result.Total != 5 || fmt.Sprintf("%+v", result.Facets) != fmt.Sprintf("%+v", expected) {
+			t.Errorf("%s: expected 5 hits counted as %+v, got %d counted as %+v", name, expected, result.Total, result.Facets)
+		}
+
+		result, _ = repository.Search(context.Background(), models.BookSearch{Query: "novel", Offset: 10})
+		if result.Total != 5 || result.Facets.Available != 2 {
+			t.Errorf("%s: expected a page past the last hit to count them all, got %+v", name, result)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		search   models.BookSearch
+		expected string
+	}{
+		{name: "available", search: models.BookSearch{Available: true}, expected: "Das Schloss|Dune Messiah"},
+		{name: "author", search: models.BookSearch{Author: "Franz Kafka"}, expected: "Das Schloss|Der Process"},
+		{name: "co-author", search: models.BookSearch{Author: "Max Brod"}, expected: "Das Schloss"},
+		{name: "language", search: models.BookSearch{Language: "EN"}, expected: "Dune|Dune Messiah"},
+		{name: "decade", search: models.BookSearch{Decade: 1960}, expected: "Dune|Dune Messiah"},
+		{name: "decade and subject", search: models.BookSearch{Decade: 1920, Subject: "Fiction"}, expected: "Das Schloss|Der Process"},
+		{name: "subject and available", search: models.BookSearch{Subject: "Science fiction", Available: true}, expected: "Dune Messiah"},
+		{name: "exact subject", search: models.BookSearch{Subject: "fiction"}, expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.search.Query = "novel"
+			for name, repository := range map[string]interfaces.BookRepository{"Postgres": repo, "memory": memory} {
+				result, err := repository.Search(context.Background(), tc.search)
+				if err != nil {
+					t.Fatalf("%s: failed to search: %v", name, err)
+				}
+				titles := make([]string, 0, len(result.Hits))
+				for _, hit := range result.Hits {
+					titles = append(titles, hit.Book.Title)
+				}
+				if strings.Join(titles, "|") != tc.expected || result.Total != len(titles) {
+					t.Errorf("%s: expected %q, got %q of %d", name, tc.expected, strings.Join(titles, "|"), result.Total)
+				}
+				if result.Facets.Available+result.Facets.Unavailable != result.Total {
+					t.Errorf("%s: expected the facets to count the filtered hits, got %+v", name, result.Facets)
+				}
+			}
+		})
+	}
+
+	for _, decade := range []int{1965, -10} {
+		if _, err := memory.Search(context.Background(), models.BookSearch{Query: "novel", Decade: decade}); err == nil {
+			t.Errorf("expected decade %d to be refused", decade)
+		}
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	cleanupDB(t)
+	memory := NewBookStorageInMemoryRepository()
+	saveListingBooks(t, repo, memory)
+	ctx := context.Background()
+
+	for name, repository := range map[string]interfaces.BookRepository{"Postgres": repo, "memory": memory} {
+		if err := repository.Delete(ctx, "9783161484100", time.Now()); err != nil {
+			t.Fatalf("%s: failed to delete book: %v", name, err)
+		}
+		if err := repository.Delete(ctx, "9783161484100", time.Now()); err != interfaces.ErrBookNotFound {
+			t.Errorf("%s: expected a deleted book not to be deleted again, got %v", name, err)
+		}
+
+		page, _ := repository.ListBooks(ctx, models.BookFilter{Author: "Tolkien"})
+		if len(page.Books) != 1 || page.Books[0].Title != "The Lord of the Rings" {
+			t.Errorf("%s: expected the deleted book to be left out of listings, got %+v", name, page.Books)
+		}
+		page, _ = repository.ListBooks(ctx, models.BookFilter{Author: "Tolkien", IncludeDeleted: true})
+		if len(page.Books) != 2 || page.Books[0].DeletedAt == nil || page.Books[1].DeletedAt != nil {
+			t.Errorf("%s: expected the deleted book to be listed when asked for, got %+v", name, page.Books)
+		}
+
+		all, _ := repository.FindAll(ctx)
+		byContributor, _ := repository.FindByContributor(ctx, "Tolkien", "")
+		result, _ := repository.Search(ctx, models.BookSearch{Query: "hobbit"})
+		if len(all) != 5 || len(byContributor) != 1 || result.Total != 0 {
+			t.Errorf("%s: expected the deleted book to be hidden, got %d books, %d by contributor and %d hits", name, len(all), len(byContributor), result.Total)
+		}
+
+		// Saving a deleted book leaves it deleted.
+		book, _ := repository.FindDeletedByISBN(ctx, "9783161484100")
+		book.Title = "The Hobbit, or There and Back Again"
+		if err := repository.Save(ctx, book); err != nil {
+			t.Fatalf("%s: failed to save book: %v", name, err)
+		}
+		if _, err := repository.FindByISBN(ctx, "9783161484100"); err != interfaces.ErrBookNotFound {
+			t.Errorf("%s: expected the saved book to stay deleted, got %v", name, err)
+		}
+
+		if err := repository.Restore(ctx, "978-3-16-148410-0"); err != nil {
+			t.Fatalf("%s: failed to restore book: %v", name, err)
+		}
+		restored, err := repository.FindByISBN(ctx, "9783161484100")
+		if err != nil || restored.DeletedAt != nil || restored.Title != "The Hobbit, or There and Back Again" {
+			t.Errorf("%s: expected the book to be restored, got %+v (%v)", name, restored, err)
+		}
+		if err := repository.Restore(ctx, "9783161484100"); err != interfaces.ErrBookNotFound {
+			t.Errorf("%s: expected a book that is not deleted not to be restored, got %v", name, err)
+		}
+		if _, err := repository.FindDeletedByISBN(ctx, "9783161484100"); err != interfaces.ErrBookNotFound {
+			t.Errorf("%s: expected a book that is not deleted not to be found deleted, got %v", name, err)
+		}
+	}
+}
+
+func TestPurge(t *testing.T) {
+	cleanupDB(t)
+	memory := NewBookStorageInMemoryRepository()
+	saveListingBooks(t, repo, memory)
+	ctx := context.Background()
+	now := time.Now()
+
+	for name, repository := range map[string]interfaces.BookRepository{"Postgres": repo, "memory": memory} {
+		_ = repository.Delete(ctx, "9783161484100", now.AddDate(0, 0, -100))
+		_ = repository.Delete(ctx, "9780306406157", now.AddDate(0, 0, -10))
+
+		deleted, err := repository.ListDeletedBefore(ctx, now.AddDate(0, 0, -30))
+		if err != nil {
+			t.Fatalf("%s: failed to list deleted books: %v", name, err)
+		}
+		if strings.Join(deleted, "|") != "9783161484100" {
+			t.Errorf("%s: expected the book deleted before the cutoff, got %v", name, deleted)
+		}
+
+		if err := repository.Purge(ctx, "9783161484100"); err != nil {
+			t.Fatalf("%s: failed to purge: %v", name, err)
+		}
+		if _, err := repository.FindDeletedByISBN(ctx, "9783161484100"); err != interfaces.ErrBookNotFound {
+			t.Errorf("%s: expected the purged book to be gone, got %v", name, err)
+		}
+		if err := repository.Purge(ctx, "9780596517748"); err != interfaces.ErrBookNotFound {
+			t.Errorf("%s: expected a book that is not deleted not to be purged, got %v", name, err)
+		}
+		if _, err := repository.FindByISBN(ctx, "9780596517748"); err != nil {
+			t.Errorf("%s: expected the book that is not deleted to be kept, got %v", name, err)
+		}
+	}
+
+	// The rentals of a purged book stay, still naming its ISBN and copy.
+	copies := NewCopyStoragePostgresRepository(db)
+	bookCopy, _ := models.NewCopy("9780596517748", "BC-1", "", "")
+	if err := copies.SaveCopy(ctx, bookCopy); err != nil {
+		t.Fatalf("Failed to save copy: %v", err)
+	}
+	_, err := db.Exec(`INSERT INTO book_rentals (book_id, copy_id, user_id, borrowed_at, return_deadline, returned_at)
+		VALUES ($1, $2, 'user1', NOW(), NOW(), NOW())`, bookCopy.ISBN, bookCopy.ID)
+	if err != nil {
+		t.Fatalf("Failed to rent copy: %v", err)
+	}
+	_ = repo.Delete(ctx, "9780596517748", now.AddDate(0, 0, -100))
+	if err := repo.Purge(ctx, "9780596517748"); err != nil {
+		t.Fatalf("Failed to purge the book with rental history: %v", err)
+	}
+	var copyID string
+	if err := db.QueryRow(`SELECT copy_id FROM book_rentals WHERE book_id = $1`, "9780596517748").Scan(&copyID); err != nil || copyID != bookCopy.ID {
+		t.Errorf("expected the rental to keep the purged copy %s, got %q (%v)", bookCopy.ID, copyID, err)
 	}
 }
 
@@ -127,7 +827,7 @@ func TestSaveUpsert(t *testing.T) {
 	cleanupDB(t)
 
 	validISBN := "9783161484100"
-	book, _ := models.NewBook(validISBN, "Original Title", "Original Author", time.Now())
+	book, _ := models.NewBook(validISBN, "Original Title", "Original Author")
 
 	err := repo.Save(context.Background(), book)
 	if err != nil {
@@ -155,7 +855,7 @@ func TestFindAll(t *testing.T) {
 
 	isbns := []string{"9783161484100", "9780306406157", "9780596517748"}
 	for i, isbn := range isbns {
-		book, _ := models.NewBook(isbn, fmt.Sprintf("Book %d", i+1), fmt.Sprintf("Author %d", i+1), time.Now())
+		book, _ := models.NewBook(isbn, fmt.Sprintf("Book %d", i+1), fmt.Sprintf("Author %d", i+1))
 		_ = repo.Save(context.Background(), book)
 	}
 
@@ -173,7 +873,7 @@ func TestFindByISBN(t *testing.T) {
 	cleanupDB(t)
 
 	validISBN := "9783161484100"
-	book, _ := models.NewBook(validISBN, "Test Book", "Test Author", time.Now())
+	book, _ := models.NewBook(validISBN, "Test Book", "Test Author")
 	_ = repo.Save(context.Background(), book)
 
 	foundBook, err := repo.FindByISBN(context.Background(), validISBN)
@@ -194,10 +894,11 @@ func TestDelete(t *testing.T) {
 	cleanupDB(t)
 
 	validISBN := "9783161484100"
-	book, _ := models.NewBook(validISBN, "Test Book", "Test Author", time.Now())
+	book, _ := models.NewBook(validISBN, "Test Book", "Test Author")
 	_ = repo.Save(context.Background(), book)
 
-	err := repo.Delete(context.Background(), validISBN)
+	deletedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	err := repo.Delete(context.Background(), validISBN, deletedAt)
 	if err != nil {
 		t.Fatalf("Failed to delete book: %v", err)
 	}
@@ -206,12 +907,17 @@ func TestDelete(t *testing.T) {
 	if err != interfaces.ErrBookNotFound {
 		t.Errorf("expected ErrBookNotFound after delete, got %v", err)
 	}
+
+	deleted, err := repo.FindDeletedByISBN(context.Background(), validISBN)
+	if err != nil || deleted.DeletedAt == nil || !deleted.DeletedAt.Equal(deletedAt) {
+		t.Errorf("expected the book to be kept as deleted at %s, got %+v (%v)", deletedAt, deleted, err)
+	}
 }
 
 func TestDeleteNotFound(t *testing.T) {
 	cleanupDB(t)
 
-	err := repo.Delete(context.Background(), "nonexistent")
+	err := repo.Delete(context.Background(), "nonexistent", time.Now())
 	if err != interfaces.ErrBookNotFound {
 		t.Errorf("expected ErrBookNotFound, got %v", err)
 	}
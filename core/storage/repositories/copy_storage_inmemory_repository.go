@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	isbns "books/core/isbn"
+	"books/core/storage/models"
+	"books/core/storage/repositories/interfaces"
+)
+
+type CopyStorageInMemoryRepository struct {
+	copies []*models.Copy
+	mutex  sync.RWMutex
+}
+
+func NewCopyStorageInMemoryRepository() *CopyStorageInMemoryRepository {
+	return &CopyStorageInMemoryRepository{
+		copies: make([]*models.Copy, 0),
+	}
+}
+
+func (r *CopyStorageInMemoryRepository) SaveCopy(ctx context.Context, bookCopy *models.Copy) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.copies {
+		if existing.Barcode == bookCopy.Barcode {
+			return interfaces.ErrDuplicateBarcode
+		}
+	}
+
+	if bookCopy.ID == "" {
+		bookCopy.ID = newCopyID()
+	}
+	if bookCopy.Status == "" {
+		bookCopy.Status = models.CopyStatusCirculating
+	}
+
+	c := *bookCopy
+	r.copies = append(r.copies, &c)
+	return nil
+}
+
+func (r *CopyStorageInMemoryRepository) UpdateCopy(ctx context.Context, bookCopy *models.Copy) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	index := -1
+	for i, existing := range r.copies {
+		if existing.ID == bookCopy.ID {
+			index = i
+		} else if existing.Barcode == bookCopy.Barcode {
+			return interfaces.ErrDuplicateBarcode
+		}
+	}
+
+	if index < 0 {
+		return interfaces.ErrCopyNotFound
+	}
+
+	c := *bookCopy
+	r.copies[index] = &c
+	return nil
+}
+
+func (r *CopyStorageInMemoryRepository) FindCopyByID(ctx context.Context, id string) (*models.Copy, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, existing := range r.copies {
+		if existing.ID == id {
+			c := *existing
+			return &c, nil
+		}
+	}
+
+	return nil, interfaces.ErrCopyNotFound
+}
+
+func (r *CopyStorageInMemoryRepository) FindCopiesByISBN(ctx context.Context, isbn string) ([]*models.Copy, error) {
+	isbn = isbns.Key(isbn)
+
+	return r.filterCopies(func(c *models.Copy) bool {
+		return c.ISBN == isbn
+	}), nil
+}
+
+func (r *CopyStorageInMemoryRepository) FindAllCopies(ctx context.Context) ([]*models.Copy, error) {
+	return r.filterCopies(func(c *models.Copy) bool {
+		return true
+	}), nil
+}
+
+func (r *CopyStorageInMemoryRepository) DeleteCopy(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, existing := range r.copies {
+		if existing.ID == id {
+			r.copies = append(r.copies[:i], r.copies[i+1:]...)
+			return nil
+		}
+	}
+
+	return interfaces.ErrCopyNotFound
+}
+
+func (r *CopyStorageInMemoryRepository) DeleteCopiesByISBN(ctx context.Context, isbn string) error {
+	isbn = isbns.Key(isbn)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	kept := r.copies[:0]
+	for _, existing := range r.copies {
+		if existing.ISBN != isbn {
+			kept = append(kept, existing)
+		}
+	}
+	r.copies = kept
+	return nil
+}
+
+func (r *CopyStorageInMemoryRepository) filterCopies(match func(*models.Copy) bool) []*models.Copy {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*models.Copy, 0)
+	for _, existing := range r.copies {
+		if match(existing) {
+			c := *existing
+			result = append(result, &c)
+		}
+	}
+	return result
+}
+
+func newCopyID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+var _ interfaces.CopyRepository = (*CopyStorageInMemoryRepository)(nil)
@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	isbns "books/core/isbn"
+	"books/core/storage/models"
+	"books/core/storage/repositories/interfaces"
+
+	"github.com/lib/pq"
+)
+
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+	pqInvalidText         = "22P02"
+)
+
+// copyColumns lists the book_copies columns read by scanCopy. Branch IDs are
+// read as text so that copies without a branch scan as empty strings.
+const copyColumns = `id, isbn, barcode, shelf_location, condition, status,
+	COALESCE(home_branch_id::text, ''), COALESCE(current_branch_id::text, ''), added_at`
+
+type CopyStoragePostgresRepository struct {
+	db *sql.DB
+}
+
+func NewCopyStoragePostgresRepository(db *sql.DB) *CopyStoragePostgresRepository {
+	return &CopyStoragePostgresRepository{
+		db: db,
+	}
+}
+
+func (r *CopyStoragePostgresRepository) SaveCopy(ctx context.Context, bookCopy *models.Copy) error {
+	if bookCopy.Status == "" {
+		bookCopy.Status = models.CopyStatusCirculating
+	}
+
+	query := `
+		INSERT INTO book_copies (id, isbn, barcode, shelf_location, condition, status, home_branch_id, current_branch_id, added_at)
+		VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), $2, $3, $4, $5, $6, NULLIF($7, '')::uuid, NULLIF($8, '')::uuid, $9)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		bookCopy.ID,
+		bookCopy.ISBN,
+		bookCopy.Barcode,
+		bookCopy.ShelfLocation,
+		bookCopy.Condition,
+		bookCopy.Status,
+		bookCopy.HomeBranchID,
+		bookCopy.CurrentBranchID,
+		bookCopy.AddedAt,
+	).Scan(&bookCopy.ID)
+	if err != nil {
+		return mapCopyWriteError(err)
+	}
+
+	return nil
+}
+
+func (r *CopyStoragePostgresRepository) UpdateCopy(ctx context.Context, bookCopy *models.Copy) error {
+	query := `
+		UPDATE book_copies
+		SET barcode = $2, shelf_location = $3, condition = $4, status = $5,
+			home_branch_id = NULLIF($6, '')::uuid, current_branch_id = NULLIF($7, '')::uuid
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		bookCopy.ID,
+		bookCopy.Barcode,
+		bookCopy.ShelfLocation,
+		bookCopy.Condition,
+		bookCopy.Status,
+		bookCopy.HomeBranchID,
+		bookCopy.CurrentBranchID,
+	)
+	if isInvalidUUID(err) {
+		return interfaces.ErrCopyNotFound
+	}
+	if err != nil {
+		return mapCopyWriteError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return interfaces.ErrCopyNotFound
+	}
+
+	return nil
+}
+
+func (r *CopyStoragePostgresRepository) FindCopyByID(ctx context.Context, id string) (*models.Copy, error) {
+	query := `SELECT ` + copyColumns + ` FROM book_copies WHERE id = $1`
+
+	bookCopy, err := scanCopy(r.db.QueryRowContext(ctx, query, id))
+
+	if err == sql.ErrNoRows || isInvalidUUID(err) {
+		return nil, interfaces.ErrCopyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find copy: %w", err)
+	}
+
+	return bookCopy, nil
+}
+
+func (r *CopyStoragePostgresRepository) FindCopiesByISBN(ctx context.Context, isbn string) ([]*models.Copy, error) {
+	isbn = isbns.Key(isbn)
+
+	query := `
+		SELECT ` + copyColumns + `
+		FROM book_copies
+		WHERE isbn = $1
+		ORDER BY added_at, barcode
+	`
+
+	return r.queryCopies(ctx, query, isbn)
+}
+
+func (r *CopyStoragePostgresRepository) FindAllCopies(ctx context.Context) ([]*models.Copy, error) {
+	query := `
+		SELECT ` + copyColumns + `
+		FROM book_copies
+		ORDER BY isbn, added_at, barcode
+	`
+
+	return r.queryCopies(ctx, query)
+}
+
+func (r *CopyStoragePostgresRepository) DeleteCopy(ctx context.Context, id string) error {
+	// A copy stays while rentals refer to it; purging its book is what
+	// removes it along with them.
+	query := `
+		DELETE FROM book_copies c
+		WHERE c.id = $1 AND NOT EXISTS (SELECT 1 FROM book_rentals r WHERE r.copy_id = c.id)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if isInvalidUUID(err) {
+		return interfaces.ErrCopyNotFound
+	}
+	if err != nil {
+		return mapCopyWriteError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		if _, err := r.FindCopyByID(ctx, id); err != nil {
+			return err
+		}
+		return interfaces.ErrCopyInUse
+	}
+
+	return nil
+}
+
+func (r *CopyStoragePostgresRepository) DeleteCopiesByISBN(ctx context.Context, isbn string) error {
+	isbn = isbns.Key(isbn)
+
+	query := `DELETE FROM book_copies WHERE isbn = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, isbn); err != nil {
+		return mapCopyWriteError(err)
+	}
+
+	return nil
+}
+
+func (r *CopyStoragePostgresRepository) queryCopies(ctx context.Context, query string, args ...any) ([]*models.Copy, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query copies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	copies := make([]*models.Copy, 0)
+	for rows.Next() {
+		bookCopy, err := scanCopy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan copy: %w", err)
+		}
+		copies = append(copies, bookCopy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate copies: %w", err)
+	}
+
+	return copies, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanCopy reads a copy selected with copyColumns.
+func scanCopy(row rowScanner) (*models.Copy, error) {
+	bookCopy := &models.Copy{}
+
+	err := row.Scan(
+		&bookCopy.ID,
+		&bookCopy.ISBN,
+		&bookCopy.Barcode,
+		&bookCopy.ShelfLocation,
+		&bookCopy.Condition,
+		&bookCopy.Status,
+		&bookCopy.HomeBranchID,
+		&bookCopy.CurrentBranchID,
+		&bookCopy.AddedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return bookCopy, nil
+}
+
+func isInvalidUUID(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqInvalidText
+}
+
+func mapCopyWriteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			return interfaces.ErrDuplicateBarcode
+		case pqForeignKeyViolation:
+			return interfaces.ErrBookNotFound
+		}
+	}
+	return fmt.Errorf("failed to save copy: %w", err)
+}
+
+var _ interfaces.CopyRepository = (*CopyStoragePostgresRepository)(nil)
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"books/core/storage/models"
+	"books/core/storage/repositories/interfaces"
+)
+
+func saveTestBook(t *testing.T, isbn string) {
+	book, _ := models.NewBook(isbn, "Test Book", "Test Author")
+	if err := repo.Save(context.Background(), book); err != nil {
+		t.Fatalf("Failed to save book: %v", err)
+	}
+}
+
+func TestSaveCopy(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	saveTestBook(t, validISBN)
+	copies := NewCopyStoragePostgresRepository(db)
+
+	bookCopy, _ := models.NewCopy(validISBN, "BC-1", "A1", models.CopyConditionNew)
+	if err := copies.SaveCopy(context.Background(), bookCopy); err != nil {
+		t.Fatalf("Failed to save copy: %v", err)
+	}
+	if bookCopy.ID == "" {
+		t.Fatalf("expected copy ID to be assigned")
+	}
+
+	found, err := copies.FindCopyByID(context.Background(), bookCopy.ID)
+	if err != nil {
+		t.Fatalf("Failed to find copy: %v", err)
+	}
+	if found.Barcode != "BC-1" || found.ShelfLocation != "A1" || found.Condition != models.CopyConditionNew {
+		t.Errorf("unexpected copy %+v", found)
+	}
+
+	duplicate, _ := models.NewCopy(validISBN, "BC-1", "", "")
+	if err := copies.SaveCopy(context.Background(), duplicate); err != interfaces.ErrDuplicateBarcode {
+		t.Errorf("expected ErrDuplicateBarcode, got %v", err)
+	}
+
+	orphan, _ := models.NewCopy("9780306406157", "BC-2", "", "")
+	if err := copies.SaveCopy(context.Background(), orphan); err != interfaces.ErrBookNotFound {
+		t.Errorf("expected ErrBookNotFound, got %v", err)
+	}
+}
+
+func TestUpdateAndDeleteCopy(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	saveTestBook(t, validISBN)
+	copies := NewCopyStoragePostgresRepository(db)
+
+	bookCopy, _ := models.NewCopy(validISBN, "BC-1", "", "")
+	_ = copies.SaveCopy(context.Background(), bookCopy)
+
+	bookCopy.Condition = models.CopyConditionPoor
+	if err := copies.UpdateCopy(context.Background(), bookCopy); err != nil {
+		t.Fatalf("Failed to update copy: %v", err)
+	}
+
+	found, _ := copies.FindCopiesByISBN(context.Background(), validISBN)
+	if len(found) != 1 || found[0].Condition != models.CopyConditionPoor {
+		t.Errorf("expected one poor copy, got %+v", found)
+	}
+
+	if err := copies.DeleteCopy(context.Background(), bookCopy.ID); err != nil {
+		t.Fatalf("Failed to delete copy: %v", err)
+	}
+	if _, err := copies.FindCopyByID(context.Background(), bookCopy.ID); err != interfaces.ErrCopyNotFound {
+		t.Errorf("expected ErrCopyNotFound, got %v", err)
+	}
+	if err := copies.DeleteCopy(context.Background(), "not-a-uuid"); err != interfaces.ErrCopyNotFound {
+		t.Errorf("expected ErrCopyNotFound for malformed ID, got %v", err)
+	}
+
+	rented, _ := models.NewCopy(validISBN, "BC-2", "", "")
+	_ = copies.SaveCopy(context.Background(), rented)
+	_, err := db.Exec(`INSERT INTO book_rentals (book_id, copy_id, user_id, borrowed_at, return_deadline, returned_at)
+		VALUES ($1, $2, 'user1', NOW(), NOW(), NOW())`, rented.ISBN, rented.ID)
+	if err != nil {
+		t.Fatalf("Failed to rent copy: %v", err)
+	}
+	if err := copies.DeleteCopy(context.Background(), rented.ID); err != interfaces.ErrCopyInUse {
+		t.Errorf("expected ErrCopyInUse for a copy with rentals, got %v", err)
+	}
+}
+
+func TestPurgingBookRemovesCopies(t *testing.T) {
+	cleanupDB(t)
+
+	validISBN := "9783161484100"
+	saveTestBook(t, validISBN)
+	copies := NewCopyStoragePostgresRepository(db)
+
+	bookCopy, _ := models.NewCopy(validISBN, "BC-1", "", "")
+	_ = copies.SaveCopy(context.Background(), bookCopy)
+
+	if err := repo.Delete(context.Background(), validISBN, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("Failed to delete book: %v", err)
+	}
+	if all, _ := copies.FindAllCopies(context.Background()); len(all) != 1 {
+		t.Errorf("expected the copies of a deleted book to be kept, got %d", len(all))
+	}
+	if err := repo.Purge(context.Background(), validISBN); err != nil {
+		t.Fatalf("Failed to purge book: %v", err)
+	}
+
+	all, err := copies.FindAllCopies(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list copies: %v", err)
+	}
+	if len(all) != 0 {
+		t.Errorf("expected copies to be removed with the book, got %d", len(all))
+	}
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import "context"
+
+// BookAvailability tells which books can be borrowed now, those with a copy
+// in circulation that is not lent out. The library's rentals decide it; the
+// in-memory book repository asks it to search by availability, where the
+// Postgres repository reads the rentals itself.
+type BookAvailability interface {
+	// AvailableISBNs returns the ISBNs of the books that can be borrowed.
+	AvailableISBNs(ctx context.Context) (map[string]bool, error)
+}
@@ -3,15 +3,54 @@ package interfaces
 import (
 	"context"
 	"errors"
+	"time"
 
 	"books/core/storage/models"
 )
 
+// BookRepository keeps the catalog. Deleted books are left out of every
+// lookup but FindDeletedByISBN, and of listings unless asked for.
 type BookRepository interface {
+	// Save adds a book or replaces its description; whether it is deleted
+	// is left as it was.
 	Save(ctx context.Context, book *models.Book) error
 	FindAll(ctx context.Context) ([]*models.Book, error)
+	// ListBooks returns a page of the books the filter selects, in its
+	// order. A cursor that does not continue the filter's order is refused
+	// with models.ErrInvalidCursor.
+	ListBooks(ctx context.Context, filter models.BookFilter) (*models.BookPage, error)
+	// Search returns a page of the books matching the words of a search,
+	// from the most relevant.
+	Search(ctx context.Context, search models.BookSearch) (*models.BookSearchResult, error)
 	FindByISBN(ctx context.Context, isbn string) (*models.Book, error)
-	Delete(ctx context.Context, isbn string) error
+	// FindByContributor returns the books crediting a contributor whose name
+	// contains name, ignoring case, ordered by title. An empty role matches
+	// any role.
+	FindByContributor(ctx context.Context, name string, role models.ContributorRole) ([]*models.Book, error)
+	// FindByAuthorID returns the books with a contributor linked to the
+	// author, ordered by title.
+	FindByAuthorID(ctx context.Context, authorID string) ([]*models.Book, error)
+	// FindDeletedByISBN returns a deleted book, failing with
+	// ErrBookNotFound when the book is not deleted.
+	FindDeletedByISBN(ctx context.Context, isbn string) (*models.Book, error)
+	// Delete marks a book deleted at the given time. Its copies and
+	// rentals are kept, so that it can be restored.
+	Delete(ctx context.Context, isbn string, at time.Time) error
+	// Restore brings a deleted book back into the catalog.
+	Restore(ctx context.Context, isbn string) error
+	// ListDeletedBefore returns the ISBNs of the books deleted before the
+	// given time.
+	ListDeletedBefore(ctx context.Context, before time.Time) ([]string, error)
+	// Purge permanently removes a deleted book, failing with ErrBookNotFound
+	// when the book is not deleted. It does not look for the rentals and
+	// holds of the book, which keep its ISBN; PurgeDeletedBooksCommand keeps
+	// the books with open ones.
+	Purge(ctx context.Context, isbn string) error
 }
 
-var ErrBookNotFound = errors.New("book not found")
\ No newline at end of file
+var ErrBookNotFound = errors.New("book not found")
+
+var (
+	ErrBookOnLoan  = errors.New("book has active rentals and cannot be deleted until they are returned")
+	ErrBookDeleted = errors.New("book was deleted: restore it instead of adding it again")
+)
\ No newline at end of file
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"books/core/storage/models"
+)
+
+type CopyRepository interface {
+	SaveCopy(ctx context.Context, bookCopy *models.Copy) error
+	UpdateCopy(ctx context.Context, bookCopy *models.Copy) error
+	FindCopyByID(ctx context.Context, id string) (*models.Copy, error)
+	FindCopiesByISBN(ctx context.Context, isbn string) ([]*models.Copy, error)
+	FindAllCopies(ctx context.Context) ([]*models.Copy, error)
+	DeleteCopy(ctx context.Context, id string) error
+	DeleteCopiesByISBN(ctx context.Context, isbn string) error
+}
+
+var (
+	ErrCopyNotFound     = errors.New("copy not found")
+	ErrDuplicateBarcode = errors.New("a copy with this barcode already exists")
+	ErrCopyInUse        = errors.New("copy has rental history and cannot be deleted")
+)
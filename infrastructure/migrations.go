@@ -28,6 +28,498 @@ var Migrations = []Migration{
 			);
 		`,
 	},
+	{
+		ID:          2,
+		Name:        "create_book_rentals_table",
+		Description: "Creates the book_rentals table with one open rental per book",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS book_rentals (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				book_id VARCHAR(13) NOT NULL REFERENCES books(isbn) ON UPDATE CASCADE,
+				user_id VARCHAR(255) NOT NULL,
+				borrowed_at TIMESTAMPTZ NOT NULL,
+				return_deadline TIMESTAMPTZ NOT NULL,
+				returned_at TIMESTAMPTZ
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_book_rentals_user_id ON book_rentals (user_id);
+
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_book_rentals_active_book
+				ON book_rentals (book_id)
+				WHERE returned_at IS NULL;
+		`,
+	},
+	{
+		ID:          3,
+		Name:        "add_book_rentals_returned_late",
+		Description: "Records whether a closed rental was returned after its deadline",
+		SQL: `
+			ALTER TABLE book_rentals
+				ADD COLUMN IF NOT EXISTS returned_late BOOLEAN NOT NULL DEFAULT FALSE;
+		`,
+	},
+	{
+		ID:          4,
+		Name:        "add_books_category",
+		Description: "Adds a category to books for loan policy selection",
+		SQL: `
+			ALTER TABLE books
+				ADD COLUMN IF NOT EXISTS category VARCHAR(50) NOT NULL DEFAULT 'standard';
+		`,
+	},
+	{
+		ID:          5,
+		Name:        "add_book_rentals_renewals",
+		Description: "Tracks the patron type and renewal count of each rental",
+		SQL: `
+			ALTER TABLE book_rentals
+				ADD COLUMN IF NOT EXISTS patron_type VARCHAR(50) NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS renewal_count INTEGER NOT NULL DEFAULT 0;
+		`,
+	},
+	{
+		ID:          6,
+		Name:        "create_book_holds_table",
+		Description: "Creates the book_holds table holding the FIFO reservation queue per book",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS book_holds (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				book_id VARCHAR(13) NOT NULL REFERENCES books(isbn) ON UPDATE CASCADE,
+				user_id VARCHAR(255) NOT NULL,
+				status VARCHAR(20) NOT NULL,
+				placed_at TIMESTAMPTZ NOT NULL,
+				ready_at TIMESTAMPTZ,
+				pickup_deadline TIMESTAMPTZ,
+				closed_at TIMESTAMPTZ,
+				queue_position BIGSERIAL NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_book_holds_user_id ON book_holds(user_id);
+			CREATE INDEX IF NOT EXISTS idx_book_holds_queue
+				ON book_holds(book_id, queue_position)
+				WHERE status IN ('waiting', 'ready');
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_book_holds_active_user
+				ON book_holds(book_id, user_id)
+				WHERE status IN ('waiting', 'ready');
+		`,
+	},
+	{
+		ID:          7,
+		Name:        "create_ledger_entries_table",
+		Description: "Creates the fines ledger of charges, payments and waivers per user",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS ledger_entries (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				user_id VARCHAR(255) NOT NULL,
+				entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN ('charge', 'payment', 'waiver')),
+				amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+				rental_id UUID REFERENCES book_rentals(id),
+				note TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMPTZ NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_ledger_entries_user_id ON ledger_entries(user_id, created_at);
+		`,
+	},
+	{
+		ID:          8,
+		Name:        "create_book_copies_table",
+		Description: "Adds physical copies per ISBN, gives every existing book one copy and points rentals at copies",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS book_copies (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				isbn VARCHAR(13) NOT NULL REFERENCES books(isbn) ON UPDATE CASCADE ON DELETE CASCADE,
+				barcode VARCHAR(64) NOT NULL UNIQUE,
+				shelf_location VARCHAR(100) NOT NULL DEFAULT '',
+				condition VARCHAR(20) NOT NULL DEFAULT 'good',
+				added_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_book_copies_isbn ON book_copies(isbn);
+
+			INSERT INTO book_copies (isbn, barcode)
+			SELECT isbn, isbn || '-1' FROM books;
+
+			ALTER TABLE book_rentals ADD COLUMN IF NOT EXISTS copy_id UUID REFERENCES book_copies(id);
+
+			UPDATE book_rentals r
+			SET copy_id = c.id
+			FROM book_copies c
+			WHERE c.isbn = r.book_id;
+
+			ALTER TABLE book_rentals ALTER COLUMN copy_id SET NOT NULL;
+
+			DROP INDEX IF EXISTS idx_book_rentals_active_book;
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_book_rentals_active_copy
+				ON book_rentals(copy_id)
+				WHERE returned_at IS NULL;
+		`,
+	},
+	{
+		ID:          9,
+		Name:        "create_patrons_table",
+		Description: "Creates registered patrons with their card number, type and membership status",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS patrons (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				card_number VARCHAR(32) NOT NULL UNIQUE,
+				name VARCHAR(255) NOT NULL,
+				email VARCHAR(255) NOT NULL DEFAULT '',
+				patron_type VARCHAR(50) NOT NULL,
+				status VARCHAR(20) NOT NULL CHECK (status IN ('active', 'suspended', 'expired')),
+				expires_at TIMESTAMPTZ NOT NULL,
+				registered_at TIMESTAMPTZ NOT NULL
+			);
+		`,
+	},
+	{
+		ID:          10,
+		Name:        "create_rental_notices_table",
+		Description: "Records due-date reminders and overdue notices so none is sent twice",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS rental_notices (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				rental_id UUID NOT NULL REFERENCES book_rentals(id) ON DELETE CASCADE,
+				user_id VARCHAR(255) NOT NULL,
+				kind VARCHAR(20) NOT NULL CHECK (kind IN ('due_soon', 'overdue')),
+				days INTEGER NOT NULL,
+				due_at TIMESTAMPTZ NOT NULL,
+				sent_at TIMESTAMPTZ NOT NULL,
+				UNIQUE (rental_id, kind, days, due_at)
+			);
+		`,
+	},
+	{
+		ID:          11,
+		Name:        "add_lost_and_damaged_items",
+		Description: "Adds copy circulation status, rental close reasons and ledger reversals for lost and damaged items",
+		SQL: `
+			ALTER TABLE book_copies
+				ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'circulating'
+				CHECK (status IN ('circulating', 'lost', 'withdrawn'));
+
+			ALTER TABLE book_rentals
+				ADD COLUMN IF NOT EXISTS close_reason VARCHAR(20) NOT NULL DEFAULT ''
+				CHECK (close_reason IN ('', 'lost', 'damaged', 'found'));
+
+			ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check;
+			ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_entry_type_check
+				CHECK (entry_type IN ('charge', 'payment', 'waiver', 'reversal'));
+		`,
+	},
+	{
+		ID:          12,
+		Name:        "create_opening_calendar",
+		Description: "Creates the weekly opening hours and closed dates used to compute due dates",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS opening_hours (
+				weekday SMALLINT PRIMARY KEY CHECK (weekday BETWEEN 0 AND 6),
+				opens_at TIME NOT NULL,
+				closes_at TIME NOT NULL,
+				CHECK (opens_at < closes_at)
+			);
+
+			CREATE TABLE IF NOT EXISTS closed_dates (
+				closed_on DATE PRIMARY KEY,
+				reason VARCHAR(255) NOT NULL DEFAULT ''
+			);
+		`,
+	},
+	{
+		ID:          13,
+		Name:        "create_branches_and_transfers",
+		Description: "Adds library branches, the home and current branch of copies, hold pickup branches and copy transfers",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS branches (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				code VARCHAR(20) NOT NULL UNIQUE,
+				name VARCHAR(255) NOT NULL,
+				address VARCHAR(500) NOT NULL DEFAULT '',
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+
+			ALTER TABLE book_copies
+				ADD COLUMN IF NOT EXISTS home_branch_id UUID REFERENCES branches(id),
+				ADD COLUMN IF NOT EXISTS current_branch_id UUID REFERENCES branches(id);
+
+			ALTER TABLE book_copies DROP CONSTRAINT IF EXISTS book_copies_status_check;
+			ALTER TABLE book_copies ADD CONSTRAINT book_copies_status_check
+				CHECK (status IN ('circulating', 'in_transit', 'lost', 'withdrawn'));
+
+			CREATE INDEX IF NOT EXISTS idx_book_copies_current_branch ON book_copies(current_branch_id);
+
+			ALTER TABLE book_holds ADD COLUMN IF NOT EXISTS pickup_branch_id UUID REFERENCES branches(id);
+
+			CREATE TABLE IF NOT EXISTS copy_transfers (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				copy_id UUID NOT NULL REFERENCES book_copies(id) ON DELETE CASCADE,
+				book_id VARCHAR(13) NOT NULL REFERENCES books(isbn) ON UPDATE CASCADE ON DELETE CASCADE,
+				from_branch_id UUID NOT NULL REFERENCES branches(id),
+				to_branch_id UUID NOT NULL REFERENCES branches(id),
+				reason VARCHAR(20) NOT NULL CHECK (reason IN ('manual', 'return', 'hold')),
+				started_at TIMESTAMPTZ NOT NULL,
+				received_at TIMESTAMPTZ
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_copy_transfers_open_book
+				ON copy_transfers(book_id, started_at)
+				WHERE received_at IS NULL;
+			CREATE INDEX IF NOT EXISTS idx_copy_transfers_open_destination
+				ON copy_transfers(to_branch_id, started_at)
+				WHERE received_at IS NULL;
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_copy_transfers_open_copy
+				ON copy_transfers(copy_id)
+				WHERE received_at IS NULL;
+		`,
+	},
+	{
+		ID:          14,
+		Name:        "add_books_bibliographic_details",
+		Description: "Adds subtitle, publisher, publication year, edition, language, page count, description and subjects to books",
+		SQL: `
+			ALTER TABLE books
+				ADD COLUMN IF NOT EXISTS subtitle VARCHAR(255) NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS publisher VARCHAR(255) NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS publication_year INTEGER NOT NULL DEFAULT 0 CHECK (publication_year >= 0),
+				ADD COLUMN IF NOT EXISTS edition VARCHAR(50) NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS language VARCHAR(2) NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS page_count INTEGER NOT NULL DEFAULT 0 CHECK (page_count >= 0),
+				ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS subjects TEXT[] NOT NULL DEFAULT '{}';
+
+			-- published_at held the time a book was added rather than when it
+			-- was published, so the old values are dropped.
+			ALTER TABLE books ALTER COLUMN published_at DROP NOT NULL;
+			UPDATE books SET published_at = NULL;
+			ALTER TABLE books ALTER COLUMN published_at TYPE DATE;
+
+			CREATE INDEX IF NOT EXISTS idx_books_language ON books(language);
+		`,
+	},
+	{
+		ID:          15,
+		Name:        "create_book_contributors_table",
+		Description: "Credits books to an ordered list of contributors with roles, starting from their authors",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS book_contributors (
+				isbn VARCHAR(13) NOT NULL REFERENCES books(isbn) ON UPDATE CASCADE ON DELETE CASCADE,
+				position INTEGER NOT NULL CHECK (position >= 0),
+				name VARCHAR(255) NOT NULL,
+				role VARCHAR(20) NOT NULL CHECK (role IN ('author', 'editor', 'translator', 'illustrator')),
+				PRIMARY KEY (isbn, position)
+			);
+
+			INSERT INTO book_contributors (isbn, position, name, role)
+			SELECT isbn, 0, author, 'author' FROM books
+			WHERE author <> ''
+			ON CONFLICT DO NOTHING;
+		`,
+	},
+	{
+		ID:          16,
+		Name:        "create_authors_table",
+		Description: "Adds author authority records with alternate names and links book contributors to them",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS authors (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				name VARCHAR(255) NOT NULL,
+				alternate_names TEXT[] NOT NULL DEFAULT '{}',
+				surnames TEXT[] NOT NULL DEFAULT '{}',
+				birth_year INTEGER NOT NULL DEFAULT 0 CHECK (birth_year >= 0),
+				death_year INTEGER NOT NULL DEFAULT 0 CHECK (death_year >= 0),
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+
+			-- Matching names looks authors up by the surnames of their name forms.
+			CREATE INDEX IF NOT EXISTS idx_authors_surnames ON authors USING GIN (surnames);
+
+			ALTER TABLE book_contributors
+				ADD COLUMN IF NOT EXISTS author_id UUID REFERENCES authors(id) ON DELETE SET NULL;
+
+			CREATE INDEX IF NOT EXISTS idx_book_contributors_author ON book_contributors(author_id);
+		`,
+	},
+	{
+		ID:          17,
+		Name:        "normalize_book_isbns",
+		Description: "Stores every book under its canonical ISBN-13, merging books stored under two forms of one ISBN",
+		SQL: `
+			-- The canonical ISBN-13 of a valid ISBN, as the isbn package computes it.
+			CREATE FUNCTION pg_temp.isbn13(value TEXT) RETURNS TEXT AS $$
+				SELECT CASE
+					WHEN cleaned ~ '^[0-9]{9}[0-9X]$' THEN
+						'978' || left(cleaned, 9) || ((10 - (
+							SELECT sum(substr('978' || left(cleaned, 9), i, 1)::int * CASE WHEN i % 2 = 1 THEN 1 ELSE 3 END)
+							FROM generate_series(1, 12) AS i
+						) % 10) % 10)::text
+					ELSE cleaned
+				END
+				FROM (SELECT upper(translate(value, '- ', '')) AS cleaned) AS input
+			$$ LANGUAGE SQL IMMUTABLE;
+
+			CREATE TEMP TABLE isbn_renames ON COMMIT DROP AS
+				SELECT isbn AS old_isbn, pg_temp.isbn13(isbn) AS new_isbn FROM books;
+
+			-- Of several books stored under forms of one ISBN, the one already
+			-- canonical, or else the first, is kept and takes over the others'
+			-- copies, rentals, holds and transfers.
+			CREATE TEMP TABLE isbn_merges ON COMMIT DROP AS
+				SELECT old_isbn, kept_isbn FROM (
+					SELECT old_isbn, first_value(old_isbn) OVER (
+						PARTITION BY new_isbn ORDER BY old_isbn = new_isbn DESC, old_isbn
+					) AS kept_isbn
+					FROM isbn_renames
+				) AS groups
+				WHERE old_isbn <> kept_isbn;
+
+			-- A patron waiting for several forms keeps only their earliest hold.
+			UPDATE book_holds SET status = 'cancelled', closed_at = NOW()
+			WHERE id IN (
+				SELECT id FROM (
+					SELECT h.id, row_number() OVER (
+						PARTITION BY r.new_isbn, h.user_id ORDER BY h.queue_position
+					) AS n
+					FROM book_holds h
+					JOIN isbn_renames r ON r.old_isbn = h.book_id
+					WHERE h.status IN ('waiting', 'ready')
+				) AS ranked
+				WHERE n > 1
+			);
+
+			UPDATE book_copies c SET isbn = m.kept_isbn FROM isbn_merges m WHERE c.isbn = m.old_isbn;
+			UPDATE book_rentals r SET book_id = m.kept_isbn FROM isbn_merges m WHERE r.book_id = m.old_isbn;
+			UPDATE book_holds h SET book_id = m.kept_isbn FROM isbn_merges m WHERE h.book_id = m.old_isbn;
+			UPDATE copy_transfers t SET book_id = m.kept_isbn FROM isbn_merges m WHERE t.book_id = m.old_isbn;
+			DELETE FROM books b USING isbn_merges m WHERE b.isbn = m.old_isbn;
+
+			-- The foreign keys cascade the new ISBNs to the copies, rentals,
+			-- holds, transfers and contributors.
+			UPDATE books b SET isbn = r.new_isbn
+			FROM isbn_renames r
+			WHERE b.isbn = r.old_isbn AND r.old_isbn <> r.new_isbn;
+		`,
+	},
+	{
+		ID:          18,
+		Name:        "add_books_listing_indexes",
+		Description: "Indexes the orders the catalog can be listed in, so that pages are read by key",
+		SQL: `
+			-- The expressions match the ORDER BY of the book repository's listing.
+			CREATE INDEX IF NOT EXISTS idx_books_title_sort ON books ((lower(title) COLLATE "C"), (isbn COLLATE "C"));
+			CREATE INDEX IF NOT EXISTS idx_books_author_sort ON books ((lower(author) COLLATE "C"), (isbn COLLATE "C"));
+			CREATE INDEX IF NOT EXISTS idx_books_publisher_sort ON books ((lower(publisher) COLLATE "C"), (isbn COLLATE "C"));
+			CREATE INDEX IF NOT EXISTS idx_books_year_sort ON books (publication_year, (isbn COLLATE "C"));
+		`,
+	},
+	{
+		ID:          19,
+		Name:        "add_books_search_vector",
+		Description: "Adds a full-text search vector over the title, author and description of books",
+		SQL: `
+			-- The simple configuration neither stems words nor drops stop
+			-- words, which keeps search the same as the in-memory index's.
+			ALTER TABLE books ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+				setweight(to_tsvector('simple', title), 'A') ||
+				setweight(to_tsvector('simple', author), 'B') ||
+				setweight(to_tsvector('simple', description), 'C')
+			) STORED;
+
+			CREATE INDEX IF NOT EXISTS idx_books_search ON books USING GIN (search_vector);
+		`,
+	},
+	{
+		ID:          20,
+		Name:        "add_typo_tolerant_search",
+		Description: "Folds diacritics in search and indexes the title and author words by trigram",
+		SQL: `
+			CREATE EXTENSION IF NOT EXISTS unaccent;
+			CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+			-- books_search is the simple configuration with diacritics folded
+			-- away, so that "Camus" finds "Camús".
+			CREATE TEXT SEARCH CONFIGURATION books_search (COPY = simple);
+			ALTER TEXT SEARCH CONFIGURATION books_search
+				ALTER MAPPING FOR word, numword, hword, numhword, hword_part, hword_numpart
+				WITH unaccent, simple;
+
+			-- Text is now stored in normalization form C.
+			UPDATE books SET
+				title = normalize(title, NFC),
+				author = normalize(author, NFC),
+				subtitle = normalize(subtitle, NFC),
+				publisher = normalize(publisher, NFC),
+				description = normalize(description, NFC);
+			UPDATE book_contributors SET name = normalize(name, NFC);
+
+			ALTER TABLE books DROP COLUMN search_vector;
+			ALTER TABLE books ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+				setweight(to_tsvector('books_search', title), 'A') ||
+				setweight(to_tsvector('books_search', author), 'B') ||
+				setweight(to_tsvector('books_search', description), 'C')
+			) STORED;
+			CREATE INDEX idx_books_search ON books USING GIN (search_vector);
+
+			-- The title and author words of each book, which searched words
+			-- are compared to by trigram to tolerate typos.
+			CREATE TABLE book_words (
+				isbn VARCHAR(13) NOT NULL REFERENCES books(isbn) ON DELETE CASCADE ON UPDATE CASCADE,
+				word TEXT NOT NULL,
+				PRIMARY KEY (isbn, word)
+			);
+			CREATE INDEX idx_book_words_trigram ON book_words USING GIN (word gin_trgm_ops);
+
+			INSERT INTO book_words (isbn, word)
+			SELECT DISTINCT isbn, unnest(tsvector_to_array(to_tsvector('books_search', title || ' ' || author)))
+			FROM books;
+		`,
+	},
+	{
+		ID:          21,
+		Name:        "add_books_soft_delete",
+		Description: "Marks deleted books with deleted_at instead of removing them, so they can be restored",
+		SQL: `
+			ALTER TABLE books ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+			-- Purges look up the books deleted before a time.
+			CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books (deleted_at)
+				WHERE deleted_at IS NOT NULL;
+		`,
+	},
+	{
+		ID:          22,
+		Name:        "add_ledger_entries_rental_fine_index",
+		Description: "Charges the overdue fine of a rental at most once, so a failed return can be retried",
+		SQL: `
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_entries_rental_fine ON ledger_entries (rental_id)
+				WHERE note = 'overdue fine';
+		`,
+	},
+	{
+		ID:          23,
+		Name:        "lower_books_listing_indexes_in_c_collation",
+		Description: "Lowercases the listing sort keys in the C collation, so they do not depend on the database's locale",
+		SQL: `
+			-- The expressions match the ORDER BY of the book repository's listing.
+			DROP INDEX IF EXISTS idx_books_title_sort;
+			DROP INDEX IF EXISTS idx_books_author_sort;
+			DROP INDEX IF EXISTS idx_books_publisher_sort;
+			CREATE INDEX IF NOT EXISTS idx_books_title_sort ON books (lower(title COLLATE "C"), (isbn COLLATE "C"));
+			CREATE INDEX IF NOT EXISTS idx_books_author_sort ON books (lower(author COLLATE "C"), (isbn COLLATE "C"));
+			CREATE INDEX IF NOT EXISTS idx_books_publisher_sort ON books (lower(publisher COLLATE "C"), (isbn COLLATE "C"));
+		`,
+	},
+	{
+		ID:          24,
+		Name:        "detach_rental_history_from_books",
+		Description: "Keeps the rentals and holds of a purged book, with its ISBN and copy, instead of refusing the purge",
+		SQL: `
+			-- The repositories check the book and copy when a rental or hold
+			-- is written, and a copy with rentals is still refused deletion.
+			ALTER TABLE book_rentals DROP CONSTRAINT IF EXISTS book_rentals_book_id_fkey;
+			ALTER TABLE book_rentals DROP CONSTRAINT IF EXISTS book_rentals_copy_id_fkey;
+			ALTER TABLE book_holds DROP CONSTRAINT IF EXISTS book_holds_book_id_fkey;
+		`,
+	},
 }
 
 func RunMigrations(db *sql.DB) error {
@@ -0,0 +1,86 @@
+// Package testdb starts the throwaway Postgres database the repository
+// integration tests run against.
+package testdb
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"books/infrastructure"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+
+	_ "github.com/lib/pq"
+)
+
+// Run starts a migrated Postgres container, hands its connection to setup
+// and runs the package's tests, exiting with their result. Without Docker
+// the tests are skipped. It is meant to be called from TestMain.
+func Run(m *testing.M, setup func(db *sql.DB)) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.Printf("Could not construct pool: %s - skipping integration tests", err)
+		os.Exit(0)
+	}
+
+	err = pool.Client.Ping()
+	if err != nil {
+		log.Printf("Could not connect to Docker: %s - skipping integration tests", err)
+		os.Exit(0)
+	}
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "postgres",
+		Tag:        "15-alpine",
+		Env: []string{
+			"POSTGRES_USER=test",
+			"POSTGRES_PASSWORD=test",
+			"POSTGRES_DB=testdb",
+			"listen_addresses = '*'",
+		},
+	}, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		log.Fatalf("Could not start resource: %s", err)
+	}
+
+	hostAndPort := resource.GetHostPort("5432/tcp")
+	databaseUrl := fmt.Sprintf("postgres://test:test@%s/testdb?sslmode=disable", hostAndPort)
+
+	log.Printf("Connecting to database on url: %s", databaseUrl)
+
+	_ = resource.Expire(120)
+
+	var db *sql.DB
+	pool.MaxWait = 120 * time.Second
+	if err = pool.Retry(func() error {
+		db, err = sql.Open("postgres", databaseUrl)
+		if err != nil {
+			return err
+		}
+		return db.Ping()
+	}); err != nil {
+		log.Fatalf("Could not connect to docker: %s", err)
+	}
+
+	if err := infrastructure.RunMigrations(db); err != nil {
+		log.Fatalf("Could not run migrations: %s", err)
+	}
+
+	setup(db)
+
+	code := m.Run()
+
+	if err := pool.Purge(resource); err != nil {
+		log.Fatalf("Could not purge resource: %s", err)
+	}
+
+	os.Exit(code)
+}
@@ -1,15 +1,30 @@
 package main
 
 import (
+	"books/config"
 	"books/core"
+	author_repositories "books/core/authors/repositories"
+	branch_repositories "books/core/branches/repositories"
+	calendar_repositories "books/core/calendar/repositories"
+	"books/core/isbn"
+	"books/core/library/policies"
+	library_repositories "books/core/library/repositories"
+	"books/core/notices"
+	"books/core/notices/notifiers"
+	notice_repositories "books/core/notices/repositories"
+	patron_repositories "books/core/patrons/repositories"
 	"books/core/storage/repositories"
 	"books/infrastructure"
 	httpControllers "books/ports/http-controlers"
+	"context"
 	"log"
 	"os"
+	"time"
 )
 
 func main() {
+	cfg := config.Load()
+
 	dbConfig := infrastructure.NewConfig(
 		os.Getenv("DB_HOST"),
 		5432,
@@ -30,12 +45,85 @@ func main() {
 	}
 
 	bookRepo := repositories.NewBookStoragePostgresRepository(db)
+	copyRepo := repositories.NewCopyStoragePostgresRepository(db)
+	rentalRepo := library_repositories.NewBookRentalPostgresRepository(db)
+	holdRepo := library_repositories.NewHoldPostgresRepository(db)
+	ledgerRepo := library_repositories.NewLedgerPostgresRepository(db)
+	patronRepo := patron_repositories.NewPatronPostgresRepository(db)
+	calendarRepo := calendar_repositories.NewCalendarPostgresRepository(db)
+
+	rules := policies.DefaultLendingRules()
+	rules.HoldPickupWindow = time.Duration(cfg.Library.HoldPickupDays) * 24 * time.Hour
+	rules.MaxUnpaidFinesCents = cfg.Library.MaxUnpaidFinesCents
+	rules.MembershipPeriod = time.Duration(cfg.Library.MembershipDays) * 24 * time.Hour
+	rules.BorrowingLimits = cfg.Library.BorrowingLimits
+	if cfg.Library.LoanPoliciesFile != "" {
+		rules.Loans, err = policies.LoadLoanPolicies(cfg.Library.LoanPoliciesFile)
+		if err != nil {
+			log.Fatalf("Failed to load loan policies: %v", err)
+		}
+	}
+
+	var isbnRanges *isbn.Ranges
+	if cfg.Catalog.ISBNRangesFile != "" {
+		isbnRanges, err = isbn.LoadRanges(cfg.Catalog.ISBNRangesFile)
+		if err != nil {
+			log.Fatalf("Failed to load ISBN ranges: %v", err)
+		}
+		log.Printf("Loaded the ISBN ranges of %s", isbnRanges.Date)
+	}
 
-	appCore := core.NewCore(bookRepo)
+	appCore := core.NewCore(core.Repositories{
+		Books:     bookRepo,
+		Copies:    copyRepo,
+		Rentals:   rentalRepo,
+		Holds:     holdRepo,
+		Ledger:    ledgerRepo,
+		Patrons:   patronRepo,
+		Calendar:  calendarRepo,
+		Branches:  branch_repositories.NewBranchPostgresRepository(db),
+		Transfers: library_repositories.NewTransferPostgresRepository(db),
+		Authors:   author_repositories.NewAuthorPostgresRepository(db),
+	}, rules, isbnRanges)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if notifier := newNotifier(cfg.Notices); notifier != nil {
+		scheduler := notices.NewScheduler(rentalRepo, patronRepo, notice_repositories.NewNoticePostgresRepository(db), notifier, notices.Schedule{
+			ReminderDays: cfg.Notices.ReminderDays,
+			OverdueDays:  cfg.Notices.OverdueDays,
+		})
+		go scheduler.Run(ctx, time.Duration(cfg.Notices.IntervalMinutes)*time.Minute)
+	}
 
 	httpModule := httpControllers.NewModuleWithDB(appCore, db)
 	if err := httpModule.Start(":8080"); err != nil {
 		log.Fatalf("Failed to start HTTP server: %v", err)
 	}
 
-}
\ No newline at end of file
+}
+
+// newNotifier returns the configured notifier, or nil when notices are off.
+func newNotifier(cfg config.NoticesConfig) notifiers.Notifier {
+	switch cfg.Notifier {
+	case "smtp":
+		return notifiers.NewSMTPNotifier(notifiers.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			log.Fatalf("NOTICE_WEBHOOK_URL is required for the webhook notifier")
+		}
+		return notifiers.NewWebhookNotifier(cfg.WebhookURL)
+	case "":
+		return nil
+	default:
+		log.Fatalf("Unknown notifier %q", cfg.Notifier)
+		return nil
+	}
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"books/core"
+	author_models "books/core/authors/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthorController struct {
+	core *core.Core
+}
+
+func NewAuthorController(core *core.Core) *AuthorController {
+	return &AuthorController{core: core}
+}
+
+type AddAuthorRequest struct {
+	Name           string   `json:"name" binding:"required,max=255"`
+	AlternateNames []string `json:"alternate_names" binding:"max=50"`
+	BirthYear      int      `json:"birth_year"`
+	DeathYear      int      `json:"death_year"`
+}
+
+type UpdateAuthorRequest struct {
+	Name           string   `json:"name" binding:"max=255"`
+	AlternateNames []string `json:"alternate_names" binding:"max=50"`
+	BirthYear      int      `json:"birth_year"`
+	DeathYear      int      `json:"death_year"`
+}
+
+type MergeAuthorsRequest struct {
+	DuplicateID string `json:"duplicate_id" binding:"required,max=64"`
+}
+
+func (c *AuthorController) AddAuthor(ctx *gin.Context) {
+	var request AddAuthorRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	author, err := c.core.AddAuthor(ctx, request.Name, request.AlternateNames, request.BirthYear, request.DeathYear)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("AddAuthor error: %v", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Author added successfully",
+		"author":  authorResponse(author),
+	})
+}
+
+func (c *AuthorController) GetAuthors(ctx *gin.Context) {
+	authors, err := c.core.GetAuthors(ctx)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetAuthors error: %v", err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(authors))
+	for _, author := range authors {
+		result = append(result, authorResponse(author))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"authors": result,
+	})
+}
+
+// MatchAuthors suggests the authors the ?name= may refer to.
+func (c *AuthorController) MatchAuthors(ctx *gin.Context) {
+	matches, err := c.core.MatchAuthors(ctx, ctx.Query("name"))
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("MatchAuthors error: %v", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"matches": matchesResponse(matches),
+	})
+}
+
+func (c *AuthorController) GetAuthor(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	author, err := c.core.GetAuthor(ctx, id)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetAuthor error for author %s: %v", id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, authorResponse(author))
+}
+
+func (c *AuthorController) UpdateAuthor(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var request UpdateAuthorRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	author, err := c.core.UpdateAuthor(ctx, id, request.Name, request.AlternateNames, request.BirthYear, request.DeathYear)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("UpdateAuthor error for author %s: %v", id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Author updated successfully",
+		"author":  authorResponse(author),
+	})
+}
+
+func (c *AuthorController) GetAuthorBooks(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	books, err := c.core.GetAuthorBooks(ctx, id)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetAuthorBooks error for author %s: %v", id, err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(books))
+	for _, book := range books {
+		result = append(result, bookResponse(c.core, book))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"books": result,
+	})
+}
+
+// MergeAuthors folds the duplicate_id author into the author in the path.
+func (c *AuthorController) MergeAuthors(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var request MergeAuthorsRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	author, err := c.core.MergeAuthors(ctx, id, request.DuplicateID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("MergeAuthors error for author %s: %v", id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Authors merged successfully",
+		"author":  authorResponse(author),
+	})
+}
+
+func authorResponse(author *author_models.Author) gin.H {
+	return gin.H{
+		"id":              author.ID,
+		"name":            author.Name,
+		"alternate_names": author.AlternateNames,
+		"birth_year":      author.BirthYear,
+		"death_year":      author.DeathYear,
+		"created_at":      author.CreatedAt,
+	}
+}
+
+func matchesResponse(matches []author_models.Match) []gin.H {
+	result := make([]gin.H, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, gin.H{
+			"author":       authorResponse(match.Author),
+			"matched_name": match.Name,
+			"exact":        match.Exact,
+		})
+	}
+	return result
+}
@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestAuthors(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	w := postJSON(router, "/authors", map[string]interface{}{
+		"name":            "J. R. R. Tolkien",
+		"alternate_names": []string{"John Ronald Reuel Tolkien"},
+		"birth_year":      1892,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var created map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	authorID, _ := created["author"]["id"].(string)
+	if authorID == "" {
+		t.Fatalf("unexpected author %v", created["author"])
+	}
+
+	w = postJSON(router, "/authors", map[string]interface{}{"name": "Tolkien, J.R.R.", "birth_year": 1892})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d for a duplicate author, got %d", http.StatusConflict, w.Code)
+	}
+
+	w = postJSON(router, "/authors", map[string]interface{}{"name": "Christopher Tolkien", "birth_year": 1924})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	w = postJSON(router, "/authors", map[string]interface{}{"name": "Ann Writer", "birth_year": 1950, "death_year": 1900})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for a death before birth, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, "/authors/"+authorID, bytes.NewBufferString(`{"death_year": 1973}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/authors/"+authorID, nil)
+	router.ServeHTTP(w, req)
+
+	var found map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &found)
+	if found["death_year"] != float64(1973) || found["birth_year"] != float64(1892) {
+		t.Errorf("unexpected author %v", found)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/authors", nil)
+	router.ServeHTTP(w, req)
+
+	var listed map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &listed)
+	if len(listed["authors"]) != 2 || listed["authors"][0]["name"] != "Christopher Tolkien" {
+		t.Errorf("unexpected authors %v", listed["authors"])
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/authors/missing", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestMatchAuthors(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	_, _ = appCore.AddAuthor(context.TODO(), "J. R. R. Tolkien", nil, 1892, 0)
+	_, _ = appCore.AddAuthor(context.TODO(), "Christopher Tolkien", nil, 1924, 0)
+	_, _ = appCore.AddAuthor(context.TODO(), "Mary Westmacott", []string{"Agatha Christie"}, 1890, 0)
+
+	tests := []struct {
+		name   string
+		author string
+		exact  bool
+	}{
+		{name: "Tolkien, J.R.R.", author: "J. R. R. Tolkien", exact: true},
+		{name: "JRR Tolkien", author: "J. R. R. Tolkien", exact: true},
+		{name: "john ronald reuel tolkien", author: "J. R. R. Tolkien"},
+		{name: "Christie, Agatha", author: "Mary Westmacott", exact: true},
+		{name: "C. Tolkien", author: "Christopher Tolkien"},
+		{name: "Simon Tolkien"},
+		{name: "Tolkien"},
+	}
+
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/authors/matches?name="+url.QueryEscape(tc.name), nil)
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status %d, got %d. Body: %s", tc.name, http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var response map[string][]map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		matches := response["matches"]
+		if tc.author == "" {
+			if len(matches) != 0 {
+				t.Errorf("%s: expected no match, got %v", tc.name, matches)
+			}
+			continue
+		}
+
+		if len(matches) != 1 {
+			t.Errorf("%s: expected one match, got %v", tc.name, matches)
+			continue
+		}
+		author, _ := matches[0]["author"].(map[string]interface{})
+		if author["name"] != tc.author || matches[0]["exact"] != tc.exact {
+			t.Errorf("%s: expected %s (exact %v), got %v", tc.name, tc.author, tc.exact, matches[0])
+		}
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/authors/matches", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d without a name, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestLinkBooksToAuthors(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	tolkien, _ := appCore.AddAuthor(context.TODO(), "J. R. R. Tolkien", nil, 1892, 0)
+
+	// An unlinked contributor is added as given, with suggestions to link it.
+	w := postJSON(router, "/books", map[string]interface{}{"isbn": "9783161484100", "title": "The Hobbit", "author": "Tolkien, J.R.R."})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	suggestions, _ := response["author_suggestions"].([]interface{})
+	if len(suggestions) != 1 {
+		t.Fatalf("expected a suggestion for the author, got %v", response)
+	}
+
+	// A linked contributor takes the author's name when none is given.
+	w = postJSON(router, "/books", map[string]interface{}{
+		"isbn":         "9780306406157",
+		"title":        "The Silmarillion",
+		"contributors": []map[string]string{{"author_id": tolkien.ID}},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	response = nil
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	book, _ := response["book"].(map[string]interface{})
+	if book["author"] != "J. R. R. Tolkien" || response["author_suggestions"] != nil {
+		t.Errorf("expected the linked author to be credited, got %v", response)
+	}
+
+	w = postJSON(router, "/books", map[string]interface{}{
+		"isbn":         "9780140449136",
+		"title":        "Other",
+		"contributors": []map[string]string{{"name": "Someone", "author_id": "missing"}},
+	})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for an unknown author, got %d", http.StatusNotFound, w.Code)
+	}
+
+	// Merging a duplicate re-points its books to the canonical author.
+	duplicate, _ := appCore.AddAuthor(context.TODO(), "John Ronald Reuel Tolkien", nil, 0, 1973)
+	req, _ := http.NewRequest(http.MethodPut, "/books/9783161484100", bytes.NewBufferString(
+		`{"contributors": [{"name": "Tolkien, J.R.R.", "author_id": "`+duplicate.ID+`"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = postJSON(router, "/authors/"+tolkien.ID+"/merge", map[string]interface{}{"duplicate_id": tolkien.ID})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for a self merge, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = postJSON(router, "/authors/"+tolkien.ID+"/merge", map[string]interface{}{"duplicate_id": duplicate.ID})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var merged map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &merged)
+	alternates, _ := merged["author"]["alternate_names"].([]interface{})
+	if len(alternates) != 1 || merged["author"]["death_year"] != float64(1973) {
+		t.Errorf("expected the duplicate's details to be kept, got %v", merged["author"])
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/authors/"+tolkien.ID+"/books", nil)
+	router.ServeHTTP(w, req)
+
+	var listed map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &listed)
+	if len(listed["books"]) != 2 {
+		t.Errorf("expected both books to credit the author, got %v", listed["books"])
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/authors/"+duplicate.ID, nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected the duplicate to be removed, got status %d", w.Code)
+	}
+}
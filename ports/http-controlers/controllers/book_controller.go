@@ -2,10 +2,20 @@ package controllers
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"books/core"
+	author_errors "books/core/authors/errors"
+	branch_errors "books/core/branches/errors"
+	calendar_errors "books/core/calendar/errors"
+	"books/core/isbn"
+	library_errors "books/core/library/errors"
+	patron_errors "books/core/patrons/errors"
+	"books/core/storage/models"
 	"books/core/storage/repositories/interfaces"
 
 	"github.com/gin-gonic/gin"
@@ -19,15 +29,78 @@ func NewBookController(core *core.Core) *BookController {
 	return &BookController{core: core}
 }
 
+// AddBookRequest needs an author unless contributors are given, in which
+// case the author is their primary author.
 type AddBookRequest struct {
-	Title  string `json:"title" binding:"required,max=255"`
-	Author string `json:"author" binding:"required,max=255"`
-	ISBN   string `json:"isbn" binding:"required,max=20"`
+	Title    string `json:"title" binding:"required,max=255"`
+	Author   string `json:"author" binding:"max=255"`
+	ISBN     string `json:"isbn" binding:"required,max=20"`
+	Category string `json:"category" binding:"max=50"`
+	Barcode  string `json:"barcode" binding:"max=64"`
+	BookDetailsRequest
 }
 
 type UpdateBookRequest struct {
-	Title  string `json:"title" binding:"max=255"`
-	Author string `json:"author" binding:"max=255"`
+	Title    string `json:"title" binding:"max=255"`
+	Author   string `json:"author" binding:"max=255"`
+	Category string `json:"category" binding:"max=50"`
+	BookDetailsRequest
+}
+
+// BookDetailsRequest holds the bibliographic fields of the add and update
+// requests. The publication date is given as YYYY-MM-DD.
+type BookDetailsRequest struct {
+	Contributors    []ContributorRequest `json:"contributors" binding:"max=50,dive"`
+	Subtitle        string               `json:"subtitle" binding:"max=255"`
+	Publisher       string               `json:"publisher" binding:"max=255"`
+	PublishedAt     string               `json:"published_at" binding:"max=10"`
+	PublicationYear int                  `json:"publication_year"`
+	Edition         string               `json:"edition" binding:"max=50"`
+	Language        string               `json:"language" binding:"max=2"`
+	PageCount       int                  `json:"page_count"`
+	Description     string               `json:"description" binding:"max=5000"`
+	Subjects        []string             `json:"subjects" binding:"max=25"`
+}
+
+// ContributorRequest credits a contributor in a role, which defaults to
+// author. A contributor linked to an author may leave out the name to be
+// credited under the author's.
+type ContributorRequest struct {
+	Name     string `json:"name" binding:"required_without=AuthorID,max=255"`
+	Role     string `json:"role" binding:"max=20"`
+	AuthorID string `json:"author_id" binding:"max=64"`
+}
+
+func (r BookDetailsRequest) details() (models.BookDetails, error) {
+	publishedAt, err := models.ParsePublicationDate(r.PublishedAt)
+	if err != nil {
+		return models.BookDetails{}, err
+	}
+
+	var contributors []models.Contributor
+	if r.Contributors != nil {
+		contributors = make([]models.Contributor, 0, len(r.Contributors))
+		for _, contributor := range r.Contributors {
+			contributors = append(contributors, models.Contributor{
+				Name:     contributor.Name,
+				Role:     models.ContributorRole(contributor.Role),
+				AuthorID: contributor.AuthorID,
+			})
+		}
+	}
+
+	return models.BookDetails{
+		Contributors:    contributors,
+		Subtitle:        r.Subtitle,
+		Publisher:       r.Publisher,
+		PublishedAt:     publishedAt,
+		PublicationYear: r.PublicationYear,
+		Edition:         r.Edition,
+		Language:        r.Language,
+		PageCount:       r.PageCount,
+		Description:     r.Description,
+		Subjects:        r.Subjects,
+	}, nil
 }
 
 func (c *BookController) AddBook(ctx *gin.Context) {
@@ -38,7 +111,13 @@ func (c *BookController) AddBook(ctx *gin.Context) {
 		return
 	}
 
-	book, err := c.core.AddBook(ctx, request.Title, request.Author, request.ISBN)
+	details, err := request.details()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	book, err := c.core.AddBook(ctx, request.Title, request.Author, request.ISBN, request.Category, request.Barcode, details)
 	if err != nil {
 		status := mapErrorToStatus(err)
 		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
@@ -46,14 +125,41 @@ func (c *BookController) AddBook(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"message": "Book created successfully",
-		"book": gin.H{
-			"isbn":   book.ISBN,
-			"title":  book.Title,
-			"author": book.Author,
-		},
-	})
+		"book":    bookResponse(c.core, book),
+	}
+	if suggestions := c.authorSuggestions(ctx, book); len(suggestions) > 0 {
+		response["author_suggestions"] = suggestions
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// authorSuggestions lists the authors that the contributors not linked to
+// one may refer to. The book is already added, so a failed lookup is only
+// logged.
+func (c *BookController) authorSuggestions(ctx *gin.Context, book *models.Book) []gin.H {
+	suggestions := make([]gin.H, 0)
+	for _, contributor := range book.Contributors {
+		if contributor.AuthorID != "" {
+			continue
+		}
+
+		matches, err := c.core.MatchAuthors(ctx, contributor.Name)
+		if err != nil {
+			log.Printf("MatchAuthors error for contributor %q: %v", contributor.Name, err)
+			continue
+		}
+		if len(matches) > 0 {
+			suggestions = append(suggestions, gin.H{
+				"name":    contributor.Name,
+				"role":    contributor.Role,
+				"matches": matchesResponse(matches),
+			})
+		}
+	}
+	return suggestions
 }
 
 func (c *BookController) GetBookByISBN(ctx *gin.Context) {
@@ -71,14 +177,41 @@ func (c *BookController) GetBookByISBN(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"book": gin.H{
-			"title":  book.Title,
-			"author": book.Author,
-			"isbn":   book.ISBN,
-		},
+		"book": bookResponse(c.core, book),
 	})
 }
 
+// GetISBN divides any ISBN, catalogued or not, into its hyphenated parts,
+// as printed on labels.
+func (c *BookController) GetISBN(ctx *gin.Context) {
+	value := ctx.Param("isbn")
+
+	parts, err := c.core.SplitISBN(value)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetISBN error for ISBN %s: %v", value, err)
+		return
+	}
+
+	response := gin.H{
+		"isbn":               isbn.Key(value),
+		"isbn_formatted":     parts.String(),
+		"prefix":             parts.Prefix,
+		"registration_group": parts.Group,
+		"language_area":      parts.Agency,
+		"registrant":         parts.Registrant,
+		"publication":        parts.Publication,
+		"check_digit":        parts.CheckDigit,
+	}
+	if isbn10, ok := parts.ISBN10(); ok {
+		response["isbn_10"] = isbn.Clean(isbn10)
+		response["isbn_10_formatted"] = isbn10
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
 func (c *BookController) GetBook(ctx *gin.Context) {
 	isbn := ctx.Param("isbn")
 
@@ -94,16 +227,38 @@ func (c *BookController) GetBook(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"book": gin.H{
-			"title":  book.Title,
-			"author": book.Author,
-			"isbn":   book.ISBN,
-		},
+		"book": bookResponse(c.core, book),
 	})
 }
 
+// GetAllBooks lists the catalog a page at a time, or with ?contributor= the
+// books crediting a contributor by that name, narrowed to a role with ?role=.
+// The listing is filtered with ?title=, ?author=, ?publisher=, ?language=,
+// ?year_from= and ?year_to=, ordered with ?sort= such as "-year,title", and
+// continued with the next_cursor of the previous page as ?cursor=.
 func (c *BookController) GetAllBooks(ctx *gin.Context) {
-	books, err := c.core.GetAllBooks(ctx)
+	if contributor, ok := ctx.GetQuery("contributor"); ok {
+		books, err := c.core.FindBooksByContributor(ctx, contributor, models.ContributorRole(ctx.Query("role")))
+		if err != nil {
+			status := mapErrorToStatus(err)
+			ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+			log.Printf("GetAllBooks error: %v", err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"books": c.bookResponses(books),
+		})
+		return
+	}
+
+	filter, err := bookFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := c.core.ListBooks(ctx, filter)
 	if err != nil {
 		status := mapErrorToStatus(err)
 		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
@@ -111,18 +266,156 @@ func (c *BookController) GetAllBooks(ctx *gin.Context) {
 		return
 	}
 
-	var result []gin.H
+	ctx.JSON(http.StatusOK, gin.H{
+		"books":       c.bookResponses(page.Books),
+		"next_cursor": page.NextCursor,
+	})
+}
+
+func (c *BookController) bookResponses(books []*models.Book) []gin.H {
+	result := make([]gin.H, 0, len(books))
 	for _, book := range books {
-		result = append(result, gin.H{
-			"title":  book.Title,
-			"author": book.Author,
-			"isbn":   book.ISBN,
-		})
+		result = append(result, bookResponse(c.core, book))
 	}
+	return result
+}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"books": result,
+// bookFilter reads the listing parameters of GetAllBooks.
+func bookFilter(ctx *gin.Context) (models.BookFilter, error) {
+	filter := models.BookFilter{
+		Title:     ctx.Query("title"),
+		Author:    ctx.Query("author"),
+		Publisher: ctx.Query("publisher"),
+		Language:  ctx.Query("language"),
+		Cursor:    ctx.Query("cursor"),
+		// Deleted books are listed for staff looking for one to restore.
+		IncludeDeleted: ctx.Query("include_deleted") == "true",
+	}
+
+	err := numberParams(ctx, map[string]*int{
+		"year_from": &filter.YearFrom,
+		"year_to":   &filter.YearTo,
+		"limit":     &filter.Limit,
 	})
+	if err != nil {
+		return filter, err
+	}
+
+	sort, err := models.ParseBookSort(ctx.Query("sort"))
+	if err != nil {
+		return filter, err
+	}
+	filter.Sort = sort
+
+	// The filter's own checks run here too, so that their messages reach
+	// the client.
+	return filter.Normalize()
+}
+
+// numberParams reads the query parameters that are given into their
+// targets.
+func numberParams(ctx *gin.Context, targets map[string]*int) error {
+	for name, target := range targets {
+		value := ctx.Query(name)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: it must be a whole number", name, value)
+		}
+		*target = number
+	}
+	return nil
+}
+
+// SearchBooks finds books by the words of ?q= in their title, author and
+// description. Words in double quotes are found as a phrase and a word
+// ending in * matches the words it starts. Pages are chosen with ?limit=
+// and ?offset=. The hits are counted by the values of their facets, which
+// ?author=, ?language=, ?decade=, ?subject= and ?available=true filter
+// them on. A search that finds nothing may suggest another query as
+// did_you_mean.
+func (c *BookController) SearchBooks(ctx *gin.Context) {
+	search := models.BookSearch{
+		Query:    ctx.Query("q"),
+		Author:   ctx.Query("author"),
+		Language: ctx.Query("language"),
+		Subject:  ctx.Query("subject"),
+	}
+	err := numberParams(ctx, map[string]*int{
+		"decade": &search.Decade,
+		"limit":  &search.Limit,
+		"offset": &search.Offset,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if available := ctx.Query("available"); available != "" {
+		if search.Available, err = strconv.ParseBool(available); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid available %q: use true or false", available)})
+			return
+		}
+	}
+	if _, _, err := search.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.core.SearchBooks(ctx, search)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("SearchBooks error for %q: %v", search.Query, err)
+		return
+	}
+
+	hits := make([]gin.H, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		hits = append(hits, gin.H{
+			"book":            bookResponse(c.core, hit.Book),
+			"title_highlight": hit.TitleHighlight,
+			"snippet":         hit.Snippet,
+		})
+	}
+
+	response := gin.H{
+		"query":  search.Query,
+		"total":  result.Total,
+		"hits":   hits,
+		"facets": facetsResponse(result.Facets),
+	}
+	if result.Suggestion != "" {
+		response["did_you_mean"] = result.Suggestion
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+func facetsResponse(facets models.BookFacets) gin.H {
+	counts := func(values []models.FacetCount) []gin.H {
+		response := make([]gin.H, 0, len(values))
+		for _, value := range values {
+			response = append(response, gin.H{"value": value.Value, "count": value.Count})
+		}
+		return response
+	}
+
+	decades := make([]gin.H, 0, len(facets.Decades))
+	for _, decade := range facets.Decades {
+		decades = append(decades, gin.H{"decade": decade.Decade, "count": decade.Count})
+	}
+
+	return gin.H{
+		"authors":   counts(facets.Authors),
+		"languages": counts(facets.Languages),
+		"decades":   decades,
+		"subjects":  counts(facets.Subjects),
+		"availability": gin.H{
+			"available":   facets.Available,
+			"unavailable": facets.Unavailable,
+		},
+	}
 }
 
 func (c *BookController) UpdateBook(ctx *gin.Context) {
@@ -140,7 +433,13 @@ func (c *BookController) UpdateBook(ctx *gin.Context) {
 		return
 	}
 
-	book, err := c.core.UpdateBook(ctx, isbn, request.Title, request.Author)
+	details, err := request.details()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	book, err := c.core.UpdateBook(ctx, isbn, request.Title, request.Author, request.Category, details)
 
 	if err != nil {
 		status := mapErrorToStatus(err)
@@ -151,11 +450,7 @@ func (c *BookController) UpdateBook(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "Book updated successfully",
-		"book": gin.H{
-			"title":  book.Title,
-			"author": book.Author,
-			"isbn":   book.ISBN,
-		},
+		"book":    bookResponse(c.core, book),
 	})
 }
 
@@ -179,10 +474,175 @@ func (c *BookController) DeleteBook(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Book deleted successfully"})
 }
 
+// RestoreBook brings a deleted book back into the catalog.
+func (c *BookController) RestoreBook(ctx *gin.Context) {
+	isbn := ctx.Param("isbn")
+
+	if isbn == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ISBN parameter is required"})
+		return
+	}
+
+	book, err := c.core.RestoreBook(ctx, isbn)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("RestoreBook error for ISBN %s: %v", isbn, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Book restored successfully",
+		"book":    bookResponse(c.core, book),
+	})
+}
+
+// PurgeDeletedBooks permanently removes the books deleted more than
+// ?retention_days= days ago, 90 by default. Books with a copy on loan or an
+// active hold are kept, and listed as kept.
+func (c *BookController) PurgeDeletedBooks(ctx *gin.Context) {
+	retentionDays := 0
+	if err := numberParams(ctx, map[string]*int{"retention_days": &retentionDays}); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if retentionDays < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid retention_days %d", retentionDays)})
+		return
+	}
+
+	purged, kept, err := c.core.PurgeDeletedBooks(ctx, time.Duration(retentionDays)*24*time.Hour)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("PurgeDeletedBooks error: %v", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Deleted books purged successfully",
+		"purged":  purged,
+		"kept":    kept,
+	})
+}
+
+// bookResponse describes a book, leaving out the details that are not
+// known. The author is the primary author, kept for clients that predate
+// contributors. The ISBN is hyphenated, with its registration group and
+// language area, when the ISBN ranges are loaded; otherwise isbn_formatted
+// is the bare ISBN. deleted_at is only given for a deleted book.
+func bookResponse(appCore *core.Core, book *models.Book) gin.H {
+	response := gin.H{
+		"isbn":           book.ISBN,
+		"isbn_formatted": book.ISBN,
+		"title":          book.Title,
+		"author":         book.Author,
+		"category":       book.Category,
+	}
+	if isbn10, err := isbn.ToISBN10(book.ISBN); err == nil {
+		response["isbn_10"] = isbn10
+	}
+	if parts, err := appCore.SplitISBN(book.ISBN); err == nil {
+		response["isbn_formatted"] = parts.String()
+		response["registration_group"] = parts.Group
+		response["language_area"] = parts.Agency
+	}
+	if len(book.Contributors) > 0 {
+		response["contributors"] = book.Contributors
+	}
+
+	for key, value := range map[string]string{
+		"subtitle":    book.Subtitle,
+		"publisher":   book.Publisher,
+		"edition":     book.Edition,
+		"language":    book.Language,
+		"description": book.Description,
+	} {
+		if value != "" {
+			response[key] = value
+		}
+	}
+	if book.PublishedAt != nil {
+		response["published_at"] = book.PublishedAt.Format(models.PublicationDateLayout)
+	}
+	if book.PublicationYear != 0 {
+		response["publication_year"] = book.PublicationYear
+	}
+	if book.PageCount != 0 {
+		response["page_count"] = book.PageCount
+	}
+	if len(book.Subjects) > 0 {
+		response["subjects"] = book.Subjects
+	}
+	if book.DeletedAt != nil {
+		response["deleted_at"] = book.DeletedAt
+	}
+
+	return response
+}
+
 func mapErrorToStatus(err error) int {
-	if errors.Is(err, interfaces.ErrBookNotFound) {
+	if errors.Is(err, interfaces.ErrBookNotFound) ||
+		errors.Is(err, library_errors.ErrNotFound) ||
+		errors.Is(err, library_errors.ErrBookNotInStorage) ||
+		errors.Is(err, interfaces.ErrCopyNotFound) ||
+		errors.Is(err, patron_errors.ErrPatronNotFound) ||
+		errors.Is(err, calendar_errors.ErrClosedDateNotFound) ||
+		errors.Is(err, branch_errors.ErrBranchNotFound) ||
+		errors.Is(err, author_errors.ErrAuthorNotFound) {
 		return http.StatusNotFound
 	}
+	if errors.Is(err, library_errors.ErrBookAlreadyBorrowed) ||
+		errors.Is(err, library_errors.ErrLoanLimitReached) ||
+		errors.Is(err, library_errors.ErrOverdueItems) ||
+		errors.Is(err, library_errors.ErrRenewalLimitReached) ||
+		errors.Is(err, library_errors.ErrRentalOverdue) ||
+		errors.Is(err, library_errors.ErrBookOnHold) ||
+		errors.Is(err, library_errors.ErrBookAlreadyRented) ||
+		errors.Is(err, library_errors.ErrBookNotBorrowed) ||
+		errors.Is(err, library_errors.ErrRentalNotOwned) ||
+		errors.Is(err, library_errors.ErrRentalClosed) ||
+		errors.Is(err, library_errors.ErrBookAvailable) ||
+		errors.Is(err, library_errors.ErrHoldAlreadyPlaced) ||
+		errors.Is(err, library_errors.ErrHoldNotOwned) ||
+		errors.Is(err, library_errors.ErrHoldClosed) ||
+		errors.Is(err, library_errors.ErrFinesOutstanding) ||
+		errors.Is(err, library_errors.ErrAmountExceedsBalance) ||
+		errors.Is(err, library_errors.ErrRentalNotLost) ||
+		errors.Is(err, library_errors.ErrNotAtBranch) ||
+		errors.Is(err, library_errors.ErrCopyOnLoan) ||
+		errors.Is(err, library_errors.ErrCopyNotInHouse) ||
+		errors.Is(err, library_errors.ErrCopyAlreadyAtBranch) ||
+		errors.Is(err, library_errors.ErrCopyWithoutBranch) ||
+		errors.Is(err, library_errors.ErrTransferReceived) ||
+		errors.Is(err, branch_errors.ErrDuplicateBranchCode) ||
+		errors.Is(err, author_errors.ErrDuplicateAuthor) ||
+		errors.Is(err, interfaces.ErrDuplicateBarcode) ||
+		errors.Is(err, interfaces.ErrCopyInUse) ||
+		errors.Is(err, interfaces.ErrBookOnLoan) ||
+		errors.Is(err, interfaces.ErrBookDeleted) ||
+		errors.Is(err, patron_errors.ErrDuplicateCardNumber) ||
+		errors.Is(err, patron_errors.ErrPatronSuspended) ||
+		errors.Is(err, patron_errors.ErrPatronExpired) ||
+		errors.Is(err, patron_errors.ErrPatronNotSuspended) {
+		return http.StatusConflict
+	}
+	if errors.Is(err, isbn.ErrNoRanges) {
+		return http.StatusServiceUnavailable
+	}
+	if errors.Is(err, library_errors.ErrInvalidAmount) ||
+		errors.Is(err, author_errors.ErrSelfMerge) ||
+		errors.Is(err, isbn.ErrInvalidLength) ||
+		errors.Is(err, isbn.ErrInvalidISBN10) ||
+		errors.Is(err, isbn.ErrInvalidISBN13) ||
+		errors.Is(err, isbn.ErrISBN10Checksum) ||
+		errors.Is(err, isbn.ErrISBN13Checksum) ||
+		errors.Is(err, isbn.ErrNoISBN10Form) ||
+		errors.Is(err, isbn.ErrNotAnISBN10) ||
+		errors.Is(err, isbn.ErrNotAnISBN13) ||
+		errors.Is(err, isbn.ErrUnassignedRange) {
+		return http.StatusBadRequest
+	}
 	errMsg := err.Error()
 	if contains(errMsg, "cannot be empty", "invalid", "required", "already exists", "ISBN must be", "checksum") {
 		return http.StatusBadRequest
@@ -209,8 +669,12 @@ func sanitizeError(err error, status int) string {
 		return "resource not found"
 	case http.StatusBadRequest:
 		return "invalid request"
+	case http.StatusConflict:
+		// Conflicts only come from domain rules, so the message is safe to expose
+		return err.Error()
+	case http.StatusServiceUnavailable:
+		return err.Error()
 	default:
 		return "internal server error"
 	}
 }
-
@@ -4,11 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"books/core"
+	author_repositories "books/core/authors/repositories"
+	branch_repositories "books/core/branches/repositories"
+	calendar_repositories "books/core/calendar/repositories"
+	"books/core/isbn"
+	"books/core/library/policies"
+	library_repositories "books/core/library/repositories"
+	patron_models "books/core/patrons/models"
+	patron_repositories "books/core/patrons/repositories"
+	"books/core/storage/models"
 	"books/core/storage/repositories"
 
 	"github.com/gin-gonic/gin"
@@ -19,7 +32,41 @@ func setupTestRouter() (*gin.Engine, *core.Core) {
 	router := gin.New()
 
 	repo := repositories.NewBookStorageInMemoryRepository()
-	appCore := core.NewCore(repo)
+	copyRepo := repositories.NewCopyStorageInMemoryRepository()
+	rentalRepo := library_repositories.NewBookRentalInMemoryRepository(repo, copyRepo)
+	repo.SetAvailability(rentalRepo)
+
+	// Rentals in the tests are made by these registered patrons.
+	patronRepo := patron_repositories.NewPatronInMemoryRepository()
+	for _, userID := range []string{"user1", "user2", "user3"} {
+		_ = patronRepo.SavePatron(context.Background(), &patron_models.Patron{
+			ID:         userID,
+			CardNumber: "card-" + userID,
+			Name:       userID,
+			PatronType: "adult",
+			Status:     patron_models.PatronStatusActive,
+			ExpiresAt:  time.Now().Add(policies.DefaultMembershipPeriod),
+		})
+	}
+
+	// The excerpt of the ISBN range message covers the ISBNs the tests use.
+	isbnRanges, err := isbn.LoadRanges("../../../core/isbn/testdata/RangeMessage.xml")
+	if err != nil {
+		panic(err)
+	}
+
+	appCore := core.NewCore(core.Repositories{
+		Books:     repo,
+		Copies:    copyRepo,
+		Rentals:   rentalRepo,
+		Holds:     library_repositories.NewHoldInMemoryRepository(),
+		Ledger:    library_repositories.NewLedgerInMemoryRepository(),
+		Patrons:   patronRepo,
+		Calendar:  calendar_repositories.NewCalendarInMemoryRepository(),
+		Branches:  branch_repositories.NewBranchInMemoryRepository(),
+		Transfers: library_repositories.NewTransferInMemoryRepository(),
+		Authors:   author_repositories.NewAuthorInMemoryRepository(),
+	}, policies.DefaultLendingRules(), isbnRanges)
 
 	controllers := NewControllers(appCore)
 	controllers.RegisterRoutes(router)
@@ -104,7 +151,7 @@ func TestAddBook(t *testing.T) {
 func TestGetAllBooks(t *testing.T) {
 	router, appCore := setupTestRouter()
 
-	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", "9783161484100")
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", "9783161484100", "", "", models.BookDetails{})
 
 	req, _ := http.NewRequest(http.MethodGet, "/books", nil)
 	w := httptest.NewRecorder()
@@ -133,7 +180,7 @@ func TestGetBookByISBN(t *testing.T) {
 
 	validISBN := "9783161484100"
 
-	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN)
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
 
 	tests := []struct {
 		name           string
@@ -145,11 +192,26 @@ func TestGetBookByISBN(t *testing.T) {
 			isbn:           validISBN,
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "hyphenated form",
+			isbn:           "978-3-16-148410-0",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "ISBN-10 form",
+			isbn:           "316148410X",
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name:           "non-existing book",
 			isbn:           "9780306406157",
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name:           "not an ISBN",
+			isbn:           "not-an-isbn",
+			expectedStatus: http.StatusNotFound,
+		},
 	}
 
 	for _, tc := range tests {
@@ -165,12 +227,107 @@ func TestGetBookByISBN(t *testing.T) {
 	}
 }
 
+func TestBookISBNForms(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	w := postJSON(router, "/books", map[string]interface{}{"isbn": "0-306-40615-2", "title": "Test Book", "author": "Test Author"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var response map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	if response["book"]["isbn"] != "9780306406157" || response["book"]["isbn_10"] != "0306406152" {
+		t.Errorf("expected the book to be stored under its ISBN-13, got %v", response["book"])
+	}
+
+	w = postJSON(router, "/books", map[string]interface{}{"isbn": "978-0-306-40615-7", "title": "Again", "author": "Test Author"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for the same ISBN in another form, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = postJSON(router, "/books/0306406152/copies", map[string]interface{}{})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/books/978-0-306-40615-7/copies", nil)
+	router.ServeHTTP(w, req)
+
+	var listed map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &listed)
+	if len(listed["copies"]) != 2 || listed["copies"][1]["isbn"] != "9780306406157" {
+		t.Errorf("expected both copies under the ISBN-13, got %v", listed["copies"])
+	}
+}
+
+func TestISBNHyphenation(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	w := postJSON(router, "/books", map[string]interface{}{"isbn": "9780134685991", "title": "Test Book", "author": "Test Author"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var response map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	book := response["book"]
+	if book["isbn_formatted"] != "978-0-13-468599-1" || book["registration_group"] != "0" || book["language_area"] != "English language" {
+		t.Errorf("expected the ISBN to be hyphenated, got %v", book)
+	}
+
+	w = postJSON(router, "/books", map[string]interface{}{"isbn": "978-2-00-000000-6", "title": "Test Book", "author": "Test Author"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for an unassigned range, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/isbns/0-8044-2957-x", nil)
+	router.ServeHTTP(w, req)
+
+	var parts map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &parts)
+	if w.Code != http.StatusOK || parts["isbn"] != "9780804429573" || parts["isbn_formatted"] != "978-0-8044-2957-3" ||
+		parts["registrant"] != "8044" || parts["isbn_10_formatted"] != "0-8044-2957-X" {
+		t.Errorf("unexpected ISBN parts %v (%d)", parts, w.Code)
+	}
+
+	for _, value := range []string{"9782000000006", "9780134685992"} {
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/isbns/"+value, nil)
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", value, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+func TestISBNErrorsAreBadRequests(t *testing.T) {
+	for _, err := range []error{
+		isbn.ErrInvalidLength,
+		isbn.ErrInvalidISBN10,
+		isbn.ErrInvalidISBN13,
+		isbn.ErrISBN10Checksum,
+		isbn.ErrISBN13Checksum,
+		isbn.ErrNoISBN10Form,
+		isbn.ErrNotAnISBN10,
+		isbn.ErrNotAnISBN13,
+		isbn.ErrUnassignedRange,
+	} {
+		// Wrapped under a message of its own, the error is still recognized.
+		if status := mapErrorToStatus(fmt.Errorf("failed to add book: %w", err)); status != http.StatusBadRequest {
+			t.Errorf("expected status %d for %q, got %d", http.StatusBadRequest, err, status)
+		}
+	}
+}
+
 func TestUpdateBook(t *testing.T) {
 	router, appCore := setupTestRouter()
 
 	validISBN := "9783161484100"
 
-	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN)
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
 
 	tests := []struct {
 		name           string
@@ -221,12 +378,142 @@ func TestUpdateBook(t *testing.T) {
 	}
 }
 
+func TestBookDetails(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	w := postJSON(router, "/books", map[string]interface{}{
+		"isbn":         "9783161484100",
+		"title":        "Test Book",
+		"author":       "Test Author",
+		"subtitle":     "A Subtitle",
+		"publisher":    "Test Publisher",
+		"published_at": "1999-03-04",
+		"language":     "en",
+		"page_count":   320,
+		"subjects":     []string{"Fiction"},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var response map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	book := response["book"]
+	if book["published_at"] != "1999-03-04" || book["publication_year"] != float64(1999) || book["language"] != "en" {
+		t.Errorf("unexpected book %v", book)
+	}
+	if _, ok := book["edition"]; ok {
+		t.Errorf("expected unknown details to be left out, got %v", book)
+	}
+
+	for name, body := range map[string]map[string]interface{}{
+		"malformed date":   {"isbn": "9780306406157", "title": "Other", "author": "Other", "published_at": "04/03/1999"},
+		"unknown language": {"isbn": "9780306406157", "title": "Other", "author": "Other", "language": "zz"},
+		"negative pages":   {"isbn": "9780306406157", "title": "Other", "author": "Other", "page_count": -5},
+	} {
+		if w := postJSON(router, "/books", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d. Body: %s", name, http.StatusBadRequest, w.Code, w.Body.String())
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, "/books/9783161484100", bytes.NewBufferString(`{"edition": "2nd"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	if response["book"]["edition"] != "2nd" || response["book"]["publisher"] != "Test Publisher" {
+		t.Errorf("unexpected book after update %v", response["book"])
+	}
+}
+
+func TestBookContributors(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	w := postJSON(router, "/books", map[string]interface{}{
+		"isbn":  "9783161484100",
+		"title": "Test Book",
+		"contributors": []map[string]string{
+			{"name": "Test Editor", "role": "editor"},
+			{"name": "First Author"},
+			{"name": "Test Illustrator", "role": "illustrator"},
+		},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var response map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	book := response["book"]
+	contributors, _ := book["contributors"].([]interface{})
+	if book["author"] != "First Author" || len(contributors) != 3 {
+		t.Errorf("expected the author to be the primary author, got %v", book)
+	}
+
+	w = postJSON(router, "/books", map[string]interface{}{"isbn": "9780306406157", "title": "Other", "author": "Other Author"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	for name, body := range map[string]map[string]interface{}{
+		"no author":         {"isbn": "9780140449136", "title": "Other"},
+		"unknown role":      {"isbn": "9780140449136", "title": "Other", "contributors": []map[string]string{{"name": "A", "role": "narrator"}}},
+		"mismatched author": {"isbn": "9780140449136", "title": "Other", "author": "B", "contributors": []map[string]string{{"name": "A"}}},
+	} {
+		if w := postJSON(router, "/books", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d. Body: %s", name, http.StatusBadRequest, w.Code, w.Body.String())
+		}
+	}
+
+	for query, expected := range map[string]int{
+		"contributor=author":                    2,
+		"contributor=illustrator":               1,
+		"contributor=first&role=illustrator":    0,
+		"contributor=TEST%20EDITOR&role=editor": 1,
+	} {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/books?"+query, nil)
+		router.ServeHTTP(w, req)
+
+		var listed map[string][]map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &listed)
+		if w.Code != http.StatusOK || len(listed["books"]) != expected {
+			t.Errorf("%s: expected %d books, got %d (%d)", query, expected, len(listed["books"]), w.Code)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/books?contributor=author&role=narrator", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for an unknown role, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	req, _ = http.NewRequest(http.MethodPut, "/books/9783161484100", bytes.NewBufferString(`{"author": "Renamed Author"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	contributors, _ = response["book"]["contributors"].([]interface{})
+	if response["book"]["author"] != "Renamed Author" || len(contributors) != 3 {
+		t.Errorf("expected the primary author to be renamed, got %v", response["book"])
+	}
+}
+
 func TestDeleteBook(t *testing.T) {
 	router, appCore := setupTestRouter()
 
 	validISBN := "9783161484100"
 
-	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN)
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
 
 	tests := []struct {
 		name           string
@@ -258,6 +545,108 @@ func TestDeleteBook(t *testing.T) {
 	}
 }
 
+func TestDeleteAndRestoreBook(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	validISBN := "9783161484100"
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
+	_, _ = appCore.RentBook(context.TODO(), validISBN, "user1", "")
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := request(http.MethodDelete, "/books/"+validISBN); w.Code != http.StatusConflict {
+		t.Fatalf("expected a book on loan not to be deleted, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	_, _ = appCore.ReturnBook(context.TODO(), validISBN, "user1", "")
+	if w := request(http.MethodDelete, "/books/"+validISBN); w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	if w := request(http.MethodGet, "/books/"+validISBN); w.Code != http.StatusNotFound {
+		t.Errorf("expected a deleted book not to be found, got %d", w.Code)
+	}
+	var listing struct {
+		Books []map[string]interface{} `json:"books"`
+	}
+	_ = json.Unmarshal(request(http.MethodGet, "/books").Body.Bytes(), &listing)
+	if len(listing.Books) != 0 {
+		t.Errorf("expected a deleted book to be left out of the listing, got %v", listing.Books)
+	}
+	_ = json.Unmarshal(request(http.MethodGet, "/books?include_deleted=true").Body.Bytes(), &listing)
+	if len(listing.Books) != 1 || listing.Books[0]["deleted_at"] == nil {
+		t.Errorf("expected the deleted book to be listed when asked for, got %v", listing.Books)
+	}
+
+	w := postJSON(router, "/books", map[string]interface{}{"title": "Test Book", "author": "Test Author", "isbn": validISBN})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected a deleted book not to be added again, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	if w := request(http.MethodPost, "/books/"+validISBN+"/restore"); w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w := request(http.MethodPost, "/books/"+validISBN+"/restore"); w.Code != http.StatusNotFound {
+		t.Errorf("expected a book that is not deleted not to be restored, got %d", w.Code)
+	}
+	if w := request(http.MethodGet, "/books/"+validISBN); w.Code != http.StatusOK {
+		t.Errorf("expected the restored book to be found, got %d", w.Code)
+	}
+}
+
+func TestPurgeDeletedBooks(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	validISBN := "9783161484100"
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
+	_ = appCore.DeleteBook(context.TODO(), validISBN)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedPurged int
+	}{
+		{
+			name:           "invalid retention",
+			query:          "?retention_days=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "deleted within the default retention",
+			expectedStatus: http.StatusOK,
+			expectedPurged: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := postJSON(router, "/books/purge"+tc.query, nil)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d. Body: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			var response struct {
+				Purged []string  `json:"purged"`
+				Kept   *[]string `json:"kept"`
+			}
+			_ = json.Unmarshal(w.Body.Bytes(), &response)
+			if len(response.Purged) != tc.expectedPurged {
+				t.Errorf("expected %d books purged, got %v", tc.expectedPurged, response.Purged)
+			}
+			if tc.expectedStatus == http.StatusOK && response.Kept == nil {
+				t.Errorf("expected the kept books to be listed. Body: %s", w.Body.String())
+			}
+		})
+	}
+}
+
 func TestHealthCheck(t *testing.T) {
 	router, _ := setupTestRouter()
 
@@ -276,3 +665,180 @@ func TestHealthCheck(t *testing.T) {
 		t.Errorf("expected status 'ok', got '%v'", response["status"])
 	}
 }
+
+func TestListBooksPages(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	for _, b := range []struct {
+		isbn, title, language string
+		year                  int
+	}{
+		{"9783161484100", "The Hobbit", "en", 1937},
+		{"9780306406157", "The Silmarillion", "en", 1977},
+		{"9780596517748", "Dune", "en", 1965},
+		{"9780140449136", "Der Process", "de", 1925},
+	} {
+		details := models.BookDetails{Language: b.language, PublicationYear: b.year}
+		if _, err := appCore.AddBook(context.TODO(), b.title, "Test Author", b.isbn, "", "", details); err != nil {
+			t.Fatalf("failed to add book: %v", err)
+		}
+	}
+
+	list := func(query string) (int, []string, string) {
+		req, _ := http.NewRequest(http.MethodGet, "/books?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response struct {
+			Books []struct {
+				Title string `json:"title"`
+			} `json:"books"`
+			NextCursor string `json:"next_cursor"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		titles := make([]string, 0, len(response.Books))
+		for _, book := range response.Books {
+			titles = append(titles, book.Title)
+		}
+		return w.Code, titles, response.NextCursor
+	}
+
+	code, titles, cursor := list("sort=-year&limit=3")
+	if code != http.StatusOK || strings.Join(titles, "|") != "The Silmarillion|Dune|The Hobbit" || cursor == "" {
+		t.Fatalf("unexpected first page %d %v %q", code, titles, cursor)
+	}
+	code, titles, cursor = list("sort=-year&limit=3&cursor=" + url.QueryEscape(cursor))
+	if code != http.StatusOK || strings.Join(titles, "|") != "Der Process" || cursor != "" {
+		t.Errorf("unexpected last page %d %v %q", code, titles, cursor)
+	}
+
+	code, titles, _ = list("language=en&year_from=1950&title=the")
+	if code != http.StatusOK || strings.Join(titles, "|") != "The Silmarillion" {
+		t.Errorf("unexpected filtered listing %d %v", code, titles)
+	}
+
+	for _, query := range []string{"limit=0x10", "limit=500", "sort=pages", "year_from=1990&year_to=1980", "language=xx", "cursor=abc"} {
+		if code, _, _ := list(query); code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusBadRequest, code)
+		}
+	}
+}
+
+func TestSearchBooks(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	for _, b := range []struct {
+		isbn, title, description string
+	}{
+		{"9783161484100", "The Hobbit", "In a hole in the ground there lived a hobbit."},
+		{"9780306406157", "The Silmarillion", "The elder days, long before the hobbits."},
+	} {
+		details := models.BookDetails{Description: b.description}
+		if _, err := appCore.AddBook(context.TODO(), b.title, "J. R. R. Tolkien", b.isbn, "", "", details); err != nil {
+			t.Fatalf("failed to add book: %v", err)
+		}
+	}
+
+	search := func(query string) (int, map[string]interface{}) {
+		req, _ := http.NewRequest(http.MethodGet, "/books/search?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := search("q=" + url.QueryEscape("hobbit*"))
+	if code != http.StatusOK || response["total"] != float64(2) {
+		t.Fatalf("expected 2 hits, got %d %v", code, response)
+	}
+	hits := response["hits"].([]interface{})
+	first := hits[0].(map[string]interface{})
+	if first["title_highlight"] != "The <mark>Hobbit</mark>" || first["book"].(map[string]interface{})["isbn"] != "9783161484100" {
+		t.Errorf("expected The Hobbit first, got %v", first)
+	}
+	second := hits[1].(map[string]interface{})
+	if second["snippet"] != "The elder days, long before the <mark>hobbits</mark>." {
+		t.Errorf("unexpected snippet %v", second["snippet"])
+	}
+
+	code, response = search("q=" + url.QueryEscape(`"lived a hobbit"`) + "&limit=1")
+	if code != http.StatusOK || response["total"] != float64(1) {
+		t.Errorf("expected the phrase to match one book, got %d %v", code, response)
+	}
+
+	code, response = search("q=Tolkein")
+	if code != http.StatusOK || response["total"] != float64(0) || response["did_you_mean"] != "tolkien" {
+		t.Errorf("expected a suggestion for the misspelt name, got %d %v", code, response)
+	}
+	code, response = search("q=silmarilion")
+	if code != http.StatusOK || response["total"] != float64(1) || response["did_you_mean"] != nil {
+		t.Errorf("expected the misspelt title to be found, got %d %v", code, response)
+	}
+
+	for _, query := range []string{"q=", "q=%22%22", "q=hobbit&limit=-1", "q=hobbit&offset=x"} {
+		if code, _ := search(query); code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusBadRequest, code)
+		}
+	}
+}
+
+func TestSearchBooksFacets(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	for _, b := range []struct {
+		isbn, title string
+		year        int
+	}{
+		{"9783161484100", "The Hobbit", 1937},
+		{"9780306406157", "The Silmarillion", 1977},
+	} {
+		details := models.BookDetails{Language: "en", PublicationYear: b.year, Subjects: []string{"Fantasy"}}
+		if _, err := appCore.AddBook(context.TODO(), b.title, "J. R. R. Tolkien", b.isbn, "", "", details); err != nil {
+			t.Fatalf("failed to add book: %v", err)
+		}
+	}
+	if w := postJSON(router, "/books/9783161484100/rentals", map[string]interface{}{"user_id": "user1"}); w.Code != http.StatusCreated {
+		t.Fatalf("failed to rent the book: %d %s", w.Code, w.Body.String())
+	}
+
+	search := func(query string) (int, map[string]interface{}) {
+		req, _ := http.NewRequest(http.MethodGet, "/books/search?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := search("q=tolkien")
+	if code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d %v", http.StatusOK, code, response)
+	}
+	facets, _ := json.Marshal(response["facets"])
+	expected := `{"authors":[{"count":2,"value":"J. R. R. Tolkien"}],` +
+		`"availability":{"available":1,"unavailable":1},` +
+		`"decades":[{"count":1,"decade":1930},{"count":1,"decade":1970}],` +
+		`"languages":[{"count":2,"value":"en"}],` +
+		`"subjects":[{"count":2,"value":"Fantasy"}]}`
+	if string(facets) != expected {
+		t.Errorf("unexpected facets %s", facets)
+	}
+
+	code, response = search("q=tolkien&available=true&decade=1970")
+	if code != http.StatusOK || response["total"] != float64(1) {
+		t.Fatalf("expected the available book to be found, got %d %v", code, response)
+	}
+	book := response["hits"].([]interface{})[0].(map[string]interface{})["book"].(map[string]interface{})
+	if book["title"] != "The Silmarillion" {
+		t.Errorf("expected The Silmarillion, got %v", book)
+	}
+
+	for _, query := range []string{"q=tolkien&available=maybe", "q=tolkien&decade=1975", "q=tolkien&language=xx"} {
+		if code, response := search(query); code != http.StatusBadRequest || response["error"] == "invalid request" {
+			t.Errorf("%s: expected the filter to be refused, got %d %v", query, code, response)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"books/core"
+	branch_models "books/core/branches/models"
+	library_models "books/core/library/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BranchController struct {
+	core *core.Core
+}
+
+func NewBranchController(core *core.Core) *BranchController {
+	return &BranchController{core: core}
+}
+
+type AddBranchRequest struct {
+	Code    string `json:"code" binding:"required,max=20"`
+	Name    string `json:"name" binding:"required,max=255"`
+	Address string `json:"address" binding:"max=500"`
+}
+
+type UpdateBranchRequest struct {
+	Code    string `json:"code" binding:"max=20"`
+	Name    string `json:"name" binding:"max=255"`
+	Address string `json:"address" binding:"max=500"`
+}
+
+type TransferRequest struct {
+	ToBranchID string `json:"to_branch_id" binding:"required,max=64"`
+}
+
+func (c *BranchController) AddBranch(ctx *gin.Context) {
+	var request AddBranchRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	branch, err := c.core.AddBranch(ctx, request.Code, request.Name, request.Address)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("AddBranch error: %v", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Branch added successfully",
+		"branch":  branchResponse(branch),
+	})
+}
+
+func (c *BranchController) GetBranches(ctx *gin.Context) {
+	branches, err := c.core.GetBranches(ctx)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetBranches error: %v", err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(branches))
+	for _, branch := range branches {
+		result = append(result, branchResponse(branch))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"branches": result,
+	})
+}
+
+func (c *BranchController) GetBranch(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	branch, err := c.core.GetBranch(ctx, id)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetBranch error for branch %s: %v", id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, branchResponse(branch))
+}
+
+func (c *BranchController) UpdateBranch(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var request UpdateBranchRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	branch, err := c.core.UpdateBranch(ctx, id, request.Code, request.Name, request.Address)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("UpdateBranch error for branch %s: %v", id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Branch updated successfully",
+		"branch":  branchResponse(branch),
+	})
+}
+
+// GetInboundTransfers lists the copies on their way to the branch.
+func (c *BranchController) GetInboundTransfers(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	transfers, err := c.core.GetInboundTransfers(ctx, id)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetInboundTransfers error for branch %s: %v", id, err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(transfers))
+	for _, transfer := range transfers {
+		result = append(result, transferResponse(transfer))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"transfers": result,
+	})
+}
+
+func (c *BranchController) StartTransfer(ctx *gin.Context) {
+	copyID := ctx.Param("id")
+
+	var request TransferRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	transfer, err := c.core.StartTransfer(ctx, copyID, request.ToBranchID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("StartTransfer error for copy %s: %v", copyID, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message":  "Transfer started successfully",
+		"transfer": transferResponse(transfer),
+	})
+}
+
+func (c *BranchController) ReceiveTransfer(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	transfer, err := c.core.ReceiveTransfer(ctx, id)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("ReceiveTransfer error for transfer %s: %v", id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":  "Transfer received successfully",
+		"transfer": transferResponse(transfer),
+	})
+}
+
+func branchResponse(branch *branch_models.Branch) gin.H {
+	return gin.H{
+		"id":         branch.ID,
+		"code":       branch.Code,
+		"name":       branch.Name,
+		"address":    branch.Address,
+		"created_at": branch.CreatedAt,
+	}
+}
+
+func transferResponse(transfer *library_models.Transfer) gin.H {
+	return gin.H{
+		"id":             transfer.ID,
+		"copy_id":        transfer.CopyID,
+		"isbn":           transfer.BookID,
+		"from_branch_id": transfer.FromBranchID,
+		"to_branch_id":   transfer.ToBranchID,
+		"reason":         transfer.Reason,
+		"started_at":     transfer.StartedAt,
+		"received_at":    transfer.ReceivedAt,
+	}
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"books/core/storage/models"
+)
+
+func TestBranches(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	w := postJSON(router, "/branches", map[string]interface{}{"code": "Main", "name": "Main Library", "address": "1 High St"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var created map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	branchID, _ := created["branch"]["id"].(string)
+	if branchID == "" || created["branch"]["code"] != "main" {
+		t.Fatalf("unexpected branch %v", created["branch"])
+	}
+
+	w = postJSON(router, "/branches", map[string]interface{}{"code": "main", "name": "Another Main"})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d for a duplicate code, got %d", http.StatusConflict, w.Code)
+	}
+
+	w = postJSON(router, "/branches", map[string]interface{}{"code": "not a code", "name": "Broken"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for an invalid code, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/branches/"+branchID, bytes.NewBufferString(`{"name": "Central Library"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/branches", nil)
+	router.ServeHTTP(w, req)
+
+	var listed map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &listed)
+	if len(listed["branches"]) != 1 || listed["branches"][0]["name"] != "Central Library" {
+		t.Errorf("unexpected branches %v", listed["branches"])
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/branches/missing", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestTransferCopyBetweenBranches(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	validISBN := "9783161484100"
+	main, _ := appCore.AddBranch(context.TODO(), "main", "Main Library", "")
+	north, _ := appCore.AddBranch(context.TODO(), "north", "North Branch", "")
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
+
+	// The first copy added with the book has no branch and cannot be moved.
+	copies, _ := appCore.GetBookCopies(context.TODO(), validISBN)
+	w := postJSON(router, "/copies/"+copies[0].ID+"/transfers", map[string]interface{}{"to_branch_id": north.ID})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d for a copy without a branch, got %d", http.StatusConflict, w.Code)
+	}
+
+	bookCopy, err := appCore.AddCopy(context.TODO(), validISBN, "", "", "", main.ID)
+	if err != nil {
+		t.Fatalf("failed to add copy: %v", err)
+	}
+
+	w = postJSON(router, "/copies/"+bookCopy.ID+"/transfers", map[string]interface{}{"to_branch_id": "missing"})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for an unknown branch, got %d", http.StatusNotFound, w.Code)
+	}
+
+	w = postJSON(router, "/copies/"+bookCopy.ID+"/transfers", map[string]interface{}{"to_branch_id": north.ID})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var started map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &started)
+	transferID, _ := started["transfer"]["id"].(string)
+	if started["transfer"]["from_branch_id"] != main.ID || started["transfer"]["reason"] != "manual" {
+		t.Errorf("unexpected transfer %v", started["transfer"])
+	}
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/branches/"+north.ID+"/transfers", nil)
+	router.ServeHTTP(w, req)
+
+	var inbound map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &inbound)
+	if len(inbound["transfers"]) != 1 {
+		t.Errorf("expected 1 inbound transfer, got %v", inbound["transfers"])
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/library/books?branch="+main.ID, nil)
+	router.ServeHTTP(w, req)
+
+	// The copy stays on main's books until it arrives.
+	var books map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &books)
+	if len(books["books"]) != 1 || books["books"][0]["in_transit_copies"] != float64(1) {
+		t.Errorf("expected the copy in transit to be listed, got %v", books["books"])
+	}
+
+	for _, expected := range []int{http.StatusOK, http.StatusConflict} {
+		w = postJSON(router, "/transfers/"+transferID+"/receive", nil)
+		if w.Code != expected {
+			t.Errorf("expected status %d, got %d. Body: %s", expected, w.Code, w.Body.String())
+		}
+	}
+
+	w = postJSON(router, "/books/"+validISBN+"/rentals", map[string]interface{}{"user_id": "user1", "branch_id": north.ID})
+	if w.Code != http.StatusOK && w.Code != http.StatusCreated {
+		t.Errorf("expected the copy to be lent at north, got %d. Body: %s", w.Code, w.Body.String())
+	}
+}
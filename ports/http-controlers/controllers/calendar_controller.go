@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"books/core"
+	calendar_models "books/core/calendar/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxICalendarSize limits the size of an imported iCalendar file.
+const maxICalendarSize = 1 << 20
+
+type CalendarController struct {
+	core *core.Core
+}
+
+func NewCalendarController(core *core.Core) *CalendarController {
+	return &CalendarController{core: core}
+}
+
+type OpeningHoursRequest struct {
+	Weekday string `json:"weekday" binding:"required"`
+	Opens   string `json:"opens" binding:"required"`
+	Closes  string `json:"closes" binding:"required"`
+}
+
+type SetOpeningHoursRequest struct {
+	OpeningHours []OpeningHoursRequest `json:"opening_hours" binding:"max=7,dive"`
+}
+
+type ClosedDateRequest struct {
+	Date   string `json:"date" binding:"required"`
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+type AddClosedDatesRequest struct {
+	Dates []ClosedDateRequest `json:"dates" binding:"required,min=1,max=1000,dive"`
+}
+
+func (c *CalendarController) GetCalendar(ctx *gin.Context) {
+	calendar, err := c.core.GetCalendar(ctx)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetCalendar error: %v", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calendarResponse(calendar))
+}
+
+func (c *CalendarController) SetOpeningHours(ctx *gin.Context) {
+	var request SetOpeningHoursRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	hours := make([]calendar_models.OpeningHours, 0, len(request.OpeningHours))
+	for _, h := range request.OpeningHours {
+		weekday, ok := parseWeekday(h.Weekday)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "weekday must be a day name such as monday"})
+			return
+		}
+		hours = append(hours, calendar_models.OpeningHours{Weekday: weekday, Opens: h.Opens, Closes: h.Closes})
+	}
+
+	calendar, err := c.core.SetOpeningHours(ctx, hours)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("SetOpeningHours error: %v", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calendarResponse(calendar))
+}
+
+func (c *CalendarController) AddClosedDates(ctx *gin.Context) {
+	var request AddClosedDatesRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	dates := make([]calendar_models.ClosedDate, 0, len(request.Dates))
+	for _, d := range request.Dates {
+		dates = append(dates, calendar_models.ClosedDate{Date: d.Date, Reason: strings.TrimSpace(d.Reason)})
+	}
+
+	calendar, err := c.core.AddClosedDates(ctx, dates)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("AddClosedDates error: %v", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calendarResponse(calendar))
+}
+
+// ImportClosedDates reads an iCalendar file from the request body.
+func (c *CalendarController) ImportClosedDates(ctx *gin.Context) {
+	data, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxICalendarSize+1))
+	if err != nil || len(data) == 0 || len(data) > maxICalendarSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "request body must be an iCalendar file of at most 1 MiB"})
+		return
+	}
+
+	calendar, err := c.core.ImportClosedDates(ctx, data)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("ImportClosedDates error: %v", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calendarResponse(calendar))
+}
+
+func (c *CalendarController) RemoveClosedDate(ctx *gin.Context) {
+	date := ctx.Param("date")
+
+	if err := c.core.RemoveClosedDate(ctx, date); err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("RemoveClosedDate error for %s: %v", date, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Closed date removed successfully",
+	})
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), strings.TrimSpace(name)) {
+			return day, true
+		}
+	}
+	return time.Sunday, false
+}
+
+func calendarResponse(calendar *calendar_models.Calendar) gin.H {
+	hours := make([]gin.H, 0, len(calendar.Hours))
+	for _, h := range calendar.Hours {
+		hours = append(hours, gin.H{
+			"weekday": strings.ToLower(h.Weekday.String()),
+			"opens":   h.Opens,
+			"closes":  h.Closes,
+		})
+	}
+
+	dates := make([]gin.H, 0, len(calendar.Closed))
+	for _, d := range calendar.Closed {
+		dates = append(dates, gin.H{
+			"date":   d.Date,
+			"reason": d.Reason,
+		})
+	}
+
+	return gin.H{
+		"opening_hours": hours,
+		"closed_dates":  dates,
+	}
+}
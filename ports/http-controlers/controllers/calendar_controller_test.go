@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"books/core/storage/models"
+)
+
+func TestCalendar(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/calendar/hours", bytes.NewBufferString(`{"opening_hours": [{"weekday": "Monday", "opens": "09:00", "closes": "18:00"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	if len(response["opening_hours"]) != 1 || response["opening_hours"][0]["weekday"] != "monday" {
+		t.Errorf("unexpected opening hours %v", response["opening_hours"])
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/calendar/hours", bytes.NewBufferString(`{"opening_hours": [{"weekday": "Someday", "opens": "09:00", "closes": "18:00"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for an unknown weekday, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/calendar/closed-dates/import", bytes.NewBufferString("BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20261225\r\nSUMMARY:Christmas\r\nEND:VEVENT\r\n"))
+	req.Header.Set("Content-Type", "text/calendar")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	if len(response["closed_dates"]) != 1 || response["closed_dates"][0]["reason"] != "Christmas" {
+		t.Errorf("unexpected closed dates %v", response["closed_dates"])
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/calendar/closed-dates/import", bytes.NewBufferString("BEGIN:VEVENT\r\n"))
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for a broken file, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	for _, expected := range []int{http.StatusOK, http.StatusNotFound} {
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodDelete, "/calendar/closed-dates/2026-12-25", nil)
+		router.ServeHTTP(w, req)
+		if w.Code != expected {
+			t.Errorf("expected status %d, got %d", expected, w.Code)
+		}
+	}
+}
+
+func TestRentalDueOnClosedDayMoves(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	// The default loan ends in 14 days; close that day and the next.
+	today := time.Now()
+	_ = postJSON(router, "/calendar/closed-dates", map[string]interface{}{
+		"dates": []map[string]interface{}{
+			{"date": today.AddDate(0, 0, 14).Format("2006-01-02"), "reason": "Stocktaking"},
+			{"date": today.AddDate(0, 0, 15).Format("2006-01-02"), "reason": "Stocktaking"},
+		},
+	})
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", "9783161484100", "", "", models.BookDetails{})
+	_, _ = appCore.RentBook(context.TODO(), "9783161484100", "user1", "")
+
+	rentals, _ := appCore.GetUserRentals(context.TODO(), "user1")
+	if len(rentals) != 1 {
+		t.Fatalf("expected 1 rental, got %d", len(rentals))
+	}
+
+	due := rentals[0].ReturnDeadline
+	if due.Format("2006-01-02") != today.AddDate(0, 0, 16).Format("2006-01-02") {
+		t.Errorf("expected the deadline to move past the closed days, got %v", due)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/rentals/"+rentals[0].ID, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	if response["rental"]["open_days_until_due"] != float64(14) {
+		t.Errorf("expected 14 open days until due, got %v", response["rental"]["open_days_until_due"])
+	}
+}
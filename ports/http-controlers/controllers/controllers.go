@@ -16,16 +16,28 @@ type DBPinger interface {
 
 // Controllers contains all HTTP controllers
 type Controllers struct {
-	BookController *BookController
-	db             DBPinger
+	BookController     *BookController
+	LibraryController  *LibraryController
+	CopyController     *CopyController
+	PatronController   *PatronController
+	CalendarController *CalendarController
+	BranchController   *BranchController
+	AuthorController   *AuthorController
+	db                 DBPinger
 	// Add other controllers here as needed
 }
 
 // NewControllers creates and initializes all HTTP controllers
 func NewControllers(core *core.Core) *Controllers {
 	return &Controllers{
-		BookController: NewBookController(core),
-		db:             nil, // No DB for simple setup
+		BookController:     NewBookController(core),
+		LibraryController:  NewLibraryController(core),
+		CopyController:     NewCopyController(core),
+		PatronController:   NewPatronController(core),
+		CalendarController: NewCalendarController(core),
+		BranchController:   NewBranchController(core),
+		AuthorController:   NewAuthorController(core),
+		db:                 nil, // No DB for simple setup
 		// Initialize other controllers here
 	}
 }
@@ -33,8 +45,14 @@ func NewControllers(core *core.Core) *Controllers {
 // NewControllersWithDB creates and initializes all HTTP controllers with DB health check
 func NewControllersWithDB(core *core.Core, db *sql.DB) *Controllers {
 	return &Controllers{
-		BookController: NewBookController(core),
-		db:             db,
+		BookController:     NewBookController(core),
+		LibraryController:  NewLibraryController(core),
+		CopyController:     NewCopyController(core),
+		PatronController:   NewPatronController(core),
+		CalendarController: NewCalendarController(core),
+		BranchController:   NewBranchController(core),
+		AuthorController:   NewAuthorController(core),
+		db:                 db,
 		// Initialize other controllers here
 	}
 }
@@ -49,14 +67,124 @@ func (c *Controllers) RegisterRoutes(router *gin.Engine) {
 
 		// Read
 		booksGroup.GET("", c.BookController.GetAllBooks)
+		booksGroup.GET("/search", c.BookController.SearchBooks)
 		booksGroup.GET("/isbn/:isbn", c.BookController.GetBookByISBN)
 		booksGroup.GET("/:isbn", c.BookController.GetBook)
 
 		// Update
 		booksGroup.PUT("/:isbn", c.BookController.UpdateBook)
+		booksGroup.POST("/:isbn/restore", c.BookController.RestoreBook)
 
 		// Delete
 		booksGroup.DELETE("/:isbn", c.BookController.DeleteBook)
+		booksGroup.POST("/purge", c.BookController.PurgeDeletedBooks)
+
+		// Rentals
+		booksGroup.POST("/:isbn/rentals", c.LibraryController.RentBook)
+		booksGroup.POST("/:isbn/return", c.LibraryController.ReturnBook)
+
+		// Holds
+		booksGroup.POST("/:isbn/holds", c.LibraryController.PlaceHold)
+		booksGroup.GET("/:isbn/holds", c.LibraryController.GetBookHolds)
+
+		// Copies
+		booksGroup.POST("/:isbn/copies", c.CopyController.AddCopy)
+		booksGroup.GET("/:isbn/copies", c.CopyController.GetBookCopies)
+	}
+
+	// Register ISBN routes
+	router.GET("/isbns/:isbn", c.BookController.GetISBN)
+
+	// Register copy routes
+	copiesGroup := router.Group("/copies")
+	{
+		copiesGroup.PUT("/:id", c.CopyController.UpdateCopy)
+		copiesGroup.DELETE("/:id", c.CopyController.DeleteCopy)
+		copiesGroup.POST("/:id/transfers", c.BranchController.StartTransfer)
+	}
+
+	// Register branch routes
+	branchesGroup := router.Group("/branches")
+	{
+		branchesGroup.POST("", c.BranchController.AddBranch)
+		branchesGroup.GET("", c.BranchController.GetBranches)
+		branchesGroup.GET("/:id", c.BranchController.GetBranch)
+		branchesGroup.PUT("/:id", c.BranchController.UpdateBranch)
+		branchesGroup.GET("/:id/transfers", c.BranchController.GetInboundTransfers)
+	}
+
+	// Register author routes
+	authorsGroup := router.Group("/authors")
+	{
+		authorsGroup.POST("", c.AuthorController.AddAuthor)
+		authorsGroup.GET("", c.AuthorController.GetAuthors)
+		authorsGroup.GET("/matches", c.AuthorController.MatchAuthors)
+		authorsGroup.GET("/:id", c.AuthorController.GetAuthor)
+		authorsGroup.PUT("/:id", c.AuthorController.UpdateAuthor)
+		authorsGroup.GET("/:id/books", c.AuthorController.GetAuthorBooks)
+		authorsGroup.POST("/:id/merge", c.AuthorController.MergeAuthors)
+	}
+
+	// Register transfer routes
+	transfersGroup := router.Group("/transfers")
+	{
+		transfersGroup.POST("/:id/receive", c.BranchController.ReceiveTransfer)
+	}
+
+	// Register rental routes
+	rentalsGroup := router.Group("/rentals")
+	{
+		rentalsGroup.GET("/overdue", c.LibraryController.GetOverdueRentals)
+		rentalsGroup.GET("/:id", c.LibraryController.GetRental)
+		rentalsGroup.POST("/:id/renew", c.LibraryController.RenewRental)
+		rentalsGroup.POST("/:id/lost", c.LibraryController.MarkRentalLost)
+		rentalsGroup.POST("/:id/damaged", c.LibraryController.MarkRentalDamaged)
+		rentalsGroup.POST("/:id/found", c.LibraryController.MarkRentalFound)
+	}
+
+	// Register hold routes
+	holdsGroup := router.Group("/holds")
+	{
+		holdsGroup.POST("/:id/cancel", c.LibraryController.CancelHold)
+	}
+
+	// Register patron routes
+	patronsGroup := router.Group("/patrons")
+	{
+		patronsGroup.POST("", c.PatronController.RegisterPatron)
+		patronsGroup.GET("", c.PatronController.GetPatrons)
+		patronsGroup.GET("/:id", c.PatronController.GetPatron)
+		patronsGroup.PUT("/:id", c.PatronController.UpdatePatron)
+		patronsGroup.DELETE("/:id", c.PatronController.DeletePatron)
+		patronsGroup.POST("/:id/suspend", c.PatronController.SuspendPatron)
+		patronsGroup.POST("/:id/reinstate", c.PatronController.ReinstatePatron)
+		patronsGroup.POST("/:id/renew", c.PatronController.RenewPatron)
+	}
+
+	// Register user routes
+	usersGroup := router.Group("/users")
+	{
+		usersGroup.GET("/:id/rentals", c.LibraryController.GetUserRentals)
+		usersGroup.GET("/:id/holds", c.LibraryController.GetUserHolds)
+		usersGroup.GET("/:id/balance", c.LibraryController.GetUserBalance)
+		usersGroup.POST("/:id/payments", c.LibraryController.PayFine)
+		usersGroup.POST("/:id/waivers", c.LibraryController.WaiveFine)
+	}
+
+	// Register library routes
+	libraryGroup := router.Group("/library")
+	{
+		libraryGroup.GET("/books", c.LibraryController.GetLibraryBooks)
+	}
+
+	// Register calendar routes
+	calendarGroup := router.Group("/calendar")
+	{
+		calendarGroup.GET("", c.CalendarController.GetCalendar)
+		calendarGroup.PUT("/hours", c.CalendarController.SetOpeningHours)
+		calendarGroup.POST("/closed-dates", c.CalendarController.AddClosedDates)
+		calendarGroup.POST("/closed-dates/import", c.CalendarController.ImportClosedDates)
+		calendarGroup.DELETE("/closed-dates/:date", c.CalendarController.RemoveClosedDate)
 	}
 
 	// Register health check with optional DB ping
@@ -87,4 +215,4 @@ func (c *Controllers) healthCheck(ctx *gin.Context) {
 		"status":   status,
 		"database": dbStatus,
 	})
-}
\ No newline at end of file
+}
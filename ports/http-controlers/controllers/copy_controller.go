@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"books/core"
+	"books/core/storage/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CopyController struct {
+	core *core.Core
+}
+
+func NewCopyController(core *core.Core) *CopyController {
+	return &CopyController{core: core}
+}
+
+type AddCopyRequest struct {
+	Barcode       string `json:"barcode" binding:"max=64"`
+	ShelfLocation string `json:"shelf_location" binding:"max=100"`
+	Condition     string `json:"condition" binding:"max=20"`
+	HomeBranchID  string `json:"home_branch_id" binding:"max=64"`
+}
+
+type UpdateCopyRequest struct {
+	Barcode       string `json:"barcode" binding:"max=64"`
+	ShelfLocation string `json:"shelf_location" binding:"max=100"`
+	Condition     string `json:"condition" binding:"max=20"`
+	HomeBranchID  string `json:"home_branch_id" binding:"max=64"`
+}
+
+func (c *CopyController) AddCopy(ctx *gin.Context) {
+	isbn := ctx.Param("isbn")
+
+	if isbn == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ISBN parameter is required"})
+		return
+	}
+
+	var request AddCopyRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	bookCopy, err := c.core.AddCopy(ctx, isbn, request.Barcode, request.ShelfLocation, request.Condition, request.HomeBranchID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("AddCopy error for ISBN %s: %v", isbn, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Copy added successfully",
+		"copy":    copyResponse(bookCopy),
+	})
+}
+
+func (c *CopyController) GetBookCopies(ctx *gin.Context) {
+	isbn := ctx.Param("isbn")
+
+	if isbn == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ISBN parameter is required"})
+		return
+	}
+
+	copies, err := c.core.GetBookCopies(ctx, isbn)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetBookCopies error for ISBN %s: %v", isbn, err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(copies))
+	for _, bookCopy := range copies {
+		result = append(result, copyResponse(bookCopy))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"copies": result,
+	})
+}
+
+func (c *CopyController) UpdateCopy(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "copy ID parameter is required"})
+		return
+	}
+
+	var request UpdateCopyRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	bookCopy, err := c.core.UpdateCopy(ctx, id, request.Barcode, request.ShelfLocation, request.Condition, request.HomeBranchID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("UpdateCopy error for copy %s: %v", id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Copy updated successfully",
+		"copy":    copyResponse(bookCopy),
+	})
+}
+
+func (c *CopyController) DeleteCopy(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "copy ID parameter is required"})
+		return
+	}
+
+	err := c.core.DeleteCopy(ctx, id)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("DeleteCopy error for copy %s: %v", id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Copy deleted successfully"})
+}
+
+func copyResponse(bookCopy *models.Copy) gin.H {
+	return gin.H{
+		"id":                bookCopy.ID,
+		"isbn":              bookCopy.ISBN,
+		"barcode":           bookCopy.Barcode,
+		"shelf_location":    bookCopy.ShelfLocation,
+		"condition":         bookCopy.Condition,
+		"status":            bookCopy.Status,
+		"home_branch_id":    bookCopy.HomeBranchID,
+		"current_branch_id": bookCopy.CurrentBranchID,
+		"added_at":          bookCopy.AddedAt,
+	}
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"books/core/storage/models"
+)
+
+func TestCopies(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	validISBN := "9783161484100"
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
+
+	w := postJSON(router, "/books/"+validISBN+"/copies", map[string]interface{}{
+		"barcode":        "BC-2",
+		"shelf_location": "A1",
+		"condition":      "new",
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var added map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &added)
+	copyID, _ := added["copy"]["id"].(string)
+	if added["copy"]["barcode"] != "BC-2" || copyID == "" {
+		t.Errorf("unexpected copy %v", added["copy"])
+	}
+
+	w = postJSON(router, "/books/"+validISBN+"/copies", map[string]interface{}{"barcode": "BC-2"})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected duplicate barcode to conflict, got %d", w.Code)
+	}
+
+	w = postJSON(router, "/books/9780306406157/copies", map[string]interface{}{})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected copy of an unknown book to be rejected, got %d", w.Code)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/books/"+validISBN+"/copies", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var listed map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &listed)
+	if len(listed["copies"]) != 2 {
+		t.Fatalf("expected 2 copies, got %d", len(listed["copies"]))
+	}
+
+	// Both copies can be out at once.
+	for _, userID := range []string{"user1", "user2"} {
+		w = postJSON(router, "/books/"+validISBN+"/rentals", map[string]interface{}{"user_id": userID})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected %s to borrow a copy, got %d. Body: %s", userID, w.Code, w.Body.String())
+		}
+	}
+
+	var rented map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &rented)
+	if rented["book"]["total_copies"] != float64(2) || rented["book"]["available_copies"] != float64(0) {
+		t.Errorf("expected 0 of 2 copies available, got %v", rented["book"])
+	}
+
+	w = postJSON(router, "/books/"+validISBN+"/rentals", map[string]interface{}{"user_id": "user3"})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected rental to be refused with every copy out, got %d", w.Code)
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{"condition": "damaged"})
+	req, _ = http.NewRequest(http.MethodPut, "/copies/"+copyID, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	req, _ = http.NewRequest(http.MethodDelete, "/copies/missing", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,556 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"books/core"
+	calendar_models "books/core/calendar/models"
+	library_errors "books/core/library/errors"
+	library_models "books/core/library/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LibraryController struct {
+	core *core.Core
+}
+
+func NewLibraryController(core *core.Core) *LibraryController {
+	return &LibraryController{core: core}
+}
+
+// RentalRequest borrows or returns a book. BranchID is the branch the patron
+// is at; empty means any branch.
+type RentalRequest struct {
+	UserID   string `json:"user_id" binding:"required,max=255"`
+	BranchID string `json:"branch_id" binding:"max=64"`
+}
+
+type PatronRequest struct {
+	UserID string `json:"user_id" binding:"required,max=255"`
+}
+
+type HoldRequest struct {
+	UserID         string `json:"user_id" binding:"required,max=255"`
+	PickupBranchID string `json:"pickup_branch_id" binding:"max=64"`
+}
+
+// WriteOffRequest optionally overrides the loan policy's replacement fee.
+type WriteOffRequest struct {
+	FeeCents int64 `json:"fee_cents" binding:"gte=0"`
+}
+
+type FineRequest struct {
+	AmountCents int64  `json:"amount_cents" binding:"required,gt=0"`
+	Note        string `json:"note" binding:"max=500"`
+}
+
+func (c *LibraryController) RentBook(ctx *gin.Context) {
+	isbn := ctx.Param("isbn")
+
+	if isbn == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ISBN parameter is required"})
+		return
+	}
+
+	var request RentalRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	book, err := c.core.RentBook(ctx, isbn, request.UserID, request.BranchID)
+	if err != nil {
+		var blocked *library_errors.BorrowingBlockedError
+		if errors.As(err, &blocked) {
+			ctx.JSON(http.StatusConflict, borrowingBlockedResponse(blocked))
+			return
+		}
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("RentBook error for ISBN %s: %v", isbn, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Book rented successfully",
+		"book":    libraryBookResponse(book),
+	})
+}
+
+func (c *LibraryController) ReturnBook(ctx *gin.Context) {
+	isbn := ctx.Param("isbn")
+
+	if isbn == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ISBN parameter is required"})
+		return
+	}
+
+	var request RentalRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	rental, err := c.core.ReturnBook(ctx, isbn, request.UserID, request.BranchID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("ReturnBook error for ISBN %s: %v", isbn, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Book returned successfully",
+		"rental":  rentalResponse(rental),
+	})
+}
+
+func (c *LibraryController) RenewRental(ctx *gin.Context) {
+	rentalID := ctx.Param("id")
+
+	if rentalID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "rental ID parameter is required"})
+		return
+	}
+
+	var request PatronRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	rental, err := c.core.RenewRental(ctx, rentalID, request.UserID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("RenewRental error for rental %s: %v", rentalID, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Rental renewed successfully",
+		"rental":  rentalResponse(rental),
+	})
+}
+
+func (c *LibraryController) MarkRentalLost(ctx *gin.Context) {
+	c.writeOff(ctx, "MarkRentalLost", "Rental marked as lost", c.core.MarkRentalLost)
+}
+
+func (c *LibraryController) MarkRentalDamaged(ctx *gin.Context) {
+	c.writeOff(ctx, "MarkRentalDamaged", "Rental marked as damaged", c.core.MarkRentalDamaged)
+}
+
+type writeOffFunc func(ctx context.Context, rentalID string, feeCents int64) (*library_models.BookRental, error)
+
+func (c *LibraryController) writeOff(ctx *gin.Context, operation, message string, writeOff writeOffFunc) {
+	rentalID := ctx.Param("id")
+
+	var request WriteOffRequest
+
+	// The body is optional; without it the loan policy's fee applies.
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+			return
+		}
+	}
+
+	rental, err := writeOff(ctx, rentalID, request.FeeCents)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("%s error for rental %s: %v", operation, rentalID, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"rental":  rentalResponse(rental),
+	})
+}
+
+func (c *LibraryController) MarkRentalFound(ctx *gin.Context) {
+	rentalID := ctx.Param("id")
+
+	rental, err := c.core.MarkRentalFound(ctx, rentalID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("MarkRentalFound error for rental %s: %v", rentalID, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Rental marked as found",
+		"rental":  rentalResponse(rental),
+	})
+}
+
+func (c *LibraryController) GetUserRentals(ctx *gin.Context) {
+	userID := ctx.Param("id")
+
+	if userID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user ID parameter is required"})
+		return
+	}
+
+	rentals, err := c.core.GetUserRentals(ctx, userID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetUserRentals error for user %s: %v", userID, err)
+		return
+	}
+
+	calendar, err := c.core.GetCalendar(ctx)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetUserRentals error for user %s: %v", userID, err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(rentals))
+	for _, rental := range rentals {
+		result = append(result, rentalCalendarResponse(rental, calendar))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"rentals": result,
+	})
+}
+
+func (c *LibraryController) GetRental(ctx *gin.Context) {
+	rentalID := ctx.Param("id")
+
+	rental, err := c.core.GetRental(ctx, rentalID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetRental error for rental %s: %v", rentalID, err)
+		return
+	}
+
+	calendar, err := c.core.GetCalendar(ctx)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetRental error for rental %s: %v", rentalID, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"rental": rentalCalendarResponse(rental, calendar),
+	})
+}
+
+func (c *LibraryController) GetOverdueRentals(ctx *gin.Context) {
+	rentals, err := c.core.GetOverdueRentals(ctx)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetOverdueRentals error: %v", err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(rentals))
+	for _, rental := range rentals {
+		result = append(result, rentalResponse(rental))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"rentals": result,
+	})
+}
+
+func (c *LibraryController) PlaceHold(ctx *gin.Context) {
+	isbn := ctx.Param("isbn")
+
+	if isbn == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ISBN parameter is required"})
+		return
+	}
+
+	var request HoldRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	hold, err := c.core.PlaceHold(ctx, isbn, request.UserID, request.PickupBranchID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("PlaceHold error for ISBN %s: %v", isbn, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Hold placed successfully",
+		"hold":    holdResponse(hold),
+	})
+}
+
+func (c *LibraryController) CancelHold(ctx *gin.Context) {
+	holdID := ctx.Param("id")
+
+	if holdID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "hold ID parameter is required"})
+		return
+	}
+
+	var request PatronRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	hold, err := c.core.CancelHold(ctx, holdID, request.UserID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("CancelHold error for hold %s: %v", holdID, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Hold cancelled successfully",
+		"hold":    holdResponse(hold),
+	})
+}
+
+func (c *LibraryController) GetBookHolds(ctx *gin.Context) {
+	isbn := ctx.Param("isbn")
+
+	if isbn == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ISBN parameter is required"})
+		return
+	}
+
+	holds, err := c.core.GetBookHolds(ctx, isbn)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetBookHolds error for ISBN %s: %v", isbn, err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(holds))
+	for i, hold := range holds {
+		response := holdResponse(hold)
+		response["position"] = i + 1
+		result = append(result, response)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"holds": result,
+	})
+}
+
+func (c *LibraryController) GetUserHolds(ctx *gin.Context) {
+	userID := ctx.Param("id")
+
+	if userID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user ID parameter is required"})
+		return
+	}
+
+	holds, err := c.core.GetUserHolds(ctx, userID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetUserHolds error for user %s: %v", userID, err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(holds))
+	for _, hold := range holds {
+		result = append(result, holdResponse(hold))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"holds": result,
+	})
+}
+
+func (c *LibraryController) GetUserBalance(ctx *gin.Context) {
+	userID := ctx.Param("id")
+
+	if userID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user ID parameter is required"})
+		return
+	}
+
+	account, err := c.core.GetUserBalance(ctx, userID)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetUserBalance error for user %s: %v", userID, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, fineAccountResponse(account))
+}
+
+func (c *LibraryController) PayFine(ctx *gin.Context) {
+	c.settleFine(ctx, "PayFine", "Payment recorded successfully", c.core.PayFine)
+}
+
+func (c *LibraryController) WaiveFine(ctx *gin.Context) {
+	c.settleFine(ctx, "WaiveFine", "Waiver recorded successfully", c.core.WaiveFine)
+}
+
+type settleFunc func(ctx context.Context, userID string, amountCents int64, note string) (*library_models.FineAccount, error)
+
+func (c *LibraryController) settleFine(ctx *gin.Context, operation, message string, settle settleFunc) {
+	userID := ctx.Param("id")
+
+	if userID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user ID parameter is required"})
+		return
+	}
+
+	var request FineRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	account, err := settle(ctx, userID, request.AmountCents, request.Note)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("%s error for user %s: %v", operation, userID, err)
+		return
+	}
+
+	response := fineAccountResponse(account)
+	response["message"] = message
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// GetLibraryBooks lists the availability of every book, counting only the
+// copies at the branch given by the branch query parameter when it is set.
+func (c *LibraryController) GetLibraryBooks(ctx *gin.Context) {
+	books, err := c.core.GetLibraryBooks(ctx, ctx.Query("branch"))
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetLibraryBooks error: %v", err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(books))
+	for _, book := range books {
+		result = append(result, libraryBookResponse(book))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"books": result,
+	})
+}
+
+func libraryBookResponse(book *library_models.LibraryBook) gin.H {
+	response := gin.H{
+		"isbn":              book.ISBN,
+		"title":             book.Title,
+		"author":            book.Author,
+		"category":          book.Category,
+		"total_copies":      book.TotalCopies,
+		"available_copies":  book.AvailableCopies,
+		"in_transit_copies": book.InTransitCopies,
+		"is_available":      book.IsAvailable,
+		"due_date":          book.DueDate,
+		"is_overdue":        book.IsOverdue,
+		"days_until_due":    book.DaysUntilDue(),
+	}
+	if book.BranchID != "" {
+		response["branch_id"] = book.BranchID
+	}
+	return response
+}
+
+// borrowingBlockedResponse tells the client which rule refused the loan, so
+// it can say why without parsing the message.
+func borrowingBlockedResponse(blocked *library_errors.BorrowingBlockedError) gin.H {
+	response := gin.H{
+		"error":      blocked.Error(),
+		"reason":     blocked.Reason,
+		"open_loans": blocked.OpenLoans,
+	}
+	if blocked.Limit > 0 {
+		response["limit"] = blocked.Limit
+	}
+	if blocked.OverdueLoans > 0 {
+		response["overdue_loans"] = blocked.OverdueLoans
+	}
+	return response
+}
+
+func rentalResponse(rental *library_models.BookRental) gin.H {
+	return gin.H{
+		"id":              rental.ID,
+		"isbn":            rental.BookID,
+		"copy_id":         rental.CopyID,
+		"borrowed_at":     rental.BorrowedAt,
+		"return_deadline": rental.ReturnDeadline,
+		"returned_at":     rental.ReturnedAt,
+		"returned_late":   rental.ReturnedLate,
+		"renewal_count":   rental.RenewalCount,
+		"close_reason":    rental.CloseReason,
+		"is_overdue":      rental.IsOverdue(),
+		"days_until_due":  rental.DaysUntilDue(),
+	}
+}
+
+// rentalCalendarResponse adds the number of days the library opens before
+// the rental is due.
+func rentalCalendarResponse(rental *library_models.BookRental, calendar *calendar_models.Calendar) gin.H {
+	response := rentalResponse(rental)
+	response["open_days_until_due"] = rental.OpenDaysUntilDue(*calendar)
+	return response
+}
+
+func holdResponse(hold *library_models.Hold) gin.H {
+	return gin.H{
+		"id":               hold.ID,
+		"isbn":             hold.BookID,
+		"pickup_branch_id": hold.PickupBranchID,
+		"status":           hold.Status,
+		"placed_at":        hold.PlacedAt,
+		"ready_at":         hold.ReadyAt,
+		"pickup_deadline":  hold.PickupDeadline,
+		"closed_at":        hold.ClosedAt,
+	}
+}
+
+func fineAccountResponse(account *library_models.FineAccount) gin.H {
+	entries := make([]gin.H, 0, len(account.Entries))
+	for _, entry := range account.Entries {
+		entries = append(entries, gin.H{
+			"id":           entry.ID,
+			"type":         entry.Type,
+			"amount_cents": entry.AmountCents,
+			"rental_id":    entry.RentalID,
+			"note":         entry.Note,
+			"created_at":   entry.CreatedAt,
+		})
+	}
+
+	return gin.H{
+		"user_id":       account.UserID,
+		"balance_cents": account.BalanceCents,
+		"entries":       entries,
+	}
+}
@@ -0,0 +1,570 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"books/core/storage/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func postJSON(router *gin.Engine, path string, body map[string]interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRentBook(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	validISBN := "9783161484100"
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
+
+	tests := []struct {
+		name           string
+		isbn           string
+		requestBody    map[string]interface{}
+		expectedStatus int
+	}{
+		{
+			name:           "successful rental",
+			isbn:           validISBN,
+			requestBody:    map[string]interface{}{"user_id": "user1"},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "already borrowed",
+			isbn:           validISBN,
+			requestBody:    map[string]interface{}{"user_id": "user2"},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "already borrowed under its ISBN-10",
+			isbn:           "3-16-148410-X",
+			requestBody:    map[string]interface{}{"user_id": "user3"},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "missing user",
+			isbn:           validISBN,
+			requestBody:    map[string]interface{}{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "non-existing book",
+			isbn:           "9780306406157",
+			requestBody:    map[string]interface{}{"user_id": "user1"},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := postJSON(router, "/books/"+tc.isbn+"/rentals", tc.requestBody)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d. Body: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRentBookResponse(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	validISBN := "9783161484100"
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
+
+	w := postJSON(router, "/books/"+validISBN+"/rentals", map[string]interface{}{"user_id": "user1"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var response map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+	book := response["book"]
+	if book["is_available"] != false {
+		t.Errorf("expected book to be unavailable, got %v", book["is_available"])
+	}
+	if book["due_date"] == nil {
+		t.Errorf("expected due date in response")
+	}
+	if book["is_overdue"] != false {
+		t.Errorf("expected book not to be overdue, got %v", book["is_overdue"])
+	}
+}
+
+func TestReturnBook(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	validISBN := "9783161484100"
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
+	_, _ = appCore.RentBook(context.TODO(), validISBN, "user1", "")
+
+	tests := []struct {
+		name           string
+		requestBody    map[string]interface{}
+		expectedStatus int
+	}{
+		{
+			name:           "borrowed by another user",
+			requestBody:    map[string]interface{}{"user_id": "user2"},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "successful return",
+			requestBody:    map[string]interface{}{"user_id": "user1"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not borrowed",
+			requestBody:    map[string]interface{}{"user_id": "user1"},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := postJSON(router, "/books/"+validISBN+"/return", tc.requestBody)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d. Body: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetUserRentals(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", "9783161484100", "", "", models.BookDetails{})
+	_, _ = appCore.AddBook(context.TODO(), "Other Book", "Other Author", "9780306406157", "", "", models.BookDetails{})
+	_, _ = appCore.RentBook(context.TODO(), "9783161484100", "user1", "")
+	_, _ = appCore.RentBook(context.TODO(), "9780306406157", "user2", "")
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/user1/rentals", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+	rentals, ok := response["rentals"].([]interface{})
+	if !ok {
+		t.Errorf("expected rentals array in response")
+		return
+	}
+
+	if len(rentals) != 1 {
+		t.Errorf("expected 1 rental, got %d", len(rentals))
+	}
+}
+
+func TestGetRental(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", "9783161484100", "", "", models.BookDetails{})
+	_, _ = appCore.RentBook(context.TODO(), "9783161484100", "user1", "")
+
+	rentals, _ := appCore.GetUserRentals(context.TODO(), "user1")
+	if len(rentals) != 1 {
+		t.Fatalf("expected 1 rental, got %d", len(rentals))
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/rentals/"+rentals[0].ID, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	if response["rental"]["id"] != rentals[0].ID || response["rental"]["isbn"] != "9783161484100" {
+		t.Errorf("unexpected rental %v", response["rental"])
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/rentals/missing", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	// The rental is not due yet, so nothing is overdue.
+	req, _ = http.NewRequest(http.MethodGet, "/rentals/overdue", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var overdue map[string][]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &overdue)
+	if w.Code != http.StatusOK || len(overdue["rentals"]) != 0 {
+		t.Errorf("expected no overdue rentals, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetLibraryBooks(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", "9783161484100", "", "", models.BookDetails{})
+	_, _ = appCore.AddBook(context.TODO(), "Other Book", "Other Author", "9780306406157", "", "", models.BookDetails{})
+	_, _ = appCore.RentBook(context.TODO(), "9783161484100", "user1", "")
+
+	req, _ := http.NewRequest(http.MethodGet, "/library/books", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+	books := response["books"]
+	if len(books) != 2 {
+		t.Fatalf("expected 2 books, got %d", len(books))
+	}
+
+	available := 0
+	for _, book := range books {
+		if book["is_available"] == true {
+			available++
+		}
+	}
+	if available != 1 {
+		t.Errorf("expected 1 available book, got %d", available)
+	}
+}
+
+func TestReturnedRentalsStayInHistory(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	validISBN := "9783161484100"
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
+	_, _ = appCore.RentBook(context.TODO(), validISBN, "user1", "")
+
+	w := postJSON(router, "/books/"+validISBN+"/return", map[string]interface{}{"user_id": "user1"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var returned map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &returned)
+	if returned["rental"]["returned_late"] != false {
+		t.Errorf("expected on-time return, got %v", returned["rental"]["returned_late"])
+	}
+
+	_, _ = appCore.RentBook(context.TODO(), validISBN, "user1", "")
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/user1/rentals", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+	rentals := response["rentals"]
+	if len(rentals) != 2 {
+		t.Fatalf("expected 2 rentals in history, got %d", len(rentals))
+	}
+	if rentals[1]["returned_at"] == nil {
+		t.Errorf("expected the older rental to be closed")
+	}
+	if rentals[0]["returned_at"] != nil {
+		t.Errorf("expected the newest rental to be open")
+	}
+}
+
+func TestRenewRental(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	validISBN := "9783161484100"
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
+	_, _ = appCore.RentBook(context.TODO(), validISBN, "user1", "")
+
+	rentals, _ := appCore.GetUserRentals(context.TODO(), "user1")
+	if len(rentals) != 1 {
+		t.Fatalf("expected 1 rental, got %d", len(rentals))
+	}
+	rentalID := rentals[0].ID
+
+	tests := []struct {
+		name           string
+		rentalID       string
+		requestBody    map[string]interface{}
+		expectedStatus int
+	}{
+		{
+			name:           "successful renewal",
+			rentalID:       rentalID,
+			requestBody:    map[string]interface{}{"user_id": "user1"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "rental of another user",
+			rentalID:       rentalID,
+			requestBody:    map[string]interface{}{"user_id": "user2"},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "missing user",
+			rentalID:       rentalID,
+			requestBody:    map[string]interface{}{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "non-existing rental",
+			rentalID:       "missing",
+			requestBody:    map[string]interface{}{"user_id": "user1"},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := postJSON(router, "/rentals/"+tc.rentalID+"/renew", tc.requestBody)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d. Body: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	rentals, _ = appCore.GetUserRentals(context.TODO(), "user1")
+	if rentals[0].RenewalCount != 1 {
+		t.Errorf("expected renewal count 1, got %d", rentals[0].RenewalCount)
+	}
+}
+
+func TestHolds(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	validISBN := "9783161484100"
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
+
+	w := postJSON(router, "/books/"+validISBN+"/holds", map[string]interface{}{"user_id": "user2"})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected hold on an available book to be refused, got %d", w.Code)
+	}
+
+	_, _ = appCore.RentBook(context.TODO(), validISBN, "user1", "")
+
+	w = postJSON(router, "/books/"+validISBN+"/holds", map[string]interface{}{"user_id": "user2"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var placed map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &placed)
+	holdID, _ := placed["hold"]["id"].(string)
+	if placed["hold"]["status"] != "waiting" {
+		t.Errorf("expected waiting hold, got %v", placed["hold"]["status"])
+	}
+
+	w = postJSON(router, "/books/"+validISBN+"/holds", map[string]interface{}{"user_id": "user3"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	_, _ = appCore.ReturnBook(context.TODO(), validISBN, "user1", "")
+
+	w = postJSON(router, "/books/"+validISBN+"/rentals", map[string]interface{}{"user_id": "user3"})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected patron behind the queue head to be refused, got %d", w.Code)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/books/"+validISBN+"/holds", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var queue map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &queue)
+	if len(queue["holds"]) != 2 {
+		t.Fatalf("expected 2 holds in queue, got %d", len(queue["holds"]))
+	}
+	if queue["holds"][0]["status"] != "ready" || queue["holds"][0]["position"] != float64(1) {
+		t.Errorf("expected first hold to be ready at position 1, got %v", queue["holds"][0])
+	}
+
+	w = postJSON(router, "/holds/"+holdID+"/cancel", map[string]interface{}{"user_id": "user2"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = postJSON(router, "/books/"+validISBN+"/rentals", map[string]interface{}{"user_id": "user3"})
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected next patron to borrow after cancellation, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/users/user2/holds", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var userHolds map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &userHolds)
+	if len(userHolds["holds"]) != 1 || userHolds["holds"][0]["status"] != "cancelled" {
+		t.Errorf("expected cancelled hold in user history, got %v", userHolds["holds"])
+	}
+}
+
+func TestUserBalance(t *testing.T) {
+	router, _ := setupTestRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/user1/balance", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	if response["balance_cents"] != float64(0) {
+		t.Errorf("expected zero balance, got %v", response["balance_cents"])
+	}
+	if entries, ok := response["entries"].([]interface{}); !ok || len(entries) != 0 {
+		t.Errorf("expected empty ledger, got %v", response["entries"])
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		requestBody    map[string]interface{}
+		expectedStatus int
+	}{
+		{
+			name:           "payment without balance",
+			path:           "/users/user1/payments",
+			requestBody:    map[string]interface{}{"amount_cents": 100},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "waiver without balance",
+			path:           "/users/user1/waivers",
+			requestBody:    map[string]interface{}{"amount_cents": 100},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "negative amount",
+			path:           "/users/user1/payments",
+			requestBody:    map[string]interface{}{"amount_cents": -5},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing amount",
+			path:           "/users/user1/payments",
+			requestBody:    map[string]interface{}{},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := postJSON(router, tc.path, tc.requestBody)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d. Body: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestLostAndFoundRental(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", "9783161484100", "", "", models.BookDetails{})
+	_, _ = appCore.RentBook(context.TODO(), "9783161484100", "user1", "")
+
+	rentals, _ := appCore.GetUserRentals(context.TODO(), "user1")
+	if len(rentals) != 1 {
+		t.Fatalf("expected 1 rental, got %d", len(rentals))
+	}
+	rentalID := rentals[0].ID
+
+	if w := postJSON(router, "/rentals/"+rentalID+"/found", nil); w.Code != http.StatusConflict {
+		t.Errorf("expected open rental not to be found, got %d", w.Code)
+	}
+	if w := postJSON(router, "/rentals/"+rentalID+"/lost", map[string]interface{}{"fee_cents": -1}); w.Code != http.StatusBadRequest {
+		t.Errorf("expected negative fee to be refused, got %d", w.Code)
+	}
+
+	w := postJSON(router, "/rentals/"+rentalID+"/lost", map[string]interface{}{"fee_cents": 1800})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	if response["rental"]["close_reason"] != "lost" {
+		t.Errorf("expected rental closed as lost, got %v", response["rental"])
+	}
+
+	balance, _ := appCore.GetUserBalance(context.TODO(), "user1")
+	if balance.BalanceCents != 1800 {
+		t.Errorf("expected replacement fee of 1800, got %d", balance.BalanceCents)
+	}
+
+	if w := postJSON(router, "/rentals/"+rentalID+"/damaged", nil); w.Code != http.StatusConflict {
+		t.Errorf("expected closed rental to be refused, got %d", w.Code)
+	}
+
+	if w := postJSON(router, "/rentals/"+rentalID+"/found", nil); w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	balance, _ = appCore.GetUserBalance(context.TODO(), "user1")
+	if balance.BalanceCents != 0 {
+		t.Errorf("expected replacement fee to be reversed, got %d", balance.BalanceCents)
+	}
+
+	if w := postJSON(router, "/rentals/missing/lost", nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestRentBookLoanLimit(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	isbns := []string{"9780306406157", "9783161484100", "9780596517748", "9780000000002", "9780000000019", "9780000000026"}
+	for _, isbn := range isbns {
+		_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", isbn, "", "", models.BookDetails{})
+	}
+	for _, isbn := range isbns[:5] {
+		if _, err := appCore.RentBook(context.TODO(), isbn, "user1", ""); err != nil {
+			t.Fatalf("failed to rent %s: %v", isbn, err)
+		}
+	}
+
+	w := postJSON(router, "/books/"+isbns[5]+"/rentals", map[string]interface{}{"user_id": "user1"})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	if response["reason"] != "loan_limit_reached" || response["limit"] != float64(5) || response["open_loans"] != float64(5) {
+		t.Errorf("unexpected response %v", response)
+	}
+}
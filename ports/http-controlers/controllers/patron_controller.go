@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"books/core"
+	patron_models "books/core/patrons/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PatronController struct {
+	core *core.Core
+}
+
+func NewPatronController(core *core.Core) *PatronController {
+	return &PatronController{core: core}
+}
+
+type RegisterPatronRequest struct {
+	CardNumber string `json:"card_number" binding:"required,max=32"`
+	Name       string `json:"name" binding:"required,max=255"`
+	Email      string `json:"email" binding:"max=255"`
+	PatronType string `json:"patron_type" binding:"max=50"`
+}
+
+type UpdatePatronRequest struct {
+	CardNumber string `json:"card_number" binding:"max=32"`
+	Name       string `json:"name" binding:"max=255"`
+	Email      string `json:"email" binding:"max=255"`
+	PatronType string `json:"patron_type" binding:"max=50"`
+}
+
+func (c *PatronController) RegisterPatron(ctx *gin.Context) {
+	var request RegisterPatronRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	patron, err := c.core.RegisterPatron(ctx, request.CardNumber, request.Name, request.Email, request.PatronType)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("RegisterPatron error: %v", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Patron registered successfully",
+		"patron":  patronResponse(patron),
+	})
+}
+
+func (c *PatronController) GetPatrons(ctx *gin.Context) {
+	patrons, err := c.core.GetPatrons(ctx)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetPatrons error: %v", err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(patrons))
+	for _, patron := range patrons {
+		result = append(result, patronResponse(patron))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"patrons": result,
+	})
+}
+
+func (c *PatronController) GetPatron(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	patron, err := c.core.GetPatron(ctx, id)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("GetPatron error for patron %s: %v", id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"patron": patronResponse(patron),
+	})
+}
+
+func (c *PatronController) UpdatePatron(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var request UpdatePatronRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+
+	patron, err := c.core.UpdatePatron(ctx, id, request.CardNumber, request.Name, request.Email, request.PatronType)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("UpdatePatron error for patron %s: %v", id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Patron updated successfully",
+		"patron":  patronResponse(patron),
+	})
+}
+
+func (c *PatronController) DeletePatron(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	err := c.core.DeletePatron(ctx, id)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("DeletePatron error for patron %s: %v", id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Patron deleted successfully"})
+}
+
+func (c *PatronController) SuspendPatron(ctx *gin.Context) {
+	c.changeStatus(ctx, "SuspendPatron", "Patron suspended successfully", c.core.SuspendPatron)
+}
+
+func (c *PatronController) ReinstatePatron(ctx *gin.Context) {
+	c.changeStatus(ctx, "ReinstatePatron", "Patron reinstated successfully", c.core.ReinstatePatron)
+}
+
+func (c *PatronController) RenewPatron(ctx *gin.Context) {
+	c.changeStatus(ctx, "RenewPatron", "Membership renewed successfully", c.core.RenewPatron)
+}
+
+type patronFunc func(ctx context.Context, id string) (*patron_models.Patron, error)
+
+func (c *PatronController) changeStatus(ctx *gin.Context, operation, message string, change patronFunc) {
+	id := ctx.Param("id")
+
+	patron, err := change(ctx, id)
+	if err != nil {
+		status := mapErrorToStatus(err)
+		ctx.JSON(status, gin.H{"error": sanitizeError(err, status)})
+		log.Printf("%s error for patron %s: %v", operation, id, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"patron":  patronResponse(patron),
+	})
+}
+
+func patronResponse(patron *patron_models.Patron) gin.H {
+	return gin.H{
+		"id":            patron.ID,
+		"card_number":   patron.CardNumber,
+		"name":          patron.Name,
+		"email":         patron.Email,
+		"patron_type":   patron.PatronType,
+		"status":        patron.CurrentStatus(),
+		"expires_at":    patron.ExpiresAt,
+		"registered_at": patron.RegisteredAt,
+	}
+}
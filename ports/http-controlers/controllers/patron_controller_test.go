@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"books/core/storage/models"
+)
+
+func TestPatrons(t *testing.T) {
+	router, appCore := setupTestRouter()
+
+	validISBN := "9783161484100"
+
+	_, _ = appCore.AddBook(context.TODO(), "Test Book", "Test Author", validISBN, "", "", models.BookDetails{})
+
+	w := postJSON(router, "/patrons", map[string]interface{}{
+		"card_number": "C-100",
+		"name":        "Ada Lovelace",
+		"email":       "ada@example.com",
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var registered map[string]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &registered)
+	patronID, _ := registered["patron"]["id"].(string)
+	if registered["patron"]["status"] != "active" || registered["patron"]["patron_type"] != "adult" {
+		t.Errorf("unexpected patron %v", registered["patron"])
+	}
+
+	w = postJSON(router, "/patrons", map[string]interface{}{"card_number": "C-100", "name": "Someone Else"})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected duplicate card number to conflict, got %d", w.Code)
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{"patron_type": "staff"})
+	req, _ := http.NewRequest(http.MethodPut, "/patrons/"+patronID, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = postJSON(router, "/patrons/"+patronID+"/suspend", map[string]interface{}{})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = postJSON(router, "/books/"+validISBN+"/rentals", map[string]interface{}{"user_id": patronID})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected suspended patron to be refused, got %d", w.Code)
+	}
+
+	w = postJSON(router, "/books/"+validISBN+"/rentals", map[string]interface{}{"user_id": "stranger"})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected unknown patron to be refused, got %d", w.Code)
+	}
+
+	w = postJSON(router, "/patrons/"+patronID+"/reinstate", map[string]interface{}{})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = postJSON(router, "/books/"+validISBN+"/rentals", map[string]interface{}{"user_id": patronID})
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected reinstated patron to borrow, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	rentals, _ := appCore.GetUserRentals(context.TODO(), patronID)
+	if len(rentals) != 1 || rentals[0].PatronType != "staff" {
+		t.Errorf("expected rental to use the patron's type, got %+v", rentals)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/patrons", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var listed map[string][]map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &listed)
+	if len(listed["patrons"]) != 4 {
+		t.Errorf("expected 4 patrons, got %d", len(listed["patrons"]))
+	}
+
+	req, _ = http.NewRequest(http.MethodDelete, "/patrons/missing", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}